@@ -0,0 +1,205 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/config"
+	"github.com/wooyey/iclogs/internal/platform/logs"
+	"github.com/wooyey/iclogs/internal/platform/logs/filter"
+	"github.com/wooyey/iclogs/internal/platform/stats"
+	"github.com/wooyey/iclogs/pkg/query"
+)
+
+// Group name for records without group by field
+const noGroup = "-"
+
+const (
+	sparkBars        = "▁▂▃▄▅▆▇█"
+	histogramBuckets = 20
+)
+
+// Time window of records volume sparkline
+type histogram struct {
+	start   time.Time
+	end     time.Time
+	buckets int
+}
+
+// Records of one group with numeric values of each aggregation field
+type aggGroup struct {
+	key    string
+	count  int
+	values [][]float64
+	times  []time.Time
+}
+
+func toNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func groupKey(r filter.Resolver, groupBy string) string {
+	if groupBy == "" {
+		return ""
+	}
+
+	v, ok := r(groupBy)
+	if !ok || v == nil {
+		return noGroup
+	}
+
+	return fmt.Sprint(v)
+}
+
+// Collect aggregation fields values of log records, grouped by field value, groups are sorted by key
+func aggregate(l []logs.Log, aggs []stats.Aggregation, groupBy string, a config.Aliases) []aggGroup {
+	index := map[string]int{}
+	var groups []aggGroup
+
+	for i := range l {
+		r := aliasResolver(filter.LogResolver(&l[i]), a)
+		key := groupKey(r, groupBy)
+
+		n, ok := index[key]
+		if !ok {
+			n = len(groups)
+			index[key] = n
+			groups = append(groups, aggGroup{key: key, values: make([][]float64, len(aggs))})
+		}
+
+		g := &groups[n]
+		g.count++
+		g.times = append(g.times, l[i].Time)
+		for j, agg := range aggs {
+			if v, ok := r(agg.Field); ok {
+				if f, ok := toNumber(v); ok {
+					g.values[j] = append(g.values[j], f)
+				}
+			}
+		}
+	}
+
+	if groupBy == "" && len(groups) == 0 {
+		groups = append(groups, aggGroup{values: make([][]float64, len(aggs))})
+	}
+
+	slices.SortFunc(groups, func(x, y aggGroup) int {
+		return strings.Compare(x.key, y.key)
+	})
+
+	return groups
+}
+
+func formatNumber(v float64) string {
+	if math.IsNaN(v) {
+		return noGroup
+	}
+
+	return strconv.FormatFloat(math.Round(v*1000)/1000, 'f', -1, 64)
+}
+
+// Count of times in equal buckets of histogram window, times out of window are skipped
+func (h *histogram) counts(times []time.Time) []int {
+	counts := make([]int, h.buckets)
+	width := h.end.Sub(h.start)
+	if width <= 0 {
+		return counts
+	}
+
+	for _, t := range times {
+		if t.Before(h.start) || t.After(h.end) {
+			continue
+		}
+		b := int(t.Sub(h.start) * time.Duration(h.buckets) / width)
+		counts[min(b, h.buckets-1)]++
+	}
+
+	return counts
+}
+
+// Render counts as unicode bars scaled to the highest one, empty buckets are blank
+func sparkline(counts []int) string {
+	bars := []rune(sparkBars)
+	top := slices.Max(counts)
+
+	line := make([]rune, len(counts))
+	for i, c := range counts {
+		if c == 0 {
+			line[i] = ' '
+			continue
+		}
+		line[i] = bars[(c*len(bars)-1)/top]
+	}
+
+	return string(line)
+}
+
+// Print aggregations summary table, group column is shown only when grouping and sparkline only with histogram
+func printAggregations(w io.Writer, aggs []stats.Aggregation, groupBy string, groups []aggGroup, h *histogram) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	var header []string
+	if groupBy != "" {
+		header = append(header, groupBy)
+	}
+	if h != nil {
+		header = append(header, "histogram")
+	}
+	header = append(header, "count")
+	for _, agg := range aggs {
+		header = append(header, agg.String())
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+
+	for _, g := range groups {
+		var row []string
+		if groupBy != "" {
+			row = append(row, g.key)
+		}
+		if h != nil {
+			row = append(row, sparkline(h.counts(g.times)))
+		}
+		row = append(row, strconv.Itoa(g.count))
+		for j, agg := range aggs {
+			row = append(row, formatNumber(agg.Compute(g.values[j])))
+		}
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+
+	tw.Flush()
+}
+
+// Print rows of Dataprime aggregation with all their columns, values missing in row are left empty
+func printTable(w io.Writer, t query.TableResult) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, strings.Join(t.Columns, "\t"))
+	for _, r := range t.Rows {
+		row := make([]string, len(t.Columns))
+		for i, c := range t.Columns {
+			switch v := r[c].(type) {
+			case nil:
+			case float64:
+				row[i] = formatNumber(v)
+			default:
+				row[i] = fmt.Sprint(v)
+			}
+		}
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+
+	tw.Flush()
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/logs"
+	"github.com/wooyey/iclogs/internal/platform/stats"
+	"github.com/wooyey/iclogs/pkg/query"
+)
+
+func TestAggregations(t *testing.T) {
+	l := []logs.Log{
+		{UserData: `{"service":"api","response_time":120,"bytes":"2048"}`},
+		{UserData: `{"service":"web","response_time":10}`},
+		{UserData: `{"service":"api","response_time":30.5,"bytes":512}`},
+		{UserData: `{"response_time":"n/a"}`},
+	}
+	aggs := []stats.Aggregation{{Func: stats.Avg, Field: "json.response_time"}, {Func: stats.Max, Field: "json.bytes"}}
+
+	testCases := []struct {
+		name    string
+		groupBy string
+		want    string
+	}{
+		{
+			name: "Total",
+			want: "count  avg(json.response_time)  max(json.bytes)\n" +
+				"4      53.5                     2048\n",
+		},
+		{
+			name:    "GroupBy",
+			groupBy: "json.service",
+			want: "json.service  count  avg(json.response_time)  max(json.bytes)\n" +
+				"-             1      -                        -\n" +
+				"api           2      75.25                    2048\n" +
+				"web           1      10                       -\n",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			buffer := bytes.Buffer{}
+			printAggregations(&buffer, aggs, tt.groupBy, aggregate(l, aggs, tt.groupBy, nil), nil)
+			assert(t, buffer.String(), tt.want)
+		})
+	}
+}
+
+func TestAggregationsEmpty(t *testing.T) {
+	buffer := bytes.Buffer{}
+	printAggregations(&buffer, nil, "", aggregate(nil, nil, "", nil), nil)
+	assert(t, buffer.String(), "count\n0\n")
+}
+
+func TestAggregationsHistogram(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	l := []logs.Log{
+		{Time: start, UserData: `{"service":"api"}`},
+		{Time: start.Add(30 * time.Minute), UserData: `{"service":"api"}`},
+		{Time: start.Add(45 * time.Minute), UserData: `{"service":"api"}`},
+		{Time: start.Add(59 * time.Minute), UserData: `{"service":"web"}`},
+		{Time: start.Add(2 * time.Hour), UserData: `{"service":"web"}`},
+	}
+	h := &histogram{start: start, end: start.Add(time.Hour), buckets: 4}
+	want := "json.service  histogram  count\n" +
+		"api           █ ██       3\n" +
+		"web              █       2\n"
+
+	buffer := bytes.Buffer{}
+	printAggregations(&buffer, nil, "json.service", aggregate(l, nil, "json.service", nil), h)
+	assert(t, buffer.String(), want)
+}
+
+func TestSparkline(t *testing.T) {
+	assert(t, sparkline([]int{0, 1, 4, 8}), " ▁▄█")
+	assert(t, sparkline([]int{0, 0}), "  ")
+}
+
+func TestPrintTable(t *testing.T) {
+	table := query.TableResult{
+		Columns: []string{"_count", "applicationname", "subsystemname"},
+		Rows:    []query.Row{{"applicationname": "web", "_count": float64(1200)}, {"applicationname": "api", "_count": 0.5, "subsystemname": "auth"}},
+	}
+	want := "_count  applicationname  subsystemname\n" +
+		"1200    web              \n" +
+		"0.5     api              auth\n"
+
+	buffer := bytes.Buffer{}
+	printTable(&buffer, table)
+	assert(t, buffer.String(), want)
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/api"
+)
+
+const defaultAPICacheTTL = 5 * time.Minute // Listings of management APIs are reused for shell completion and pickers
+
+var (
+	errAPIArgs   = errors.New("you need to provide method and path, ie. api GET /v1/alerts")
+	errAPIMethod = errors.New("unknown method, use GET, POST, PUT, PATCH or DELETE")
+	errAPIBody   = errors.New("request body is not valid JSON")
+)
+
+var apiMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// Request body of --data option: inline JSON, @file or @- for standard input
+func apiData(data string, in io.Reader) ([]byte, error) {
+	switch {
+	case data == "":
+		return nil, nil
+	case data == "@-":
+		b, err := io.ReadAll(in)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read request body: %w", err)
+		}
+		return b, nil
+	case strings.HasPrefix(data, "@"):
+		b, err := os.ReadFile(data[1:])
+		if err != nil {
+			return nil, fmt.Errorf("cannot read request body: %w", err)
+		}
+		return b, nil
+	default:
+		return []byte(data), nil
+	}
+}
+
+// Call management API with method and path given as arguments, printing response JSON indented
+func runAPI(out io.Writer, in io.Reader, args *CmdArgs, token func() (string, error)) error {
+	fields := strings.Fields(args.Query)
+	if len(fields) != 2 {
+		return errAPIArgs
+	}
+	method, path := strings.ToUpper(fields[0]), fields[1]
+	if !slices.Contains(apiMethods, method) {
+		return errAPIMethod
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	if args.APIKey == "" {
+		return errMissingAPIKey
+	}
+	if args.LogsURL == "" {
+		return errMissingURL
+	}
+
+	body, err := apiData(args.Data, in)
+	if err != nil {
+		return err
+	}
+	if body != nil && !json.Valid(body) {
+		return errAPIBody
+	}
+
+	resp, err := api.Do(context.Background(), method, args.LogsURL, token, path, body)
+	if err != nil {
+		return err
+	}
+	if len(bytes.TrimSpace(resp)) == 0 {
+		return nil
+	}
+
+	var b bytes.Buffer
+	if err := json.Indent(&b, resp, "", "  "); err != nil {
+		b.Reset()
+		b.Write(resp) // Not JSON, printed as received
+	}
+	if !bytes.HasSuffix(b.Bytes(), []byte("\n")) {
+		b.WriteByte('\n')
+	}
+	_, err = out.Write(b.Bytes())
+
+	return err
+}
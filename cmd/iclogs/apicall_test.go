@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunAPI(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/alerts" && r.URL.RawQuery == "limit=1":
+			w.Write([]byte(`{"alerts":[{"id":"a1"}]}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/views":
+			w.Write(body)
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	file := filepath.Join(t.TempDir(), "view.json")
+	if err := os.WriteFile(file, []byte(`{"name":"from file"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	token := func() (string, error) { return "Good_Token", nil }
+
+	testCases := []struct {
+		name     string
+		query    string
+		data     string
+		in       string
+		expected string
+	}{
+		{name: "Get", query: "get /v1/alerts?limit=1", expected: "{\n  \"alerts\": [\n    {\n      \"id\": \"a1\"\n    }\n  ]\n}\n"},
+		{name: "PostInline", query: "POST v1/views", data: `{"name":"x"}`, expected: "{\n  \"name\": \"x\"\n}\n"},
+		{name: "PostFile", query: "POST /v1/views", data: "@" + file, expected: "{\n  \"name\": \"from file\"\n}\n"},
+		{name: "PostStdin", query: "POST /v1/views", data: "@-", in: `[1,2]`, expected: "[\n  1,\n  2\n]\n"},
+		{name: "DeleteEmpty", query: "DELETE /v1/views/1", expected: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			args := CmdArgs{APIKey: "key", LogsURL: server.URL, Query: tc.query, Data: tc.data}
+			out := bytes.Buffer{}
+			if err := runAPI(&out, strings.NewReader(tc.in), &args, token); err != nil {
+				t.Fatalf("Got unexpected error: %v", err)
+			}
+			assert(t, out.String(), tc.expected)
+		})
+	}
+
+	errorCases := []struct {
+		name  string
+		query string
+		data  string
+		err   error
+	}{
+		{name: "MissingPath", query: "GET", err: errAPIArgs},
+		{name: "UnknownMethod", query: "FETCH /v1/alerts", err: errAPIMethod},
+		{name: "BadBody", query: "POST /v1/views", data: "{broken", err: errAPIBody},
+	}
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			args := CmdArgs{APIKey: "key", LogsURL: server.URL, Query: tc.query, Data: tc.data}
+			assertError(t, runAPI(&bytes.Buffer{}, strings.NewReader(""), &args, token), tc.err)
+		})
+	}
+
+	args := CmdArgs{APIKey: "key", LogsURL: server.URL, Query: "GET /v1/missing"}
+	if err := runAPI(&bytes.Buffer{}, strings.NewReader(""), &args, token); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("Expected HTTP error, got: %v", err)
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/wooyey/iclogs/internal/platform/config"
+)
+
+// Config command actions
+const (
+	configExport = "export"
+	configImport = "import"
+)
+
+var errConfigAction = errors.New("you need to provide config action (export or import) and bundle file")
+
+// Export shareable configuration to bundle file or import bundle into configuration file, empty path means default location
+func runConfig(out io.Writer, path string, cfg config.Config, spec string) error {
+	action, bundle, _ := strings.Cut(spec, " ")
+	if bundle = strings.TrimSpace(bundle); bundle == "" {
+		return errConfigAction
+	}
+
+	switch action {
+	case configExport:
+		f, err := os.OpenFile(bundle, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, exportFileMode)
+		if err != nil {
+			return fmt.Errorf("cannot create bundle file: %w", err)
+		}
+		defer f.Close()
+
+		if err := config.WriteBundle(f, cfg); err != nil {
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("cannot write bundle file: %w", err)
+		}
+
+		fmt.Fprintf(out, "Exported %d profile(s), %d saved query(ies), %d alias(es) to %s\n", len(cfg.Profiles), len(cfg.Queries), len(cfg.Aliases), bundle)
+	case configImport:
+		f, err := os.Open(bundle)
+		if err != nil {
+			return fmt.Errorf("cannot open bundle file: %w", err)
+		}
+		defer f.Close()
+
+		b, err := config.ReadBundle(f)
+		if err != nil {
+			return err
+		}
+
+		cfg.Merge(b)
+		if err := config.Save(path, cfg); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(out, "Imported %d profile(s), %d saved query(ies), %d alias(es) from %s\n", len(b.Profiles), len(b.Queries), len(b.Aliases), bundle)
+	default:
+		return errConfigAction
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/wooyey/iclogs/internal/platform/config"
+)
+
+func TestRunConfig(t *testing.T) {
+	dir := t.TempDir()
+	bundle := filepath.Join(dir, "bundle.tar.gz")
+	path := filepath.Join(dir, "config.json")
+
+	shared := config.Config{
+		Audit:   config.Audit{Webhook: "https://hooks.example.com/secret-token"},
+		Queries: map[string]config.SavedQuery{"errors": {Query: "severity:error"}},
+	}
+	if err := runConfig(io.Discard, "", shared, "export "+bundle); err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+
+	local := config.Config{Audit: config.Audit{File: "audit.log"}}
+	if err := runConfig(io.Discard, path, local, "import "+bundle); err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+
+	got, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+	assertDeepEqual(t, got, config.Config{Audit: local.Audit, Queries: shared.Queries})
+
+	for _, spec := range []string{"", "export", "publish " + bundle} {
+		if err := runConfig(io.Discard, path, local, spec); err != errConfigAction {
+			t.Errorf("For '%s' want error: '%v', got: '%v'", spec, errConfigAction, err)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/wooyey/iclogs/internal/platform/logs"
+)
+
+const (
+	compactDate        = "2006-01-02 "
+	compactDayDate     = "01-02 "
+	compactLabelWidth  = 12 // Characters of one label value shown in compact mode
+	compactIDWidth     = 8  // Leading characters of record ID shown in compact mode
+	compactLabelJoiner = "/"
+)
+
+// Single character glyphs of record severities shown in compact mode
+var severityGlyphs = map[string]string{
+	"Verbose":  "·",
+	"Debug":    "○",
+	"Info":     "●",
+	"Warning":  "▲",
+	"Error":    "✖",
+	"Critical": "‼",
+}
+
+// Glyph of severity, unknown severities are shown by their first letter
+func severityGlyph(severity string) string {
+	if g, ok := severityGlyphs[severity]; ok {
+		return g
+	}
+
+	r, _ := utf8.DecodeRuneInString(severity)
+	if r == utf8.RuneError {
+		return "?"
+	}
+
+	return string(r)
+}
+
+// Layout of compact timestamps: time of day only, with month and day when records span more days
+func compactTimeFormat(l []logs.Log, layout timeLayout) string {
+	format := strings.TrimPrefix(layout.stamp(), compactDate)
+	if len(l) == 0 {
+		return format
+	}
+
+	first, last := layout.in(l[0].Time), layout.in(l[len(l)-1].Time)
+	if first.YearDay() != last.YearDay() || first.Year() != last.Year() {
+		return compactDayDate + format
+	}
+
+	return format
+}
+
+// Non-empty label values cut to compactLabelWidth, ie. `some-observe/some-agent`
+func compactLabels(labels []logs.KeyValue) string {
+	var values []string
+	for _, label := range labels {
+		if label.Value != "" {
+			values = append(values, fit(label.Value, compactLabelWidth))
+		}
+	}
+
+	return strings.Join(values, compactLabelJoiner)
+}
+
+// Prefix of record line in compact mode: time, severity glyph, and shortened ID and labels when shown
+func compactPrefix(l *logs.Log, args *CmdArgs, format string) string {
+	var b strings.Builder
+
+	b.WriteString(args.Times.in(l.Time).Format(format))
+	b.WriteString(" " + severityGlyph(l.Severity) + " ")
+
+	if args.ShowID {
+		id := l.ID
+		if len(id) > compactIDWidth {
+			id = id[:compactIDWidth]
+		}
+		b.WriteString("#" + id + " ")
+	}
+
+	if showsLabels(args) {
+		b.WriteString("<" + compactLabels(shownLabels(l, args)) + "> ")
+	}
+
+	return b.String()
+}
+
+// Cut text to width with ellipsis, newlines are shown as spaces
+func fit(text string, width int) string {
+	text = strings.ReplaceAll(text, "\n", " ")
+	if utf8.RuneCountInString(text) <= width {
+		return text
+	}
+
+	return string([]rune(text)[:width-1]) + "…"
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/logs"
+)
+
+func TestSeverityGlyph(t *testing.T) {
+	testCases := []struct {
+		severity string
+		want     string
+	}{
+		{severity: "Error", want: "✖"},
+		{severity: "Warning", want: "▲"},
+		{severity: "Unusual", want: "U"},
+		{severity: "", want: "?"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.severity, func(t *testing.T) {
+			assert(t, severityGlyph(tc.severity), tc.want)
+		})
+	}
+}
+
+func TestCompactTimeFormat(t *testing.T) {
+	at := func(day int) logs.Log {
+		return logs.Log{Time: time.Date(2025, 1, day, 10, 0, 0, 0, time.Local)}
+	}
+
+	assert(t, compactTimeFormat(nil, timeLayout{}), "15:04:05")
+	assert(t, compactTimeFormat([]logs.Log{at(11), at(11)}, timeLayout{}), "15:04:05")
+	assert(t, compactTimeFormat([]logs.Log{at(11), at(12)}, timeLayout{}), "01-02 15:04:05")
+}
+
+func TestCompactLabels(t *testing.T) {
+	l := logs.Log{Labels: []logs.KeyValue{
+		{Key: "applicationname", Value: "payments"},
+		{Key: "subsystemname", Value: "payment-gateway-worker"},
+		{Key: "computername", Value: ""},
+	}}
+
+	assert(t, compactLabels(l.Labels), "payments/payment-gat…")
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/wooyey/iclogs/internal/platform/config"
+)
+
+// Context command actions
+const (
+	contextList    = "list"
+	contextCurrent = "current"
+	contextUse     = "use"
+)
+
+var (
+	errContextAction = errors.New("you need to provide context action: list, current or use <profile>")
+	errNoContext     = errors.New("no profile selected, set one with context use <profile>")
+)
+
+// List profiles, print the one in use or persist default one, like kubectl contexts.
+// Selected profile from --profile option or ICLOGS_PROFILE is in use before persisted default.
+func runContext(out io.Writer, path string, cfg config.Config, selected, spec string) error {
+	action, name, _ := strings.Cut(strings.TrimSpace(spec), " ")
+	name = strings.TrimSpace(name)
+
+	current := selected
+	if current == "" {
+		current = cfg.DefaultProfile
+	}
+
+	switch {
+	case action == contextList && name == "":
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "current\tname\taccount\tlogs url\tauth url")
+		for _, n := range cfg.ProfileNames() {
+			p := cfg.Profiles[n]
+			marker, authURL := "", config.IAMURL(p.AuthURL)
+			if n == current {
+				marker = "*"
+			}
+			if authURL == "" {
+				authURL = defaultIAMURL
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", marker, n, p.Account, p.LogsURL, authURL)
+		}
+		return w.Flush()
+	case action == contextCurrent && name == "":
+		if current == "" {
+			return errNoContext
+		}
+		fmt.Fprintln(out, current)
+	case action == contextUse && name != "":
+		if err := cfg.UseProfile(name); err != nil {
+			return err
+		}
+		if err := config.Save(path, cfg); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Switched to profile %s\n", name)
+		if selected != "" && selected != name {
+			fmt.Fprintf(out, "Profile %s selected with --profile or ICLOGS_PROFILE is still used while set\n", selected)
+		}
+	default:
+		return errContextAction
+	}
+
+	return nil
+}
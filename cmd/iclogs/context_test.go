@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/wooyey/iclogs/internal/platform/config"
+)
+
+func TestRunContext(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	cfg := config.Config{
+		DefaultProfile: "prod",
+		Profiles: map[string]config.Profile{
+			"prod":    {LogsURL: "https://prod.logs.cloud.ibm.com", Account: "a1"},
+			"staging": {LogsURL: "https://staging.logs.cloud.ibm.com", AuthURL: "test", Account: "b2"},
+		},
+	}
+
+	out := &bytes.Buffer{}
+	if err := runContext(out, path, cfg, "", "list"); err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+	want := `current  name     account  logs url                            auth url
+*        prod     a1       https://prod.logs.cloud.ibm.com     https://iam.cloud.ibm.com
+         staging  b2       https://staging.logs.cloud.ibm.com  https://iam.test.cloud.ibm.com
+`
+	assert(t, out.String(), want)
+
+	out.Reset()
+	if err := runContext(out, path, cfg, "staging", "current"); err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+	assert(t, out.String(), "staging\n")
+
+	if err := runContext(io.Discard, path, cfg, "", "use staging"); err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+	saved, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+	assert(t, saved.DefaultProfile, "staging")
+
+	if err := runContext(io.Discard, path, cfg, "", "use dev"); err == nil {
+		t.Error("Should get an error for missing profile!")
+	}
+	assertError(t, runContext(io.Discard, path, config.Config{}, "", "current"), errNoContext)
+	for _, spec := range []string{"", "use", "list prod", "switch prod"} {
+		assertError(t, runContext(io.Discard, path, cfg, "", spec), errContextAction)
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/audit"
+	"github.com/wooyey/iclogs/internal/platform/logs"
+	"github.com/wooyey/iclogs/internal/platform/logs/tier"
+)
+
+// Archive scans over longer window need confirmation
+const expensiveWindow = 7 * 24 * time.Hour
+
+var errExpensiveQuery = errors.New("query scans long archive window, confirm it with --yes")
+
+// Estimated cost of the query, records are expected from hit rate of past runs of the same query
+type costEstimate struct {
+	tier    tier.Tier
+	window  time.Duration
+	limit   int
+	samples int     // Past runs the rate comes from, 0 when unknown
+	rate    float64 // Records per hour
+}
+
+// Records per hour of past successful runs of the same query, from audit entries
+func hitRate(entries []audit.Entry, query string) (float64, int) {
+	var (
+		records float64
+		hours   float64
+		samples int
+	)
+
+	for _, e := range entries {
+		w := e.EndDate.Sub(e.StartDate).Hours()
+		if e.Query != query || e.Error != "" || w <= 0 {
+			continue
+		}
+		records += float64(e.Count)
+		hours += w
+		samples++
+	}
+
+	if samples == 0 {
+		return 0, 0
+	}
+
+	return records / hours, samples
+}
+
+// Estimate cost of query over spec window, or over given windows when there are more of them, each queried with records limit
+func estimateCost(spec logs.QuerySpec, w windows, entries []audit.Entry, query string) costEstimate {
+	c := costEstimate{tier: spec.Tier, window: spec.EndDate.Sub(spec.StartDate), limit: spec.Limit}
+	if len(w) > 1 {
+		c.window, c.limit = w.duration(), spec.Limit*len(w)
+	}
+	c.rate, c.samples = hitRate(entries, query)
+
+	return c
+}
+
+func (c costEstimate) expensive() bool {
+	return c.tier == tier.Archive && c.window > expensiveWindow
+}
+
+// Expected records count, capped by records limit
+func (c costEstimate) records() int {
+	n := int(math.Round(c.rate * c.window.Hours()))
+	if c.limit > 0 && n > c.limit {
+		return c.limit
+	}
+
+	return n
+}
+
+func (c costEstimate) String() string {
+	s := fmt.Sprintf("Estimated scan: %s tier, %s window", c.tier, c.window.Round(time.Minute))
+	if c.samples == 0 {
+		return s + ", no past runs of the query to estimate records"
+	}
+
+	return s + fmt.Sprintf(", ~%d records expected from %d past run(s) (limit %d)", c.records(), c.samples, c.limit)
+}
+
+// Ask for confirmation of expensive query when interactive, otherwise it needs to be confirmed up front
+func confirmCost(in io.Reader, out io.Writer, c costEstimate, yes, interactive bool) error {
+	if !c.expensive() || yes {
+		return nil
+	}
+
+	fmt.Fprintln(out, c)
+	if !interactive {
+		return errExpensiveQuery
+	}
+
+	fmt.Fprint(out, "Continue? [y/N] ")
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+		return errExpensiveQuery
+	}
+
+	return nil
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/audit"
+	"github.com/wooyey/iclogs/internal/platform/logs"
+	"github.com/wooyey/iclogs/internal/platform/logs/tier"
+)
+
+func TestEstimateCost(t *testing.T) {
+	end := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+	history := []audit.Entry{
+		{Query: "app:web", StartDate: end.Add(-2 * time.Hour), EndDate: end, Count: 30},
+		{Query: "app:web", StartDate: end.Add(-time.Hour), EndDate: end, Count: 0},
+		{Query: "app:web", StartDate: end.Add(-time.Hour), EndDate: end, Error: "timeout"},
+		{Query: "app:api", StartDate: end.Add(-time.Hour), EndDate: end, Count: 5000},
+	}
+	spec := logs.QuerySpec{Tier: tier.Archive, Limit: tier.LimitArchive, StartDate: end.Add(-30 * 24 * time.Hour), EndDate: end}
+
+	c := estimateCost(spec, nil, history, "app:web")
+	assert(t, c.expensive(), true)
+	assert(t, c.records(), 7200)
+	assert(t, c.String(), "Estimated scan: archive tier, 720h0m0s window, ~7200 records expected from 2 past run(s) (limit 50000)")
+
+	c = estimateCost(spec, nil, history, "app:api")
+	assert(t, c.records(), tier.LimitArchive)
+
+	c = estimateCost(spec, nil, nil, "app:web")
+	assert(t, c.String(), "Estimated scan: archive tier, 720h0m0s window, no past runs of the query to estimate records")
+
+	spec.StartDate = end.Add(-24 * time.Hour)
+	assert(t, estimateCost(spec, nil, nil, "app:web").expensive(), false)
+
+	// Daily windows count only searched hours, not the span of all of them
+	spec.StartDate = end.Add(-30 * 24 * time.Hour)
+	daily, err := dailyWindows("09:00..10:00", 30, end)
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	c = estimateCost(spec, daily, history, "app:web")
+	assert(t, c.expensive(), false)
+	assert(t, c.window, 30*time.Hour)
+	assert(t, c.records(), 300)
+	assert(t, c.limit, 30*tier.LimitArchive)
+}
+
+func TestConfirmCost(t *testing.T) {
+	expensive := costEstimate{tier: tier.Archive, window: 30 * 24 * time.Hour, limit: tier.LimitArchive}
+	cheap := costEstimate{tier: tier.Archive, window: time.Hour}
+
+	testCases := []struct {
+		name        string
+		cost        costEstimate
+		yes         bool
+		interactive bool
+		input       string
+		err         error
+	}{
+		{name: "Cheap", cost: cheap, err: nil},
+		{name: "Yes", cost: expensive, yes: true, err: nil},
+		{name: "NotInteractive", cost: expensive, err: errExpensiveQuery},
+		{name: "Confirmed", cost: expensive, interactive: true, input: "y\n", err: nil},
+		{name: "Declined", cost: expensive, interactive: true, input: "\n", err: errExpensiveQuery},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			out := bytes.Buffer{}
+			err := confirmCost(strings.NewReader(tt.input), &out, tt.cost, tt.yes, tt.interactive)
+			assertError(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strings"
+
+	"github.com/wooyey/iclogs/internal/platform/crash"
+)
+
+const redactedArg = "[REDACTED]"
+
+// Flags holding credentials, their values never land in crash reports
+var secretFlags = map[string]bool{
+	"key":                  true,
+	"k":                    true,
+	"slack-signing-secret": true,
+	"slack-token":          true,
+	"notify-pagerduty":     true,
+	"notify-opsgenie":      true,
+}
+
+// Replace values of credential flags, given both as separate argument and after `=`
+func redactArgs(a []string) []string {
+	r := make([]string, len(a))
+	for i := 0; i < len(a); i++ {
+		r[i] = a[i]
+
+		name := strings.TrimLeft(a[i], "-")
+		if name == a[i] || a[i] == querySeparator {
+			continue
+		}
+
+		if n, _, ok := strings.Cut(name, "="); ok {
+			if secretFlags[n] {
+				r[i] = a[i][:len(a[i])-len(name)] + n + "=" + redactedArg
+			}
+			continue
+		}
+
+		if secretFlags[name] && i+1 < len(a) {
+			i++
+			r[i] = redactedArg
+		}
+	}
+
+	return r
+}
+
+// Write crash report of panic and exit, to be deferred at the start of main
+func recoverCrash() {
+	p := recover()
+	if p == nil {
+		return
+	}
+
+	r := crash.NewReport(version, redactArgs(os.Args[1:]), p, debug.Stack())
+	r.Log = recentLogs.tail()
+	fmt.Fprintf(os.Stderr, "iclogs crashed: %s\n", r.Panic)
+
+	dir, err := crash.DefaultDir()
+	if err == nil {
+		var path string
+		if path, err = crash.Write(dir, r); err == nil {
+			fmt.Fprintf(os.Stderr, "Diagnostic report written to %s, run 'iclogs bugreport' to prepare issue.\n", path)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Cannot write diagnostic report: %v\n%s", err, r.Stack)
+	}
+
+	os.Exit(2)
+}
+
+// Print issue-ready Markdown report with environment and the latest crash, if any
+func printBugReport(w io.Writer, r crash.Report, crashed bool) {
+	fmt.Fprintln(w, "### Environment")
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "- Version: %s\n", version)
+	fmt.Fprintf(w, "- Go: %s\n", runtime.Version())
+	fmt.Fprintf(w, "- Platform: %s/%s\n", runtime.GOOS, runtime.GOARCH)
+
+	if !crashed {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "No crash report found.")
+		return
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "### Latest crash")
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "- Time: %s\n", r.Time.Format(timeStampFormat))
+	fmt.Fprintf(w, "- Version: %s (%s, %s)\n", r.Version, r.GoVersion, r.Platform)
+	fmt.Fprintf(w, "- Arguments: `%s`\n", strings.Join(r.Args, " "))
+	fmt.Fprintf(w, "- Panic: %s\n", r.Panic)
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "```\n%s\n```\n", strings.TrimSpace(r.Stack))
+
+	if len(r.Log) != 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "#### Recent log")
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "```\n%s\n```\n", strings.Join(r.Log, "\n"))
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/crash"
+)
+
+func TestRedactArgs(t *testing.T) {
+
+	testCases := []struct {
+		name  string
+		input []string
+		want  []string
+	}{
+		{name: "Separate", input: []string{"-k", "s3cr3t", "-r", "1h"}, want: []string{"-k", redactedArg, "-r", "1h"}},
+		{name: "Equals", input: []string{"--key=s3cr3t", "--slack-token=xoxb"}, want: []string{"--key=" + redactedArg, "--slack-token=" + redactedArg}},
+		{name: "Query", input: []string{"get", "key", "--", "k"}, want: []string{"get", "key", "--", "k"}},
+		{name: "Last", input: []string{"--notify-opsgenie"}, want: []string{"--notify-opsgenie"}},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assertDeepEqual(t, redactArgs(tt.input), tt.want)
+		})
+	}
+}
+
+func TestPrintBugReport(t *testing.T) {
+	env := "### Environment\n\n- Version: \n- Go: " + runtime.Version() + "\n- Platform: " + runtime.GOOS + "/" + runtime.GOARCH + "\n"
+
+	out := bytes.Buffer{}
+	printBugReport(&out, crash.Report{}, false)
+	assert(t, out.String(), env+"\nNo crash report found.\n")
+
+	r := crash.Report{
+		Time:      time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+		Version:   "v1.0.0",
+		GoVersion: "go1.24.0",
+		Platform:  "linux/amd64",
+		Args:      []string{"-k", redactedArg, "app:web"},
+		Panic:     "boom",
+		Stack:     "goroutine 1 [running]:\n",
+	}
+	out.Reset()
+	printBugReport(&out, r, true)
+	assert(t, out.String(), env+"\n### Latest crash\n\n- Time: 2025-01-01 10:00:00\n- Version: v1.0.0 (go1.24.0, linux/amd64)\n"+
+		"- Arguments: `-k [REDACTED] app:web`\n- Panic: boom\n\n```\ngoroutine 1 [running]:\n```\n")
+
+	r.Log = []string{"level=DEBUG msg=\"Token obtained\"", "level=DEBUG msg=\"Running query\""}
+	out.Reset()
+	printBugReport(&out, r, true)
+	assert(t, strings.HasSuffix(out.String(), "```\n\n#### Recent log\n\n```\nlevel=DEBUG msg=\"Token obtained\"\nlevel=DEBUG msg=\"Running query\"\n```\n"), true)
+}
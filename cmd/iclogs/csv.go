@@ -0,0 +1,247 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/logs"
+)
+
+// Export command file formats
+const (
+	formatNDJSON = "ndjson"
+	formatCSV    = "csv"
+)
+
+const (
+	csvExtension        = ".csv"
+	schemaName          = "schema.json"
+	defaultSchemaSample = 1000
+	labelColumnPrefix   = "labels."
+)
+
+// Types of CSV columns
+const (
+	typeString  = "string"
+	typeNumber  = "number"
+	typeBoolean = "boolean"
+)
+
+// Columns of every CSV file, before inferred label and user data columns
+var recordColumns = []column{{Name: "id", Type: typeString}, {Name: "time", Type: typeString}, {Name: "severity", Type: typeString}}
+
+// Column of CSV export with type of its values
+type column struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// Columns of CSV export, the same for all files of one export
+type csvSchema []column
+
+// Read schema from JSON file with array of columns
+func loadSchema(path string) (csvSchema, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read schema: %w", err)
+	}
+
+	var s csvSchema
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, fmt.Errorf("cannot parse schema: %w", err)
+	}
+	if len(s) == 0 {
+		return nil, fmt.Errorf("cannot use schema %s: no columns", path)
+	}
+
+	return s, nil
+}
+
+func (s csvSchema) save(path string) error {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot encode schema: %w", err)
+	}
+	if err := os.WriteFile(path, append(b, '\n'), exportFileMode); err != nil {
+		return fmt.Errorf("cannot write schema: %w", err)
+	}
+
+	return nil
+}
+
+// Flattened label and user data values of record by column name, arrays are kept as values
+func recordValues(l *logs.Log) map[string]any {
+	v := map[string]any{}
+	for _, label := range l.Labels {
+		v[labelColumnPrefix+label.Key] = label.Value
+	}
+
+	dec := json.NewDecoder(strings.NewReader(l.UserData))
+	dec.UseNumber()
+	var ud map[string]any
+	if err := dec.Decode(&ud); err == nil {
+		flattenValue(v, "", ud, arraysKeep)
+	}
+
+	return v
+}
+
+// Infer columns from the union of fields of the first sample records. Column with values of one JSON type gets it,
+// mixed columns are strings.
+func inferSchema(l []logs.Log, sample int) csvSchema {
+	if sample > 0 && len(l) > sample {
+		l = l[:sample]
+	}
+
+	types := map[string]string{}
+	for i := range l {
+		for name, v := range recordValues(&l[i]) {
+			t := valueType(v)
+			if t == "" {
+				if _, ok := types[name]; !ok {
+					types[name] = ""
+				}
+				continue
+			}
+			if known, ok := types[name]; ok && known != "" && known != t {
+				t = typeString
+			}
+			types[name] = t
+		}
+	}
+
+	for _, c := range recordColumns {
+		delete(types, c.Name) // Record fields take precedence over user data fields of the same name
+	}
+
+	names := make([]string, 0, len(types))
+	for n := range types {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	s := append(csvSchema{}, recordColumns...)
+	for _, n := range names {
+		t := types[n]
+		if t == "" {
+			t = typeString
+		}
+		s = append(s, column{Name: n, Type: t})
+	}
+
+	return s
+}
+
+// JSON type of value, empty for null
+func valueType(v any) string {
+	switch v.(type) {
+	case nil:
+		return ""
+	case json.Number:
+		return typeNumber
+	case bool:
+		return typeBoolean
+	default:
+		return typeString
+	}
+}
+
+// Text of CSV cell, objects and arrays are written as JSON
+func cellValue(v any) string {
+	s, _ := scalarString(v)
+	return s
+}
+
+// CSV export state: columns are given, loaded from previous run of the export, or inferred from the first records
+type csvExport struct {
+	schema csvSchema
+	sample int
+}
+
+// CSV export of given format, nil for JSON lines. Schema file sets columns instead of inferring them.
+func newCSVExport(format, schema string, sample int) (*csvExport, error) {
+	switch format {
+	case formatNDJSON:
+		return nil, nil
+	case formatCSV:
+	default:
+		return nil, fmt.Errorf("unknown export format '%s', use %s or %s", format, formatNDJSON, formatCSV)
+	}
+
+	c := &csvExport{sample: sample}
+	if schema != "" {
+		var err error
+		if c.schema, err = loadSchema(schema); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// Load schema of previous run from export directory, so resumed export keeps its columns
+func (c *csvExport) resume(dir string) error {
+	if c.schema != nil {
+		return nil
+	}
+
+	s, err := loadSchema(filepath.Join(dir, schemaName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	c.schema = s
+
+	return err
+}
+
+// Encoder writing CSV header and records to one export file, schema is inferred and saved on first use
+func (c *csvExport) encoder(w io.Writer, dir string) func([]logs.Log) error {
+	cw := csv.NewWriter(w)
+	header := false
+
+	return func(l []logs.Log) error {
+		if c.schema == nil {
+			if len(l) == 0 {
+				return nil // Nothing to infer columns from yet
+			}
+			c.schema = inferSchema(l, c.sample)
+			if err := c.schema.save(filepath.Join(dir, schemaName)); err != nil {
+				return err
+			}
+		}
+
+		if !header {
+			names := make([]string, len(c.schema))
+			for i, col := range c.schema {
+				names[i] = col.Name
+			}
+			if err := cw.Write(names); err != nil {
+				return fmt.Errorf("cannot write CSV header: %w", err)
+			}
+			header = true
+		}
+
+		row := make([]string, len(c.schema))
+		for i := range l {
+			values := recordValues(&l[i])
+			values["id"], values["time"], values["severity"] = l[i].ID, l[i].Time.Format(time.RFC3339Nano), l[i].Severity
+			for j, col := range c.schema {
+				row[j] = cellValue(values[col.Name])
+			}
+			if err := cw.Write(row); err != nil {
+				return fmt.Errorf("cannot write record: %w", err)
+			}
+		}
+
+		cw.Flush()
+		return cw.Error()
+	}
+}
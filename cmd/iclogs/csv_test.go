@@ -0,0 +1,106 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/logs"
+)
+
+func TestInferSchema(t *testing.T) {
+	l := []logs.Log{
+		{Labels: []logs.KeyValue{{Key: "applicationname", Value: "web"}}, UserData: `{"status":200,"ok":true,"user":{"id":"u1"},"tags":["a"],"id":"shadowed"}`},
+		{UserData: `{"status":"timeout","ok":false,"retry":null}`},
+		{UserData: `{"late":1}`},
+	}
+
+	want := csvSchema{
+		{Name: "id", Type: typeString},
+		{Name: "time", Type: typeString},
+		{Name: "severity", Type: typeString},
+		{Name: "labels.applicationname", Type: typeString},
+		{Name: "ok", Type: typeBoolean},
+		{Name: "retry", Type: typeString},
+		{Name: "status", Type: typeString},
+		{Name: "tags", Type: typeString},
+		{Name: "user.id", Type: typeString},
+	}
+	assertDeepEqual(t, inferSchema(l, 2), want)
+	assert(t, len(inferSchema(l, 0)), len(want)+1)
+}
+
+func TestNewCSVExport(t *testing.T) {
+	c, err := newCSVExport(formatNDJSON, "", 10)
+	if err != nil || c != nil {
+		t.Errorf("Got: %v, %v, want JSON lines export", c, err)
+	}
+
+	if _, err := newCSVExport("parquet", "", 10); err == nil {
+		t.Error("Expected error of unknown format")
+	}
+
+	path := filepath.Join(t.TempDir(), "schema.json")
+	if err := os.WriteFile(path, []byte(`[{"name":"id","type":"string"},{"name":"status","type":"number"}]`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c, err = newCSVExport(formatCSV, path, 10)
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	assertDeepEqual(t, c.schema, csvSchema{{Name: "id", Type: typeString}, {Name: "status", Type: typeNumber}})
+
+	if _, err := newCSVExport(formatCSV, filepath.Join(t.TempDir(), "missing.json"), 10); err == nil {
+		t.Error("Expected error of missing schema file")
+	}
+}
+
+func TestExportCSV(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	dir := t.TempDir()
+	records := map[time.Time][]logs.Log{
+		start: {},
+		start.Add(time.Hour): {
+			{ID: "1", Time: start.Add(time.Hour), Severity: "Info", UserData: `{"status":200,"msg":"a, \"b\""}`},
+		},
+		start.Add(2 * time.Hour): {
+			{ID: "2", Time: start.Add(2 * time.Hour), Severity: "Error", UserData: `{"status":500,"extra":"dropped"}`},
+		},
+	}
+
+	e := &exporter{
+		search: func(client, query string, spec logs.QuerySpec) (logs.Result, error) {
+			return logs.Result{Logs: records[spec.StartDate]}, nil
+		},
+		dir:   dir,
+		chunk: time.Hour,
+		csv:   &csvExport{sample: 10},
+	}
+
+	spec := logs.QuerySpec{StartDate: start, EndDate: start.Add(3 * time.Hour)}
+	if err := e.run(io.Discard, "some query", spec); err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+
+	read := func(name string) string {
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("Got an error: '%v'", err)
+		}
+		return string(b)
+	}
+
+	// Columns come from the first records and stay the same in later files
+	assert(t, read("20250101T100000Z_20250101T110000Z.csv"), "")
+	assert(t, read("20250101T110000Z_20250101T120000Z.csv"), "id,time,severity,msg,status\n1,2025-01-01T11:00:00Z,Info,\"a, \"\"b\"\"\",200\n")
+	assert(t, read("20250101T120000Z_20250101T130000Z.csv"), "id,time,severity,msg,status\n2,2025-01-01T12:00:00Z,Error,,500\n")
+
+	// Resumed export keeps saved columns
+	resumed := &csvExport{sample: 10}
+	if err := resumed.resume(dir); err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+	assertDeepEqual(t, resumed.schema, e.csv.schema)
+}
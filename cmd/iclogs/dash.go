@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/config"
+	"github.com/wooyey/iclogs/internal/platform/logs"
+)
+
+const (
+	defaultRefresh   = 10 * time.Second
+	defaultDashGroup = "label.applicationname"
+	dashTopGroups    = 5
+	dashLatestErrors = 5
+	clearScreen      = "\033[H\033[2J"
+)
+
+// Severities of records shown as latest errors
+var errorSeverities = []string{"Error", "Critical"}
+
+// Print one dashboard frame: rate per severity, top groups and latest errors of found records
+func printDash(w io.Writer, l []logs.Log, spec logs.QuerySpec, args *CmdArgs, a config.Aliases) {
+	groupBy := args.GroupBy
+	if groupBy == "" {
+		groupBy = defaultDashGroup
+	}
+
+	minutes := spec.EndDate.Sub(spec.StartDate).Minutes()
+	h := &histogram{start: spec.StartDate, end: spec.EndDate, buckets: histogramBuckets}
+
+	fmt.Fprintf(w, "Query: %s\n", args.Query)
+	fmt.Fprintf(w, "Window: %s - %s, %d records\n\n",
+		args.Times.format(spec.StartDate), args.Times.format(spec.EndDate), len(l))
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "severity\thistogram\tcount\tper minute")
+	for _, g := range aggregate(l, nil, "severity", a) {
+		rate := 0.0
+		if minutes > 0 {
+			rate = float64(g.count) / minutes
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\n", g.key, sparkline(h.counts(g.times)), g.count, formatNumber(rate))
+	}
+	tw.Flush()
+
+	groups := aggregate(l, nil, groupBy, a)
+	slices.SortStableFunc(groups, func(x, y aggGroup) int {
+		return y.count - x.count
+	})
+
+	fmt.Fprintln(w)
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "top %s\tcount\n", groupBy)
+	for _, g := range groups[:min(len(groups), dashTopGroups)] {
+		fmt.Fprintf(tw, "%s\t%d\n", g.key, g.count)
+	}
+	tw.Flush()
+
+	var errs []logs.Log
+	for _, r := range l {
+		if slices.Contains(errorSeverities, r.Severity) {
+			errs = append(errs, r)
+		}
+	}
+	slices.SortStableFunc(errs, func(x, y logs.Log) int {
+		return x.Time.Compare(y.Time)
+	})
+	errs = errs[max(len(errs)-dashLatestErrors, 0):]
+
+	fmt.Fprintln(w, "\nLatest errors:")
+	latest := *args
+	latest.Timestamp = true
+	latest.Severity = true
+	printLogs(w, &errs, &latest)
+}
+
+// Refresh dashboard in terminal until interrupted, query errors are shown instead of stopping
+func runDash(search searchFunc, args *CmdArgs, a config.Aliases, spec logs.QuerySpec) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	ticker := time.NewTicker(args.Refresh)
+	defer ticker.Stop()
+
+	for {
+		spec.EndDate = time.Now()
+		spec.StartDate = spec.EndDate.Add(-args.TimeRange)
+
+		fmt.Print(clearScreen)
+
+		l, err := search("", args.Query, spec)
+		if err != nil {
+			slog.Warn("Cannot refresh dashboard", "error", err)
+		} else {
+			printDash(os.Stdout, l.Logs, spec, args, a)
+		}
+		fmt.Printf("\nRefreshing every %s, press Ctrl+C to quit.\n", args.Refresh)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/logs"
+)
+
+func TestPrintDash(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.Local)
+	spec := logs.QuerySpec{StartDate: start, EndDate: start.Add(10 * time.Minute)}
+
+	l := []logs.Log{
+		{Time: start.Add(time.Minute), Severity: "Error", UserData: `{"message":"first error","app":"api"}`},
+		{Time: start.Add(2 * time.Minute), Severity: "Info", UserData: `{"message":"info","app":"api"}`},
+		{Time: start.Add(9 * time.Minute), Severity: "Critical", UserData: `{"message":"second error","app":"web"}`},
+		{Time: start.Add(9 * time.Minute), Severity: "Info", UserData: `{"message":"info","app":"api"}`},
+	}
+	args := CmdArgs{Query: "some query", KeyNames: "message", GroupBy: "json.app"}
+
+	want := "Query: some query\n" +
+		"Window: 2025-01-01 10:00:00 - 2025-01-01 10:10:00, 4 records\n\n" +
+		"severity  histogram             count  per minute\n" +
+		"Critical                    █   1      0.1\n" +
+		"Error       █                   1      0.1\n" +
+		"Info          █             █   2      0.2\n" +
+		"\n" +
+		"top json.app  count\n" +
+		"api           3\n" +
+		"web           1\n" +
+		"\nLatest errors:\n" +
+		"2025-01-01 10:01:00: [Error] first error\n" +
+		"2025-01-01 10:09:00: [Critical] second error\n"
+
+	buffer := bytes.Buffer{}
+	printDash(&buffer, l, spec, &args, nil)
+	assert(t, buffer.String(), want)
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Docs command formats
+const (
+	docsMan      = "man"
+	docsMarkdown = "markdown"
+)
+
+const (
+	docsName     = "iclogs"
+	docsSummary  = "search and tail logs of IBM Cloud Logs instance"
+	docsSynopsis = "[command] [options] <lucene query> [-- <lucene query> ...]"
+)
+
+var errDocsFormat = errors.New("you need to provide docs format: man or markdown")
+
+// Write reference of commands and options as man page or Markdown, generated from the same metadata as usage
+func runDocs(out io.Writer, format string) error {
+	switch strings.TrimSpace(format) {
+	case docsMan:
+		writeMan(out, getVersion())
+	case docsMarkdown:
+		writeMarkdown(out)
+	default:
+		return errDocsFormat
+	}
+
+	return nil
+}
+
+func writeMan(w io.Writer, version string) {
+	fmt.Fprintf(w, ".TH %s 1 \"\" \"%s\" \"User Commands\"\n", strings.ToUpper(docsName), roff(version))
+	fmt.Fprintf(w, ".SH NAME\n%s \\- %s\n", docsName, roff(docsSummary))
+	fmt.Fprintf(w, ".SH SYNOPSIS\n.B %s\n%s\n", docsName, roff(docsSynopsis))
+	fmt.Fprintf(w, ".SH DESCRIPTION\nRunning without command searches logs with Lucene query.\n")
+
+	fmt.Fprintln(w, ".SH COMMANDS")
+	for _, n := range commandNames() {
+		fmt.Fprintf(w, ".TP\n\\fB%s\\fR", roff(n))
+		if a := commands[n].args; a != "" {
+			fmt.Fprintf(w, " \\fI%s\\fR", roff(a))
+		}
+		fmt.Fprintf(w, "\n%s\n", roff(commands[n].usage))
+	}
+
+	fmt.Fprintln(w, ".SH OPTIONS")
+	for _, o := range options() {
+		fmt.Fprintf(w, ".TP\n\\fB%s\\fR", roff(strings.Join(o.names, ", ")))
+		if o.name != "" {
+			fmt.Fprintf(w, " \\fI%s\\fR", roff(o.name))
+		}
+		fmt.Fprintf(w, "\n%s", roff(o.usage))
+		if o.defValue != "" {
+			fmt.Fprintf(w, " (default %s)", roff(o.defValue))
+		}
+		fmt.Fprint(w, "\n")
+	}
+}
+
+func writeMarkdown(w io.Writer) {
+	fmt.Fprintf(w, "# %s\n\n%s.\n\n", docsName, strings.ToUpper(docsSummary[:1])+docsSummary[1:])
+	fmt.Fprintf(w, "```\n%s %s\n```\n\n", docsName, docsSynopsis)
+
+	fmt.Fprintln(w, "## Commands")
+	for _, n := range commandNames() {
+		fmt.Fprintf(w, "\n### `%s`\n\n%s\n", strings.TrimSpace(n+" "+commands[n].args), commands[n].usage)
+	}
+
+	fmt.Fprintln(w, "\n## Options")
+	for _, o := range options() {
+		fmt.Fprintf(w, "\n### `%s`\n\n%s", strings.TrimSpace(strings.Join(o.names, ", ")+" "+o.name), o.usage)
+		if o.defValue != "" {
+			fmt.Fprintf(w, " Default: `%s`.", o.defValue)
+		}
+		fmt.Fprint(w, "\n")
+	}
+}
+
+// Escape text for roff, so backslashes, dashes and leading dots are printed as they are
+func roff(s string) string {
+	s = strings.NewReplacer(`\`, `\e`, "-", `\-`).Replace(s)
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = `\&` + s
+	}
+
+	return s
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestRunDocs(t *testing.T) {
+	os.Args = []string{"./iclogs"}
+	initParser(&CmdArgs{})
+
+	testCases := []struct {
+		format string
+		want   []string
+	}{
+		{format: docsMan, want: []string{
+			".SH NAME\niclogs \\- search and tail logs of IBM Cloud Logs instance\n",
+			".TP\n\\fBcontext\\fR \\fIlist|current|use <profile>\\fR\nList profiles with their account and endpoints",
+			".TP\n\\fB\\-r, \\-\\-range\\fR \\fIduration\\fR\nRelative time for log search, before now or end time, or after start time when only it is given. (default 1h0m0s)\n",
+		}},
+		{format: docsMarkdown, want: []string{
+			"# iclogs\n\nSearch and tail logs of IBM Cloud Logs instance.\n",
+			"\n### `docs man|markdown`\n\nPrint reference of commands and options",
+			"\n### `-r, --range duration`\n\nRelative time for log search, before now or end time, or after start time when only it is given. Default: `1h0m0s`.\n",
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.format, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			if err := runDocs(out, tc.format); err != nil {
+				t.Fatalf("Got an error: '%v'", err)
+			}
+			for _, w := range tc.want {
+				if !strings.Contains(out.String(), w) {
+					t.Errorf("Expected docs containing:\n%s\ngot:\n%s", w, out.String())
+				}
+			}
+		})
+	}
+
+	assertError(t, runDocs(&bytes.Buffer{}, "html"), errDocsFormat)
+}
+
+func TestRoff(t *testing.T) {
+	assert(t, roff(`--file C:\logs`), `\-\-file C:\elogs`)
+	assert(t, roff(".hidden"), `\&.hidden`)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/endpoint"
+)
+
+var errUnreachable = errors.New("some endpoints are unreachable")
+
+// Connectivity check of endpoint URL returning connection time
+type checkFunc func(ctx context.Context, u string) (time.Duration, error)
+
+// Check connectivity to IAM, logs and ingestion endpoints, printing result of each
+func runDoctor(out io.Writer, args *CmdArgs, check checkFunc) error {
+	endpoints := [][2]string{{"iam", args.AuthURL}, {"logs", args.LogsURL}}
+	if u, err := ingestEndpoint(args); err == nil {
+		endpoints = append(endpoints, [2]string{"ingress", u})
+	}
+
+	failed := false
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "endpoint\turl\tprivate\tresult")
+	for _, e := range endpoints {
+		private := "no"
+		if endpoint.IsPrivate(e[1]) {
+			private = "yes"
+		}
+
+		result := "missing, set it with option or profile"
+		if e[1] != "" {
+			d, err := check(context.Background(), e[1])
+			result = fmt.Sprintf("ok in %s", d.Round(time.Millisecond))
+			if err != nil {
+				result = err.Error()
+			}
+			failed = failed || err != nil
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", e[0], e[1], private, result)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if failed {
+		return errUnreachable
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/endpoint"
+)
+
+func TestRunDoctor(t *testing.T) {
+	check := func(ctx context.Context, u string) (time.Duration, error) {
+		if endpoint.IsPrivate(u) {
+			return 0, errors.New("no route")
+		}
+		return 12 * time.Millisecond, nil
+	}
+
+	out := &bytes.Buffer{}
+	args := CmdArgs{AuthURL: defaultIAMURL, LogsURL: "https://abc.api.eu-de.logs.cloud.ibm.com"}
+	if err := runDoctor(out, &args, check); err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+	want := `endpoint  url                                           private  result
+iam       https://iam.cloud.ibm.com                     no       ok in 12ms
+logs      https://abc.api.eu-de.logs.cloud.ibm.com      no       ok in 12ms
+ingress   https://abc.ingress.eu-de.logs.cloud.ibm.com  no       ok in 12ms
+`
+	assert(t, out.String(), want)
+
+	out.Reset()
+	args = CmdArgs{AuthURL: "https://private.iam.cloud.ibm.com"}
+	assertError(t, runDoctor(out, &args, check), errUnreachable)
+	want = `endpoint  url                                private  result
+iam       https://private.iam.cloud.ibm.com  yes      no route
+logs                                         no       missing, set it with option or profile
+`
+	assert(t, out.String(), want)
+}
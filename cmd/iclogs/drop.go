@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/wooyey/iclogs/internal/platform/config"
+	"github.com/wooyey/iclogs/internal/platform/logs"
+)
+
+// User data paths of comma separated --drop-fields names, aliases are expanded and `json.` prefix is optional
+func dropPaths(names string, a config.Aliases) []string {
+	var paths []string
+	for _, n := range strings.Split(names, ",") {
+		if n = strings.TrimSpace(n); n != "" {
+			paths = append(paths, strings.TrimPrefix(a.Expand(n), jsonFieldPrefix))
+		}
+	}
+
+	return paths
+}
+
+// Remove paths from user data of log records, records without any of them are kept as they are
+func dropFields(l []logs.Log, paths []string) error {
+	for i := range l {
+		ud, err := dropData(l[i].UserData, paths)
+		if err != nil {
+			return fmt.Errorf("cannot drop fields of record '%s': %w", l[i].ID, err)
+		}
+		l[i].UserData = ud
+	}
+
+	return nil
+}
+
+// Remove paths from user data JSON object. User data which is not an object is kept.
+func dropData(userData string, paths []string) (string, error) {
+	if !strings.HasPrefix(strings.TrimSpace(userData), "{") {
+		return userData, nil
+	}
+
+	dec := json.NewDecoder(strings.NewReader(userData))
+	dec.UseNumber() // Keep numbers as they were
+	var ud map[string]any
+	if err := dec.Decode(&ud); err != nil {
+		return userData, nil
+	}
+
+	dropped := false
+	for _, p := range paths {
+		dropped = dropPath(ud, p) || dropped
+	}
+	if !dropped {
+		return userData, nil
+	}
+
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(ud); err != nil {
+		return userData, err
+	}
+
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
+
+// Delete dotted path from object, keys containing dots themselves (ie. Kubernetes annotations) are matched too
+func dropPath(m map[string]any, path string) bool {
+	if _, ok := m[path]; ok {
+		delete(m, path)
+		return true
+	}
+
+	for i := range len(path) {
+		if path[i] != '.' {
+			continue
+		}
+		if sub, ok := m[path[:i]].(map[string]any); ok && dropPath(sub, path[i+1:]) {
+			return true
+		}
+	}
+
+	return false
+}
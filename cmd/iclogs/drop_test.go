@@ -0,0 +1,57 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/wooyey/iclogs/internal/platform/config"
+	"github.com/wooyey/iclogs/internal/platform/logs"
+)
+
+func TestDropPaths(t *testing.T) {
+	a := config.Aliases{"ns": "json.kubernetes.namespace_name"}
+
+	assertDeepEqual(t, dropPaths("kubernetes.annotations, json.tag,,ns", a), []string{"kubernetes.annotations", "tag", "kubernetes.namespace_name"})
+	assertDeepEqual(t, dropPaths("", a), []string(nil))
+}
+
+func TestDropData(t *testing.T) {
+	testCases := []struct {
+		name     string
+		userData string
+		paths    []string
+		want     string
+	}{
+		{name: "TopLevel", userData: `{"tag":"kube","message":"a<b","file":"/var/log"}`, paths: []string{"tag", "file"}, want: `{"message":"a<b"}`},
+		{name: "Nested", userData: `{"kubernetes":{"annotations":{"a":1},"host":"h"},"n":12345678901234567890}`, paths: []string{"kubernetes.annotations"}, want: `{"kubernetes":{"host":"h"},"n":12345678901234567890}`},
+		{name: "DottedKey", userData: `{"annotations":{"kubernetes.io/config.seen":"x","y":1}}`, paths: []string{"annotations.kubernetes.io/config.seen"}, want: `{"annotations":{"y":1}}`},
+		{name: "Missing", userData: `{ "message": "kept as is" }`, paths: []string{"tag", "message.text"}, want: `{ "message": "kept as is" }`},
+		{name: "NotObject", userData: `plain text`, paths: []string{"tag"}, want: `plain text`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := dropData(tc.userData, tc.paths)
+			if err != nil {
+				t.Fatalf("Got unexpected error: %v", err)
+			}
+			assert(t, got, tc.want)
+		})
+	}
+}
+
+func TestDropFields(t *testing.T) {
+	l := []logs.Log{{UserData: `{"tag":"kube","message":"hello"}`}}
+	msg, _ := l[0].Message([]string{"tag"})
+	assert(t, msg, "kube")
+
+	if err := dropFields(l, []string{"tag"}); err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	assert(t, l[0].UserData, `{"message":"hello"}`)
+
+	// Parsed user data is not reused after drop
+	_, err := l[0].Message([]string{"tag"})
+	if err == nil {
+		t.Error("Expected error of dropped message field")
+	}
+}
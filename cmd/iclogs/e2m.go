@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/wooyey/iclogs/internal/platform/e2m"
+)
+
+// Events-to-metrics command actions
+const (
+	e2mList = "list"
+	e2mShow = "show"
+)
+
+var errE2MAction = errors.New("you need to provide e2m action (list or show <id>)")
+
+// Names of metrics made by definition, one per enabled aggregation
+func e2mMetrics(d e2m.Definition) []string {
+	var m []string
+	for _, f := range d.Fields {
+		for _, a := range f.Aggregations {
+			if a.Enabled {
+				m = append(m, a.Metric)
+			}
+		}
+	}
+
+	return m
+}
+
+func e2mQuery(q e2m.Query) string {
+	if q.Lucene == "" {
+		return "*"
+	}
+
+	return q.Lucene
+}
+
+// Print definitions with their query and metrics
+func printE2MList(out io.Writer, defs []e2m.Definition) error {
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "name\tid\tquery\tmetrics")
+
+	for _, d := range defs {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", d.Name, d.ID, e2mQuery(d.Query), strings.Join(e2mMetrics(d), ","))
+	}
+
+	return tw.Flush()
+}
+
+// Print one definition with its filters, labels and aggregated fields
+func printE2M(out io.Writer, d e2m.Definition) error {
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Name:\t%s\n", d.Name)
+	fmt.Fprintf(tw, "ID:\t%s\n", d.ID)
+	if d.Description != "" {
+		fmt.Fprintf(tw, "Description:\t%s\n", d.Description)
+	}
+	fmt.Fprintf(tw, "Query:\t%s\n", e2mQuery(d.Query))
+	for _, filter := range []struct {
+		name   string
+		values []string
+	}{{"Applications", d.Query.Applications}, {"Subsystems", d.Query.Subsystems}, {"Severities", d.Query.Severities}} {
+		if len(filter.values) > 0 {
+			fmt.Fprintf(tw, "%s:\t%s\n", filter.name, strings.Join(filter.values, ", "))
+		}
+	}
+	for _, l := range d.Labels {
+		fmt.Fprintf(tw, "Label:\t%s <- %s\n", l.Target, l.Source)
+	}
+	for _, f := range d.Fields {
+		var aggs []string
+		for _, a := range f.Aggregations {
+			if a.Enabled {
+				aggs = append(aggs, a.Type)
+			}
+		}
+		fmt.Fprintf(tw, "Metric:\t%s <- %s (%s)\n", f.BaseMetric, f.Source, strings.Join(aggs, ", "))
+	}
+	if d.PermutationsLimit > 0 {
+		fmt.Fprintf(tw, "Permutations limit:\t%d\n", d.PermutationsLimit)
+	}
+	if d.Updated != "" {
+		fmt.Fprintf(tw, "Updated:\t%s\n", d.Updated)
+	}
+
+	return tw.Flush()
+}
+
+// List events-to-metrics definitions of the instance or show one of them
+func runE2M(out io.Writer, args *CmdArgs, token func() (string, error)) error {
+	action, id, _ := strings.Cut(strings.TrimSpace(args.Query), " ")
+	id = strings.TrimSpace(id)
+	if !(action == e2mList && id == "") && !(action == e2mShow && id != "") {
+		return errE2MAction
+	}
+	if args.APIKey == "" {
+		return errMissingAPIKey
+	}
+	if args.LogsURL == "" {
+		return errMissingURL
+	}
+
+	if action == e2mShow {
+		d, err := e2m.Get(context.Background(), args.LogsURL, token, id)
+		if err != nil {
+			return err
+		}
+		return printE2M(out, d)
+	}
+
+	defs, err := e2m.List(context.Background(), args.LogsURL, token)
+	if err != nil {
+		return err
+	}
+
+	return printE2MList(out, defs)
+}
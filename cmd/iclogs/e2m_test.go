@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRunE2M(t *testing.T) {
+	definition := `{"id":"e1","name":"latency","logs_query":{"lucene":"path:/pay","severity_filters":["error"]},"permutations_limit":30000,` +
+		`"metric_labels":[{"target_label":"status","source_field":"status_code"}],` +
+		`"metric_fields":[{"target_base_metric_name":"pay","source_field":"took","aggregations":[{"enabled":true,"agg_type":"max","target_metric_name":"pay_max"},` +
+		`{"enabled":false,"agg_type":"min","target_metric_name":"pay_min"},{"enabled":true,"agg_type":"avg","target_metric_name":"pay_avg"}]}]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/events2metrics":
+			w.Write([]byte(`{"events2metrics":[` + definition + `,{"id":"e2","name":"all"}]}`))
+		case "/v1/events2metrics/e1":
+			w.Write([]byte(definition))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	token := func() (string, error) { return "Good_Token", nil }
+
+	testCases := []struct {
+		name     string
+		query    string
+		expected string
+	}{
+		{
+			name:  "List",
+			query: "list",
+			expected: "name     id  query      metrics\n" +
+				"all      e2  *          \n" +
+				"latency  e1  path:/pay  pay_max,pay_avg\n",
+		},
+		{
+			name:  "Show",
+			query: "show e1",
+			expected: "Name:                latency\n" +
+				"ID:                  e1\n" +
+				"Query:               path:/pay\n" +
+				"Severities:          error\n" +
+				"Label:               status <- status_code\n" +
+				"Metric:              pay <- took (max, avg)\n" +
+				"Permutations limit:  30000\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			args := CmdArgs{APIKey: "key", LogsURL: server.URL, Query: tc.query}
+			out := bytes.Buffer{}
+			if err := runE2M(&out, &args, token); err != nil {
+				t.Fatalf("Got unexpected error: %v", err)
+			}
+			assert(t, out.String(), tc.expected)
+		})
+	}
+
+	for _, q := range []string{"", "show", "list e1", "delete e1"} {
+		args := CmdArgs{APIKey: "key", LogsURL: server.URL, Query: q}
+		assertError(t, runE2M(&bytes.Buffer{}, &args, token), errE2MAction)
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/wooyey/iclogs/internal/platform/enrichments"
+)
+
+// Enrichments command actions
+const enrichmentsList = "list"
+
+var errEnrichmentsAction = errors.New("you need to provide enrichments action (list)")
+
+// Print enriched fields with enrichment type
+func printEnrichments(out io.Writer, e []enrichments.Enrichment) error {
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "field\ttype\tid")
+
+	for _, enrichment := range e {
+		fmt.Fprintf(tw, "%s\t%s\t%d\n", enrichment.Field, enrichment.Kind(), enrichment.ID)
+	}
+
+	return tw.Flush()
+}
+
+// List enrichments of the instance
+func runEnrichments(out io.Writer, args *CmdArgs, token func() (string, error)) error {
+	if strings.TrimSpace(args.Query) != enrichmentsList {
+		return errEnrichmentsAction
+	}
+	if args.APIKey == "" {
+		return errMissingAPIKey
+	}
+	if args.LogsURL == "" {
+		return errMissingURL
+	}
+
+	e, err := enrichments.List(context.Background(), args.LogsURL, token)
+	if err != nil {
+		return err
+	}
+
+	return printEnrichments(out, e)
+}
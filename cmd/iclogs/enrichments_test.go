@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRunEnrichments(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"enrichments":[{"id":1,"field_name":"client_ip","enrichment_type":{"geo_ip":{}}},{"id":4,"field_name":"ip","enrichment_type":{"suspicious_ip":{}}}]}`))
+	}))
+	defer server.Close()
+
+	token := func() (string, error) { return "Good_Token", nil }
+	args := CmdArgs{APIKey: "key", LogsURL: server.URL, Query: "list"}
+
+	out := bytes.Buffer{}
+	if err := runEnrichments(&out, &args, token); err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	assert(t, out.String(), "field      type           id\n"+
+		"client_ip  geo_ip         1\n"+
+		"ip         suspicious_ip  4\n")
+
+	testCases := []struct {
+		name string
+		args CmdArgs
+		err  error
+	}{
+		{name: "MissingAction", args: CmdArgs{APIKey: "key", LogsURL: server.URL, Query: "show"}, err: errEnrichmentsAction},
+		{name: "MissingAPIKey", args: CmdArgs{LogsURL: server.URL, Query: "list"}, err: errMissingAPIKey},
+		{name: "MissingURL", args: CmdArgs{APIKey: "key", Query: "list"}, err: errMissingURL},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assertError(t, runEnrichments(&out, &tc.args, token), tc.err)
+		})
+	}
+}
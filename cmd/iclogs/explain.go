@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/auth"
+	"github.com/wooyey/iclogs/internal/platform/logs"
+)
+
+// Print fully resolved query request without running it, as block ready to attach to support ticket.
+// Token itself is never printed, only subject and account it was issued for.
+func printExplain(out io.Writer, endpoint, query string, spec logs.QuerySpec, getToken func() (string, error)) error {
+	payload, err := logs.Payload(query, spec)
+	if err != nil {
+		return err
+	}
+
+	request := bytes.Buffer{}
+	if err := json.Indent(&request, payload, "", "  "); err != nil {
+		return fmt.Errorf("cannot indent payload: %w", err)
+	}
+
+	addr, err := logs.GetQueryURL(endpoint)
+	if err != nil {
+		return fmt.Errorf("cannot create query URL: %w", err)
+	}
+
+	fmt.Fprintln(out, "```")
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Version:\t%s\n", getVersion())
+	fmt.Fprintf(w, "Endpoint:\tPOST %s\n", addr)
+	fmt.Fprintf(w, "Tier:\t%s\n", spec.Tier)
+	fmt.Fprintf(w, "Window:\t%s - %s (%v)\n", spec.StartDate.UTC().Format(time.RFC3339Nano), spec.EndDate.UTC().Format(time.RFC3339Nano), spec.EndDate.Sub(spec.StartDate))
+	fmt.Fprintf(w, "Subject:\t%s\n", tokenSubject(getToken))
+	w.Flush()
+	fmt.Fprintf(out, "Request:\n%s\n```\n", request.String())
+
+	return nil
+}
+
+// Subject and account of token, or why it is not known, as token failures are often the reason of support ticket
+func tokenSubject(getToken func() (string, error)) string {
+	token, err := getToken()
+	if err != nil {
+		return fmt.Sprintf("unknown, %v", err)
+	}
+
+	c, err := auth.ParseClaims(token)
+	if err != nil {
+		return fmt.Sprintf("unknown, %v", err)
+	}
+
+	return fmt.Sprintf("%s, account %s", subjectOf(c), c.Account.ID)
+}
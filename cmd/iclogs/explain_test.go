@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/logs"
+	"github.com/wooyey/iclogs/internal/platform/logs/syntax"
+	"github.com/wooyey/iclogs/internal/platform/logs/tier"
+)
+
+func TestPrintExplain(t *testing.T) {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"ServiceId-123","sub_type":"ServiceId","account":{"bss":"abc123"}}`))
+	token := "header." + payload + ".signature"
+
+	start := time.Date(2025, 1, 11, 13, 0, 0, 0, time.FixedZone("CET", 3600))
+	spec := logs.QuerySpec{Syntax: syntax.Lucene, Tier: tier.Archive, Limit: tier.LimitArchive, StartDate: start, EndDate: start.Add(15 * time.Minute)}
+
+	out := &bytes.Buffer{}
+	err := printExplain(out, "https://abc.api.eu-de.logs.cloud.ibm.com", "app:web", spec, func() (string, error) { return token, nil })
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+
+	want := "```\n" + `Version:   iclogs version 
+Endpoint:  POST https://abc.api.eu-de.logs.cloud.ibm.com/v1/query
+Tier:      archive
+Window:    2025-01-11T12:00:00Z - 2025-01-11T12:15:00Z (15m0s)
+Subject:   ServiceId-123 (ServiceId), account abc123
+Request:
+{
+  "query": "app:web",
+  "metadata": {
+    "end_date": "2025-01-11T13:15:00+01:00",
+    "limit": 50000,
+    "start_date": "2025-01-11T13:00:00+01:00",
+    "syntax": "lucene",
+    "tier": "archive"
+  }
+}
+` + "```\n"
+	assert(t, out.String(), want)
+
+	out.Reset()
+	err = printExplain(out, "https://abc.api.eu-de.logs.cloud.ibm.com", "app:web", spec, func() (string, error) { return "", errors.New("no access") })
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Subject:   unknown, no access\n") {
+		t.Errorf("Expected unknown subject, got: %s", out.String())
+	}
+}
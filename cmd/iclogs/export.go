@@ -0,0 +1,444 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/cos"
+	"github.com/wooyey/iclogs/internal/platform/encrypt"
+	"github.com/wooyey/iclogs/internal/platform/logs"
+)
+
+const (
+	defaultOutput    = "."
+	exportTimeFormat = "20060102T150405Z"
+	exportExtension  = ".ndjson"
+	exportFileMode   = 0o600
+	manifestName     = "manifest.json"
+	minRetryWindow   = time.Minute // Failed windows shorter than two of these are not split for retry
+	defaultRetries   = 2
+)
+
+// Byte units of --max-bandwidth and --max-response, decimal and binary ones
+var bandwidthUnits = map[string]int64{
+	"B": 1, "KB": 1000, "MB": 1000 * 1000, "GB": 1000 * 1000 * 1000,
+	"KIB": 1 << 10, "MIB": 1 << 20, "GIB": 1 << 30,
+}
+
+var errInvalidBandwidth = errors.New("bandwidth needs to be positive size per second, ie. 10MB/s or 512KiB/s")
+
+// Bytes per second from --max-bandwidth option, 0 means unlimited
+type bandwidth int64
+
+func (b *bandwidth) String() string {
+	if *b == 0 {
+		return ""
+	}
+	return strconv.FormatInt(int64(*b), 10) + "B/s"
+}
+
+func (b *bandwidth) Set(value string) error {
+	n, ok := parseBytes(strings.TrimSuffix(strings.TrimSpace(value), "/s"))
+	if !ok {
+		return errInvalidBandwidth
+	}
+	*b = bandwidth(n)
+
+	return nil
+}
+
+// Parse positive size with byte unit, ie. 10MB or 512KiB
+func parseBytes(value string) (int64, bool) {
+	v := strings.ToUpper(strings.TrimSpace(value))
+	i := strings.IndexFunc(v, func(r rune) bool { return r != '.' && (r < '0' || r > '9') })
+	if i <= 0 {
+		return 0, false
+	}
+
+	n, err := strconv.ParseFloat(v[:i], 64)
+	unit, ok := bandwidthUnits[strings.TrimSpace(v[i:])]
+	if err != nil || !ok || n*float64(unit) < 1 {
+		return 0, false
+	}
+
+	return int64(n * float64(unit)), true
+}
+
+// Export file entry in manifest, checksum is computed over file content as written
+type manifestEntry struct {
+	File    string    `json:"file"`
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Records int       `json:"records"`
+	SHA256  string    `json:"sha256"`
+}
+
+// Manifest of completed export files, used to verify integrity and skip complete chunks on re-run
+type manifest struct {
+	Query string          `json:"query"`
+	Files []manifestEntry `json:"files"`
+}
+
+// Exports records as JSON lines or CSV files, one per chunk of time range, optionally uploaded to object storage.
+// With stream set records are written as they arrive instead of searching for all of them first.
+type exporter struct {
+	search   searchFunc
+	stream   streamFunc
+	dir      string
+	chunk    time.Duration
+	encrypt  encrypt.Encrypter
+	upload   func(path string) error
+	progress *progress
+	csv      *csvExport // CSV files instead of JSON lines, nil means JSON lines
+	retries  int        // Retries of failed windows of one chunk
+}
+
+// Split time range into chunks, the last one can be shorter
+func exportWindows(start, end time.Time, chunk time.Duration) [][2]time.Time {
+	if chunk <= 0 {
+		return [][2]time.Time{{start, end}}
+	}
+
+	var w [][2]time.Time
+	for s := start; s.Before(end); s = s.Add(chunk) {
+		e := s.Add(chunk)
+		if e.After(end) {
+			e = end
+		}
+		w = append(w, [2]time.Time{s, e})
+	}
+
+	return w
+}
+
+func exportName(start, end time.Time, extension string) string {
+	return start.UTC().Format(exportTimeFormat) + "_" + end.UTC().Format(exportTimeFormat) + extension
+}
+
+// Read manifest from export directory, empty one is returned when there is none yet
+func loadManifest(dir string) (manifest, error) {
+	var m manifest
+
+	b, err := os.ReadFile(filepath.Join(dir, manifestName))
+	if errors.Is(err, os.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return m, fmt.Errorf("cannot read export manifest: %w", err)
+	}
+
+	if err := json.Unmarshal(b, &m); err != nil {
+		return m, fmt.Errorf("cannot parse export manifest: %w", err)
+	}
+
+	return m, nil
+}
+
+// Write manifest to export directory, replacing previous one only when fully written
+func (m *manifest) save(dir string) error {
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot encode export manifest: %w", err)
+	}
+
+	path := filepath.Join(dir, manifestName)
+	if err := os.WriteFile(path+".tmp", append(b, '\n'), exportFileMode); err != nil {
+		return fmt.Errorf("cannot write export manifest: %w", err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return fmt.Errorf("cannot write export manifest: %w", err)
+	}
+
+	return nil
+}
+
+// Add or replace manifest entry of the file
+func (m *manifest) set(e manifestEntry) {
+	for i := range m.Files {
+		if m.Files[i].File == e.File {
+			m.Files[i] = e
+			return
+		}
+	}
+	m.Files = append(m.Files, e)
+}
+
+// Check whether file is listed in manifest and its content still matches the checksum
+func (m *manifest) complete(dir, file string) bool {
+	for _, e := range m.Files {
+		if e.File != file {
+			continue
+		}
+		sum, err := fileChecksum(filepath.Join(dir, file))
+		return err == nil && sum == e.SHA256
+	}
+
+	return false
+}
+
+// SHA-256 of file content as hex string
+func fileChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Write records as JSON lines in REST API format
+func encodeRecords(w io.Writer, l []logs.Log) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for i := range l {
+		if err := enc.Encode(newAPIRecord(&l[i])); err != nil {
+			return fmt.Errorf("cannot write record: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Encoder of records written to one export file
+func (e *exporter) encoder(w io.Writer) func([]logs.Log) error {
+	if e.csv != nil {
+		return e.csv.encoder(w, e.dir)
+	}
+
+	return func(l []logs.Log) error {
+		return encodeRecords(w, l)
+	}
+}
+
+// Create file written by write function, encrypted when encrypter is given, returns checksum of written file
+func writeRecords(path string, e encrypt.Encrypter, write func(w io.Writer) error) (string, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, exportFileMode)
+	if err != nil {
+		return "", fmt.Errorf("cannot create export file: %w", err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	var w io.WriteCloser = nopCloser{io.MultiWriter(f, h)}
+	if e != nil {
+		if w, err = e.Writer(io.MultiWriter(f, h)); err != nil {
+			return "", fmt.Errorf("cannot encrypt export file: %w", err)
+		}
+	}
+
+	if err := write(w); err != nil {
+		w.Close()
+		return "", err
+	}
+
+	if err := w.Close(); err != nil {
+		return "", fmt.Errorf("cannot encrypt export file: %w", err)
+	}
+
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("cannot write export file: %w", err)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+// Query records of chunk, returning count of written ones. Query API has no continuation token
+// or offset to resume from, so failed window is split in halves and retried instead, while retries last. Windows
+// completed before are not fetched again, and records streamed before the failure are skipped by their IDs.
+func (e *exporter) fetch(query string, spec logs.QuerySpec, encode func([]logs.Log) error) (int, error) {
+	windows := [][2]time.Time{{spec.StartDate, spec.EndDate}}
+	written := map[string]bool{} // IDs of records streamed from failed windows
+	count, retries := 0, e.retries
+
+	for len(windows) > 0 {
+		w := windows[0]
+		s := spec
+		s.StartDate, s.EndDate = w[0], w[1]
+
+		attempt := map[string]bool{}
+		var err error
+		if e.stream != nil {
+			_, _, err = e.stream("", query, s, func(l []logs.Log) error {
+				fresh := make([]logs.Log, 0, len(l))
+				for i := range l {
+					if written[l[i].ID] {
+						continue
+					}
+					if retries > 0 {
+						attempt[l[i].ID] = true
+					}
+					fresh = append(fresh, l[i])
+				}
+				count += len(fresh)
+				e.progress.received(len(fresh))
+				return encode(fresh)
+			})
+		} else {
+			var r logs.Result
+			if r, err = e.search("", query, s); err == nil {
+				count += len(r.Logs)
+				err = encode(r.Logs)
+			}
+		}
+
+		if err != nil {
+			if errors.Is(err, errDeadline) || retries <= 0 || w[1].Sub(w[0]) < 2*minRetryWindow {
+				return count, err
+			}
+			retries--
+			for id := range attempt {
+				written[id] = true
+			}
+			mid := w[0].Add(w[1].Sub(w[0]) / 2)
+			windows = append([][2]time.Time{{w[0], mid}, {mid, w[1]}}, windows[1:]...)
+			continue
+		}
+
+		windows = windows[1:]
+	}
+
+	return count, nil
+}
+
+// Export query results chunk by chunk, printing written files, chunks complete according to manifest are skipped
+func (e *exporter) run(out io.Writer, query string, spec logs.QuerySpec) error {
+	if err := os.MkdirAll(e.dir, 0o700); err != nil {
+		return fmt.Errorf("cannot create export directory: %w", err)
+	}
+
+	m, err := loadManifest(e.dir)
+	if err != nil {
+		return err
+	}
+	if m.Query != "" && m.Query != query {
+		return fmt.Errorf("cannot export to %s: directory contains export of other query", e.dir)
+	}
+	m.Query = query
+
+	extension := exportExtension
+	if e.csv != nil {
+		if err := e.csv.resume(e.dir); err != nil {
+			return err
+		}
+		extension = csvExtension
+	}
+
+	chunks := exportWindows(spec.StartDate, spec.EndDate, e.chunk)
+	for i, w := range chunks {
+		name := exportName(w[0], w[1], extension)
+		if e.encrypt != nil {
+			name += e.encrypt.Extension()
+		}
+		path := filepath.Join(e.dir, name)
+
+		if m.complete(e.dir, name) {
+			fmt.Fprintf(out, "%s: complete, skipped\n", path)
+			e.progress.finish()
+			continue
+		}
+
+		s := spec
+		s.StartDate, s.EndDate = w[0], w[1]
+
+		count := 0
+		e.progress.start()
+		sum, err := writeRecords(path, e.encrypt, func(f io.Writer) error {
+			var err error
+			count, err = e.fetch(query, s, e.encoder(f))
+			return err
+		})
+		if errors.Is(err, errDeadline) {
+			return fmt.Errorf("%w: %d of %d chunks exported, run again to resume", err, i, len(chunks))
+		}
+		if err != nil {
+			return err
+		}
+
+		e.progress.finish()
+		if e.stream == nil {
+			e.progress.received(count)
+		}
+
+		fmt.Fprintf(out, "%s: %d records\n", path, count)
+		if count >= s.Limit && s.Limit > 0 {
+			fmt.Fprintf(out, "%s: records limit reached, use shorter chunk\n", path)
+		}
+
+		if e.upload != nil {
+			if err := e.upload(path); err != nil {
+				return err
+			}
+		}
+
+		m.set(manifestEntry{File: name, Start: w[0], End: w[1], Records: count, SHA256: sum})
+		if err := m.save(e.dir); err != nil {
+			return err
+		}
+	}
+
+	if err := m.save(e.dir); err != nil {
+		return err
+	}
+	e.progress.event(phaseDone)
+
+	if e.upload != nil {
+		return e.upload(filepath.Join(e.dir, manifestName))
+	}
+
+	return nil
+}
+
+// Upload of export files to object storage location, HMAC credentials from environment are used when set, IAM token otherwise
+func newUpload(location, endpoint, sse string, token func() (string, error)) (func(string) error, error) {
+	loc, err := cos.ParseLocation(location)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &cos.Client{Endpoint: endpoint, SSE: sse, Sign: cos.Bearer(token)}
+	if key := os.Getenv("AWS_ACCESS_KEY_ID"); key != "" {
+		region := os.Getenv("AWS_REGION")
+		if region == "" {
+			region = "us-east-1"
+		}
+		c.Sign = cos.SigV4(key, os.Getenv("AWS_SECRET_ACCESS_KEY"), region)
+	}
+
+	return func(path string) error {
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("cannot open export file: %w", err)
+		}
+		defer f.Close()
+
+		info, err := f.Stat()
+		if err != nil {
+			return fmt.Errorf("cannot check export file: %w", err)
+		}
+
+		return c.Upload(loc.Bucket, loc.Prefix+filepath.Base(path), f, info.Size())
+	}, nil
+}
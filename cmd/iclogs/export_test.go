@@ -0,0 +1,289 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/logs"
+	"github.com/wooyey/iclogs/internal/platform/secrets"
+)
+
+func TestExportWindows(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	end := start.Add(150 * time.Minute)
+
+	assertDeepEqual(t, exportWindows(start, end, 0), [][2]time.Time{{start, end}})
+	assertDeepEqual(t, exportWindows(start, end, time.Hour), [][2]time.Time{
+		{start, start.Add(time.Hour)},
+		{start.Add(time.Hour), start.Add(2 * time.Hour)},
+		{start.Add(2 * time.Hour), end},
+	})
+}
+
+func TestExport(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	dir := filepath.Join(t.TempDir(), "out")
+	var uploaded []string
+
+	e := &exporter{
+		search: func(client, query string, spec logs.QuerySpec) (logs.Result, error) {
+			return logs.Result{Logs: []logs.Log{{ID: "1", Time: spec.StartDate, Severity: "Info", UserData: `{"message":"<a>"}`}}}, nil
+		},
+		dir:   dir,
+		chunk: time.Hour,
+		upload: func(path string) error {
+			uploaded = append(uploaded, filepath.Base(path))
+			return nil
+		},
+	}
+
+	out := bytes.Buffer{}
+	spec := logs.QuerySpec{StartDate: start, EndDate: start.Add(2 * time.Hour), Limit: 1}
+	if err := e.run(&out, "some query", spec); err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+
+	first := filepath.Join(dir, "20250101T100000Z_20250101T110000Z.ndjson")
+	second := filepath.Join(dir, "20250101T110000Z_20250101T120000Z.ndjson")
+
+	assert(t, out.String(), first+": 1 records\n"+first+": records limit reached, use shorter chunk\n"+
+		second+": 1 records\n"+second+": records limit reached, use shorter chunk\n")
+	assertDeepEqual(t, uploaded, []string{filepath.Base(first), filepath.Base(second), manifestName})
+
+	got, err := os.ReadFile(first)
+	if err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+	assert(t, string(got), `{"id":"1","time":"2025-01-01T10:00:00Z","severity":"Info","data":{"message":"<a>"}}`+"\n")
+}
+
+func TestExportDeadline(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	calls := 0
+
+	e := &exporter{
+		search: func(client, query string, spec logs.QuerySpec) (logs.Result, error) {
+			if calls++; calls > 1 {
+				return logs.Result{}, errDeadline
+			}
+			return logs.Result{}, nil
+		},
+		dir:   t.TempDir(),
+		chunk: time.Hour,
+	}
+
+	spec := logs.QuerySpec{StartDate: start, EndDate: start.Add(3 * time.Hour)}
+	err := e.run(&bytes.Buffer{}, "some query", spec)
+	if !errors.Is(err, errDeadline) {
+		t.Fatalf("Got error: %v, want: %v", err, errDeadline)
+	}
+	assert(t, strings.HasSuffix(err.Error(), ": 1 of 3 chunks exported, run again to resume"), true)
+}
+
+func TestExportManifest(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	dir := t.TempDir()
+	var calls []time.Time
+
+	e := &exporter{
+		search: func(client, query string, spec logs.QuerySpec) (logs.Result, error) {
+			calls = append(calls, spec.StartDate)
+			return logs.Result{Logs: []logs.Log{{ID: "1", Time: spec.StartDate, UserData: `{}`}}}, nil
+		},
+		dir:   dir,
+		chunk: time.Hour,
+	}
+	spec := logs.QuerySpec{StartDate: start, EndDate: start.Add(2 * time.Hour)}
+
+	if err := e.run(io.Discard, "some query", spec); err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+
+	m, err := loadManifest(dir)
+	if err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+	first := "20250101T100000Z_20250101T110000Z.ndjson"
+	second := "20250101T110000Z_20250101T120000Z.ndjson"
+	sum, _ := fileChecksum(filepath.Join(dir, first))
+	assert(t, m.Query, "some query")
+	assert(t, len(m.Files), 2)
+	assertDeepEqual(t, m.Files[0], manifestEntry{File: first, Start: start, End: start.Add(time.Hour), Records: 1, SHA256: sum})
+
+	// Corrupt second file, only that chunk is exported again
+	if err := os.WriteFile(filepath.Join(dir, second), []byte("broken"), 0o600); err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+	calls = nil
+	out := bytes.Buffer{}
+	if err := e.run(&out, "some query", spec); err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+	assertDeepEqual(t, calls, []time.Time{start.Add(time.Hour)})
+	assert(t, out.String(), filepath.Join(dir, first)+": complete, skipped\n"+filepath.Join(dir, second)+": 1 records\n")
+
+	if err := e.run(io.Discard, "other query", spec); err == nil {
+		t.Errorf("Expected error for export of other query to the same directory")
+	}
+}
+
+// Fake encryption prefixing data
+type testEncrypter struct{}
+
+type prefixWriter struct {
+	io.Writer
+}
+
+func (w prefixWriter) Close() error {
+	return nil
+}
+
+func (testEncrypter) Writer(w io.Writer) (io.WriteCloser, error) {
+	io.WriteString(w, "ENC:")
+	return prefixWriter{w}, nil
+}
+
+func (testEncrypter) Extension() string {
+	return ".enc"
+}
+
+func TestExportEncrypted(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	dir := t.TempDir()
+
+	e := &exporter{
+		search: func(client, query string, spec logs.QuerySpec) (logs.Result, error) {
+			return logs.Result{Logs: []logs.Log{{ID: "1", Time: start, UserData: `{}`}}}, nil
+		},
+		dir:     dir,
+		encrypt: testEncrypter{},
+	}
+
+	if err := e.run(io.Discard, "some query", logs.QuerySpec{StartDate: start, EndDate: start.Add(time.Hour)}); err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(dir, "20250101T100000Z_20250101T110000Z.ndjson.enc"))
+	if err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+	assert(t, string(got), `ENC:{"id":"1","time":"2025-01-01T10:00:00Z","severity":"","data":{}}`+"\n")
+}
+
+func TestExportProgress(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	dir := t.TempDir()
+	search := func(client, query string, spec logs.QuerySpec) (logs.Result, error) {
+		return logs.Result{Logs: []logs.Log{{ID: "1", Time: spec.StartDate, UserData: `{}`}}}, nil
+	}
+	spec := logs.QuerySpec{StartDate: start, EndDate: start.Add(2 * time.Hour)}
+
+	e := &exporter{search: search, dir: dir, chunk: time.Hour}
+	if err := e.run(io.Discard, "some query", spec); err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+
+	// Complete chunks are skipped, counting as done
+	buffer := bytes.Buffer{}
+	e.progress, _ = newProgress(progressJSON, &buffer, 3)
+	spec.EndDate = start.Add(3 * time.Hour)
+	if err := e.run(io.Discard, "some query", spec); err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+
+	assertDeepEqual(t, decodeEvents(t, &buffer), []progressEvent{
+		{Phase: phaseQuery, Shard: 3, Shards: 3, Percent: 66},
+		{Phase: phaseRecords, Shard: 3, Shards: 3, Percent: 100, Records: 1},
+		{Phase: phaseDone, Shards: 3, Percent: 100, Records: 1},
+	})
+}
+
+func TestExportRetry(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	mid := start.Add(30 * time.Minute)
+	failed := errors.New("connection reset")
+	spec := logs.QuerySpec{StartDate: start, EndDate: start.Add(time.Hour)}
+	record := func(id string) logs.Log { return logs.Log{ID: id, Time: start, UserData: `{}`} }
+
+	// Records streamed before failure are not written again
+	dir := t.TempDir()
+	var calls []time.Time
+	e := &exporter{
+		stream: func(client, query string, spec logs.QuerySpec, fn func([]logs.Log) error) (logs.Result, []secrets.Finding, error) {
+			calls = append(calls, spec.StartDate)
+			switch len(calls) {
+			case 1:
+				return logs.Result{}, nil, errors.Join(fn([]logs.Log{record("1"), record("2")}), failed)
+			case 2:
+				return logs.Result{}, nil, fn([]logs.Log{record("1"), record("2"), record("3")})
+			}
+			return logs.Result{}, nil, fn([]logs.Log{record("4")})
+		},
+		dir:     dir,
+		chunk:   time.Hour,
+		retries: 1,
+	}
+
+	out := bytes.Buffer{}
+	if err := e.run(&out, "some query", spec); err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+	assertDeepEqual(t, calls, []time.Time{start, start, mid})
+	path := filepath.Join(dir, "20250101T100000Z_20250101T110000Z.ndjson")
+	assert(t, out.String(), path+": 4 records\n")
+
+	// Failed chunk is not listed in manifest, so it is exported again on re-run
+	dir = t.TempDir()
+	calls = nil
+	e = &exporter{
+		search: func(client, query string, spec logs.QuerySpec) (logs.Result, error) {
+			calls = append(calls, spec.StartDate)
+			if len(calls) == 2 {
+				return logs.Result{Logs: []logs.Log{record("1")}}, nil
+			}
+			return logs.Result{}, failed
+		},
+		dir:     dir,
+		chunk:   time.Hour,
+		retries: 1,
+	}
+
+	assertError(t, e.run(io.Discard, "some query", spec), failed)
+	assertDeepEqual(t, calls, []time.Time{start, start, mid})
+	m, err := loadManifest(dir)
+	if err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+	assert(t, len(m.Files), 0)
+}
+
+func TestBandwidth(t *testing.T) {
+	testCases := []struct {
+		input string
+		want  bandwidth
+		err   error
+	}{
+		{input: "10MB/s", want: 10_000_000},
+		{input: "512KiB/s", want: 524_288},
+		{input: "1.5 gb", want: 1_500_000_000},
+		{input: "800B/s", want: 800},
+		{input: "10", err: errInvalidBandwidth},
+		{input: "MB/s", err: errInvalidBandwidth},
+		{input: "10TB/s", err: errInvalidBandwidth},
+		{input: "0MB/s", err: errInvalidBandwidth},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			var b bandwidth
+			assertError(t, b.Set(tc.input), tc.err)
+			assert(t, b, tc.want)
+		})
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package main
+
+import "os"
+
+// Identity of file is not known on this platform, rotation is then told only by file getting smaller
+func fileID(os.FileInfo) string {
+	return ""
+}
@@ -0,0 +1,19 @@
+//go:build unix
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// Identity of file as device and inode, so file rotated under the same path is told apart
+func fileID(fi os.FileInfo) string {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return ""
+	}
+
+	return fmt.Sprintf("%d:%d", st.Dev, st.Ino)
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/wooyey/iclogs/internal/platform/logs"
+)
+
+// Handling of arrays in flattened user data
+const (
+	arraysIndex = "index" // Items get index keys, ie. `tags.0`
+	arraysJoin  = "join"  // Items are joined into comma separated string
+	arraysKeep  = "keep"  // Arrays are kept as values
+)
+
+const defaultFlattenArrays = arraysIndex
+
+var flattenArrays = []string{arraysIndex, arraysJoin, arraysKeep}
+
+func validFlattenArrays(mode string) error {
+	for _, m := range flattenArrays {
+		if m == mode {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown arrays handling '%s', use %s", mode, strings.Join(flattenArrays, ", "))
+}
+
+// Rewrite user data of log records as single level JSON objects with dotted keys. User data which is not an object is kept.
+func flattenLogs(l []logs.Log, arrays string) error {
+	for i := range l {
+		ud, err := flattenData(l[i].UserData, arrays)
+		if err != nil {
+			return fmt.Errorf("cannot flatten record '%s': %w", l[i].ID, err)
+		}
+		l[i].UserData = ud
+	}
+
+	return nil
+}
+
+func flattenData(userData, arrays string) (string, error) {
+	if !strings.HasPrefix(strings.TrimSpace(userData), "{") {
+		return userData, nil
+	}
+
+	dec := json.NewDecoder(strings.NewReader(userData))
+	dec.UseNumber() // Keep numbers as they were
+	var ud map[string]any
+	if err := dec.Decode(&ud); err != nil {
+		return userData, nil
+	}
+
+	flat := map[string]any{}
+	if err := flattenValue(flat, "", ud, arrays); err != nil {
+		return userData, err
+	}
+
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(flat); err != nil {
+		return userData, err
+	}
+
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
+
+// Add value under dotted key to flat object, empty objects and arrays are kept as values
+func flattenValue(flat map[string]any, key string, v any, arrays string) error {
+	switch t := v.(type) {
+	case map[string]any:
+		if len(t) == 0 && key != "" {
+			flat[key] = t
+			return nil
+		}
+		for k, nested := range t {
+			if err := flattenValue(flat, joinKey(key, k), nested, arrays); err != nil {
+				return err
+			}
+		}
+	case []any:
+		switch {
+		case arrays == arraysKeep || len(t) == 0:
+			flat[key] = t
+		case arrays == arraysJoin:
+			items := make([]string, len(t))
+			for i, item := range t {
+				s, err := scalarString(item)
+				if err != nil {
+					return err
+				}
+				items[i] = s
+			}
+			flat[key] = strings.Join(items, ",")
+		default:
+			for i, item := range t {
+				if err := flattenValue(flat, joinKey(key, strconv.Itoa(i)), item, arrays); err != nil {
+					return err
+				}
+			}
+		}
+	default:
+		flat[key] = v
+	}
+
+	return nil
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+
+	return prefix + "." + key
+}
+
+// Text of joined array item, objects and arrays are written as JSON
+func scalarString(v any) (string, error) {
+	switch t := v.(type) {
+	case string:
+		return t, nil
+	case nil:
+		return "", nil
+	case map[string]any, []any:
+		b, err := json.Marshal(t)
+		return string(b), err
+	default:
+		return fmt.Sprint(t), nil
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/wooyey/iclogs/internal/platform/logs"
+)
+
+func TestFlattenData(t *testing.T) {
+	userData := `{"kubernetes":{"labels":{"app":"web"},"annotations":{}},"tags":["a",1,null],"items":[{"id":7}],"n":12345678901234567890,"msg":"a<b"}`
+
+	testCases := []struct {
+		arrays string
+		want   string
+	}{
+		{arrays: arraysIndex, want: `{"items.0.id":7,"kubernetes.annotations":{},"kubernetes.labels.app":"web","msg":"a<b","n":12345678901234567890,"tags.0":"a","tags.1":1,"tags.2":null}`},
+		{arrays: arraysJoin, want: `{"items":"{\"id\":7}","kubernetes.annotations":{},"kubernetes.labels.app":"web","msg":"a<b","n":12345678901234567890,"tags":"a,1,"}`},
+		{arrays: arraysKeep, want: `{"items":[{"id":7}],"kubernetes.annotations":{},"kubernetes.labels.app":"web","msg":"a<b","n":12345678901234567890,"tags":["a",1,null]}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.arrays, func(t *testing.T) {
+			got, err := flattenData(userData, tc.arrays)
+			if err != nil {
+				t.Fatalf("Got unexpected error: %v", err)
+			}
+			assert(t, got, tc.want)
+		})
+	}
+
+	got, err := flattenData("plain text", arraysIndex)
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	assert(t, got, "plain text")
+}
+
+func TestFlattenLogs(t *testing.T) {
+	l := []logs.Log{{UserData: `{"message_obj":{"msg":"hello"}}`}}
+	if err := flattenLogs(l, arraysIndex); err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	assert(t, l[0].UserData, `{"message_obj.msg":"hello"}`)
+
+	// Default message fields still find flattened message
+	msg, err := l[0].Message([]string{"message", "message_obj.msg"})
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	assert(t, msg, "hello")
+}
+
+func TestValidFlattenArrays(t *testing.T) {
+	for _, m := range flattenArrays {
+		if err := validFlattenArrays(m); err != nil {
+			t.Errorf("Got unexpected error: %v", err)
+		}
+	}
+	if err := validFlattenArrays("zip"); err == nil {
+		t.Error("Expected error of unknown arrays handling")
+	}
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/logs"
+)
+
+const (
+	defaultCacheTTL  = 30 * time.Second
+	defaultRateLimit = 60
+	rateLimitWindow  = time.Minute
+	maxRateClients   = 1024 // Forget clients with expired windows above this count
+)
+
+// Address of client the request comes from
+func clientAddr(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+type cacheEntry struct {
+	res     searchResult
+	expires time.Time
+}
+
+// Search results reused for the same query and range until they expire, disabled when ttl is not positive
+type resultCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+func newResultCache(ttl time.Duration) *resultCache {
+	return &resultCache{ttl: ttl, entries: map[string]cacheEntry{}}
+}
+
+func (c *resultCache) get(key string) (searchResult, bool) {
+	if c == nil || c.ttl <= 0 {
+		return searchResult{}, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok || time.Now().After(e.expires) {
+		return searchResult{}, false
+	}
+
+	return e.res, true
+}
+
+func (c *resultCache) put(key string, res searchResult) {
+	if c == nil || c.ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for k, e := range c.entries {
+		if now.After(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+
+	c.entries[key] = cacheEntry{res: res, expires: now.Add(c.ttl)}
+}
+
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// Fixed window limit of requests per minute from one client, disabled when limit is not positive
+type rateLimiter struct {
+	limit int
+
+	mu      sync.Mutex
+	clients map[string]rateWindow
+}
+
+func newRateLimiter(limit int) *rateLimiter {
+	return &rateLimiter{limit: limit, clients: map[string]rateWindow{}}
+}
+
+// Allow request from client, otherwise return time to wait for next window
+func (l *rateLimiter) allow(client string) (bool, time.Duration) {
+	if l == nil || l.limit <= 0 {
+		return true, 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if len(l.clients) > maxRateClients {
+		for c, w := range l.clients {
+			if now.Sub(w.start) >= rateLimitWindow {
+				delete(l.clients, c)
+			}
+		}
+	}
+
+	w := l.clients[client]
+	if now.Sub(w.start) >= rateLimitWindow {
+		w = rateWindow{start: now}
+	}
+
+	if w.count >= l.limit {
+		return false, w.start.Add(rateLimitWindow).Sub(now)
+	}
+
+	w.count++
+	l.clients[client] = w
+
+	return true, 0
+}
+
+func (l *rateLimiter) wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ok, wait := l.allow(clientAddr(r)); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(wait.Seconds())+1))
+			writeJSON(w, http.StatusTooManyRequests, apiError{Error: "rate limit exceeded"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Key to recognize already sent records in overlapping tail windows
+func recordKey(l *logs.Log) string {
+	if l.ID != "" {
+		return l.ID
+	}
+	return l.Time.String() + l.UserData
+}
+
+func writeEvent(w http.ResponseWriter, event string, v any) {
+	j, _ := json.Marshal(v)
+	if event != "" {
+		fmt.Fprintf(w, "event: %s\n", event)
+	}
+	fmt.Fprintf(w, "data: %s\n\n", j)
+}
+
+// Stream new records as server-sent events, polling for them every refresh interval.
+// Windows overlap by one interval to catch records ingested late, already sent ones are skipped.
+func (s *server) handleTail(w http.ResponseWriter, r *http.Request) {
+	query, window, err := s.parseRequest(r, s.refresh)
+	if err != nil {
+		writeJSON(w, errorStatus(err), apiError{Error: err.Error()})
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	rc.Flush()
+
+	ticker := time.NewTicker(s.refresh)
+	defer ticker.Stop()
+
+	spec := s.spec
+	spec.EndDate = time.Now()
+	spec.StartDate = spec.EndDate.Add(-window)
+	sent := map[string]bool{}
+
+	for {
+		l, err := s.search(clientAddr(r), query, spec)
+		if err != nil {
+			writeEvent(w, "error", apiError{Error: err.Error()})
+		}
+
+		seen := map[string]bool{}
+		for i := range l.Logs {
+			key := recordKey(&l.Logs[i])
+			seen[key] = true
+			if !sent[key] {
+				writeEvent(w, "", newAPIRecord(&l.Logs[i]))
+			}
+		}
+		if err == nil {
+			sent = seen
+		}
+		rc.Flush()
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+		}
+
+		spec.StartDate = spec.EndDate.Add(-s.refresh)
+		spec.EndDate = time.Now()
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/logs"
+)
+
+func TestServeCache(t *testing.T) {
+	var calls []string
+	s := testServer(&calls)
+	s.cache = newResultCache(time.Minute)
+
+	for _, target := range []string{"/query?query=error", "/query?query=error", "/query?query=error&range=5m", "/?query=error"} {
+		rec := httptest.NewRecorder()
+		s.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		assert(t, rec.Code, http.StatusOK)
+	}
+
+	// Same query and range is searched only once, also from web UI
+	assert(t, len(calls), 2)
+}
+
+func TestServeRateLimit(t *testing.T) {
+	var calls []string
+	s := testServer(&calls)
+	s.limiter = newRateLimiter(2)
+
+	for i, want := range []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests} {
+		req := httptest.NewRequest(http.MethodGet, "/query?query=error", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		rec := httptest.NewRecorder()
+		s.routes().ServeHTTP(rec, req)
+		if rec.Code != want {
+			t.Errorf("Request %d: got status %d, want %d", i, rec.Code, want)
+		}
+	}
+
+	// Other client has its own limit
+	req := httptest.NewRequest(http.MethodGet, "/query?query=error", nil)
+	req.RemoteAddr = "10.0.0.2:1234"
+	rec := httptest.NewRecorder()
+	s.routes().ServeHTTP(rec, req)
+	assert(t, rec.Code, http.StatusOK)
+}
+
+func TestServeTail(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	polls := [][]logs.Log{
+		{{ID: "1", UserData: `{"message":"first"}`}},
+		{{ID: "1", UserData: `{"message":"first"}`}, {ID: "2", UserData: `{"message":"second"}`}},
+	}
+	var clients []string
+
+	s := &server{
+		search: func(client, query string, spec logs.QuerySpec) (logs.Result, error) {
+			clients = append(clients, client)
+			n := len(clients) - 1
+			if n == len(polls)-1 {
+				cancel()
+			}
+			return logs.Result{Logs: polls[n]}, nil
+		},
+		maxRange: time.Hour,
+		refresh:  time.Millisecond,
+	}
+
+	req := httptest.NewRequestWithContext(ctx, http.MethodGet, "/tail?query=error", nil)
+	rec := httptest.NewRecorder()
+	s.routes().ServeHTTP(rec, req)
+
+	assert(t, rec.Header().Get("Content-Type"), "text/event-stream")
+
+	var events []string
+	sc := bufio.NewScanner(strings.NewReader(rec.Body.String()))
+	for sc.Scan() {
+		if d, ok := strings.CutPrefix(sc.Text(), "data: "); ok {
+			events = append(events, d)
+		}
+	}
+
+	assertDeepEqual(t, events, []string{
+		`{"id":"1","time":"0001-01-01T00:00:00Z","severity":"","data":{"message":"first"}}`,
+		`{"id":"2","time":"0001-01-01T00:00:00Z","severity":"","data":{"message":"second"}}`,
+	})
+	assertDeepEqual(t, clients, []string{"192.0.2.1", "192.0.2.1"})
+}
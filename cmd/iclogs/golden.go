@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/wooyey/iclogs/internal/platform/logs"
+)
+
+// Exit status of assert command when found records differ from golden file
+const driftStatus = 1
+
+// Record compared with golden file, ID and time differ between runs of the same pipeline
+type goldenRecord struct {
+	Severity string          `json:"severity"`
+	Labels   []string        `json:"labels,omitempty"`
+	Data     json.RawMessage `json:"data"`
+}
+
+// Canonical JSON of record without ID and time, with sorted labels and user data keys
+func normalizeRecord(r apiRecord) (string, error) {
+	dec := json.NewDecoder(bytes.NewReader(r.Data))
+	dec.UseNumber()
+	var data any
+	if err := dec.Decode(&data); err != nil {
+		return "", fmt.Errorf("cannot parse record data: %w", err)
+	}
+
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(data); err != nil {
+		return "", err
+	}
+
+	labels := append([]string(nil), r.Labels...)
+	sort.Strings(labels)
+
+	b2, err := json.Marshal(goldenRecord{Severity: r.Severity, Labels: labels, Data: bytes.TrimSpace(b.Bytes())})
+	if err != nil {
+		return "", err
+	}
+
+	return string(b2), nil
+}
+
+// Normalized records of golden file, which is in export command format
+func readGolden(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open golden file: %w", err)
+	}
+	defer f.Close()
+
+	var records []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 64*1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var r apiRecord
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			return nil, fmt.Errorf("cannot parse line %d of golden file: %w", n, err)
+		}
+		s, err := normalizeRecord(r)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse line %d of golden file: %w", n, err)
+		}
+		records = append(records, s)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read golden file: %w", err)
+	}
+
+	return records, nil
+}
+
+// Records of golden file missing from found ones, and found records not in golden file, regardless of order
+func goldenDrift(golden, found []string) (missing, unexpected []string) {
+	counts := map[string]int{}
+	for _, r := range golden {
+		counts[r]++
+	}
+	for _, r := range found {
+		if counts[r] > 0 {
+			counts[r]--
+			continue
+		}
+		unexpected = append(unexpected, r)
+	}
+	for _, r := range golden {
+		if counts[r] > 0 {
+			counts[r]--
+			missing = append(missing, r)
+		}
+	}
+
+	sort.Strings(missing)
+	sort.Strings(unexpected)
+
+	return missing, unexpected
+}
+
+// Compare found records with golden file, or write them to it when updating. Returns false on drift.
+func runAssert(out io.Writer, search searchFunc, query string, spec logs.QuerySpec, golden string, update bool) (bool, error) {
+	l, err := search("", query, spec)
+	if err != nil {
+		return false, err
+	}
+
+	if update {
+		var b bytes.Buffer
+		if err := encodeRecords(&b, l.Logs); err != nil {
+			return false, err
+		}
+		if err := os.WriteFile(golden, b.Bytes(), exportFileMode); err != nil {
+			return false, fmt.Errorf("cannot write golden file: %w", err)
+		}
+		fmt.Fprintf(out, "%s: updated with %d records\n", golden, len(l.Logs))
+		return true, nil
+	}
+
+	expected, err := readGolden(golden)
+	if err != nil {
+		return false, err
+	}
+
+	found := make([]string, len(l.Logs))
+	for i := range l.Logs {
+		if found[i], err = normalizeRecord(newAPIRecord(&l.Logs[i])); err != nil {
+			return false, fmt.Errorf("cannot compare record '%s': %w", l.Logs[i].ID, err)
+		}
+	}
+
+	missing, unexpected := goldenDrift(expected, found)
+	if len(missing) == 0 && len(unexpected) == 0 {
+		fmt.Fprintf(out, "%s: %d records match\n", golden, len(found))
+		return true, nil
+	}
+
+	fmt.Fprintf(out, "%s: %d missing and %d unexpected of %d records\n", golden, len(missing), len(unexpected), len(found))
+	for _, r := range missing {
+		fmt.Fprintf(out, "- %s\n", r)
+	}
+	for _, r := range unexpected {
+		fmt.Fprintf(out, "+ %s\n", r)
+	}
+
+	return false, nil
+}
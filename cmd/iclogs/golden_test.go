@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/logs"
+)
+
+func TestGoldenDrift(t *testing.T) {
+	missing, unexpected := goldenDrift([]string{"a", "b", "b", "c"}, []string{"c", "b", "d", "a"})
+
+	assertDeepEqual(t, missing, []string{"b"})
+	assertDeepEqual(t, unexpected, []string{"d"})
+}
+
+func TestRunAssert(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	golden := filepath.Join(t.TempDir(), "expected.ndjson")
+
+	found := []logs.Log{
+		{ID: "1", Time: start, Severity: "Info", Labels: []logs.KeyValue{{Key: "applicationname", Value: "web"}}, UserData: `{"event":"started","n":1}`},
+		{ID: "2", Time: start, Severity: "Error", UserData: `{"event":"failed"}`},
+	}
+	search := func(client, query string, spec logs.QuerySpec) (logs.Result, error) {
+		return logs.Result{Logs: found}, nil
+	}
+
+	out := bytes.Buffer{}
+	ok, err := runAssert(&out, search, "q", logs.QuerySpec{}, golden, true)
+	if err != nil || !ok {
+		t.Fatalf("Got: %v, %v, want updated golden file", ok, err)
+	}
+	assert(t, out.String(), golden+": updated with 2 records\n")
+
+	// The same events in other order, with other IDs, times and key order match
+	found = []logs.Log{
+		{ID: "4", Time: start.Add(time.Hour), Severity: "Error", UserData: `{"event":"failed"}`},
+		{ID: "3", Time: start.Add(time.Hour), Severity: "Info", Labels: []logs.KeyValue{{Key: "applicationname", Value: "web"}}, UserData: `{"n":1, "event":"started"}`},
+	}
+	out.Reset()
+	ok, err = runAssert(&out, search, "q", logs.QuerySpec{}, golden, false)
+	if err != nil || !ok {
+		t.Fatalf("Got: %v, %v, want match", ok, err)
+	}
+	assert(t, out.String(), golden+": 2 records match\n")
+
+	found = []logs.Log{
+		{ID: "5", Time: start, Severity: "Error", UserData: `{"event":"failed"}`},
+		{ID: "6", Time: start, Severity: "Error", UserData: `{"event":"failed again"}`},
+	}
+	out.Reset()
+	ok, err = runAssert(&out, search, "q", logs.QuerySpec{}, golden, false)
+	if err != nil || ok {
+		t.Fatalf("Got: %v, %v, want drift", ok, err)
+	}
+	assert(t, out.String(), golden+": 1 missing and 1 unexpected of 2 records\n"+
+		`- {"severity":"Info","labels":["applicationname:\"web\""],"data":{"event":"started","n":1}}`+"\n"+
+		`+ {"severity":"Error","data":{"event":"failed again"}}`+"\n")
+
+	if err := os.WriteFile(golden, []byte("not json\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runAssert(&out, search, "q", logs.QuerySpec{}, golden, false); err == nil {
+		t.Error("Expected error of invalid golden file")
+	}
+}
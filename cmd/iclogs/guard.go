@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/wooyey/iclogs/internal/platform/logs"
+)
+
+const defaultMaxResponse = 1 << 30 // Response size asked for confirmation - default 1GiB
+
+var (
+	errInvalidSize   = errors.New("size needs to be positive, ie. 500MB or 2GiB")
+	errLargeResponse = errors.New("query response is larger than --max-response, confirm it with --force")
+)
+
+// Bytes from size option, 0 means default
+type byteSize int64
+
+func (b *byteSize) String() string {
+	if *b == 0 {
+		return ""
+	}
+	return strconv.FormatInt(int64(*b), 10) + "B"
+}
+
+func (b *byteSize) Set(value string) error {
+	n, ok := parseBytes(value)
+	if !ok {
+		return errInvalidSize
+	}
+	*b = byteSize(n)
+
+	return nil
+}
+
+// Guard of query responses growing over max bytes, none when forced. Answers are lines typed on terminal,
+// without them, ie. when standard input is not terminal, large response is not confirmed.
+func guardResponse(ctx context.Context, out io.Writer, max byteSize, force bool, answers <-chan string) *logs.ResponseGuard {
+	if force {
+		return nil
+	}
+
+	if max == 0 {
+		max = defaultMaxResponse
+	}
+
+	return &logs.ResponseGuard{Max: int64(max), Confirm: func(size int64, records int) error {
+		return confirmResponse(ctx, out, size, records, answers)
+	}}
+}
+
+// Ask for confirmation of large response when interactive, otherwise it needs to be forced up front
+func confirmResponse(ctx context.Context, out io.Writer, size int64, records int, answers <-chan string) error {
+	fmt.Fprintf(out, "Query response is larger than %.1f MB already, with %d records so far.\n", float64(size)/1e6, records)
+	if answers == nil {
+		return errLargeResponse
+	}
+
+	fmt.Fprint(out, "Continue? [y/N] ")
+	select {
+	case answer := <-answers:
+		if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+			return errLargeResponse
+		}
+	case <-ctx.Done():
+		return errLargeResponse
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestByteSize(t *testing.T) {
+	var b byteSize
+	assertError(t, b.Set("500MB"), nil)
+	assert(t, b, byteSize(500*1000*1000))
+	assertError(t, b.Set("2GiB"), nil)
+	assert(t, b.String(), "2147483648B")
+	assertError(t, b.Set("0MB"), errInvalidSize)
+	assertError(t, b.Set("lots"), errInvalidSize)
+}
+
+func TestConfirmResponse(t *testing.T) {
+	answered := func(a string) <-chan string {
+		c := make(chan string, 1)
+		c <- a
+		return c
+	}
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	testCases := []struct {
+		name    string
+		ctx     context.Context
+		answers <-chan string
+		want    error
+	}{
+		{name: "Yes", ctx: context.Background(), answers: answered("y"), want: nil},
+		{name: "No", ctx: context.Background(), answers: answered(""), want: errLargeResponse},
+		{name: "NotInteractive", ctx: context.Background(), answers: nil, want: errLargeResponse},
+		{name: "Stopped", ctx: cancelled, answers: make(chan string), want: errLargeResponse},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			assertError(t, confirmResponse(tc.ctx, out, 1500*1000*1000, 42, tc.answers), tc.want)
+			assert(t, bytes.HasPrefix(out.Bytes(), []byte("Query response is larger than 1500.0 MB already, with 42 records so far.\n")), true)
+		})
+	}
+}
+
+func TestGuardResponse(t *testing.T) {
+	g := guardResponse(context.Background(), &bytes.Buffer{}, 0, false, nil)
+	assert(t, g.Max, int64(defaultMaxResponse))
+	assertError(t, g.Confirm(defaultMaxResponse+1, 1), errLargeResponse)
+
+	assert(t, guardResponse(context.Background(), &bytes.Buffer{}, 1000, true, nil) == nil, true)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/config"
+	"github.com/wooyey/iclogs/internal/platform/logs"
+)
+
+var errGuardrail = errors.New("narrow the query or run it with --override")
+
+// Check query against maximum range and records budget of the profile, and cap records limit to the budget.
+// Records are only rejected up front when past runs of the query allow to estimate them.
+func applyGuardrails(p config.Profile, spec *logs.QuerySpec, c costEstimate, override bool) error {
+	if override {
+		return nil
+	}
+
+	if p.MaxRange != "" {
+		max, err := time.ParseDuration(p.MaxRange)
+		if err != nil {
+			return fmt.Errorf("invalid maximum range of profile: %w", err)
+		}
+		if c.window > max {
+			return fmt.Errorf("query window %s exceeds profile maximum %s, %w", c.window.Round(time.Minute), max, errGuardrail)
+		}
+	}
+
+	if p.MaxRecords > 0 {
+		if c.samples > 0 && c.records() > p.MaxRecords {
+			return fmt.Errorf("~%d records expected exceed profile budget %d, %w", c.records(), p.MaxRecords, errGuardrail)
+		}
+		if spec.Limit == 0 || spec.Limit > p.MaxRecords {
+			spec.Limit = p.MaxRecords
+		}
+	}
+
+	return nil
+}
+
+// Longest query window served to clients, capped by maximum range of the profile
+func maxQueryRange(args *CmdArgs, p config.Profile) time.Duration {
+	if max, err := time.ParseDuration(p.MaxRange); err == nil && !args.Override {
+		return min(args.TimeRange, max)
+	}
+
+	return args.TimeRange
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/config"
+	"github.com/wooyey/iclogs/internal/platform/logs"
+	"github.com/wooyey/iclogs/internal/platform/logs/tier"
+)
+
+func TestApplyGuardrails(t *testing.T) {
+	day := costEstimate{tier: tier.Archive, window: 24 * time.Hour, limit: tier.LimitArchive}
+	busy := day
+	busy.rate, busy.samples = 1000, 3
+
+	tests := []struct {
+		name     string
+		profile  config.Profile
+		cost     costEstimate
+		override bool
+		limit    int
+		err      error
+	}{
+		{name: "No guardrails", cost: busy, limit: tier.LimitArchive},
+		{name: "Within range", profile: config.Profile{MaxRange: "48h"}, cost: day, limit: tier.LimitArchive},
+		{name: "Range exceeded", profile: config.Profile{MaxRange: "12h"}, cost: day, limit: tier.LimitArchive, err: errGuardrail},
+		{name: "Range overridden", profile: config.Profile{MaxRange: "12h"}, cost: day, override: true, limit: tier.LimitArchive},
+		{name: "Limit capped", profile: config.Profile{MaxRecords: 1000}, cost: day, limit: 1000},
+		{name: "Budget exceeded", profile: config.Profile{MaxRecords: 1000}, cost: busy, limit: tier.LimitArchive, err: errGuardrail},
+		{name: "Budget overridden", profile: config.Profile{MaxRecords: 1000}, cost: busy, override: true, limit: tier.LimitArchive},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			spec := logs.QuerySpec{Limit: tier.LimitArchive}
+			err := applyGuardrails(tc.profile, &spec, tc.cost, tc.override)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("Got error: %v, want: %v", err, tc.err)
+			}
+			assert(t, spec.Limit, tc.limit)
+		})
+	}
+
+	spec := logs.QuerySpec{}
+	if err := applyGuardrails(config.Profile{MaxRange: "week"}, &spec, day, false); err == nil {
+		t.Error("Expected error for invalid maximum range")
+	}
+}
+
+func TestMaxQueryRange(t *testing.T) {
+	args := &CmdArgs{TimeRange: 24 * time.Hour}
+	assert(t, maxQueryRange(args, config.Profile{}), 24*time.Hour)
+	assert(t, maxQueryRange(args, config.Profile{MaxRange: "1h"}), time.Hour)
+	assert(t, maxQueryRange(args, config.Profile{MaxRange: "48h"}), 24*time.Hour)
+
+	args.Override = true
+	assert(t, maxQueryRange(args, config.Profile{MaxRange: "1h"}), 24*time.Hour)
+}
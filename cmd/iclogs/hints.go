@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/i18n"
+	"github.com/wooyey/iclogs/internal/platform/logs"
+)
+
+// What is known about search which found no records, to point at the likely cause
+type hintContext struct {
+	spec      logs.QuerySpec
+	now       time.Time
+	warnings  []string // Returned by the service
+	scope     string   // Profile scope ANDed with the query
+	retention string   // Of the instance data from profile, ie. `720h`, unknown when empty
+	fetched   int      // Records returned by the service, before client-side filters
+	filtered  bool     // Client-side filters are used
+	layout    timeLayout
+}
+
+// Likely causes of search without results
+func noResultsHints(c hintContext) []string {
+	var hints []string
+
+	if c.spec.StartDate.After(c.now) {
+		hints = append(hints, i18n.Sprintf("time range starts in the future (%s), check --from, --to and --window", c.layout.format(c.spec.StartDate)))
+	}
+
+	if c.retention != "" {
+		retention, err := time.ParseDuration(c.retention)
+		if err != nil {
+			hints = append(hints, i18n.Sprintf("invalid retention of profile: %v", err))
+		} else if c.spec.EndDate.Before(c.now.Add(-retention)) {
+			hints = append(hints, i18n.Sprintf("time range ends before retention of %s tier (%s), records are no longer kept", c.spec.Tier, retention))
+		}
+	}
+
+	for _, w := range c.warnings {
+		hints = append(hints, i18n.Sprintf("service warned about the query: %s", w))
+	}
+
+	if c.fetched > 0 && c.filtered {
+		hints = append(hints, i18n.Sprintf("%d record(s) found were removed by client-side filters (--where, --ip-in)", c.fetched))
+	}
+
+	if c.scope = strings.TrimSpace(c.scope); c.scope != "" && c.fetched == 0 {
+		hints = append(hints, i18n.Sprintf("profile scope '%s' is ANDed with the query, records outside of it are not searched", c.scope))
+	}
+
+	return hints
+}
+
+func printHints(w io.Writer, hints []string) {
+	fmt.Fprintln(w, i18n.T("No records found, hints:"))
+	for _, h := range hints {
+		fmt.Fprintf(w, "- %s\n", h)
+	}
+}
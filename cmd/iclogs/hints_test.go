@@ -0,0 +1,46 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/logs"
+	"github.com/wooyey/iclogs/internal/platform/logs/tier"
+)
+
+func TestNoResultsHints(t *testing.T) {
+	now := time.Date(2025, 1, 11, 18, 30, 0, 0, time.UTC)
+	spec := func(start, end time.Time) logs.QuerySpec {
+		return logs.QuerySpec{StartDate: start, EndDate: end, Tier: tier.Archive}
+	}
+	lastHour := spec(now.Add(-time.Hour), now)
+
+	testCases := []struct {
+		name string
+		ctx  hintContext
+		want []string
+	}{
+		{name: "None", ctx: hintContext{spec: lastHour, now: now}},
+		{name: "Future", ctx: hintContext{spec: spec(now.Add(time.Hour), now.Add(2*time.Hour)), now: now}, want: []string{"starts in the future"}},
+		{name: "Retention", ctx: hintContext{spec: spec(now.Add(-50*24*time.Hour), now.Add(-40*24*time.Hour)), now: now, retention: "720h"}, want: []string{"retention of archive tier (720h0m0s)"}},
+		{name: "WithinRetention", ctx: hintContext{spec: lastHour, now: now, retention: "720h"}},
+		{name: "InvalidRetention", ctx: hintContext{spec: lastHour, now: now, retention: "month"}, want: []string{"invalid retention"}},
+		{name: "Warnings", ctx: hintContext{spec: lastHour, now: now, warnings: []string{"unknown field 'json.usr'"}}, want: []string{"unknown field 'json.usr'"}},
+		{name: "Filtered", ctx: hintContext{spec: lastHour, now: now, fetched: 12, filtered: true}, want: []string{"12 record(s) found were removed"}},
+		{name: "Scope", ctx: hintContext{spec: lastHour, now: now, scope: "app:web"}, want: []string{"scope 'app:web'"}},
+		{name: "ScopeMatched", ctx: hintContext{spec: lastHour, now: now, scope: "app:web", fetched: 3, filtered: true}, want: []string{"3 record(s)"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := noResultsHints(tc.ctx)
+			assert(t, len(got), len(tc.want))
+			for i, w := range tc.want {
+				if i < len(got) && !strings.Contains(got[i], w) {
+					t.Errorf("Got hint: %q, want containing: %q", got[i], w)
+				}
+			}
+		})
+	}
+}
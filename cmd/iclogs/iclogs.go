@@ -1,46 +1,200 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"flag"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
+	"net/http"
+	"net/netip"
 	"os"
+	"os/exec"
 	"reflect"
+	"runtime"
+	"slices"
 	"sort"
 	"strings"
 	"time"
-
-	"github.com/wooyey/iclogs/internal/platform/auth"
+	"unicode/utf8"
+
+	"github.com/wooyey/iclogs/internal/platform/api"
+	"github.com/wooyey/iclogs/internal/platform/audit"
+	"github.com/wooyey/iclogs/internal/platform/clipboard"
+	"github.com/wooyey/iclogs/internal/platform/config"
+	"github.com/wooyey/iclogs/internal/platform/crash"
+	"github.com/wooyey/iclogs/internal/platform/dashboard"
+	"github.com/wooyey/iclogs/internal/platform/encrypt"
+	"github.com/wooyey/iclogs/internal/platform/endpoint"
+	"github.com/wooyey/iclogs/internal/platform/enrich"
+	"github.com/wooyey/iclogs/internal/platform/geoip"
+	"github.com/wooyey/iclogs/internal/platform/i18n"
+	"github.com/wooyey/iclogs/internal/platform/library"
 	"github.com/wooyey/iclogs/internal/platform/logs"
+	"github.com/wooyey/iclogs/internal/platform/logs/filter"
 	"github.com/wooyey/iclogs/internal/platform/logs/syntax"
 	"github.com/wooyey/iclogs/internal/platform/logs/tier"
+	"github.com/wooyey/iclogs/internal/platform/notify"
+	"github.com/wooyey/iclogs/internal/platform/pin"
+	"github.com/wooyey/iclogs/internal/platform/plugin"
+	"github.com/wooyey/iclogs/internal/platform/redact"
+	"github.com/wooyey/iclogs/internal/platform/schema"
+	"github.com/wooyey/iclogs/internal/platform/secrets"
+	"github.com/wooyey/iclogs/internal/platform/stats"
+	"github.com/wooyey/iclogs/pkg/query"
 )
 
 const (
 	timeFormat       = "2006-01-02T15:04"
 	timeStampFormat  = "2006-01-02 15:04:05"
+	dateFormat       = "2006-01-02"
 	defaultTimeRange = time.Hour
 )
 
 const defaultIAMURL = "https://iam.cloud.ibm.com"
 const defaultKeyNames = "message,message_obj.msg,log"
 const versionString = "iclogs version %s"
+const querySeparator = "--"
+const jsonFieldPrefix = "json."
+const truncatedMarker = "… [%d bytes truncated]"
+const geoipKey = "geoip"
+
+// Commands, running without command means logs search
+const (
+	commandGet     = "get"
+	commandOpen    = "open"
+	commandDash    = "dash"
+	commandServe   = "serve"
+	commandMCP     = "mcp"
+	commandSlack   = "slackbot"
+	commandWatch   = "watch"
+	commandExport  = "export"
+	commandConfig  = "config"
+	commandPlugins = "plugins"
+	commandBug     = "bugreport"
+	commandAuth    = "auth"
+	commandLabels  = "labels"
+	commandSnips   = "snippets"
+	commandAssert  = "assert"
+	commandIngest  = "ingest"
+	commandPush    = "push"
+	commandShip    = "ship"
+	commandVerify  = "verify-pipeline"
+	commandUsage   = "usage"
+	commandPolicy  = "policies"
+	commandEnrich  = "enrichments"
+	commandE2M     = "e2m"
+	commandHooks   = "webhooks"
+	commandAPI     = "api"
+	commandContext = "context"
+	commandDoctor  = "doctor"
+	commandDocs    = "docs"
+	commandSummary = "summarize"
+)
+
+type command struct {
+	args  string
+	usage string
+}
+
+var commands = map[string]command{
+	commandGet:     {args: "<record id>", usage: "Print one full record by its ID. Time range options need to cover record timestamp."},
+	commandOpen:    {args: "<lucene query>", usage: "Open the search in IBM Cloud Logs dashboard using default browser."},
+	commandDash:    {args: "<lucene query>", usage: "Show terminal dashboard (rate per severity, top applications, latest errors) refreshed until interrupted."},
+	commandMCP:     {usage: "Serve read-only query, tail and stats tools over Model Context Protocol (stdio) within profile scope and time range."},
+	commandSlack:   {usage: "Serve Slack slash command (/slack/commands) and mentions (/slack/events) running saved queries allowed in configuration."},
+	commandWatch:   {args: "<lucene query>", usage: "Count records every refresh interval, notifying when count goes above threshold or trigger expression holds and when it clears."},
+	commandExport:  {args: "<lucene query>", usage: "Write found records as JSON lines or CSV files, one per time range chunk, optionally uploaded to object storage."},
+	commandSummary: {args: "<lucene query>", usage: "Report incident window at once: records stats, timeline per severity, top applications (or other --group-by field) and top message patterns with their first and last occurrence."},
+	commandAssert:  {args: "<lucene query>", usage: "Compare found records, without their ID and time, with --golden file in export format, exiting with status 1 on drift."},
+	commandIngest:  {usage: "Send test record with --message through ingestion API of the logs instance, to verify ingestion and search end-to-end."},
+	commandPush:    {usage: "Forward lines or NDJSON read from standard input to ingestion API of the logs instance, labeled with --app and --subsystem."},
+	commandShip:    {usage: "Tail --file paths and forward appended lines to ingestion API of the logs instance until interrupted, resuming from checkpointed positions."},
+	commandVerify:  {usage: "Send uniquely tagged record through ingestion API and search for it until found or --verify-timeout, reporting end-to-end latency."},
+	commandUsage:   {args: "[last_week|current_month|last_30_days|last_90_days]", usage: "Report ingested GB per day and TCO policy tier from data usage API, last week by default."},
+	commandAPI:     {args: "<method> <path>", usage: "Call management API of the instance with the same token, ie. GET /v1/alerts, printing response JSON. For endpoints without own command."},
+	commandHooks:   {args: "list|test <id>", usage: "List outbound integrations of the instance, or send test notification to URL of one, verifying alert notification channel."},
+	commandE2M:     {args: "list|show <id>", usage: "List events-to-metrics definitions of the instance with their query and metrics, or show one with its filters, labels and fields."},
+	commandDoctor:  {usage: "Check connectivity to IAM, logs and ingestion endpoints, explaining private endpoints unreachable from current network."},
+	commandEnrich:  {args: "list", usage: "List enrichments of the instance: enriched record fields with enrichment type (geo_ip, suspicious_ip or custom)."},
+	commandPolicy:  {args: "list", usage: "List TCO policies of the instance in evaluation order with tier (priority insights, analyze and alert, store and search) of matched records."},
+	commandLabels:  {args: "[application|subsystem]", usage: "List distinct application and subsystem label values found in time range, or cached values of one label for shell completion."},
+	commandSnips:   {usage: "List built-in Dataprime snippets usable with --snippet option, with their parameters in braces."},
+	commandAuth:    {args: "whoami", usage: "Print identity, account, expiry and scopes of IAM token obtained for the API key."},
+	commandBug:     {usage: "Print issue-ready report with version, platform and the latest crash diagnostic report."},
+	commandConfig:  {args: "export|import <bundle.tar.gz>", usage: "Export profiles, saved queries, aliases and redactors (without audit settings) as bundle, or merge bundle into configuration file."},
+	commandContext: {args: "list|current|use <profile>", usage: "List profiles with their account and endpoints, print profile in use, or persist default profile like kubectl contexts."},
+	commandDocs:    {args: "man|markdown", usage: "Print reference of commands and options as man page or Markdown, generated from the same metadata as this usage."},
+	commandPlugins: {usage: "List output sink plugins found in plugins directory, usable with --sink option."},
+	commandServe:   {usage: "Serve web UI and REST API (/query and /tail with server-sent events) running searches within profile scope and time range."},
+}
+
+// Browser opening command per OS
+var browserCommands = map[string][]string{
+	"darwin":  {"open"},
+	"windows": {"rundll32", "url.dll,FileProtocolHandler"},
+}
+
+var defaultBrowserCommand = []string{"xdg-open"}
 
 // Possible errors list for easier testing later on
 var (
-	errMissingURL    = errors.New("you need to provide IBM Cloud Logs endpoint URL")
-	errMissingAPIKey = errors.New("you need to provide API key")
-	errMissingQuery  = errors.New("you need to provide logs query string")
-	errUnknownFlag   = errors.New("unknown type of flag value")
+	errMissingURL      = errors.New("you need to provide IBM Cloud Logs endpoint URL")
+	errMissingAPIKey   = errors.New("you need to provide API key")
+	errMissingQuery    = errors.New("you need to provide logs query string")
+	errMissingID       = errors.New("you need to provide record ID")
+	errMissingEnrich   = errors.New("you need to provide lookup key field for enrichment")
+	errMissingGeoIP    = errors.New("you need to provide IP address field for GeoIP enrichment")
+	errMissingIPField  = errors.New("you need to provide IP address field for network filter")
+	errMissingDuration = errors.New("you need to provide duration expression for percentiles")
+	errInvalidRefresh  = errors.New("refresh interval needs to be positive")
+	errNegativeRange   = errors.New("time range cannot be negative")
+	errInvertedRange   = errors.New("start time needs to be before end time")
+	errMissingSlack    = errors.New("you need to provide Slack signing secret and bot token")
+	errMissingStorage  = errors.New("you need to provide object storage endpoint for upload")
+	errLowMemory       = errors.New("low memory mode cannot be used with percentiles, patterns, aggregations, histogram or copy")
+	errUnknownFlag     = errors.New("unknown type of flag value")
+	errMissingGolden   = errors.New("you need to provide golden file for assert command")
 )
 
 // Should be set in compile time
 var version string
 
+// Parse time option as local time, with optional seconds and their fraction, as RFC3339 time with explicit zone,
+// as date or keyword relative to current time
 func parseTime(t string) (time.Time, error) {
-	return time.ParseInLocation(timeFormat, t, time.Local)
+	return parseTimeAt(t, time.Now())
+}
+
+// Parse time option, keywords `now`, `today` and `yesterday` are resolved against given time.
+// Days of keywords and dates without time start at local midnight.
+func parseTimeAt(t string, now time.Time) (time.Time, error) {
+	y, m, d := now.In(time.Local).Date()
+
+	switch t {
+	case "now":
+		return explicitZone(now), nil
+	case "today":
+		return time.Date(y, m, d, 0, 0, 0, 0, time.Local), nil
+	case "yesterday":
+		return time.Date(y, m, d-1, 0, 0, 0, 0, time.Local), nil
+	}
+
+	if pt, err := time.Parse(time.RFC3339Nano, t); err == nil {
+		return explicitZone(pt), nil
+	}
+
+	if pt, err := time.ParseInLocation(dateFormat, t, time.Local); err == nil {
+		return pt, nil
+	}
+
+	if pt, err := time.ParseInLocation(timeFormat, t, time.Local); err == nil {
+		return pt, nil
+	}
+
+	// Fraction of seconds is accepted after seconds in parsed value
+	return time.ParseInLocation(timeFormat+":05", t, time.Local)
 }
 
 type timestamp time.Time
@@ -58,9 +212,22 @@ func (t *timestamp) Set(value string) error {
 	return nil
 }
 
+// List of queries from repeated flag
+type queries []string
+
+func (q *queries) String() string {
+	return strings.Join(*q, ", ")
+}
+
+func (q *queries) Set(value string) error {
+	*q = append(*q, value)
+	return nil
+}
+
 // CmdArgs includes all options
 // need to have exportable fields for reflect ...
 type CmdArgs struct {
+	Command   string
 	APIKey    string `env:"LOGS_API_KEY"`
 	TimeRange time.Duration
 	LogsURL   string `env:"LOGS_ENDPOINT"`
@@ -68,12 +235,105 @@ type CmdArgs struct {
 	StartTime timestamp
 	EndTime   timestamp
 	Query     string
+	Queries   queries
 	Version   bool
 	JSON      bool
 	Labels    bool
 	Severity  bool
 	Timestamp bool
 	KeyNames  string
+	Where     string
+	Config    string `env:"ICLOGS_CONFIG"`
+	Profile   string `env:"ICLOGS_PROFILE"`
+	Redact    string
+	Secrets   bool
+	MaxBytes  int
+	ShowID    bool
+	Link      bool
+	Copy      bool
+	Enrich    string
+	EnrichKey string
+	GeoIP     string
+	GeoField  string
+	IPIn      string
+	IPField   string
+
+	ExtractDuration string
+	DurationUnit    time.Duration
+	Percentiles     bool
+	Agg             string
+	GroupBy         string
+	Histogram       bool
+	Refresh         time.Duration
+	Listen          string
+	CacheTTL        time.Duration
+	RateLimit       int
+	Saved           string
+	SlackSecret     string `env:"SLACK_SIGNING_SECRET"`
+	SlackToken      string `env:"SLACK_BOT_TOKEN"`
+	Threshold       int
+	PagerDuty       string
+	Opsgenie        string
+	Output          string
+	Chunk           time.Duration
+	Upload          string
+	StorageURL      string
+	UploadSSE       string
+	Encrypt         string
+	RefreshQueries  bool
+	Sink            string
+	Summary         bool
+	LowMemory       bool
+	Yes             bool
+	Override        bool
+	Session         string
+	UTC             bool
+	Precision       string
+	Align           time.Duration
+	Windows         windows
+	Daily           string
+	Days            int
+	Deadline        time.Duration
+	IdleTimeout     time.Duration
+	Progress        string
+	Snippet         string
+	Params          params
+	Compact         bool
+	ShowLabel       labelKeys
+	DropFields      string
+	Flatten         bool
+	FlattenArrays   string
+	ExportFormat    string
+	Schema          string
+	SchemaSample    int
+	Golden          string
+	UpdateGolden    bool
+	Message         string
+	App             string
+	Subsystem       string
+	IngestSeverity  string
+	IngressURL      string
+	ShipFiles       shipFiles
+	Checkpoint      string
+	VerifyTimeout   time.Duration
+	UsageCSV        bool
+	Data            string
+	RefreshCache    bool
+	Private         bool
+	MaxBandwidth    bandwidth
+	Retries         int
+	Verbose         bool
+	Debug           bool
+	Explain         bool
+	MaxResponse     byteSize
+	Force           bool
+	FallbackURL     string
+	When            string
+	Silences        silences
+	SilenceFile     string
+	Patterns        bool
+	RangeSet        bool       // Range was given, by --range or saved query, not just defaulted
+	Times           timeLayout // Of displayed times, by --precision and --utc
 }
 
 // Set CmdArgs structure annotated elements with environment variable values if exists
@@ -105,6 +365,8 @@ func addFlagsVar(value interface{}, names []string, usage string, defaultValue i
 			flag.Var(v, name, usage)
 		case *bool:
 			flag.BoolVar(v, name, defaultValue.(bool), usage)
+		case *int:
+			flag.IntVar(v, name, defaultValue.(int), usage)
 		default:
 			return errUnknownFlag
 		}
@@ -112,14 +374,28 @@ func addFlagsVar(value interface{}, names []string, usage string, defaultValue i
 	return nil
 }
 
-func printUsage(w io.Writer) {
-	fmt.Fprintf(w, "Usage of %s: [options] <lucene query>\n\n", os.Args[0])
+// Option of the command line, flag names sharing the same usage are one option
+type option struct {
+	names    []string // Flag names sorted by length, with dashes
+	name     string   // Type name of the value
+	usage    string
+	defValue string // Default value, empty when it is zero value
+}
+
+// Sorted command names
+func commandNames() []string {
+	names := make([]string, 0, len(commands))
+	for n := range commands {
+		names = append(names, n)
+	}
+	sort.Strings(names)
 
-	args := map[string]struct {
-		names    []string
-		name     string
-		defValue string
-	}{}
+	return names
+}
+
+// Options of the command line in alphabetical order of flag names
+func options() []option {
+	args := map[string]option{}
 
 	flag.VisitAll(func(f *flag.Flag) {
 		name, usage := flag.UnquoteUsage(f)
@@ -134,6 +410,7 @@ func printUsage(w io.Writer) {
 		})
 
 		option.name = name
+		option.usage = usage
 
 		// almost copy pasta from flag to check zero value of default value
 		typ := reflect.TypeOf(f.Value)
@@ -152,62 +429,165 @@ func printUsage(w io.Writer) {
 		args[usage] = option
 	})
 
-	keys := make([]string, 0, len(args))
-	for k := range args {
-		keys = append(keys, k)
+	opts := make([]option, 0, len(args))
+	for _, o := range args {
+		opts = append(opts, o)
 	}
 
 	// Sort printout in alphabetical order of flag names
-	sort.SliceStable(keys, func(i, j int) bool {
-		return args[keys[i]].names[0] < args[keys[j]].names[0]
+	sort.SliceStable(opts, func(i, j int) bool {
+		return opts[i].names[0] < opts[j].names[0]
 	})
 
-	for _, k := range keys {
-
-		// Add proper number of dashes to options
-		names := make([]string, len(args[k].names))
-		for i, n := range args[k].names {
+	// Add proper number of dashes to options
+	for _, o := range opts {
+		for i, n := range o.names {
 			if len(n) > 1 {
-				names[i] = "--" + n
+				o.names[i] = "--" + n
 			} else {
-				names[i] = "-" + n
+				o.names[i] = "-" + n
 			}
 		}
+	}
+
+	return opts
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintf(w, "%s\n\n", i18n.Sprintf("Usage of %s: [command] [options] <lucene query> [-- <lucene query> ...]", os.Args[0]))
+
+	fmt.Fprintln(w, i18n.T("Commands:"))
+	for _, n := range commandNames() {
+		fmt.Fprintf(w, "  %s\n        %s\n", strings.TrimSpace(n+" "+commands[n].args), i18n.T(commands[n].usage))
+	}
+
+	fmt.Fprintf(w, "\n%s\n", i18n.T("Options:"))
+
+	for _, o := range options() {
 
 		// flags
-		fmt.Fprintf(w, "  %s", strings.Join(names, ", "))
+		fmt.Fprintf(w, "  %s", strings.Join(o.names, ", "))
 
 		// type names
-		if args[k].name != "" {
-			fmt.Fprintf(w, " %s", args[k].name)
+		if o.name != "" {
+			fmt.Fprintf(w, " %s", o.name)
 		}
 
 		// usage
-		fmt.Fprintf(w, "\n        %s", k)
-		if args[k].defValue != "" {
-			fmt.Fprintf(w, " (default %s)", args[k].defValue)
+		fmt.Fprintf(w, "\n        %s", i18n.T(o.usage))
+		if o.defValue != "" {
+			fmt.Fprint(w, i18n.Sprintf(" (default %s)", o.defValue))
 		}
 		fmt.Fprint(w, "\n")
 	}
 
 }
 
-// Configure command line arguments parsing
 func initParser(args *CmdArgs) {
 	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
 
 	addFlagsVar(&args.APIKey, []string{"key", "k"}, "API Key to use. Overrides `LOG_API_KEY` environment variable.", "")
-	addFlagsVar(&args.AuthURL, []string{"auth-url", "a"}, "Authorization Endpoint URL.", defaultIAMURL)
+	addFlagsVar(&args.Config, []string{"config", "c"}, "Configuration file path. Overrides `ICLOGS_CONFIG` environment variable.", "")
+	addFlagsVar(&args.AuthURL, []string{"auth-url", "a"}, "Authorization Endpoint URL, or public, private or test IAM endpoint.", defaultIAMURL)
 	addFlagsVar(&args.LogsURL, []string{"logs-url", "l"}, "URL of IBM Cloud Log Endpoint. Overrides `LOGS_ENDPOINT` environment variable.", "")
-	addFlagsVar(&args.TimeRange, []string{"range", "r"}, "Relative time for log search, from now (or from end time if specified).", defaultTimeRange)
-	addFlagsVar(&args.StartTime, []string{"from", "f"}, "Start time for log search in format `"+timeFormat+"`.", nil)
+	addFlagsVar(&args.FallbackURL, []string{"fallback-url"}, "Logs endpoint URL failed over to when logs endpoint cannot be connected, ie. public one of private endpoint.", "")
+	addFlagsVar(&args.TimeRange, []string{"range", "r"}, "Relative time for log search, before now or end time, or after start time when only it is given.", defaultTimeRange)
+	addFlagsVar(&args.Copy, []string{"copy"}, "Copy printed records to system clipboard.", false)
+	addFlagsVar(&args.Enrich, []string{"enrich"}, "CSV or JSON lookup table `file` joined onto records as enrichment user data object.", "")
+	addFlagsVar(&args.EnrichKey, []string{"enrich-key"}, "Record `field` used as lookup key for enrichment, ie. json.node_name.", "")
+	addFlagsVar(&args.GeoIP, []string{"geoip"}, "MaxMind DB `file` (ie. GeoLite2 Country or ASN) for GeoIP enrichment.", "")
+	addFlagsVar(&args.GeoField, []string{"geoip-field"}, "Record `field` with IP address for GeoIP enrichment, ie. json.client_ip.", "")
+	addFlagsVar(&args.IPIn, []string{"ip-in"}, "Keep records with IP address within comma separated `networks`, ie. 10.0.0.0/8,192.168.0.0/16.", "")
+	addFlagsVar(&args.IPField, []string{"ip-field"}, "Record `field` with IP address for network filter, ie. json.client_ip.", "")
+	addFlagsVar(&args.ExtractDuration, []string{"extract-duration"}, "Regular `expression` with capture group matched on message (ie. 'took (\\d+)ms') or record field with duration.", "")
+	addFlagsVar(&args.DurationUnit, []string{"duration-unit"}, "Unit of extracted durations given without one.", defaultDurationUnit)
+	addFlagsVar(&args.Percentiles, []string{"percentiles"}, "Show percentiles of extracted durations instead of records.", false)
+	addFlagsVar(&args.Agg, []string{"agg"}, "Show comma separated `aggregations` (sum, avg, min, max) of numeric fields instead of records, ie. avg(json.response_time),max(json.bytes).", "")
+	addFlagsVar(&args.GroupBy, []string{"group-by"}, "Record `field` to group records count and aggregations by, ie. json.service.", "")
+	addFlagsVar(&args.Patterns, []string{"patterns"}, "Show message patterns, messages with numbers, IDs, addresses and times masked, with records count and first and last occurrence instead of records.", false)
+	addFlagsVar(&args.Histogram, []string{"histogram"}, "Show sparkline of records volume over time range next to each group.", false)
+	addFlagsVar(&args.Refresh, []string{"refresh"}, "Refresh interval of dash and watch commands and serve command tail.", defaultRefresh)
+	addFlagsVar(&args.Output, []string{"output", "o"}, "Output `directory` of export command files.", defaultOutput)
+	addFlagsVar(&args.Chunk, []string{"chunk"}, "Time range of one export command file, 0 means whole time range.", time.Duration(0))
+	addFlagsVar(&args.Encrypt, []string{"encrypt"}, "Encrypt export command files before writing with `method` age:<recipients file> or gpg:<recipient>.", "")
+	addFlagsVar(&args.Golden, []string{"golden"}, "Golden `file` of assert command with expected records in export format.", "")
+	addFlagsVar(&args.UpdateGolden, []string{"update-golden"}, "Write found records to golden file of assert command instead of comparing them.", false)
+	addFlagsVar(&args.Message, []string{"message"}, "Message `text` of record sent by ingest command, JSON object is sent as structured data.", "")
+	addFlagsVar(&args.App, []string{"app"}, "Application `name` of records sent by ingest, push, ship and verify-pipeline commands.", defaultIngestApp)
+	addFlagsVar(&args.Subsystem, []string{"subsystem"}, "Subsystem `name` of records sent by ingest, push, ship and verify-pipeline commands.", defaultIngestSubsystem)
+	addFlagsVar(&args.IngestSeverity, []string{"severity"}, "Severity `name` of records sent by ingest, push, ship and verify-pipeline commands: debug, verbose, info, warning, error or critical. JSON lines pushed with severity or level field keep their own.", defaultIngestSeverity)
+	addFlagsVar(&args.IngressURL, []string{"ingress-url"}, "Ingestion endpoint `URL` of ingest, push, ship and verify-pipeline commands, derived from logs endpoint by default.", "")
+	addFlagsVar(&args.ShipFiles, []string{"file"}, "Log `path` tailed by ship command. Can be repeated.", nil)
+	addFlagsVar(&args.VerifyTimeout, []string{"verify-timeout"}, "Time verify-pipeline command waits for sent record to be found.", defaultVerifyTimeout)
+	addFlagsVar(&args.Data, []string{"data"}, "JSON request `body` of api command, @file reads it from file and @- from standard input.", "")
+	addFlagsVar(&args.RefreshCache, []string{"refresh-cache"}, "Fetch management API listings and label values instead of using cached ones.", false)
+	addFlagsVar(&args.MaxBandwidth, []string{"max-bandwidth"}, "Limit reading of export command responses to `rate`, ie. 10MB/s or 512KiB/s, so exports don't saturate VPN links.", nil)
+	addFlagsVar(&args.Retries, []string{"retries"}, "Retries of failed export command query, each querying failed time window in halves.", defaultRetries)
+	addFlagsVar(&args.Verbose, []string{"verbose"}, "Log debug messages of internal steps, ie. queries run and tokens obtained, to standard error.", false)
+	addFlagsVar(&args.Debug, []string{"debug"}, "Log debug messages like --verbose, with their source code location.", false)
+	addFlagsVar(&args.Explain, []string{"explain"}, "Print resolved query request, endpoint, tier, time window in UTC and token subject without running it, for support tickets.", false)
+	addFlagsVar(&args.MaxResponse, []string{"max-response"}, "Query response `size`, ie. 500MB, over which search or export asks to continue on terminal, otherwise aborts. 1GiB by default.", nil)
+	addFlagsVar(&args.Force, []string{"force"}, "Receive query responses of any size without confirmation.", false)
+	addFlagsVar(&args.Private, []string{"private"}, "Use private endpoints of logs instance and IAM, reachable only from IBM Cloud private network.", false)
+	addFlagsVar(&args.UsageCSV, []string{"csv"}, "Print usage command report as CSV, without totals row.", false)
+	addFlagsVar(&args.Checkpoint, []string{"checkpoint"}, "JSON `file` with read positions of ship command or state of watch command, in user cache directory by default.", "")
+	addFlagsVar(&args.ExportFormat, []string{"export-format"}, "Format of export command files: ndjson or csv.", formatNDJSON)
+	addFlagsVar(&args.Schema, []string{"schema"}, "JSON `file` with array of {\"name\", \"type\"} columns of CSV export, instead of columns inferred from records.", "")
+	addFlagsVar(&args.SchemaSample, []string{"schema-sample"}, "Number of first `records` whose fields make columns of CSV export.", defaultSchemaSample)
+	addFlagsVar(&args.Upload, []string{"upload"}, "Upload export command files to `location` in cos://bucket/prefix/ format.", "")
+	addFlagsVar(&args.StorageURL, []string{"storage-url"}, "Object storage endpoint `URL` for upload, ie. https://s3.us-south.cloud-object-storage.appdomain.cloud.", "")
+	addFlagsVar(&args.UploadSSE, []string{"upload-sse"}, "Server-side encryption `algorithm` of uploaded files, ie. AES256.", "")
+	addFlagsVar(&args.Threshold, []string{"threshold"}, "Records `count` per interval above which watch command triggers.", 0)
+	addFlagsVar(&args.When, []string{"when"}, "Trigger `expression` of watch command evaluated per interval instead of --threshold, over count, count(<filter expression>), rate per minute and baseline rate of previous intervals, ie. 'count(severity>=error) > 10 || rate > 2*baseline'.", "")
+	addFlagsVar(&args.Silences, []string{"silence"}, "Daily `window` as HH:MM-HH:MM or one-off from..to window when watch command does not notify, ie. maintenance. Silenced intervals are marked in interval lines. Can be repeated.", nil)
+	addFlagsVar(&args.SilenceFile, []string{"silence-file"}, "File `path` with silence windows of watch command, one per line with # comments, read again each interval.", "")
+	addFlagsVar(&args.PagerDuty, []string{"notify-pagerduty"}, "Routing `key` of PagerDuty Events API integration to create and resolve incidents from watch command.", "")
+	addFlagsVar(&args.Opsgenie, []string{"notify-opsgenie"}, "Opsgenie API `key` to create and close alerts from watch command.", "")
+	addFlagsVar(&args.Listen, []string{"listen"}, "Listen `address` of serve and slackbot commands.", defaultListen)
+	addFlagsVar(&args.SlackSecret, []string{"slack-signing-secret"}, "Slack app signing secret. Overrides `SLACK_SIGNING_SECRET` environment variable.", "")
+	addFlagsVar(&args.SlackToken, []string{"slack-token"}, "Slack bot token. Overrides `SLACK_BOT_TOKEN` environment variable.", "")
+	addFlagsVar(&args.CacheTTL, []string{"cache-ttl"}, "Time to reuse serve command results of the same query and range, 0 disables cache.", defaultCacheTTL)
+	addFlagsVar(&args.RateLimit, []string{"rate-limit"}, "Maximum `requests` per minute from one client of serve command, 0 means no limit.", defaultRateLimit)
+	addFlagsVar(&args.StartTime, []string{"from", "f"}, "Start time for log search in format `"+timeFormat+"`, with optional seconds and their fraction, RFC3339 time, date, now, today or yesterday.", nil)
 	addFlagsVar(&args.KeyNames, []string{"message-fields", "m"}, "Comma separated message field names.", defaultKeyNames)
-	addFlagsVar(&args.EndTime, []string{"to", "t"}, "End time for log search in range format `"+timeFormat+"`.", nil)
+	addFlagsVar(&args.Profile, []string{"profile", "p"}, "Configuration profile to use. Overrides `ICLOGS_PROFILE` environment variable.", "")
+	addFlagsVar(&args.Queries, []string{"query", "q"}, "Lucene `query` to run. Can be repeated, all queries are OR-combined.", nil)
+	addFlagsVar(&args.Windows, []string{"window"}, "Time window `from..to` setting start and end time at once, ie. 14:00..14:15 for today or 2006-01-02T15:04..2006-01-02T15:20. Can be repeated, search then queries each window and labels records with it.", nil)
+	addFlagsVar(&args.Daily, []string{"daily"}, "Search the same time of day window `from..to`, ie. 03:00..03:30, on each of last days.", "")
+	addFlagsVar(&args.Days, []string{"days"}, "Number of last `days` searched with daily window.", defaultDays)
+	addFlagsVar(&args.IdleTimeout, []string{"idle-timeout"}, "Abort query only when no data, keepalives included, arrives for `duration`, instead of after 3 minutes request timeout.", time.Duration(0))
+	addFlagsVar(&args.Progress, []string{"progress"}, "Write newline-delimited progress events (phase, shard, percent of shards done, records so far) to standard error in `format`, only json is supported.", "")
+	addFlagsVar(&args.Deadline, []string{"deadline"}, "Time budget shared by all queries of one-shot run, ie. export chunks or windows, 0 means no limit. Each request keeps its own timeout, serve, mcp, slackbot, watch and dash commands are not limited.", time.Duration(0))
+	addFlagsVar(&args.Align, []string{"align"}, "Snap time range start down and end up to multiples of `interval`, ie. 5m.", time.Duration(0))
+	addFlagsVar(&args.EndTime, []string{"to", "t"}, "End time for log search in range format `"+timeFormat+"`, with optional seconds and their fraction, RFC3339 time, date, now, today or yesterday.", nil)
 	addFlagsVar(&args.Version, []string{"version"}, "Show binary version.", false)
 	addFlagsVar(&args.JSON, []string{"j", "show-json"}, "Show record as JSON.", false)
+	addFlagsVar(&args.DropFields, []string{"drop-fields"}, "Comma separated user data `paths` removed from records before output and export, ie. kubernetes.annotations,tag,file.", "")
+	addFlagsVar(&args.Flatten, []string{"flatten"}, "Rewrite user data as single level object with dotted keys, ie. kubernetes.labels.app, before output and export.", false)
+	addFlagsVar(&args.FlattenArrays, []string{"flatten-arrays"}, "Arrays handling `mode` of flattened user data: index (tags.0), join (comma separated string) or keep.", defaultFlattenArrays)
+	addFlagsVar(&args.Redact, []string{"redact"}, "Comma separated `names` of redactors hiding sensitive data (built-in: "+strings.Join(redact.Names(), ", ")+").", "")
+	addFlagsVar(&args.Link, []string{"link"}, "Print link to the same search in IBM Cloud Logs dashboard.", false)
+	addFlagsVar(&args.MaxBytes, []string{"max-field-bytes"}, "Truncate displayed message or JSON longer than `bytes`, 0 means no limit.", 0)
+	addFlagsVar(&args.Secrets, []string{"scan-secrets"}, "Warn about records containing likely secrets.", false)
+	addFlagsVar(&args.ShowID, []string{"show-id"}, "Show record ID.", false)
 	addFlagsVar(&args.Labels, []string{"show-labels"}, "Show record labels.", false)
+	addFlagsVar(&args.ShowLabel, []string{"show-label"}, "Show only record label with given `key`, ie. applicationname. Can be repeated.", nil)
 	addFlagsVar(&args.Severity, []string{"show-severity"}, "Show record severity.", false)
 	addFlagsVar(&args.Timestamp, []string{"show-timestamp"}, "Show record timestamp.", false)
+	addFlagsVar(&args.Compact, []string{"compact"}, "Show records compactly on narrow terminals: time of day, single character severity glyph, and shortened ID and labels when shown.", false)
+	addFlagsVar(&args.Saved, []string{"saved", "s"}, "Run saved query with given `name` from configuration file, ANDed with given query.", "")
+	addFlagsVar(&args.RefreshQueries, []string{"refresh-queries"}, "Fetch shared saved queries from configured source instead of using cached copy.", false)
+	addFlagsVar(&args.LowMemory, []string{"low-memory"}, "Print, export or send records to sink as they arrive without keeping them, ordered only within received batches.", false)
+	addFlagsVar(&args.Yes, []string{"yes", "y"}, "Run expensive archive scans without confirmation.", false)
+	addFlagsVar(&args.Precision, []string{"precision"}, "Precision `unit` of displayed times: s, ms, us or ns.", defaultPrecision)
+	addFlagsVar(&args.UTC, []string{"utc"}, "Parse time options, query and display all times in UTC, shown with explicit zone suffix.", false)
+	addFlagsVar(&args.Snippet, []string{"snippet"}, "Run built-in Dataprime snippet `name` (see snippets command), query is then applied as Lucene stage before the snippet pipeline.", "")
+	addFlagsVar(&args.Params, []string{"param"}, "Snippet parameter as `name=value`, filling {name} placeholder. Can be repeated.", nil)
+	addFlagsVar(&args.Session, []string{"session"}, "Pin endpoint, time window and token under session `name` on first use and reuse them in later runs with the same name.", "")
+	addFlagsVar(&args.Override, []string{"override"}, "Run queries exceeding maximum range and records of the profile.", false)
+	addFlagsVar(&args.Summary, []string{"summary"}, "Print records count, time range and timings of query phases to standard error.", false)
+	addFlagsVar(&args.Sink, []string{"sink"}, "Send found records as JSON lines to sink plugin with given `name` instead of printing them.", "")
+	addFlagsVar(&args.Where, []string{"where", "w"}, "Client-side filter `expression` over id, severity, timestamp, label.<key> and json.<path> fields.", "")
 }
 
 // Parse command line args
@@ -224,14 +604,176 @@ func parseArgs() CmdArgs {
 		printUsage(w)
 	}
 
-	flag.Parse()
-	args.Query = strings.Join(flag.Args(), " ")
+	a := os.Args[1:]
+	if len(a) > 0 {
+		if _, ok := commands[a[0]]; ok {
+			args.Command = a[0]
+			a = a[1:]
+		}
+	}
+
+	flag.CommandLine.Parse(a)
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "range" || f.Name == "r" {
+			args.RangeSet = true
+		}
+	})
+	args.Query = combineQueries(append(splitQueries(flag.Args()), args.Queries...))
 
 	getEnvArgs(&args)
 
 	return args
 }
 
+// Split positional arguments into separate queries on `--` separator
+func splitQueries(a []string) []string {
+	var (
+		result []string
+		words  []string
+	)
+
+	for _, w := range a {
+		if w == querySeparator {
+			result = append(result, strings.Join(words, " "))
+			words = nil
+			continue
+		}
+		words = append(words, w)
+	}
+
+	return append(result, strings.Join(words, " "))
+}
+
+// Join queries with OR, each query is grouped to keep its own operators precedence
+func combineQueries(qs []string) string {
+	var parts []string
+
+	for _, q := range qs {
+		if q = strings.TrimSpace(q); q != "" {
+			parts = append(parts, q)
+		}
+	}
+
+	if len(parts) == 1 {
+		return parts[0]
+	}
+
+	for i, p := range parts {
+		parts[i] = "(" + p + ")"
+	}
+
+	return strings.Join(parts, " OR ")
+}
+
+// Fill missing arguments with profile values
+func applyProfile(args *CmdArgs, p config.Profile) {
+	if args.LogsURL == "" {
+		args.LogsURL = p.LogsURL
+	}
+
+	if args.FallbackURL == "" {
+		args.FallbackURL = p.FallbackLogsURL
+	}
+
+	if args.AuthURL == defaultIAMURL && p.AuthURL != "" {
+		args.AuthURL = p.AuthURL
+	}
+	args.AuthURL = config.IAMURL(args.AuthURL)
+
+	if args.APIKey == "" && p.APIKeyEnv != "" {
+		args.APIKey = os.Getenv(p.APIKeyEnv)
+	}
+}
+
+// Switch logs, ingestion and IAM endpoints to their private ones
+func usePrivateEndpoints(args *CmdArgs) error {
+	for _, u := range []*string{&args.LogsURL, &args.IngressURL, &args.AuthURL} {
+		p, err := endpoint.Private(*u)
+		if err != nil {
+			return err
+		}
+		*u = p
+	}
+
+	return nil
+}
+
+// Use saved query, ANDed with given query if any, and its time range unless other was given
+func applySavedQuery(args *CmdArgs, q config.SavedQuery) error {
+	if args.Query == "" {
+		args.Query = q.Query
+	} else {
+		args.Query = scopeQuery(q.Query, args.Query)
+	}
+
+	if q.Range != "" && !args.RangeSet {
+		d, err := time.ParseDuration(q.Range)
+		if err != nil {
+			return fmt.Errorf("invalid range of saved query: %w", err)
+		}
+		args.TimeRange, args.RangeSet = d, true
+	}
+
+	return nil
+}
+
+// Restrict query with scope clause
+func scopeQuery(scope, query string) string {
+	if scope = strings.TrimSpace(scope); scope == "" {
+		return query
+	}
+
+	return "(" + scope + ") AND (" + query + ")"
+}
+
+// Escapes string for Dataprime single quotes
+var dataprimeEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+
+// Quote string for Dataprime query
+func dataprimeString(s string) string {
+	return "'" + dataprimeEscaper.Replace(s) + "'"
+}
+
+// Dataprime query for single record, restricted with Lucene scope clause
+func recordQuery(id, scope string) string {
+	q := "source logs | filter $m.logid == " + dataprimeString(id)
+
+	if scope = strings.TrimSpace(scope); scope != "" {
+		q += " | lucene " + dataprimeString(scope)
+	}
+
+	return q
+}
+
+// Build dashboard link for the query, profile dashboard address takes precedence
+func dashboardLink(args *CmdArgs, p config.Profile, spec logs.QuerySpec) (string, error) {
+	base := p.DashboardURL
+
+	if base == "" {
+		var err error
+		if base, err = dashboard.BaseURL(args.LogsURL); err != nil {
+			return "", err
+		}
+	}
+
+	return dashboard.QueryURL(base, args.Query, spec.Syntax, spec.StartDate, spec.EndDate), nil
+}
+
+// Open URL in default browser
+var openBrowser = func(url string) error {
+	c, ok := browserCommands[runtime.GOOS]
+	if !ok {
+		c = defaultBrowserCommand
+	}
+
+	cmd := exec.Command(c[0], append(c[1:], url)...)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("cannot run '%s': %w", c[0], err)
+	}
+
+	return cmd.Process.Release()
+}
+
 // Simple produce version string
 func getVersion() string {
 	return fmt.Sprintf(versionString, version)
@@ -240,7 +782,7 @@ func getVersion() string {
 // Validate if CmdArgs has proper values
 func validateArgs(args *CmdArgs) error {
 
-	if args.APIKey == "" {
+	if args.APIKey == "" && args.Command != commandOpen {
 		return errMissingAPIKey
 	}
 
@@ -248,46 +790,289 @@ func validateArgs(args *CmdArgs) error {
 		return errMissingURL
 	}
 
-	if args.Query == "" {
+	if args.Enrich != "" && args.EnrichKey == "" {
+		return errMissingEnrich
+	}
+
+	if args.GeoIP != "" && args.GeoField == "" {
+		return errMissingGeoIP
+	}
+
+	if args.IPIn != "" && args.IPField == "" {
+		return errMissingIPField
+	}
+
+	if args.Percentiles && args.ExtractDuration == "" {
+		return errMissingDuration
+	}
+
+	if args.Refresh <= 0 && (args.Command == commandDash || args.Command == commandWatch || args.Command == commandServe) {
+		return errInvalidRefresh
+	}
+
+	if args.LowMemory && (args.Percentiles || args.Patterns || args.Agg != "" || args.GroupBy != "" || args.Histogram || args.Copy) {
+		return errLowMemory
+	}
+
+	if args.Query == "" && args.Command == commandGet {
+		return errMissingID
+	}
+
+	if args.Upload != "" && args.StorageURL == "" {
+		return errMissingStorage
+	}
+
+	if args.Command == commandAssert && args.Golden == "" {
+		return errMissingGolden
+	}
+
+	if args.Command == commandSlack && (args.SlackSecret == "" || args.SlackToken == "") {
+		return errMissingSlack
+	}
+
+	if args.Query == "" && args.Snippet == "" && args.Command != commandServe && args.Command != commandMCP && args.Command != commandSlack && args.Command != commandLabels {
 		return errMissingQuery
 	}
 
 	return nil
 }
 
+// Cut text to max bytes, keeping UTF-8 characters whole, and mark how much was dropped
+func truncate(text string, max int) string {
+	if max <= 0 || len(text) <= max {
+		return text
+	}
+
+	cut := max
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+
+	return text[:cut] + fmt.Sprintf(truncatedMarker, len(text)-cut)
+}
+
 // Printout log records based on setup in CmdArgs
 func printLogs(w io.Writer, l *[]logs.Log, args *CmdArgs) {
 
 	keyNames := strings.Split(args.KeyNames, ",")
+	compact := compactTimeFormat(*l, args.Times)
 
 	for _, line := range *l {
-		if args.Timestamp {
-			fmt.Fprintf(w, "%s: ", line.Time.Format(timeStampFormat))
+		if args.Compact {
+			fmt.Fprint(w, compactPrefix(&line, args, compact))
+		}
+
+		if args.Timestamp && !args.Compact {
+			fmt.Fprintf(w, "%s: ", args.Times.format(line.Time))
 		}
 
-		if args.Severity {
+		if args.ShowID && !args.Compact {
+			fmt.Fprintf(w, "#%s ", line.ID)
+		}
+
+		if args.Severity && !args.Compact {
 			fmt.Fprintf(w, "[%s] ", line.Severity)
 		}
 
-		if args.Labels {
-			fmt.Fprintf(w, "<%s> ", strings.Join(line.Labels, ", "))
+		if showsLabels(args) && !args.Compact {
+			fmt.Fprintf(w, "<%s> ", strings.Join(logs.FormatLabels(shownLabels(&line, args)), ", "))
 		}
 
 		if args.JSON {
-			fmt.Fprintln(w, line.UserData)
+			fmt.Fprintln(w, truncate(line.UserData, args.MaxBytes))
 			continue
 		}
 
-		msg, err := logs.GetMessage(&line.UserData, &keyNames)
+		msg, err := line.Message(keyNames)
 		if err == nil {
-			fmt.Fprintln(w, msg)
+			fmt.Fprintln(w, truncate(msg, args.MaxBytes))
+		}
+	}
+}
+
+// Resolve record fields using configured aliases
+func aliasResolver(r filter.Resolver, a config.Aliases) filter.Resolver {
+	return func(name string) (any, bool) {
+		return r(a.Expand(name))
+	}
+}
+
+// Expand aliases of comma separated message field names, message fields are always user data paths
+func expandKeyNames(keyNames string, a config.Aliases) string {
+	names := strings.Split(keyNames, ",")
+
+	for i, n := range names {
+		names[i] = strings.TrimPrefix(a.Expand(n), jsonFieldPrefix)
+	}
+
+	return strings.Join(names, ",")
+}
+
+// Join lookup table fields onto log records by key field value
+func enrichLogs(l []logs.Log, t enrich.Table, key string, a config.Aliases) error {
+	for i := range l {
+		v, ok := aliasResolver(filter.LogResolver(&l[i]), a)(key)
+		if !ok || v == nil {
+			continue
+		}
+
+		ud, err := t.Apply(l[i].UserData, fmt.Sprint(v))
+		if err != nil {
+			return fmt.Errorf("cannot enrich record '%s': %w", l[i].ID, err)
+		}
+		l[i].UserData = ud
+	}
+
+	return nil
+}
+
+// Annotate log records with country and autonomous system of IP address from field
+func geoipLogs(l []logs.Log, r *geoip.Reader, field string, a config.Aliases) error {
+	for i := range l {
+		v, ok := aliasResolver(filter.LogResolver(&l[i]), a)(field)
+		if !ok || v == nil {
+			continue
+		}
+
+		ip, err := netip.ParseAddr(fmt.Sprint(v))
+		if err != nil {
+			continue
+		}
+
+		record, found, err := r.Lookup(ip)
+		if err != nil {
+			return fmt.Errorf("cannot look up '%s': %w", ip, err)
+		}
+		if !found {
+			continue
+		}
+
+		ud, err := enrich.Add(l[i].UserData, geoipKey, geoip.Summary(record))
+		if err != nil {
+			return fmt.Errorf("cannot enrich record '%s': %w", l[i].ID, err)
+		}
+		l[i].UserData = ud
+	}
+
+	return nil
+}
+
+// Parse comma separated networks in CIDR notation, single addresses are accepted too
+func parseNetworks(s string) ([]netip.Prefix, error) {
+	var networks []netip.Prefix
+
+	for _, n := range strings.Split(s, ",") {
+		if n = strings.TrimSpace(n); n == "" {
+			continue
+		}
+
+		if ip, err := netip.ParseAddr(n); err == nil {
+			networks = append(networks, netip.PrefixFrom(ip, ip.BitLen()))
+			continue
+		}
+
+		p, err := netip.ParsePrefix(n)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse network: %w", err)
+		}
+		networks = append(networks, p.Masked())
+	}
+
+	return networks, nil
+}
+
+// Keep only log records with IP address in field within one of networks
+func filterNetworks(l []logs.Log, networks []netip.Prefix, field string, a config.Aliases) []logs.Log {
+	result := []logs.Log{}
+
+	for i := range l {
+		v, ok := aliasResolver(filter.LogResolver(&l[i]), a)(field)
+		if !ok || v == nil {
+			continue
+		}
+
+		ip, err := netip.ParseAddr(fmt.Sprint(v))
+		if err != nil {
+			continue
+		}
+
+		for _, n := range networks {
+			if n.Contains(ip.Unmap()) {
+				result = append(result, l[i])
+				break
+			}
+		}
+	}
+
+	return result
+}
+
+// Keep only log records matching the filter
+func filterLogs(l []logs.Log, f *filter.Filter, a config.Aliases) []logs.Log {
+	result := []logs.Log{}
+
+	for i := range l {
+		if f.Match(aliasResolver(filter.LogResolver(&l[i]), a)) {
+			result = append(result, l[i])
+		}
+	}
+
+	return result
+}
+
+// Record executed query in configured audit destinations
+func writeAudit(c config.Audit, e audit.Entry) error {
+	if c.File != "" {
+		if err := audit.WriteFile(c.File, e); err != nil {
+			return err
+		}
+	}
+
+	if c.Webhook != "" {
+		if err := audit.Send(c.Webhook, e); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Hide sensitive data in user data and labels of log records
+func redactLogs(l []logs.Log, s redact.Set) {
+	for i := range l {
+		l[i].UserData = s.ApplyJSON(l[i].UserData)
+		for j := range l[i].Labels {
+			l[i].Labels[j].Value = s.Apply(l[i].Labels[j].Value)
 		}
 	}
 }
 
+func printSecrets(w io.Writer, fs []secrets.Finding, layout timeLayout) {
+
+	fmt.Fprintln(w, i18n.T("Possible secrets:"))
+	for _, f := range fs {
+		fmt.Fprint(w, i18n.Sprintf("- %s: %d record(s), first at %s\n", f.Kind, f.Count, layout.format(f.First)))
+	}
+
+}
+
+// Print summary with timings of query phases, so slow service, network or local processing can be told apart
+func printSummary(w io.Writer, records int, spec logs.QuerySpec, t logs.Timings, process, render time.Duration, layout timeLayout) {
+	ms := func(d time.Duration) time.Duration {
+		return d.Round(time.Millisecond)
+	}
+	total := t.Token + t.Request + t.Parse + process + render
+
+	fmt.Fprintln(w, i18n.T("Summary:"))
+	fmt.Fprint(w, i18n.Sprintf("- records: %d\n", records))
+	fmt.Fprint(w, i18n.Sprintf("- time range: %s - %s (%s, %v)\n", layout.format(spec.StartDate), layout.format(spec.EndDate), layout.in(spec.StartDate).Format("MST -07:00"), spec.EndDate.Sub(spec.StartDate)))
+	fmt.Fprint(w, i18n.Sprintf("- timings: token %v, first byte %v, request %v, parse %v, process %v, render %v, total %v\n",
+		ms(t.Token), ms(t.FirstByte), ms(t.Request), ms(t.Parse), ms(process), ms(render), ms(total)))
+}
+
 func printWarnings(w io.Writer, ws []string) {
 
-	fmt.Fprintln(w, "Warnings:")
+	fmt.Fprintln(w, i18n.T("Warnings:"))
 	for _, l := range ws {
 		fmt.Fprintf(w, "- %s\n", l)
 	}
@@ -296,50 +1081,665 @@ func printWarnings(w io.Writer, ws []string) {
 
 func main() {
 
+	defer recoverCrash()
+
+	if err := run(); err != nil {
+		var status exitStatus
+		if errors.As(err, &status) {
+			os.Exit(int(status))
+		}
+		if r, ok := explainError(err); ok {
+			printRemedy(os.Stderr, r, err)
+			os.Exit(1)
+		}
+		slog.Error("Command failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+// Run command given by arguments, returning its error up to main
+func run() error {
+
+	endpoint.Explain(http.DefaultTransport.(*http.Transport))
+
+	// Usage is printed while parsing arguments, so language is detected before
+	i18n.Lang = i18n.Detect(os.Getenv)
 	args := parseArgs()
+	slog.SetDefault(newRunLogger(os.Stderr, args.Verbose, args.Debug, recentLogs))
+
+	times, err := newTimeLayout(args.Precision, args.UTC)
+	if err != nil {
+		return fmt.Errorf("error in parsing arguments: %w", err)
+	}
+	args.Times = times
+
+	logs.IdleTimeout = args.IdleTimeout
+
+	if args.Daily != "" {
+		w, err := dailyWindows(args.Daily, args.Days, time.Now())
+		if err != nil {
+			return fmt.Errorf("error in parsing arguments: %w", err)
+		}
+		args.Windows = append(args.Windows, w...)
+	}
+
+	if args.UTC {
+		useUTC(&args)
+	}
+
+	if len(args.Windows) > 0 {
+		start, end := args.Windows.span()
+		args.StartTime, args.EndTime = timestamp(start), timestamp(end)
+	}
 
 	if args.Version {
 		w := flag.CommandLine.Output()
 		fmt.Fprintf(w, "%s\n", getVersion())
-		os.Exit(0)
+		return nil
+	}
+
+	if args.Command == commandDocs {
+		if err := runDocs(os.Stdout, args.Query); err != nil {
+			return fmt.Errorf("cannot generate docs: %w", err)
+		}
+		return nil
+	}
+
+	cfg, err := config.Load(args.Config)
+	if err != nil {
+		return fmt.Errorf("cannot load configuration: %w", err)
+	}
+
+	notice := checkUpdate(cfg.UpdateCheck, version)
+	defer notice(os.Stderr)
+
+	if args.Command == commandBug {
+		dir, err := crash.DefaultDir()
+		if err != nil {
+			return fmt.Errorf("cannot find crash reports: %w", err)
+		}
+		r, ok, err := crash.Latest(dir)
+		if err != nil {
+			return fmt.Errorf("cannot read crash report: %w", err)
+		}
+		printBugReport(os.Stdout, r, ok)
+		return nil
+	}
+
+	if args.Command == commandConfig {
+		if err := runConfig(os.Stdout, args.Config, cfg, args.Query); err != nil {
+			return fmt.Errorf("cannot %s configuration: %w", strings.Fields(args.Query + " manage")[0], err)
+		}
+		return nil
+	}
+
+	if args.Command == commandContext {
+		if err := runContext(os.Stdout, args.Config, cfg, args.Profile, args.Query); err != nil {
+			return fmt.Errorf("cannot use context: %w", err)
+		}
+		return nil
+	}
+
+	if args.Command == commandSnips {
+		printSnippets(os.Stdout)
+		return nil
+	}
+
+	profile, err := cfg.Profile(args.Profile)
+	if err != nil {
+		return fmt.Errorf("cannot select profile: %w", err)
+	}
+	applyProfile(&args, profile)
+	if args.Private || profile.PrivateEndpoints {
+		if err := usePrivateEndpoints(&args); err != nil {
+			return fmt.Errorf("error in parsing arguments: %w", err)
+		}
+	}
+	api.CacheTTL, api.Refresh = defaultAPICacheTTL, args.RefreshCache
+
+	if slices.Contains(warmCommands, args.Command) {
+		warmUp(http.DefaultTransport.(*http.Transport), args.Refresh, args.AuthURL, args.LogsURL)
+	}
+
+	if args.Command == commandDoctor {
+		if err := runDoctor(os.Stdout, &args, endpoint.Check); err != nil {
+			return fmt.Errorf("cannot reach endpoints: %w", err)
+		}
+		return nil
+	}
+
+	if args.Command == commandAuth {
+		if err := runAuth(os.Stdout, &args); err != nil {
+			return fmt.Errorf("cannot show token identity: %w", err)
+		}
+		return nil
+	}
+
+	if args.Command == commandAPI {
+		if err := runAPI(os.Stdout, os.Stdin, &args, apiKeyToken(&args)); err != nil {
+			return fmt.Errorf("cannot call API: %w", err)
+		}
+		return nil
+	}
+
+	if args.Command == commandHooks {
+		if err := runWebhooks(os.Stdout, &args, apiKeyToken(&args), time.Now()); err != nil {
+			return fmt.Errorf("cannot use webhooks: %w", err)
+		}
+		return nil
+	}
+
+	if args.Command == commandE2M {
+		if err := runE2M(os.Stdout, &args, apiKeyToken(&args)); err != nil {
+			return fmt.Errorf("cannot read events-to-metrics: %w", err)
+		}
+		return nil
+	}
+
+	if args.Command == commandEnrich {
+		if err := runEnrichments(os.Stdout, &args, apiKeyToken(&args)); err != nil {
+			return fmt.Errorf("cannot list enrichments: %w", err)
+		}
+		return nil
+	}
+
+	if args.Command == commandPolicy {
+		if err := runPolicies(os.Stdout, &args, apiKeyToken(&args)); err != nil {
+			return fmt.Errorf("cannot list policies: %w", err)
+		}
+		return nil
+	}
+
+	if args.Command == commandUsage {
+		if err := runUsage(os.Stdout, &args, apiKeyToken(&args)); err != nil {
+			return fmt.Errorf("cannot report data usage: %w", err)
+		}
+		return nil
+	}
+
+	if args.Command == commandIngest {
+		if err := runIngest(os.Stdout, &args, apiKeyToken(&args), time.Now()); err != nil {
+			return fmt.Errorf("cannot ingest record: %w", err)
+		}
+		return nil
+	}
+
+	if args.Command == commandPush {
+		if err := runPush(os.Stdin, os.Stderr, &args, apiKeyToken(&args), time.Now); err != nil {
+			return fmt.Errorf("cannot push logs: %w", err)
+		}
+		return nil
+	}
+
+	if args.Command == commandShip {
+		s, err := newShipper(&args, apiKeyToken(&args), time.Now)
+		if err != nil {
+			return fmt.Errorf("cannot ship logs: %w", err)
+		}
+		runShip(s, os.Stderr, shipPollInterval)
+		return nil
+	}
+
+	if args.Command == commandVerify {
+		s := newSession(&args, cfg, profile)
+		if err := runVerify(os.Stdout, &args, s.query, s.getToken, time.Now, verifyPollInterval); err != nil {
+			return fmt.Errorf("cannot verify pipeline: %w", err)
+		}
+		return nil
+	}
+
+	if cfg.QueriesSource != "" && (args.Saved != "" || args.RefreshQueries || args.Command == commandSlack) {
+		shared, err := library.Queries(cfg.QueriesSource, args.RefreshQueries)
+		if err != nil {
+			return fmt.Errorf("cannot load shared queries: %w", err)
+		}
+		cfg.AddQueries(shared)
+	}
+
+	if args.Saved != "" {
+		q, err := cfg.SavedQuery(args.Saved)
+		if err != nil {
+			return fmt.Errorf("cannot select saved query: %w", err)
+		}
+		if err = applySavedQuery(&args, q); err != nil {
+			return fmt.Errorf("error in parsing arguments: %w", err)
+		}
+	}
+
+	var (
+		pinned   pin.Context
+		restored bool
+	)
+	if args.Session != "" {
+		if pinned, restored, err = restorePin(args.Session, &args); err != nil {
+			return fmt.Errorf("cannot restore session: %w", err)
+		}
 	}
 
 	if err := validateArgs(&args); err != nil {
-		log.Fatalf("Error in parsing arguments: %v", err)
+		return fmt.Errorf("error in parsing arguments: %w", err)
 	}
 
-	token, err := auth.GetToken(args.AuthURL, args.APIKey)
+	querySyntax := syntax.Lucene
+
+	labelKind := ""
+
+	switch args.Command {
+	case commandLabels:
+		labelKind = args.Query
+		args.Query = labelsQuery(profile.Scope)
+		querySyntax = syntax.Dataprime
+	case commandGet:
+		args.Query = recordQuery(args.Query, profile.Scope)
+		querySyntax = syntax.Dataprime
+		args.JSON = true
+		args.MaxBytes = 0
+	default:
+		if args.Snippet != "" {
+			if args.Query, err = snippetQuery(args.Snippet, args.Params, profile.Scope, args.Query); err != nil {
+				return fmt.Errorf("error in parsing arguments: %w", err)
+			}
+			querySyntax = syntax.Dataprime
+			args.JSON = true
+			break
+		}
+		args.Query = scopeQuery(profile.Scope, args.Query)
+	}
+
+	args.KeyNames = expandKeyNames(args.KeyNames, cfg.Aliases)
 
+	var durations *durationExtractor
+	if args.ExtractDuration != "" {
+		if durations, err = newDurationExtractor(args.ExtractDuration, args.DurationUnit); err != nil {
+			return fmt.Errorf("error in parsing arguments: %w", err)
+		}
+	}
+
+	var aggs []stats.Aggregation
+	if args.Agg != "" {
+		if aggs, err = stats.ParseAggregations(args.Agg); err != nil {
+			return fmt.Errorf("error in parsing arguments: %w", err)
+		}
+	}
+
+	pipe, err := newPipeline(&args, cfg)
 	if err != nil {
-		log.Fatalf("Cannot get token from '%s': %v", args.AuthURL, err)
+		return fmt.Errorf("error in parsing arguments: %w", err)
 	}
 
-	endDate := time.Time(args.EndTime)
-	startDate := time.Time(args.StartTime)
+	startDate, endDate, err := searchWindow(time.Time(args.StartTime), time.Time(args.EndTime), args.TimeRange, args.RangeSet, time.Now())
+	if err != nil {
+		return fmt.Errorf("error in parsing arguments: %w", err)
+	}
 
-	if endDate.IsZero() {
-		endDate = time.Now()
+	startDate, endDate = alignWindow(startDate, endDate, args.Align)
+	for i, w := range args.Windows {
+		args.Windows[i].start, args.Windows[i].end = alignWindow(w.start, w.end, args.Align)
 	}
 
-	if startDate.IsZero() {
-		startDate = endDate.Add(-args.TimeRange)
+	if restored {
+		startDate, endDate = pinned.StartDate, pinned.EndDate
 	}
 
 	spec := logs.QuerySpec{
-		Syntax:    syntax.Lucene,
+		Syntax:    querySyntax,
 		Tier:      tier.Archive,
 		Limit:     tier.LimitArchive,
 		StartDate: startDate,
 		EndDate:   endDate,
 	}
 
-	l, err := logs.QueryLogs(args.LogsURL, token.Value, args.Query, spec)
+	if args.Command == commandOpen {
+		link, err := dashboardLink(&args, profile, spec)
+		if err != nil {
+			return fmt.Errorf("cannot create dashboard link: %w", err)
+		}
+		if err = openBrowser(link); err != nil {
+			return fmt.Errorf("cannot open browser: %w", err)
+		}
+		return nil
+	}
+
+	var history []audit.Entry
+	if cfg.Audit.File != "" {
+		if history, err = audit.ReadFile(cfg.Audit.File); err != nil {
+			return fmt.Errorf("cannot read query history: %w", err)
+		}
+	}
+	estimate := estimateCost(spec, args.Windows, history, args.Query)
+
+	if err := applyGuardrails(profile, &spec, estimate, args.Override); err != nil {
+		return fmt.Errorf("query not run: %w", err)
+	}
+
+	if args.Explain {
+		if err := printExplain(os.Stdout, args.LogsURL, args.Query, spec, newSession(&args, cfg, profile).getToken); err != nil {
+			return fmt.Errorf("cannot explain query: %w", err)
+		}
+		return nil
+	}
+
+	if args.Command == "" || args.Command == commandExport {
+		if err := confirmCost(os.Stdin, os.Stderr, estimate, args.Yes, isTerminal(os.Stdin)); err != nil {
+			return fmt.Errorf("query not run: %w", err)
+		}
+	}
+
+	s := newSession(&args, cfg, profile)
+	s.history = history
+
+	if args.Session != "" {
+		if err := s.pin(args.Session, pinned.Token, spec); err != nil {
+			return fmt.Errorf("cannot pin session: %w", err)
+		}
+	}
+
+	if args.Command == commandLabels {
+		fetch := func() ([]query.Row, error) {
+			t, err := s.table("", args.Query, spec)
+			return t.Rows, err
+		}
+		if err := runLabels(os.Stdout, args.LogsURL+" "+args.Query, labelKind, args.RefreshCache, fetch); err != nil {
+			return fmt.Errorf("cannot list labels: %w", err)
+		}
+		return nil
+	}
+
+	if args.Command == commandDash {
+		runDash(newSearch(s, pipe), &args, cfg.Aliases, spec)
+		return nil
+	}
+
+	if args.Command == commandSummary {
+		if err := runSummarize(os.Stdout, newSearch(s, pipe), args.Query, spec, strings.Split(args.KeyNames, ","), args.GroupBy, cfg.Aliases, args.Times); err != nil {
+			return fmt.Errorf("cannot summarize logs: %w", err)
+		}
+		return nil
+	}
+
+	if args.Command == commandServe {
+		srv := &server{
+			search:   newSearch(s, pipe),
+			scope:    profile.Scope,
+			maxRange: maxQueryRange(&args, profile),
+			keyNames: strings.Split(args.KeyNames, ","),
+			spec:     spec,
+			refresh:  args.Refresh,
+			cache:    newResultCache(args.CacheTTL),
+			limiter:  newRateLimiter(args.RateLimit),
+			layout:   args.Times,
+		}
+		return fmt.Errorf("cannot serve: %w", runServer(srv, args.Listen))
+	}
+
+	shards := len(args.Windows)
+	if args.Command == commandExport {
+		shards = len(exportWindows(spec.StartDate, spec.EndDate, args.Chunk))
+	}
+	prog, err := newProgress(args.Progress, os.Stderr, shards)
 	if err != nil {
-		log.Fatalf("Cannot get logs from '%s': %v", args.LogsURL, err)
+		return fmt.Errorf("error in parsing arguments: %w", err)
+	}
+
+	if args.Command == commandExport {
+		logs.MaxBandwidth = int64(args.MaxBandwidth)
+		stop := context.Background()
+		var answers <-chan string
+		if isTerminal(os.Stdin) {
+			var endStop func()
+			s.stop, answers, endStop = watchStop(os.Stderr)
+			defer endStop()
+			stop = s.stop
+		}
+		spec.Guard = guardResponse(stop, os.Stderr, args.MaxResponse, args.Force, answers)
+		e := &exporter{search: newSearch(s, pipe), dir: args.Output, chunk: args.Chunk, progress: prog, retries: args.Retries}
+		if args.LowMemory {
+			e.stream = newStream(s, pipe)
+		}
+		if e.csv, err = newCSVExport(args.ExportFormat, args.Schema, args.SchemaSample); err != nil {
+			return fmt.Errorf("error in parsing arguments: %w", err)
+		}
+		if args.Encrypt != "" {
+			if e.encrypt, err = encrypt.New(args.Encrypt); err != nil {
+				return fmt.Errorf("error in parsing arguments: %w", err)
+			}
+		}
+		if args.Upload != "" {
+			if e.upload, err = newUpload(args.Upload, args.StorageURL, args.UploadSSE, s.getToken); err != nil {
+				return fmt.Errorf("error in parsing arguments: %w", err)
+			}
+		}
+		if err := e.run(os.Stdout, args.Query, spec); err != nil {
+			return fmt.Errorf("cannot export logs: %w", err)
+		}
+		return nil
+	}
+
+	if args.Command == commandAssert {
+		ok, err := runAssert(os.Stdout, newSearch(s, pipe), args.Query, spec, args.Golden, args.UpdateGolden)
+		if err != nil {
+			return fmt.Errorf("cannot assert logs: %w", err)
+		}
+		if !ok {
+			return exitStatus(driftStatus)
+		}
+		return nil
+	}
+
+	if args.Command == commandWatch {
+		w := &watcher{search: newSearch(s, pipe), query: args.Query, threshold: args.Threshold, aliases: cfg.Aliases, layout: args.Times}
+		w.silences, w.silenceFile = args.Silences, args.SilenceFile
+		if w.state = args.Checkpoint; w.state == "" {
+			if w.state, err = watchStatePath(w.key()); err != nil {
+				return err
+			}
+		}
+		if err := w.load(); err != nil {
+			return err
+		}
+		slog.Debug("Watch state", "path", w.state, "triggered", w.triggered, "intervals", len(w.rates))
+		if w.silenceFile != "" {
+			if w.fileSilences, err = loadSilences(w.silenceFile, time.Now()); err != nil {
+				return fmt.Errorf("cannot read silence file: %w", err)
+			}
+		}
+		if args.When != "" {
+			if w.when, err = parseTrigger(args.When); err != nil {
+				return fmt.Errorf("cannot parse trigger expression: %w", err)
+			}
+		}
+		w.source, _ = os.Hostname()
+		if args.PagerDuty != "" {
+			w.notifiers = append(w.notifiers, notify.PagerDuty{RoutingKey: args.PagerDuty})
+		}
+		if args.Opsgenie != "" {
+			w.notifiers = append(w.notifiers, notify.Opsgenie{APIKey: args.Opsgenie})
+		}
+		runWatch(w, args.Refresh, spec)
+		return nil
+	}
+
+	if args.Command == commandSlack {
+		b, err := newSlackBot(cfg, newSearch(s, pipe), &args, profile, spec)
+		if err != nil {
+			return fmt.Errorf("cannot create Slack bot: %w", err)
+		}
+		return fmt.Errorf("cannot serve Slack bot: %w", runSlackBot(b, args.Listen))
+	}
+
+	if args.Command == commandMCP {
+		m := &mcpServer{
+			search:     newSearch(s, pipe),
+			scope:      profile.Scope,
+			toolScopes: profile.ToolScopes,
+			maxRange:   maxQueryRange(&args, profile),
+			spec:       spec,
+			aliases:    cfg.Aliases,
+		}
+		if err := m.serve(os.Stdin, os.Stdout); err != nil {
+			return fmt.Errorf("cannot serve MCP: %w", err)
+		}
+		return nil
+	}
+
+	if args.Command == "" && snippets[args.Snippet].table {
+		t, err := s.table("", args.Query, spec)
+		if err != nil {
+			return fmt.Errorf("cannot search logs: %w", err)
+		}
+		printTable(os.Stdout, t)
+		if len(t.Warnings) != 0 {
+			printWarnings(os.Stderr, t.Warnings)
+		}
+		return nil
 	}
 
-	printLogs(os.Stdout, &l.Logs, &args)
+	var sink plugin.Plugin
+	if args.Sink != "" {
+		if sink, err = plugin.Find(cfg.PluginsDir, args.Sink); err != nil {
+			return fmt.Errorf("cannot select sink: %w", err)
+		}
+	}
+
+	var (
+		l       logs.Result
+		found   []secrets.Finding
+		records int
+		fetched int
+
+		processTime, renderTime time.Duration
+	)
+
+	var out io.Writer = os.Stdout
+	copied := strings.Builder{}
+	if args.Copy {
+		out = io.MultiWriter(os.Stdout, &copied)
+	}
+
+	endStop := func() {}
+	stop := context.Background()
+	var answers <-chan string
+	if isTerminal(os.Stdin) {
+		s.stop, answers, endStop = watchStop(os.Stderr)
+		stop = s.stop
+	}
+	spec.Guard = guardResponse(stop, os.Stderr, args.MaxResponse, args.Force, answers)
+
+	if args.LowMemory {
+		var sinkPlugin *plugin.Plugin
+		if args.Sink != "" {
+			sinkPlugin = &sink
+		}
+		stream := trackStream(newStream(s, pipe), prog)
+		if len(args.Windows) > 1 {
+			stream = streamWindows(stream, args.Windows)
+		}
+		l, found, records, err = streamLogs(stream, out, sinkPlugin, &args, spec)
+		endStop()
+		if errors.Is(err, errStopped) {
+			l.Warnings, err = append(l.Warnings, stoppedWarning), nil
+		}
+		if errors.Is(err, errDeadline) && records > 0 {
+			l.Warnings, err = append(l.Warnings, deadlineWarning()), nil
+		}
+		if err != nil {
+			return fmt.Errorf("cannot search logs: %w", err)
+		}
+	} else {
+		query := trackQuery(s.query, prog)
+		if len(args.Windows) > 1 {
+			query = queryWindows(query, args.Windows)
+		}
+		l, err = query("", args.Query, spec)
+		endStop()
+		if errors.Is(err, errStopped) {
+			l.Warnings, err = append(l.Warnings, stoppedWarning), nil
+		}
+		if errors.Is(err, errDeadline) && len(l.Logs) > 0 {
+			l.Warnings, err = append(l.Warnings, deadlineWarning()), nil
+		}
+		if err != nil {
+			return fmt.Errorf("cannot search logs: %w", err)
+		}
+		if err := discoverFields(args.LogsURL, l.Logs); err != nil {
+			slog.Warn("Cannot cache discovered fields", "error", err)
+		}
+
+		fetched = len(l.Logs)
+		prog.event(phaseProcess)
+		processStart := time.Now()
+		l.Logs, found, err = pipe.process(l.Logs)
+		if err != nil {
+			return fmt.Errorf("cannot process logs: %w", err)
+		}
+		processTime = time.Since(processStart)
+		prog.event(phaseRender)
+		renderStart := time.Now()
+		records = len(l.Logs)
+
+		if args.Sink != "" {
+			if err := writeSink(out, sink, l.Logs, args.Query, spec); err != nil {
+				return fmt.Errorf("cannot write records to sink: %w", err)
+			}
+		} else if args.Percentiles {
+			values := extractDurations(l.Logs, durations, strings.Split(args.KeyNames, ","), cfg.Aliases)
+			printPercentiles(out, stats.Summarize(values), len(l.Logs))
+		} else if args.Patterns {
+			printPatterns(out, topPatterns(l.Logs, strings.Split(args.KeyNames, ",")), 0, args.Times)
+		} else if args.Agg != "" || args.GroupBy != "" || args.Histogram {
+			var h *histogram
+			if args.Histogram {
+				h = &histogram{start: spec.StartDate, end: spec.EndDate, buckets: histogramBuckets}
+			}
+			printAggregations(out, aggs, args.GroupBy, aggregate(l.Logs, aggs, args.GroupBy, cfg.Aliases), h)
+		} else {
+			printLogs(out, &l.Logs, &args)
+		}
+
+		renderTime = time.Since(renderStart)
+	}
+	prog.event(phaseDone)
+
+	if args.Copy {
+		if err := clipboard.Write(copied.String()); err != nil {
+			return fmt.Errorf("cannot copy records to clipboard: %w", err)
+		}
+	}
+	if args.Summary {
+		printSummary(os.Stderr, records, spec, l.Timings, processTime, renderTime, args.Times)
+	}
 	if len(l.Warnings) != 0 {
+		if fields, err := schema.Load(args.LogsURL); err == nil {
+			l.Warnings = suggestKeypaths(l.Warnings, fields)
+		}
 		printWarnings(os.Stderr, l.Warnings)
 	}
+	if len(found) != 0 {
+		printSecrets(os.Stderr, found, args.Times)
+	}
+	if records == 0 {
+		hints := noResultsHints(hintContext{
+			spec:      spec,
+			now:       time.Now(),
+			warnings:  l.Warnings,
+			scope:     profile.Scope,
+			retention: profile.Retention,
+			fetched:   fetched,
+			filtered:  pipe.filters(),
+			layout:    args.Times,
+		})
+		if len(hints) != 0 {
+			printHints(os.Stderr, hints)
+		}
+	}
+
+	if args.Link {
+		link, err := dashboardLink(&args, profile, spec)
+		if err != nil {
+			return fmt.Errorf("cannot create dashboard link: %w", err)
+		}
+		fmt.Fprint(os.Stderr, i18n.Sprintf("Link: %s\n", link))
+	}
+
+	return nil
 }
@@ -2,12 +2,25 @@ package main
 
 import (
 	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/netip"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/wooyey/iclogs/internal/platform/config"
+	"github.com/wooyey/iclogs/internal/platform/enrich"
+	"github.com/wooyey/iclogs/internal/platform/i18n"
 	"github.com/wooyey/iclogs/internal/platform/logs"
+	"github.com/wooyey/iclogs/internal/platform/logs/filter"
+	"github.com/wooyey/iclogs/internal/platform/redact"
+	"github.com/wooyey/iclogs/internal/platform/secrets"
 )
 
 func assert[T comparable](t testing.TB, got T, want T) {
@@ -17,6 +30,13 @@ func assert[T comparable](t testing.TB, got T, want T) {
 	}
 }
 
+func assertDeepEqual(t testing.TB, got, want any) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("\nGot:\t%+v\nWant:\t%+v", got, want)
+	}
+}
+
 func assertError(t testing.TB, got, want error) {
 	t.Helper()
 	if want == nil && got != nil {
@@ -41,14 +61,31 @@ func TestParseArgs(t *testing.T) {
 			input: "./iclogs --key ApiKey --from 2024-03-12T12:00 --to 2024-03-12T13:00 --range 30m --logs-url https://logs.endpoint.cloud.ibm.com --auth-url https://iam.different.cloud.ibm.com --message-fields another,keys lucene query",
 			envs:  map[string]string{},
 			want: CmdArgs{
-				APIKey:    "ApiKey",
-				TimeRange: time.Minute * 30,
-				LogsURL:   "https://logs.endpoint.cloud.ibm.com",
-				AuthURL:   "https://iam.different.cloud.ibm.com",
-				StartTime: timestamp(time.Date(2024, 3, 12, 12, 0, 0, 0, time.Local)),
-				EndTime:   timestamp(time.Date(2024, 3, 12, 13, 0, 0, 0, time.Local)),
-				Query:     "lucene query",
-				KeyNames:  "another,keys",
+				APIKey:         "ApiKey",
+				TimeRange:      time.Minute * 30,
+				RangeSet:       true,
+				LogsURL:        "https://logs.endpoint.cloud.ibm.com",
+				AuthURL:        "https://iam.different.cloud.ibm.com",
+				StartTime:      timestamp(time.Date(2024, 3, 12, 12, 0, 0, 0, time.Local)),
+				EndTime:        timestamp(time.Date(2024, 3, 12, 13, 0, 0, 0, time.Local)),
+				Query:          "lucene query",
+				KeyNames:       "another,keys",
+				DurationUnit:   defaultDurationUnit,
+				Refresh:        defaultRefresh,
+				Listen:         defaultListen,
+				CacheTTL:       defaultCacheTTL,
+				RateLimit:      defaultRateLimit,
+				Precision:      defaultPrecision,
+				Days:           defaultDays,
+				FlattenArrays:  defaultFlattenArrays,
+				ExportFormat:   formatNDJSON,
+				SchemaSample:   defaultSchemaSample,
+				App:            defaultIngestApp,
+				Subsystem:      defaultIngestSubsystem,
+				IngestSeverity: defaultIngestSeverity,
+				VerifyTimeout:  defaultVerifyTimeout,
+				Retries:        defaultRetries,
+				Output:         defaultOutput,
 			},
 		},
 		{
@@ -56,14 +93,31 @@ func TestParseArgs(t *testing.T) {
 			input: "./iclogs -k ApiKey -f 2024-03-12T12:00 -t 2024-03-12T13:00 -r 30m -l https://logs.endpoint.cloud.ibm.com -a https://iam.different.cloud.ibm.com -m some,keys lucene query",
 			envs:  map[string]string{},
 			want: CmdArgs{
-				APIKey:    "ApiKey",
-				TimeRange: time.Minute * 30,
-				LogsURL:   "https://logs.endpoint.cloud.ibm.com",
-				AuthURL:   "https://iam.different.cloud.ibm.com",
-				StartTime: timestamp(time.Date(2024, 3, 12, 12, 0, 0, 0, time.Local)),
-				EndTime:   timestamp(time.Date(2024, 3, 12, 13, 0, 0, 0, time.Local)),
-				Query:     "lucene query",
-				KeyNames:  "some,keys",
+				APIKey:         "ApiKey",
+				TimeRange:      time.Minute * 30,
+				RangeSet:       true,
+				LogsURL:        "https://logs.endpoint.cloud.ibm.com",
+				AuthURL:        "https://iam.different.cloud.ibm.com",
+				StartTime:      timestamp(time.Date(2024, 3, 12, 12, 0, 0, 0, time.Local)),
+				EndTime:        timestamp(time.Date(2024, 3, 12, 13, 0, 0, 0, time.Local)),
+				Query:          "lucene query",
+				KeyNames:       "some,keys",
+				DurationUnit:   defaultDurationUnit,
+				Refresh:        defaultRefresh,
+				Listen:         defaultListen,
+				CacheTTL:       defaultCacheTTL,
+				RateLimit:      defaultRateLimit,
+				Precision:      defaultPrecision,
+				Days:           defaultDays,
+				FlattenArrays:  defaultFlattenArrays,
+				ExportFormat:   formatNDJSON,
+				SchemaSample:   defaultSchemaSample,
+				App:            defaultIngestApp,
+				Subsystem:      defaultIngestSubsystem,
+				IngestSeverity: defaultIngestSeverity,
+				VerifyTimeout:  defaultVerifyTimeout,
+				Retries:        defaultRetries,
+				Output:         defaultOutput,
 			},
 		},
 		{
@@ -71,10 +125,26 @@ func TestParseArgs(t *testing.T) {
 			input: "./iclogs lucene query",
 			envs:  map[string]string{},
 			want: CmdArgs{
-				TimeRange: defaultTimeRange,
-				AuthURL:   defaultIAMURL,
-				Query:     "lucene query",
-				KeyNames:  defaultKeyNames,
+				TimeRange:      defaultTimeRange,
+				AuthURL:        defaultIAMURL,
+				Query:          "lucene query",
+				KeyNames:       defaultKeyNames,
+				DurationUnit:   defaultDurationUnit,
+				Refresh:        defaultRefresh,
+				Listen:         defaultListen,
+				CacheTTL:       defaultCacheTTL,
+				RateLimit:      defaultRateLimit,
+				Precision:      defaultPrecision,
+				Days:           defaultDays,
+				FlattenArrays:  defaultFlattenArrays,
+				ExportFormat:   formatNDJSON,
+				SchemaSample:   defaultSchemaSample,
+				App:            defaultIngestApp,
+				Subsystem:      defaultIngestSubsystem,
+				IngestSeverity: defaultIngestSeverity,
+				VerifyTimeout:  defaultVerifyTimeout,
+				Retries:        defaultRetries,
+				Output:         defaultOutput,
 			},
 		},
 		{
@@ -82,12 +152,28 @@ func TestParseArgs(t *testing.T) {
 			input: "./iclogs lucene query",
 			envs:  map[string]string{"LOGS_API_KEY": "api_key", "LOGS_ENDPOINT": "https://logs.cloud.ibm.com"},
 			want: CmdArgs{
-				TimeRange: defaultTimeRange,
-				AuthURL:   defaultIAMURL,
-				Query:     "lucene query",
-				LogsURL:   "https://logs.cloud.ibm.com",
-				APIKey:    "api_key",
-				KeyNames:  defaultKeyNames,
+				TimeRange:      defaultTimeRange,
+				AuthURL:        defaultIAMURL,
+				Query:          "lucene query",
+				LogsURL:        "https://logs.cloud.ibm.com",
+				APIKey:         "api_key",
+				KeyNames:       defaultKeyNames,
+				DurationUnit:   defaultDurationUnit,
+				Refresh:        defaultRefresh,
+				Listen:         defaultListen,
+				CacheTTL:       defaultCacheTTL,
+				RateLimit:      defaultRateLimit,
+				Precision:      defaultPrecision,
+				Days:           defaultDays,
+				FlattenArrays:  defaultFlattenArrays,
+				ExportFormat:   formatNDJSON,
+				SchemaSample:   defaultSchemaSample,
+				App:            defaultIngestApp,
+				Subsystem:      defaultIngestSubsystem,
+				IngestSeverity: defaultIngestSeverity,
+				VerifyTimeout:  defaultVerifyTimeout,
+				Retries:        defaultRetries,
+				Output:         defaultOutput,
 			},
 		},
 		{
@@ -95,12 +181,112 @@ func TestParseArgs(t *testing.T) {
 			input: "./iclogs -k some_key lucene query",
 			envs:  map[string]string{"LOGS_API_KEY": "api_key", "LOGS_ENDPOINT": "https://logs.cloud.ibm.com"},
 			want: CmdArgs{
-				TimeRange: defaultTimeRange,
-				AuthURL:   defaultIAMURL,
-				Query:     "lucene query",
-				LogsURL:   "https://logs.cloud.ibm.com",
-				APIKey:    "some_key",
-				KeyNames:  defaultKeyNames,
+				TimeRange:      defaultTimeRange,
+				AuthURL:        defaultIAMURL,
+				Query:          "lucene query",
+				LogsURL:        "https://logs.cloud.ibm.com",
+				APIKey:         "some_key",
+				KeyNames:       defaultKeyNames,
+				DurationUnit:   defaultDurationUnit,
+				Refresh:        defaultRefresh,
+				Listen:         defaultListen,
+				CacheTTL:       defaultCacheTTL,
+				RateLimit:      defaultRateLimit,
+				Precision:      defaultPrecision,
+				Days:           defaultDays,
+				FlattenArrays:  defaultFlattenArrays,
+				ExportFormat:   formatNDJSON,
+				SchemaSample:   defaultSchemaSample,
+				App:            defaultIngestApp,
+				Subsystem:      defaultIngestSubsystem,
+				IngestSeverity: defaultIngestSeverity,
+				VerifyTimeout:  defaultVerifyTimeout,
+				Retries:        defaultRetries,
+				Output:         defaultOutput,
+			},
+		},
+		{
+			name:  "GetCommand",
+			input: "./iclogs get -r 24h 2875ffa6-d102-4043-b9dd-a8daf3f7d3c7",
+			envs:  map[string]string{},
+			want: CmdArgs{
+				Command:        commandGet,
+				TimeRange:      24 * time.Hour,
+				RangeSet:       true,
+				AuthURL:        defaultIAMURL,
+				Query:          "2875ffa6-d102-4043-b9dd-a8daf3f7d3c7",
+				KeyNames:       defaultKeyNames,
+				DurationUnit:   defaultDurationUnit,
+				Refresh:        defaultRefresh,
+				Listen:         defaultListen,
+				CacheTTL:       defaultCacheTTL,
+				RateLimit:      defaultRateLimit,
+				Precision:      defaultPrecision,
+				Days:           defaultDays,
+				FlattenArrays:  defaultFlattenArrays,
+				ExportFormat:   formatNDJSON,
+				SchemaSample:   defaultSchemaSample,
+				App:            defaultIngestApp,
+				Subsystem:      defaultIngestSubsystem,
+				IngestSeverity: defaultIngestSeverity,
+				VerifyTimeout:  defaultVerifyTimeout,
+				Retries:        defaultRetries,
+				Output:         defaultOutput,
+			},
+		},
+		{
+			name:  "RepeatedQueries",
+			input: "./iclogs -q first --query second third",
+			envs:  map[string]string{},
+			want: CmdArgs{
+				TimeRange:      defaultTimeRange,
+				AuthURL:        defaultIAMURL,
+				Query:          "(third) OR (first) OR (second)",
+				Queries:        queries{"first", "second"},
+				KeyNames:       defaultKeyNames,
+				DurationUnit:   defaultDurationUnit,
+				Refresh:        defaultRefresh,
+				Listen:         defaultListen,
+				CacheTTL:       defaultCacheTTL,
+				RateLimit:      defaultRateLimit,
+				Precision:      defaultPrecision,
+				Days:           defaultDays,
+				FlattenArrays:  defaultFlattenArrays,
+				ExportFormat:   formatNDJSON,
+				SchemaSample:   defaultSchemaSample,
+				App:            defaultIngestApp,
+				Subsystem:      defaultIngestSubsystem,
+				IngestSeverity: defaultIngestSeverity,
+				VerifyTimeout:  defaultVerifyTimeout,
+				Retries:        defaultRetries,
+				Output:         defaultOutput,
+			},
+		},
+		{
+			name:  "SeparatedQueries",
+			input: "./iclogs first query -- second query",
+			envs:  map[string]string{},
+			want: CmdArgs{
+				TimeRange:      defaultTimeRange,
+				AuthURL:        defaultIAMURL,
+				Query:          "(first query) OR (second query)",
+				KeyNames:       defaultKeyNames,
+				DurationUnit:   defaultDurationUnit,
+				Refresh:        defaultRefresh,
+				Listen:         defaultListen,
+				CacheTTL:       defaultCacheTTL,
+				RateLimit:      defaultRateLimit,
+				Precision:      defaultPrecision,
+				Days:           defaultDays,
+				FlattenArrays:  defaultFlattenArrays,
+				ExportFormat:   formatNDJSON,
+				SchemaSample:   defaultSchemaSample,
+				App:            defaultIngestApp,
+				Subsystem:      defaultIngestSubsystem,
+				IngestSeverity: defaultIngestSeverity,
+				VerifyTimeout:  defaultVerifyTimeout,
+				Retries:        defaultRetries,
+				Output:         defaultOutput,
 			},
 		},
 	}
@@ -119,7 +305,7 @@ func TestParseArgs(t *testing.T) {
 			}()
 
 			got := parseArgs()
-			assert(t, got, tt.want)
+			assertDeepEqual(t, got, tt.want)
 		})
 	}
 
@@ -134,37 +320,361 @@ func TestPrintUsage(t *testing.T) {
 	printUsage(&b)
 	got := b.String()
 
-	want := `Usage of ./iclogs: [options] <lucene query>
+	want := `Usage of ./iclogs: [command] [options] <lucene query> [-- <lucene query> ...]
 
+Commands:
+  api <method> <path>
+        Call management API of the instance with the same token, ie. GET /v1/alerts, printing response JSON. For endpoints without own command.
+  assert <lucene query>
+        Compare found records, without their ID and time, with --golden file in export format, exiting with status 1 on drift.
+  auth whoami
+        Print identity, account, expiry and scopes of IAM token obtained for the API key.
+  bugreport
+        Print issue-ready report with version, platform and the latest crash diagnostic report.
+  config export|import <bundle.tar.gz>
+        Export profiles, saved queries, aliases and redactors (without audit settings) as bundle, or merge bundle into configuration file.
+  context list|current|use <profile>
+        List profiles with their account and endpoints, print profile in use, or persist default profile like kubectl contexts.
+  dash <lucene query>
+        Show terminal dashboard (rate per severity, top applications, latest errors) refreshed until interrupted.
+  docs man|markdown
+        Print reference of commands and options as man page or Markdown, generated from the same metadata as this usage.
+  doctor
+        Check connectivity to IAM, logs and ingestion endpoints, explaining private endpoints unreachable from current network.
+  e2m list|show <id>
+        List events-to-metrics definitions of the instance with their query and metrics, or show one with its filters, labels and fields.
+  enrichments list
+        List enrichments of the instance: enriched record fields with enrichment type (geo_ip, suspicious_ip or custom).
+  export <lucene query>
+        Write found records as JSON lines or CSV files, one per time range chunk, optionally uploaded to object storage.
+  get <record id>
+        Print one full record by its ID. Time range options need to cover record timestamp.
+  ingest
+        Send test record with --message through ingestion API of the logs instance, to verify ingestion and search end-to-end.
+  labels [application|subsystem]
+        List distinct application and subsystem label values found in time range, or cached values of one label for shell completion.
+  mcp
+        Serve read-only query, tail and stats tools over Model Context Protocol (stdio) within profile scope and time range.
+  open <lucene query>
+        Open the search in IBM Cloud Logs dashboard using default browser.
+  plugins
+        List output sink plugins found in plugins directory, usable with --sink option.
+  policies list
+        List TCO policies of the instance in evaluation order with tier (priority insights, analyze and alert, store and search) of matched records.
+  push
+        Forward lines or NDJSON read from standard input to ingestion API of the logs instance, labeled with --app and --subsystem.
+  serve
+        Serve web UI and REST API (/query and /tail with server-sent events) running searches within profile scope and time range.
+  ship
+        Tail --file paths and forward appended lines to ingestion API of the logs instance until interrupted, resuming from checkpointed positions.
+  slackbot
+        Serve Slack slash command (/slack/commands) and mentions (/slack/events) running saved queries allowed in configuration.
+  snippets
+        List built-in Dataprime snippets usable with --snippet option, with their parameters in braces.
+  summarize <lucene query>
+        Report incident window at once: records stats, timeline per severity, top applications (or other --group-by field) and top message patterns with their first and last occurrence.
+  usage [last_week|current_month|last_30_days|last_90_days]
+        Report ingested GB per day and TCO policy tier from data usage API, last week by default.
+  verify-pipeline
+        Send uniquely tagged record through ingestion API and search for it until found or --verify-timeout, reporting end-to-end latency.
+  watch <lucene query>
+        Count records every refresh interval, notifying when count goes above threshold or trigger expression holds and when it clears.
+  webhooks list|test <id>
+        List outbound integrations of the instance, or send test notification to URL of one, verifying alert notification channel.
+
+Options:
   -a, --auth-url string
-        Authorization Endpoint URL. (default https://iam.cloud.ibm.com)
+        Authorization Endpoint URL, or public, private or test IAM endpoint. (default https://iam.cloud.ibm.com)
+  --agg aggregations
+        Show comma separated aggregations (sum, avg, min, max) of numeric fields instead of records, ie. avg(json.response_time),max(json.bytes).
+  --align interval
+        Snap time range start down and end up to multiples of interval, ie. 5m.
+  --app name
+        Application name of records sent by ingest, push, ship and verify-pipeline commands. (default iclogs)
+  -c, --config ICLOGS_CONFIG
+        Configuration file path. Overrides ICLOGS_CONFIG environment variable.
+  --cache-ttl duration
+        Time to reuse serve command results of the same query and range, 0 disables cache. (default 30s)
+  --checkpoint file
+        JSON file with read positions of ship command or state of watch command, in user cache directory by default.
+  --chunk duration
+        Time range of one export command file, 0 means whole time range.
+  --compact
+        Show records compactly on narrow terminals: time of day, single character severity glyph, and shortened ID and labels when shown.
+  --copy
+        Copy printed records to system clipboard.
+  --csv
+        Print usage command report as CSV, without totals row.
+  --daily from..to
+        Search the same time of day window from..to, ie. 03:00..03:30, on each of last days.
+  --data body
+        JSON request body of api command, @file reads it from file and @- from standard input.
+  --days days
+        Number of last days searched with daily window. (default 7)
+  --deadline duration
+        Time budget shared by all queries of one-shot run, ie. export chunks or windows, 0 means no limit. Each request keeps its own timeout, serve, mcp, slackbot, watch and dash commands are not limited.
+  --debug
+        Log debug messages like --verbose, with their source code location.
+  --drop-fields paths
+        Comma separated user data paths removed from records before output and export, ie. kubernetes.annotations,tag,file.
+  --duration-unit duration
+        Unit of extracted durations given without one. (default 1ms)
+  --encrypt method
+        Encrypt export command files before writing with method age:<recipients file> or gpg:<recipient>.
+  --enrich file
+        CSV or JSON lookup table file joined onto records as enrichment user data object.
+  --enrich-key field
+        Record field used as lookup key for enrichment, ie. json.node_name.
+  --explain
+        Print resolved query request, endpoint, tier, time window in UTC and token subject without running it, for support tickets.
+  --export-format string
+        Format of export command files: ndjson or csv. (default ndjson)
+  --extract-duration expression
+        Regular expression with capture group matched on message (ie. 'took (\d+)ms') or record field with duration.
   -f, --from 2006-01-02T15:04
-        Start time for log search in format 2006-01-02T15:04.
+        Start time for log search in format 2006-01-02T15:04, with optional seconds and their fraction, RFC3339 time, date, now, today or yesterday.
+  --fallback-url string
+        Logs endpoint URL failed over to when logs endpoint cannot be connected, ie. public one of private endpoint.
+  --file path
+        Log path tailed by ship command. Can be repeated.
+  --flatten
+        Rewrite user data as single level object with dotted keys, ie. kubernetes.labels.app, before output and export.
+  --flatten-arrays mode
+        Arrays handling mode of flattened user data: index (tags.0), join (comma separated string) or keep. (default index)
+  --force
+        Receive query responses of any size without confirmation.
+  --geoip file
+        MaxMind DB file (ie. GeoLite2 Country or ASN) for GeoIP enrichment.
+  --geoip-field field
+        Record field with IP address for GeoIP enrichment, ie. json.client_ip.
+  --golden file
+        Golden file of assert command with expected records in export format.
+  --group-by field
+        Record field to group records count and aggregations by, ie. json.service.
+  --histogram
+        Show sparkline of records volume over time range next to each group.
+  --idle-timeout duration
+        Abort query only when no data, keepalives included, arrives for duration, instead of after 3 minutes request timeout.
+  --ingress-url URL
+        Ingestion endpoint URL of ingest, push, ship and verify-pipeline commands, derived from logs endpoint by default.
+  --ip-field field
+        Record field with IP address for network filter, ie. json.client_ip.
+  --ip-in networks
+        Keep records with IP address within comma separated networks, ie. 10.0.0.0/8,192.168.0.0/16.
   -j, --show-json
         Show record as JSON.
   -k, --key LOG_API_KEY
         API Key to use. Overrides LOG_API_KEY environment variable.
   -l, --logs-url LOGS_ENDPOINT
         URL of IBM Cloud Log Endpoint. Overrides LOGS_ENDPOINT environment variable.
+  --link
+        Print link to the same search in IBM Cloud Logs dashboard.
+  --listen address
+        Listen address of serve and slackbot commands. (default localhost:8080)
+  --low-memory
+        Print, export or send records to sink as they arrive without keeping them, ordered only within received batches.
   -m, --message-fields string
         Comma separated message field names. (default message,message_obj.msg,log)
+  --max-bandwidth rate
+        Limit reading of export command responses to rate, ie. 10MB/s or 512KiB/s, so exports don't saturate VPN links.
+  --max-field-bytes bytes
+        Truncate displayed message or JSON longer than bytes, 0 means no limit.
+  --max-response size
+        Query response size, ie. 500MB, over which search or export asks to continue on terminal, otherwise aborts. 1GiB by default.
+  --message text
+        Message text of record sent by ingest command, JSON object is sent as structured data.
+  --notify-opsgenie key
+        Opsgenie API key to create and close alerts from watch command.
+  --notify-pagerduty key
+        Routing key of PagerDuty Events API integration to create and resolve incidents from watch command.
+  -o, --output directory
+        Output directory of export command files. (default .)
+  --override
+        Run queries exceeding maximum range and records of the profile.
+  -p, --profile ICLOGS_PROFILE
+        Configuration profile to use. Overrides ICLOGS_PROFILE environment variable.
+  --param name=value
+        Snippet parameter as name=value, filling {name} placeholder. Can be repeated.
+  --patterns
+        Show message patterns, messages with numbers, IDs, addresses and times masked, with records count and first and last occurrence instead of records.
+  --percentiles
+        Show percentiles of extracted durations instead of records.
+  --precision unit
+        Precision unit of displayed times: s, ms, us or ns. (default s)
+  --private
+        Use private endpoints of logs instance and IAM, reachable only from IBM Cloud private network.
+  --progress format
+        Write newline-delimited progress events (phase, shard, percent of shards done, records so far) to standard error in format, only json is supported.
+  -q, --query query
+        Lucene query to run. Can be repeated, all queries are OR-combined.
   -r, --range duration
-        Relative time for log search, from now (or from end time if specified). (default 1h0m0s)
+        Relative time for log search, before now or end time, or after start time when only it is given. (default 1h0m0s)
+  --rate-limit requests
+        Maximum requests per minute from one client of serve command, 0 means no limit. (default 60)
+  --redact names
+        Comma separated names of redactors hiding sensitive data (built-in: creditcard, email, ip).
+  --refresh duration
+        Refresh interval of dash and watch commands and serve command tail. (default 10s)
+  --refresh-cache
+        Fetch management API listings and label values instead of using cached ones.
+  --refresh-queries
+        Fetch shared saved queries from configured source instead of using cached copy.
+  --retries int
+        Retries of failed export command query, each querying failed time window in halves. (default 2)
+  -s, --saved name
+        Run saved query with given name from configuration file, ANDed with given query.
+  --scan-secrets
+        Warn about records containing likely secrets.
+  --schema file
+        JSON file with array of {"name", "type"} columns of CSV export, instead of columns inferred from records.
+  --schema-sample records
+        Number of first records whose fields make columns of CSV export. (default 1000)
+  --session name
+        Pin endpoint, time window and token under session name on first use and reuse them in later runs with the same name.
+  --severity name
+        Severity name of records sent by ingest, push, ship and verify-pipeline commands: debug, verbose, info, warning, error or critical. JSON lines pushed with severity or level field keep their own. (default info)
+  --show-id
+        Show record ID.
+  --show-label key
+        Show only record label with given key, ie. applicationname. Can be repeated.
   --show-labels
         Show record labels.
   --show-severity
         Show record severity.
   --show-timestamp
         Show record timestamp.
+  --silence window
+        Daily window as HH:MM-HH:MM or one-off from..to window when watch command does not notify, ie. maintenance. Silenced intervals are marked in interval lines. Can be repeated.
+  --silence-file path
+        File path with silence windows of watch command, one per line with # comments, read again each interval.
+  --sink name
+        Send found records as JSON lines to sink plugin with given name instead of printing them.
+  --slack-signing-secret SLACK_SIGNING_SECRET
+        Slack app signing secret. Overrides SLACK_SIGNING_SECRET environment variable.
+  --slack-token SLACK_BOT_TOKEN
+        Slack bot token. Overrides SLACK_BOT_TOKEN environment variable.
+  --snippet name
+        Run built-in Dataprime snippet name (see snippets command), query is then applied as Lucene stage before the snippet pipeline.
+  --storage-url URL
+        Object storage endpoint URL for upload, ie. https://s3.us-south.cloud-object-storage.appdomain.cloud.
+  --subsystem name
+        Subsystem name of records sent by ingest, push, ship and verify-pipeline commands. (default ingest)
+  --summary
+        Print records count, time range and timings of query phases to standard error.
   -t, --to 2006-01-02T15:04
-        End time for log search in range format 2006-01-02T15:04.
+        End time for log search in range format 2006-01-02T15:04, with optional seconds and their fraction, RFC3339 time, date, now, today or yesterday.
+  --threshold count
+        Records count per interval above which watch command triggers.
+  --update-golden
+        Write found records to golden file of assert command instead of comparing them.
+  --upload location
+        Upload export command files to location in cos://bucket/prefix/ format.
+  --upload-sse algorithm
+        Server-side encryption algorithm of uploaded files, ie. AES256.
+  --utc
+        Parse time options, query and display all times in UTC, shown with explicit zone suffix.
+  --verbose
+        Log debug messages of internal steps, ie. queries run and tokens obtained, to standard error.
+  --verify-timeout duration
+        Time verify-pipeline command waits for sent record to be found. (default 2m0s)
   --version
         Show binary version.
+  -w, --where expression
+        Client-side filter expression over id, severity, timestamp, label.<key> and json.<path> fields.
+  --when expression
+        Trigger expression of watch command evaluated per interval instead of --threshold, over count, count(<filter expression>), rate per minute and baseline rate of previous intervals, ie. 'count(severity>=error) > 10 || rate > 2*baseline'.
+  --window from..to
+        Time window from..to setting start and end time at once, ie. 14:00..14:15 for today or 2006-01-02T15:04..2006-01-02T15:20. Can be repeated, search then queries each window and labels records with it.
+  -y, --yes
+        Run expensive archive scans without confirmation.
 `
 
 	assert(t, got, want)
 }
 
+// Messages translated with i18n, literals and usage of commands and options, need to be in each catalog
+func TestTranslations(t *testing.T) {
+	var messages []string
+
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fset := token.NewFileSet()
+	for _, name := range files {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, name, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ast.Inspect(f, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || sel.Sel.Name != "T" && sel.Sel.Name != "Sprintf" {
+				return true
+			}
+			if pkg, ok := sel.X.(*ast.Ident); !ok || pkg.Name != "i18n" {
+				return true
+			}
+			if lit, ok := call.Args[0].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+				msg, err := strconv.Unquote(lit.Value)
+				if err != nil {
+					t.Fatal(err)
+				}
+				messages = append(messages, msg)
+			}
+			return true
+		})
+	}
+
+	os.Args = []string{"./iclogs"}
+	initParser(&CmdArgs{})
+	for _, n := range commandNames() {
+		messages = append(messages, commands[n].usage)
+	}
+	for _, o := range options() {
+		messages = append(messages, o.usage)
+	}
+
+	for _, lang := range i18n.Languages() {
+		for _, m := range messages {
+			if !i18n.Translated(lang, m) {
+				t.Errorf("Missing %s translation of: %q", lang, m)
+			}
+		}
+	}
+}
+
+func TestCombineQueries(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input []string
+		want  string
+	}{
+		{name: "Empty", input: []string{}, want: ""},
+		{name: "Single", input: []string{"kubernetes.pod_name:some-pod"}, want: "kubernetes.pod_name:some-pod"},
+		{name: "SkipBlank", input: []string{" ", "some query "}, want: "some query"},
+		{name: "Multiple", input: []string{"a AND b", "c"}, want: "(a AND b) OR (c)"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got := combineQueries(tt.input)
+			assert(t, got, tt.want)
+		})
+	}
+}
+
+func TestRecordQuery(t *testing.T) {
+	assert(t, recordQuery("some-id", ""), "source logs | filter $m.logid == 'some-id'")
+	assert(t, recordQuery("it's", "applicationname:prod-*"), `source logs | filter $m.logid == 'it\'s' | lucene 'applicationname:prod-*'`)
+}
+
 func TestGetVersion(t *testing.T) {
 
 	version = "v1.0.0"
@@ -200,6 +710,56 @@ func TestValidateArgs(t *testing.T) {
 			input: CmdArgs{APIKey: "api_key", LogsURL: "url"},
 			want:  errMissingQuery,
 		},
+		{
+			name:  "OpenWithoutAPIKey",
+			input: CmdArgs{Command: commandOpen, LogsURL: "url", Query: "some query"},
+			want:  nil,
+		},
+		{
+			name:  "MissingEnrichKey",
+			input: CmdArgs{APIKey: "api_key", LogsURL: "url", Query: "some query", Enrich: "nodes.csv"},
+			want:  errMissingEnrich,
+		},
+		{
+			name:  "MissingGeoIPField",
+			input: CmdArgs{APIKey: "api_key", LogsURL: "url", Query: "some query", GeoIP: "GeoLite2-ASN.mmdb"},
+			want:  errMissingGeoIP,
+		},
+		{
+			name:  "MissingIPField",
+			input: CmdArgs{APIKey: "api_key", LogsURL: "url", Query: "some query", IPIn: "10.0.0.0/8"},
+			want:  errMissingIPField,
+		},
+		{
+			name:  "MissingID",
+			input: CmdArgs{Command: commandGet, APIKey: "api_key", LogsURL: "url"},
+			want:  errMissingID,
+		},
+		{
+			name:  "MissingStorageURL",
+			input: CmdArgs{Command: commandExport, APIKey: "api_key", LogsURL: "url", Query: "some query", Upload: "cos://exports/"},
+			want:  errMissingStorage,
+		},
+		{
+			name:  "MissingSlackToken",
+			input: CmdArgs{Command: commandSlack, APIKey: "api_key", LogsURL: "url", SlackSecret: "secret"},
+			want:  errMissingSlack,
+		},
+		{
+			name:  "MissingGolden",
+			input: CmdArgs{Command: commandAssert, APIKey: "api_key", LogsURL: "url", Query: "some query"},
+			want:  errMissingGolden,
+		},
+		{
+			name:  "InvalidRefresh",
+			input: CmdArgs{Command: commandDash, APIKey: "api_key", LogsURL: "url", Query: "some query"},
+			want:  errInvalidRefresh,
+		},
+		{
+			name:  "LowMemoryAggregations",
+			input: CmdArgs{APIKey: "api_key", LogsURL: "url", Query: "some query", LowMemory: true, GroupBy: "json.service"},
+			want:  errLowMemory,
+		},
 	}
 
 	for _, tt := range testCases {
@@ -216,9 +776,10 @@ func TestPrintLogs(t *testing.T) {
 	logs := []logs.Log{
 		{
 			Time:     time.Date(2025, 1, 11, 18, 52, 21, 26304000, time.Local),
+			ID:       "2875ffa6-d102-4043-b9dd-a8daf3f7d3c7",
 			Severity: "Debug",
 			UserData: `{"message":"some_message"}`,
-			Labels:   []string{"label:\"value-of-label\""},
+			Labels:   []logs.KeyValue{{Key: "label", Value: "value-of-label"}},
 		},
 	}
 
@@ -242,6 +803,11 @@ func TestPrintLogs(t *testing.T) {
 			args: CmdArgs{KeyNames: defaultKeyNames, Severity: true},
 			want: "[Debug] some_message\n",
 		},
+		{
+			name: "ShowID",
+			args: CmdArgs{KeyNames: defaultKeyNames, ShowID: true},
+			want: "#2875ffa6-d102-4043-b9dd-a8daf3f7d3c7 some_message\n",
+		},
 		{
 			name: "ShowLabels",
 			args: CmdArgs{KeyNames: defaultKeyNames, Labels: true},
@@ -252,6 +818,31 @@ func TestPrintLogs(t *testing.T) {
 			args: CmdArgs{KeyNames: defaultKeyNames, JSON: true},
 			want: "{\"message\":\"some_message\"}\n",
 		},
+		{
+			name: "MaxBytes",
+			args: CmdArgs{KeyNames: defaultKeyNames, MaxBytes: 4},
+			want: "some… [8 bytes truncated]\n",
+		},
+		{
+			name: "MaxBytesJSON",
+			args: CmdArgs{KeyNames: defaultKeyNames, JSON: true, MaxBytes: 11},
+			want: "{\"message\":… [15 bytes truncated]\n",
+		},
+		{
+			name: "ShowLabel",
+			args: CmdArgs{KeyNames: defaultKeyNames, ShowLabel: labelKeys{"missing", "label"}},
+			want: "<label:\"value-of-label\"> some_message\n",
+		},
+		{
+			name: "Compact",
+			args: CmdArgs{KeyNames: defaultKeyNames, Compact: true, Timestamp: true, Severity: true},
+			want: "18:52:21 ○ some_message\n",
+		},
+		{
+			name: "CompactIDAndLabels",
+			args: CmdArgs{KeyNames: defaultKeyNames, Compact: true, ShowID: true, Labels: true},
+			want: "18:52:21 ○ #2875ffa6 <value-of-la…> some_message\n",
+		},
 	}
 
 	for _, tt := range testCases {
@@ -265,6 +856,24 @@ func TestPrintLogs(t *testing.T) {
 
 }
 
+func TestPrintSecrets(t *testing.T) {
+	found := []secrets.Finding{
+		{Kind: "private key", Count: 2, First: time.Date(2025, 1, 11, 18, 52, 21, 0, time.Local)},
+	}
+	want := "Possible secrets:\n- private key: 2 record(s), first at 2025-01-11 18:52:21\n"
+
+	buffer := bytes.Buffer{}
+	printSecrets(&buffer, found, timeLayout{})
+	got := buffer.String()
+	assert(t, got, want)
+}
+
+func TestTruncate(t *testing.T) {
+	assert(t, truncate("short", 0), "short")
+	assert(t, truncate("short", 5), "short")
+	assert(t, truncate("zażółć", 3), "za… [8 bytes truncated]")
+}
+
 func TestPrintWarnings(t *testing.T) {
 	warnings := []string{
 		"some warning",
@@ -277,3 +886,253 @@ func TestPrintWarnings(t *testing.T) {
 	got := buffer.String()
 	assert(t, got, want)
 }
+
+func TestPrintSummary(t *testing.T) {
+	start := time.Date(2025, 1, 11, 18, 0, 0, 0, time.FixedZone("CET", 3600))
+	spec := logs.QuerySpec{StartDate: start, EndDate: start.Add(time.Hour)}
+	timings := logs.Timings{Token: 120 * time.Millisecond, FirstByte: 790 * time.Millisecond, Request: 800 * time.Millisecond, Parse: 45 * time.Millisecond}
+	want := "Summary:\n- records: 12\n- time range: 2025-01-11 18:00:00 - 2025-01-11 19:00:00 (CET +01:00, 1h0m0s)\n" +
+		"- timings: token 120ms, first byte 790ms, request 800ms, parse 45ms, process 2ms, render 3ms, total 970ms\n"
+
+	buffer := bytes.Buffer{}
+	printSummary(&buffer, 12, spec, timings, 2*time.Millisecond+100*time.Microsecond, 3*time.Millisecond, timeLayout{})
+	assert(t, buffer.String(), want)
+}
+
+func TestFilterLogs(t *testing.T) {
+	l := []logs.Log{
+		{Severity: "Debug", UserData: `{"message":"first"}`},
+		{Severity: "Error", UserData: `{"message":"second"}`},
+	}
+
+	f, err := filter.Parse(`severity=="Error"`)
+	if err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+
+	got := filterLogs(l, f, nil)
+	assertDeepEqual(t, got, l[1:])
+}
+
+func TestFilterLogsWithAliases(t *testing.T) {
+	l := []logs.Log{
+		{Severity: "Debug", UserData: `{"kubernetes":{"namespace_name":"dev"}}`},
+		{Severity: "Error", UserData: `{"kubernetes":{"namespace_name":"prod"}}`},
+	}
+
+	f, err := filter.Parse(`ns=="prod"`)
+	if err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+
+	got := filterLogs(l, f, config.Aliases{"ns": "json.kubernetes.namespace_name"})
+	assertDeepEqual(t, got, l[1:])
+}
+
+func TestExpandKeyNames(t *testing.T) {
+	aliases := config.Aliases{"msg": "json.message_obj.msg", "short": "log"}
+
+	got := expandKeyNames("message,msg,short", aliases)
+	assert(t, got, "message,message_obj.msg,log")
+}
+
+func TestApplyProfile(t *testing.T) {
+	profile := config.Profile{LogsURL: "https://profile.logs.cloud.ibm.com", AuthURL: "https://iam.profile.cloud.ibm.com", APIKeyEnv: "ICLOGS_TEST_KEY"}
+	t.Setenv("ICLOGS_TEST_KEY", "Profile_Key")
+
+	testCases := []struct {
+		name  string
+		input CmdArgs
+		want  CmdArgs
+	}{
+		{
+			name:  "FillMissing",
+			input: CmdArgs{AuthURL: defaultIAMURL},
+			want:  CmdArgs{LogsURL: profile.LogsURL, AuthURL: profile.AuthURL, APIKey: "Profile_Key"},
+		},
+		{
+			name:  "KeepGiven",
+			input: CmdArgs{LogsURL: "https://logs.cloud.ibm.com", AuthURL: "https://iam.test.cloud.ibm.com", APIKey: "Key"},
+			want:  CmdArgs{LogsURL: "https://logs.cloud.ibm.com", AuthURL: "https://iam.test.cloud.ibm.com", APIKey: "Key"},
+		},
+		{
+			name:  "NamedEndpoint",
+			input: CmdArgs{LogsURL: "https://logs.cloud.ibm.com", AuthURL: "private", APIKey: "Key"},
+			want:  CmdArgs{LogsURL: "https://logs.cloud.ibm.com", AuthURL: "https://private.iam.cloud.ibm.com", APIKey: "Key"},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			applyProfile(&tt.input, profile)
+			assertDeepEqual(t, tt.input, tt.want)
+		})
+	}
+}
+
+func TestParseArgsRange(t *testing.T) {
+	from := time.Date(2025, 1, 11, 12, 0, 0, 0, time.Local)
+	now := from.Add(5 * time.Hour)
+
+	testCases := []struct {
+		name  string
+		input string
+		end   time.Time
+	}{
+		{name: "FromWithRange", input: "iclogs -f 2025-01-11T12:00 -r 1h query", end: from.Add(time.Hour)},
+		{name: "FromWithLongRange", input: "iclogs --from 2025-01-11T12:00 --range 2h query", end: from.Add(2 * time.Hour)},
+		{name: "FromOnly", input: "iclogs -f 2025-01-11T12:00 query", end: now},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = strings.Split(tt.input, " ")
+			args := parseArgs()
+
+			start, end, err := searchWindow(time.Time(args.StartTime), time.Time(args.EndTime), args.TimeRange, args.RangeSet, now)
+			assertError(t, err, nil)
+			assert(t, start, from)
+			assert(t, end, tt.end)
+		})
+	}
+}
+
+func TestApplySavedQuery(t *testing.T) {
+	saved := config.SavedQuery{Query: "severity:error", Range: "15m"}
+
+	testCases := []struct {
+		name  string
+		input CmdArgs
+		want  CmdArgs
+	}{
+		{
+			name:  "SavedOnly",
+			input: CmdArgs{TimeRange: defaultTimeRange},
+			want:  CmdArgs{Query: "severity:error", TimeRange: 15 * time.Minute, RangeSet: true},
+		},
+		{
+			name:  "WithQueryAndRange",
+			input: CmdArgs{Query: "timeout", TimeRange: 3 * time.Hour, RangeSet: true},
+			want:  CmdArgs{Query: "(severity:error) AND (timeout)", TimeRange: 3 * time.Hour, RangeSet: true},
+		},
+		{
+			name:  "WithDefaultValueRange",
+			input: CmdArgs{TimeRange: defaultTimeRange, RangeSet: true},
+			want:  CmdArgs{Query: "severity:error", TimeRange: defaultTimeRange, RangeSet: true},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assertError(t, applySavedQuery(&tt.input, saved), nil)
+			assertDeepEqual(t, tt.input, tt.want)
+		})
+	}
+
+	args := CmdArgs{TimeRange: defaultTimeRange}
+	if err := applySavedQuery(&args, config.SavedQuery{Query: "a", Range: "soon"}); err == nil {
+		t.Error("Should get an error for invalid saved query range!")
+	}
+}
+
+func TestScopeQuery(t *testing.T) {
+	assert(t, scopeQuery("", "some query"), "some query")
+	assert(t, scopeQuery("applicationname:prod-*", "a OR b"), "(applicationname:prod-*) AND (a OR b)")
+}
+
+func TestRedactLogs(t *testing.T) {
+	l := []logs.Log{
+		{UserData: `{"message":"user a@b.io logged in"}`, Labels: []logs.KeyValue{{Key: "ipaddress", Value: "10.0.0.1"}}},
+	}
+
+	s, err := redact.New("email,ip", nil)
+	if err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+
+	redactLogs(l, s)
+
+	assert(t, l[0].UserData, `{"message":"user [REDACTED:email] logged in"}`)
+	assert(t, l[0].Labels[0].String(), `ipaddress:"[REDACTED:ip]"`)
+
+	l = []logs.Log{{UserData: `{"message":"paid","order_id":4111111111111111}`}}
+	s, err = redact.New("creditcard", nil)
+	if err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+
+	redactLogs(l, s)
+
+	assert(t, l[0].UserData, `{"message":"paid","order_id":"[REDACTED:creditcard]"}`)
+	msg, err := l[0].Message([]string{"message"})
+	assertError(t, err, nil)
+	assert(t, msg, "paid")
+}
+
+func TestDashboardLink(t *testing.T) {
+	start := time.Date(2025, 1, 11, 18, 0, 0, 0, time.UTC)
+	spec := logs.QuerySpec{Syntax: "lucene", StartDate: start, EndDate: start.Add(time.Hour)}
+	args := CmdArgs{LogsURL: "https://1234.api.eu-de.logs.cloud.ibm.com", Query: "q"}
+	suffix := "/#/query-new/logs?query=q&querySyntax=lucene&time=from%3A2025-01-11T18%3A00%3A00.000Z%2Cto%3A2025-01-11T19%3A00%3A00.000Z"
+
+	got, err := dashboardLink(&args, config.Profile{}, spec)
+	assertError(t, err, nil)
+	assert(t, got, "https://1234.eu-de.logs.cloud.ibm.com"+suffix)
+
+	got, err = dashboardLink(&args, config.Profile{DashboardURL: "https://dashboard.example.com"}, spec)
+	assertError(t, err, nil)
+	assert(t, got, "https://dashboard.example.com"+suffix)
+}
+
+func TestEnrichLogs(t *testing.T) {
+	l := []logs.Log{
+		{UserData: `{"node_name":"10.10.10.10"}`},
+		{UserData: `{"message":"no node"}`},
+	}
+	table := enrich.Table{"10.10.10.10": {"datacenter": "dal10"}}
+
+	err := enrichLogs(l, table, "node", config.Aliases{"node": "json.node_name"})
+	assertError(t, err, nil)
+
+	assert(t, l[0].UserData, `{"node_name":"10.10.10.10","enrichment":{"datacenter":"dal10"}}`)
+	assert(t, l[1].UserData, `{"message":"no node"}`)
+}
+
+func TestFilterNetworks(t *testing.T) {
+	l := []logs.Log{
+		{UserData: `{"client_ip":"10.1.2.3"}`},
+		{UserData: `{"client_ip":"192.168.1.1"}`},
+		{UserData: `{"client_ip":"2001:db8::1"}`},
+		{UserData: `{"client_ip":"not an ip"}`},
+		{UserData: `{"message":"no ip"}`},
+	}
+
+	networks, err := parseNetworks("10.0.0.0/8, 2001:db8::/32")
+	assertError(t, err, nil)
+
+	got := filterNetworks(l, networks, "json.client_ip", nil)
+	assertDeepEqual(t, got, []logs.Log{l[0], l[2]})
+}
+
+func TestParseNetworks(t *testing.T) {
+	got, err := parseNetworks("10.1.2.3/8,192.168.1.1")
+	assertError(t, err, nil)
+	assertDeepEqual(t, got, []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("192.168.1.1/32")})
+
+	if _, err = parseNetworks("10.0.0.0/33"); err == nil {
+		t.Error("Should get an error!")
+	}
+}
+
+func TestUsePrivateEndpoints(t *testing.T) {
+	args := CmdArgs{LogsURL: "https://abc.api.eu-de.logs.cloud.ibm.com", AuthURL: defaultIAMURL}
+	if err := usePrivateEndpoints(&args); err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+	assertDeepEqual(t, args, CmdArgs{LogsURL: "https://abc.api.private.eu-de.logs.cloud.ibm.com", AuthURL: "https://private.iam.cloud.ibm.com"})
+
+	args = CmdArgs{LogsURL: "https://logs.example.com", AuthURL: defaultIAMURL}
+	if err := usePrivateEndpoints(&args); err == nil {
+		t.Error("Should get an error for endpoint without private one!")
+	}
+}
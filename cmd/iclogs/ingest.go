@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/auth"
+	"github.com/wooyey/iclogs/pkg/ingest"
+)
+
+const (
+	defaultIngestApp       = "iclogs"
+	defaultIngestSubsystem = "ingest"
+	defaultIngestSeverity  = "info"
+)
+
+var errMissingMessage = errors.New("you need to provide message of ingested record")
+
+// Token of the API key obtained from IAM, reused until it expires
+func apiKeyToken(args *CmdArgs) func() (string, error) {
+	var t auth.Token
+	return func() (string, error) {
+		if !t.Valid() {
+			var err error
+			if t, err = auth.GetToken(args.AuthURL, args.APIKey); err != nil {
+				return "", fmt.Errorf("cannot get token from '%s': %w", args.AuthURL, err)
+			}
+		}
+		return t.Value, nil
+	}
+}
+
+// Ingestion endpoint given explicitly or derived from logs endpoint
+func ingestEndpoint(args *CmdArgs) (string, error) {
+	if args.IngressURL != "" {
+		return args.IngressURL, nil
+	}
+	if args.LogsURL == "" {
+		return "", errMissingURL
+	}
+
+	return ingest.URL(args.LogsURL)
+}
+
+// Send test record through ingestion API of the logs instance and print query finding it
+func runIngest(out io.Writer, args *CmdArgs, token func() (string, error), now time.Time) error {
+	if args.Message == "" {
+		return errMissingMessage
+	}
+	if args.APIKey == "" {
+		return errMissingAPIKey
+	}
+
+	severity, err := ingest.Severity(args.IngestSeverity)
+	if err != nil {
+		return err
+	}
+
+	endpoint, err := ingestEndpoint(args)
+	if err != nil {
+		return err
+	}
+
+	c := ingest.Client{Endpoint: endpoint, Token: token}
+	r := ingest.Record{Application: args.App, Subsystem: args.Subsystem, Severity: severity, Text: args.Message, Time: now}
+	if err := c.Send(context.Background(), []ingest.Record{r}); err != nil {
+		return fmt.Errorf("cannot send record to '%s': %w", endpoint, err)
+	}
+
+	fmt.Fprintf(out, "Record sent to %s at %s, it should be found in a few seconds with:\n", endpoint, args.Times.format(now))
+	fmt.Fprintf(out, "  iclogs -r 15m 'applicationname:%s AND subsystemname:%s'\n", args.App, args.Subsystem)
+
+	return nil
+}
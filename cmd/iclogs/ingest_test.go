@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRunIngest(t *testing.T) {
+	var body []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+	}))
+	defer server.Close()
+
+	token := func() (string, error) { return "Good_Token", nil }
+	now := time.Date(2025, 1, 11, 10, 0, 0, 0, time.Local)
+	args := CmdArgs{APIKey: "key", IngressURL: server.URL, Message: "e2e check", App: "test", Subsystem: defaultIngestSubsystem, IngestSeverity: "warning"}
+
+	out := bytes.Buffer{}
+	if err := runIngest(&out, &args, token, now); err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	assert(t, out.String(), "Record sent to "+server.URL+" at 2025-01-11 10:00:00, it should be found in a few seconds with:\n"+
+		"  iclogs -r 15m 'applicationname:test AND subsystemname:ingest'\n")
+	assert(t, len(body), 1)
+	assert(t, body[0]["text"], any("e2e check"))
+	assert(t, body[0]["severity"], any(float64(4)))
+
+	testCases := []struct {
+		name string
+		args CmdArgs
+		err  error
+	}{
+		{name: "MissingMessage", args: CmdArgs{APIKey: "key", IngressURL: server.URL}, err: errMissingMessage},
+		{name: "MissingAPIKey", args: CmdArgs{Message: "m", IngressURL: server.URL}, err: errMissingAPIKey},
+		{name: "MissingURL", args: CmdArgs{APIKey: "key", Message: "m", IngestSeverity: "info"}, err: errMissingURL},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assertError(t, runIngest(&out, &tc.args, token, now), tc.err)
+		})
+	}
+
+	failing := func() (string, error) { return "", errors.New("no token") }
+	if err := runIngest(&out, &args, failing, now); err == nil {
+		t.Error("Expected error of failing token")
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/wooyey/iclogs/internal/platform/i18n"
+	"github.com/wooyey/iclogs/internal/platform/logs"
+	"github.com/wooyey/iclogs/internal/platform/schema"
+)
+
+const warningKeypath = "keypath does not exist"
+
+// Offending token of warning, ie. `'w.e' in line 0 at column 0`
+var warningToken = regexp.MustCompile(`'([^']+)'`)
+
+// Add closest discovered keypaths to warnings about unknown ones
+func suggestKeypaths(warnings []string, fields schema.Fields) []string {
+	out := make([]string, len(warnings))
+
+	for i, w := range warnings {
+		out[i] = w
+		if !strings.HasPrefix(w, warningKeypath) {
+			continue
+		}
+		m := warningToken.FindStringSubmatch(w)
+		if m == nil {
+			continue
+		}
+		if paths := fields.Suggest(strings.TrimPrefix(m[1], "$d.")); len(paths) != 0 {
+			out[i] = i18n.Sprintf("%s\ndid you mean %s?", w, strings.Join(paths, i18n.T(" or ")))
+		}
+	}
+
+	return out
+}
+
+// Add keypaths of sampled records to cached schema of endpoint, which is written only when keypaths were added
+func discoverFields(endpoint string, l []logs.Log) error {
+	found := schema.Collect(l)
+	if len(found) == 0 {
+		return nil
+	}
+
+	fields, err := schema.Load(endpoint)
+	if err != nil {
+		return err
+	}
+	if !fields.Merge(found) {
+		return nil
+	}
+
+	return schema.Save(endpoint, fields)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/wooyey/iclogs/internal/platform/logs"
+	"github.com/wooyey/iclogs/internal/platform/schema"
+)
+
+func TestSuggestKeypaths(t *testing.T) {
+	fields := schema.Fields{"kubernetes.pod_name": 3, "message": 3}
+	warnings := []string{
+		"keypath does not exist\n'kubernetes.podname' in line 0 at column 0",
+		"keypath does not exist\n'$d.mesage' in line 0 at column 0",
+		"keypath does not exist\n'w.e' in line 0 at column 0",
+		"tokens less than 4 bytes or more than 64 bytes in UTF-8 are not indexed and will likely be excluded from the query\n'12' in line 0 at column 22",
+	}
+
+	got := suggestKeypaths(warnings, fields)
+	assert(t, got[0], warnings[0]+"\ndid you mean kubernetes.pod_name?")
+	assert(t, got[1], warnings[1]+"\ndid you mean message?")
+	assert(t, got[2], warnings[2])
+	assert(t, got[3], warnings[3])
+}
+
+func TestDiscoverFields(t *testing.T) {
+	dir := t.TempDir()
+	defaultPath := schema.CachePath
+	schema.CachePath = func(endpoint string) (string, error) { return filepath.Join(dir, "schema.json"), nil }
+	defer func() { schema.CachePath = defaultPath }()
+
+	// Counts of known keypaths alone are not written
+	for _, data := range []string{`{"message":"ok"}`, `{"message":"ok","level":"info"}`, `{"level":"info"}`} {
+		if err := discoverFields("https://logs.example.com", []logs.Log{{UserData: data}}); err != nil {
+			t.Fatalf("Got unexpected error: %v", err)
+		}
+	}
+
+	fields, err := schema.Load("https://logs.example.com")
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	assertDeepEqual(t, fields, schema.Fields{"message": 2, "level": 1})
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/wooyey/iclogs/internal/platform/labels"
+	"github.com/wooyey/iclogs/internal/platform/logs"
+	"github.com/wooyey/iclogs/pkg/query"
+)
+
+// Labels command kinds, printing values of one label one per line for shell completion
+var labelKinds = map[string]string{
+	"application": labels.Application,
+	"subsystem":   labels.Subsystem,
+}
+
+var errLabelKind = errors.New("you need to provide label kind (application or subsystem) or nothing")
+
+var errLabelKey = errors.New("label key cannot be empty")
+
+// Dataprime query counting records of scope by application and subsystem on logs service side
+func labelsQuery(scope string) string {
+	q := snippetSource
+	if scope = strings.TrimSpace(scope); scope != "" {
+		q += " | lucene " + dataprimeString(scope)
+	}
+
+	return q + " | " + labels.Aggregation
+}
+
+// Print distinct application and subsystem values with records count. Values are fetched and cached without kind,
+// kind prints cached values unless they are stale or refresh is requested.
+func runLabels(out io.Writer, source, kind string, refresh bool, fetch func() ([]query.Row, error)) error {
+	key, ok := labelKinds[kind]
+	if kind != "" && !ok {
+		return errLabelKind
+	}
+
+	v, fresh, err := labels.Load(source)
+	if err != nil || kind == "" || !fresh || refresh {
+		rows, err := fetch()
+		if err != nil {
+			return err
+		}
+		v = labels.Collect(rows)
+		if err := labels.Save(source, v); err != nil {
+			return err
+		}
+	}
+
+	if kind != "" {
+		for _, n := range v.Names(key) {
+			fmt.Fprintln(out, n)
+		}
+		return nil
+	}
+
+	for _, kind := range []string{"application", "subsystem"} {
+		key := labelKinds[kind]
+		fmt.Fprintf(out, "%s:\n", key)
+		for _, n := range v.Names(key) {
+			fmt.Fprintf(out, "  %s (%d)\n", n, v.Counts[key][n])
+		}
+	}
+
+	return nil
+}
+
+// Label keys from repeated --show-label flag
+type labelKeys []string
+
+func (k *labelKeys) String() string {
+	return strings.Join(*k, ",")
+}
+
+func (k *labelKeys) Set(value string) error {
+	key := strings.TrimSpace(value)
+	if key == "" {
+		return errLabelKey
+	}
+	*k = append(*k, key)
+
+	return nil
+}
+
+// Whether record labels are shown, all of them or only chosen ones
+func showsLabels(args *CmdArgs) bool {
+	return args.Labels || len(args.ShowLabel) != 0
+}
+
+// Labels of record to show: all with --show-labels, otherwise only present ones chosen with --show-label in their order
+func shownLabels(l *logs.Log, args *CmdArgs) []logs.KeyValue {
+	if args.Labels {
+		return l.Labels
+	}
+
+	var shown []logs.KeyValue
+	for _, key := range args.ShowLabel {
+		for _, label := range l.Labels {
+			if label.Key == key {
+				shown = append(shown, label)
+				break
+			}
+		}
+	}
+
+	return shown
+}
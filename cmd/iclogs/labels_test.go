@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/wooyey/iclogs/internal/platform/labels"
+	"github.com/wooyey/iclogs/internal/platform/logs"
+	"github.com/wooyey/iclogs/pkg/query"
+)
+
+func TestRunLabels(t *testing.T) {
+	dir := t.TempDir()
+	labels.CachePath = func(source string) (string, error) { return filepath.Join(dir, "labels.json"), nil }
+
+	fetched := 0
+	fetch := func() ([]query.Row, error) {
+		fetched++
+		return []query.Row{
+			{"applicationname": "web", "subsystemname": "api", "count": float64(1)},
+			{"applicationname": "web", "subsystemname": "worker", "count": float64(1)},
+			{"applicationname": "payments", "subsystemname": "api", "count": float64(1)},
+		}, nil
+	}
+
+	out := &bytes.Buffer{}
+	if err := runLabels(out, "source", "", false, fetch); err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	want := `applicationname:
+  web (2)
+  payments (1)
+subsystemname:
+  api (2)
+  worker (1)
+`
+	assert(t, out.String(), want)
+
+	// One label values come from cache
+	out.Reset()
+	if err := runLabels(out, "source", "application", false, fetch); err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	assert(t, out.String(), "web\npayments\n")
+	assert(t, fetched, 1)
+
+	out.Reset()
+	if err := runLabels(out, "source", "subsystem", true, fetch); err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	assert(t, out.String(), "api\nworker\n")
+	assert(t, fetched, 2)
+
+	assertError(t, runLabels(out, "source", "severity", false, fetch), errLabelKind)
+}
+
+func TestLabelsQuery(t *testing.T) {
+	want := "source logs | groupby $l.applicationname as applicationname, $l.subsystemname as subsystemname aggregate count() as count"
+	assert(t, labelsQuery(" "), want)
+	assert(t, labelsQuery("env:prod"), "source logs | lucene 'env:prod' | groupby $l.applicationname as applicationname, $l.subsystemname as subsystemname aggregate count() as count")
+}
+
+func TestShownLabels(t *testing.T) {
+	l := logs.Log{Labels: []logs.KeyValue{{Key: "applicationname", Value: "web"}, {Key: "subsystemname", Value: "api"}, {Key: "ipaddress", Value: ""}}}
+
+	var keys labelKeys
+	for _, k := range []string{"subsystemname", " applicationname ", "threadid"} {
+		if err := keys.Set(k); err != nil {
+			t.Fatalf("Got unexpected error: %v", err)
+		}
+	}
+	assertError(t, keys.Set(" "), errLabelKey)
+
+	assertDeepEqual(t, shownLabels(&l, &CmdArgs{ShowLabel: keys}), []logs.KeyValue{{Key: "subsystemname", Value: "api"}, {Key: "applicationname", Value: "web"}})
+	assertDeepEqual(t, shownLabels(&l, &CmdArgs{Labels: true, ShowLabel: keys}), l.Labels)
+	assert(t, showsLabels(&CmdArgs{}), false)
+	assert(t, showsLabels(&CmdArgs{ShowLabel: keys}), true)
+}
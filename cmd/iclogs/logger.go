@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	recentLogLines   = 100  // Kept for crash report
+	recentLogLineMax = 1024 // Bytes of kept line, longer ones are truncated
+)
+
+// Exit status of command finished without error, ie. drift found by assert command
+type exitStatus int
+
+func (s exitStatus) Error() string {
+	return "exit status " + strconv.Itoa(int(s))
+}
+
+// Recent log lines of the run at debug level, even without --verbose, included in crash report
+var recentLogs = &logRing{size: recentLogLines}
+
+// Internal logger writing text records, debug ones too with --verbose, with their source location with --debug
+func newLogger(w io.Writer, verbose, debug bool) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: slog.LevelInfo, AddSource: debug}
+	if verbose || debug {
+		opts.Level = slog.LevelDebug
+	}
+
+	return slog.New(slog.NewTextHandler(w, opts))
+}
+
+// Internal logger of newLogger, also keeping recent debug records in ring
+func newRunLogger(w io.Writer, verbose, debug bool, ring *logRing) *slog.Logger {
+	recent := slog.NewTextHandler(ring, &slog.HandlerOptions{Level: slog.LevelDebug})
+	return slog.New(teeHandler{newLogger(w, verbose, debug).Handler(), recent})
+}
+
+// Handler passing records to each handler enabled for their level
+type teeHandler []slog.Handler
+
+func (t teeHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return slices.ContainsFunc(t, func(h slog.Handler) bool { return h.Enabled(ctx, level) })
+}
+
+func (t teeHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, h := range t {
+		if h.Enabled(ctx, r.Level) {
+			errs = append(errs, h.Handle(ctx, r.Clone()))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (t teeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	w := make(teeHandler, len(t))
+	for i, h := range t {
+		w[i] = h.WithAttrs(attrs)
+	}
+
+	return w
+}
+
+func (t teeHandler) WithGroup(name string) slog.Handler {
+	w := make(teeHandler, len(t))
+	for i, h := range t {
+		w[i] = h.WithGroup(name)
+	}
+
+	return w
+}
+
+// Writer keeping the last size lines written to it, one line per write like text handler does, safe for concurrent use
+type logRing struct {
+	mu    sync.Mutex
+	size  int
+	lines []string
+	next  int // Index of the oldest line once ring is full
+}
+
+func (r *logRing) Write(p []byte) (int, error) {
+	line := strings.TrimRight(string(p), "\n")
+	if len(line) > recentLogLineMax {
+		line = strings.ToValidUTF8(line[:recentLogLineMax], "") + "…"
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.lines) < r.size {
+		r.lines = append(r.lines, line)
+	} else {
+		r.lines[r.next] = line
+		r.next = (r.next + 1) % r.size
+	}
+
+	return len(p), nil
+}
+
+// Kept lines from the oldest one
+func (r *logRing) tail() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append(slices.Clone(r.lines[r.next:]), r.lines[:r.next]...)
+}
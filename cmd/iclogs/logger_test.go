@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestNewLogger(t *testing.T) {
+	testCases := []struct {
+		name    string
+		verbose bool
+		debug   bool
+		want    []string
+		missing []string
+	}{
+		{name: "Default", want: []string{"level=INFO"}, missing: []string{"level=DEBUG", "source="}},
+		{name: "Verbose", verbose: true, want: []string{"level=INFO", "level=DEBUG"}, missing: []string{"source="}},
+		{name: "Debug", debug: true, want: []string{"level=DEBUG", "source="}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			l := newLogger(out, tc.verbose, tc.debug)
+			l.Debug("Running query", "query", "*")
+			l.Info("Serving", "url", "http://localhost")
+
+			for _, w := range tc.want {
+				assert(t, strings.Contains(out.String(), w), true)
+			}
+			for _, m := range tc.missing {
+				assert(t, strings.Contains(out.String(), m), false)
+			}
+		})
+	}
+}
+
+func TestRunLogger(t *testing.T) {
+	out := &bytes.Buffer{}
+	ring := &logRing{size: 2}
+	l := newRunLogger(out, false, false, ring).With("run", 1)
+
+	l.Debug("Token obtained")
+	l.Debug("Running query", "query", "*")
+	l.Info("Query done")
+
+	assert(t, strings.Contains(out.String(), "level=DEBUG"), false)
+	assert(t, strings.Contains(out.String(), `msg="Query done" run=1`), true)
+
+	tail := ring.tail()
+	assert(t, len(tail), 2)
+	assert(t, strings.Contains(tail[0], `level=DEBUG msg="Running query" run=1 query=*`), true)
+	assert(t, strings.Contains(tail[1], `level=INFO msg="Query done" run=1`), true)
+}
+
+func TestExitStatus(t *testing.T) {
+	var status exitStatus
+	err := fmt.Errorf("cannot assert logs: %w", exitStatus(driftStatus))
+
+	assert(t, errors.As(err, &status), true)
+	assert(t, status, exitStatus(driftStatus))
+}
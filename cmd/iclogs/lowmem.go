@@ -0,0 +1,43 @@
+package main
+
+import (
+	"io"
+
+	"github.com/wooyey/iclogs/internal/platform/logs"
+	"github.com/wooyey/iclogs/internal/platform/plugin"
+	"github.com/wooyey/iclogs/internal/platform/secrets"
+)
+
+// Stream processed records straight to output, or sink plugin when given, without keeping them.
+// Records are ordered only within batches received from the service. Returns result without records and records count.
+func streamLogs(stream streamFunc, out io.Writer, sink *plugin.Plugin, args *CmdArgs, spec logs.QuerySpec) (logs.Result, []secrets.Finding, int, error) {
+	write := func(l []logs.Log) error {
+		printLogs(out, &l, args)
+		return nil
+	}
+
+	var w io.WriteCloser
+	if sink != nil {
+		var err error
+		if w, err = openSink(out, *sink, args.Query, spec); err != nil {
+			return logs.Result{}, nil, 0, err
+		}
+		write = func(l []logs.Log) error {
+			return encodeRecords(w, l)
+		}
+	}
+
+	count := 0
+	l, found, err := stream("", args.Query, spec, func(b []logs.Log) error {
+		count += len(b)
+		return write(b)
+	})
+
+	if w != nil {
+		if cErr := w.Close(); err == nil {
+			err = cErr
+		}
+	}
+
+	return l, found, count, err
+}
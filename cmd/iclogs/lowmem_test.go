@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/logs"
+	"github.com/wooyey/iclogs/internal/platform/secrets"
+)
+
+func TestStreamLogs(t *testing.T) {
+	start := time.Date(2025, 1, 11, 18, 52, 21, 0, time.Local)
+	batches := [][]logs.Log{
+		{{Time: start.Add(time.Second), UserData: `{"message":"second"}`}},
+		{{Time: start, UserData: `{"message":"first"}`}, {Time: start.Add(2 * time.Second), UserData: `{"message":"third"}`}},
+	}
+	found := []secrets.Finding{{Kind: "private key", Count: 1, First: start}}
+
+	stream := func(client, query string, spec logs.QuerySpec, fn func([]logs.Log) error) (logs.Result, []secrets.Finding, error) {
+		for _, b := range batches {
+			if err := fn(b); err != nil {
+				return logs.Result{}, nil, err
+			}
+		}
+		return logs.Result{Warnings: []string{"some warning"}}, found, nil
+	}
+
+	out := bytes.Buffer{}
+	args := CmdArgs{Query: "some query", KeyNames: defaultKeyNames}
+	l, gotFound, count, err := streamLogs(stream, &out, nil, &args, logs.QuerySpec{})
+	if err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+
+	// Order is kept only within batches
+	assert(t, out.String(), "second\nfirst\nthird\n")
+	assert(t, count, 3)
+	assertDeepEqual(t, l.Warnings, []string{"some warning"})
+	assertDeepEqual(t, gotFound, found)
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/config"
+	"github.com/wooyey/iclogs/internal/platform/logs"
+)
+
+const (
+	mcpProtocolVersion = "2024-11-05"
+	mcpClient          = "mcp" // Audit client of tool queries
+	mcpMaxLineSize     = 1024 * 1024
+	defaultToolLimit   = 100
+	defaultTailRange   = 5 * time.Minute
+	defaultTailLimit   = 20
+)
+
+// JSON-RPC error codes
+const (
+	rpcParseError     = -32700
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+)
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type mcpTool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"inputSchema"`
+}
+
+type mcpContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type mcpToolResult struct {
+	Content []mcpContent `json:"content"`
+	IsError bool         `json:"isError,omitempty"`
+}
+
+type toolArgs struct {
+	Query   string `json:"query"`
+	Range   string `json:"range"`
+	Limit   int    `json:"limit"`
+	GroupBy string `json:"group_by"`
+}
+
+func toolSchema(extra map[string]any) map[string]any {
+	props := map[string]any{
+		"query": map[string]any{"type": "string", "description": "Lucene query"},
+		"range": map[string]any{"type": "string", "description": "Time range back from now, ie. 15m or 2h"},
+	}
+	for k, v := range extra {
+		props[k] = v
+	}
+
+	return map[string]any{"type": "object", "properties": props, "required": []string{"query"}}
+}
+
+var mcpTools = []mcpTool{
+	{
+		Name:        "query",
+		Description: "Search IBM Cloud Logs records, returns JSON lines with id, time, severity, labels and data.",
+		InputSchema: toolSchema(map[string]any{"limit": map[string]any{"type": "integer", "description": "Maximum number of records returned, default 100"}}),
+	},
+	{
+		Name:        "tail",
+		Description: "Latest IBM Cloud Logs records (default last 5 minutes), newest last, as JSON lines.",
+		InputSchema: toolSchema(map[string]any{"limit": map[string]any{"type": "integer", "description": "Maximum number of records returned, default 20"}}),
+	},
+	{
+		Name:        "stats",
+		Description: "Count IBM Cloud Logs records per group with volume sparkline over time range.",
+		InputSchema: toolSchema(map[string]any{"group_by": map[string]any{"type": "string", "description": "Record field to group by, ie. severity or json.service, default severity"}}),
+	},
+}
+
+// Read-only logs search tools over Model Context Protocol, queries are limited to profile scopes and maximum time range
+type mcpServer struct {
+	search     searchFunc
+	scope      string
+	toolScopes map[string]string
+	maxRange   time.Duration
+	spec       logs.QuerySpec
+	aliases    config.Aliases
+}
+
+// Serve newline delimited JSON-RPC messages until input ends
+func (m *mcpServer) serve(r io.Reader, w io.Writer) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), mcpMaxLineSize)
+	enc := json.NewEncoder(w)
+
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+
+		var req rpcRequest
+		if err := json.Unmarshal([]byte(line), &req); err != nil {
+			if err := enc.Encode(rpcResponse{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{rpcParseError, err.Error()}}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		result, rErr := m.handle(&req)
+
+		// Notifications don't get response
+		if len(req.ID) == 0 {
+			continue
+		}
+
+		if err := enc.Encode(rpcResponse{JSONRPC: "2.0", ID: req.ID, Result: result, Error: rErr}); err != nil {
+			return err
+		}
+	}
+
+	return sc.Err()
+}
+
+func (m *mcpServer) handle(req *rpcRequest) (any, *rpcError) {
+	switch req.Method {
+	case "initialize":
+		return map[string]any{
+			"protocolVersion": mcpProtocolVersion,
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+			"serverInfo":      map[string]any{"name": "iclogs", "version": version},
+		}, nil
+	case "ping":
+		return map[string]any{}, nil
+	case "tools/list":
+		return map[string]any{"tools": mcpTools}, nil
+	case "tools/call":
+		var p struct {
+			Name      string   `json:"name"`
+			Arguments toolArgs `json:"arguments"`
+		}
+		if err := json.Unmarshal(req.Params, &p); err != nil {
+			return nil, &rpcError{rpcInvalidParams, err.Error()}
+		}
+		if !slices.ContainsFunc(mcpTools, func(t mcpTool) bool { return t.Name == p.Name }) {
+			return nil, &rpcError{rpcInvalidParams, fmt.Sprintf("unknown tool '%s'", p.Name)}
+		}
+
+		text, err := m.call(p.Name, p.Arguments)
+		if err != nil {
+			return mcpToolResult{Content: []mcpContent{{Type: "text", Text: err.Error()}}, IsError: true}, nil
+		}
+		return mcpToolResult{Content: []mcpContent{{Type: "text", Text: text}}}, nil
+	}
+
+	if strings.HasPrefix(req.Method, "notifications/") {
+		return nil, nil
+	}
+
+	return nil, &rpcError{rpcMethodNotFound, fmt.Sprintf("unknown method '%s'", req.Method)}
+}
+
+// Run tool search and format its result
+func (m *mcpServer) call(name string, a toolArgs) (string, error) {
+	if strings.TrimSpace(a.Query) == "" {
+		return "", errMissingQuery
+	}
+
+	window, limit := m.maxRange, defaultToolLimit
+	if name == "tail" {
+		window, limit = min(defaultTailRange, m.maxRange), defaultTailLimit
+	}
+	if a.Range != "" {
+		d, err := time.ParseDuration(a.Range)
+		if err != nil || d <= 0 {
+			return "", fmt.Errorf("invalid range '%s'", a.Range)
+		}
+		if d > m.maxRange {
+			return "", fmt.Errorf("range cannot be longer than %s", m.maxRange)
+		}
+		window = d
+	}
+	if a.Limit > 0 {
+		limit = a.Limit
+	}
+
+	spec := m.spec
+	spec.EndDate = time.Now()
+	spec.StartDate = spec.EndDate.Add(-window)
+
+	query := scopeQuery(m.scope, scopeQuery(m.toolScopes[name], a.Query))
+	l, err := m.search(mcpClient, query, spec)
+	if err != nil {
+		return "", err
+	}
+
+	b := strings.Builder{}
+
+	if name == "stats" {
+		groupBy := a.GroupBy
+		if groupBy == "" {
+			groupBy = "severity"
+		}
+		h := &histogram{start: spec.StartDate, end: spec.EndDate, buckets: histogramBuckets}
+		printAggregations(&b, nil, groupBy, aggregate(l.Logs, nil, groupBy, m.aliases), h)
+		return b.String(), nil
+	}
+
+	records := l.Logs
+	if name == "tail" {
+		records = slices.Clone(records)
+		slices.SortStableFunc(records, func(x, y logs.Log) int {
+			return x.Time.Compare(y.Time)
+		})
+		records = records[max(len(records)-limit, 0):]
+	} else {
+		records = records[:min(len(records), limit)]
+	}
+
+	fmt.Fprintf(&b, "%d records found, showing %d\n", len(l.Logs), len(records))
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	for i := range records {
+		if err := enc.Encode(newAPIRecord(&records[i])); err != nil {
+			return "", fmt.Errorf("cannot encode record: %w", err)
+		}
+	}
+	for _, w := range l.Warnings {
+		fmt.Fprintf(&b, "Warning: %s\n", w)
+	}
+
+	return b.String(), nil
+}
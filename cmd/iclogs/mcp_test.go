@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/logs"
+)
+
+type mcpTestResponse struct {
+	ID     json.RawMessage `json:"id"`
+	Result struct {
+		ProtocolVersion string       `json:"protocolVersion"`
+		Tools           []mcpTool    `json:"tools"`
+		Content         []mcpContent `json:"content"`
+		IsError         bool         `json:"isError"`
+	} `json:"result"`
+	Error *rpcError `json:"error"`
+}
+
+func TestMCPServe(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	var queries []string
+
+	m := &mcpServer{
+		search: func(client, query string, spec logs.QuerySpec) (logs.Result, error) {
+			assert(t, client, mcpClient)
+			queries = append(queries, query)
+			return logs.Result{Logs: []logs.Log{
+				{ID: "2", Time: start.Add(time.Minute), Severity: "Error", UserData: `{"message":"later"}`},
+				{ID: "1", Time: start, Severity: "Info", UserData: `{"message":"earlier"}`},
+			}}, nil
+		},
+		scope:      "app:web",
+		toolScopes: map[string]string{"tail": "severity:error"},
+		maxRange:   time.Hour,
+	}
+
+	input := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"query","arguments":{"query":"error","limit":1}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"tail","arguments":{"query":"timeout","limit":1}}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"query","arguments":{"query":"error","range":"2h"}}}`,
+		`{"jsonrpc":"2.0","id":6,"method":"tools/call","params":{"name":"delete","arguments":{}}}`,
+		`{"jsonrpc":"2.0","id":7,"method":"resources/list"}`,
+		`{broken`,
+	}, "\n")
+
+	out := bytes.Buffer{}
+	if err := m.serve(strings.NewReader(input), &out); err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+
+	var responses []mcpTestResponse
+	dec := json.NewDecoder(&out)
+	for dec.More() {
+		var resp mcpTestResponse
+		if err := dec.Decode(&resp); err != nil {
+			t.Fatalf("Cannot decode response: %v", err)
+		}
+		responses = append(responses, resp)
+	}
+
+	assert(t, len(responses), 8)
+	assert(t, responses[0].Result.ProtocolVersion, mcpProtocolVersion)
+	assert(t, len(responses[1].Result.Tools), len(mcpTools))
+	assert(t, responses[2].Result.Content[0].Text, "2 records found, showing 1\n"+
+		`{"id":"2","time":"2025-01-01T10:01:00Z","severity":"Error","data":{"message":"later"}}`+"\n")
+	assert(t, responses[3].Result.Content[0].Text, "2 records found, showing 1\n"+
+		`{"id":"2","time":"2025-01-01T10:01:00Z","severity":"Error","data":{"message":"later"}}`+"\n")
+	assert(t, responses[4].Result.IsError, true)
+	assert(t, responses[5].Error.Code, rpcInvalidParams)
+	assert(t, responses[6].Error.Code, rpcMethodNotFound)
+	assert(t, responses[7].Error.Code, rpcParseError)
+
+	assertDeepEqual(t, queries, []string{"(app:web) AND (error)", "(app:web) AND ((severity:error) AND (timeout))"})
+}
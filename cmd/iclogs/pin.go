@@ -0,0 +1,36 @@
+package main
+
+import (
+	"github.com/wooyey/iclogs/internal/platform/auth"
+	"github.com/wooyey/iclogs/internal/platform/logs"
+	"github.com/wooyey/iclogs/internal/platform/pin"
+)
+
+// Load context pinned under session name and take its endpoints over arguments
+func restorePin(name string, args *CmdArgs) (pin.Context, bool, error) {
+	c, ok, err := pin.Load(name)
+	if err != nil || !ok {
+		return c, ok, err
+	}
+
+	args.LogsURL = c.LogsURL
+	args.AuthURL = c.AuthURL
+
+	return c, true, nil
+}
+
+// Pin session endpoints, time window and token under name, reusing pinned token while it is valid
+func (s *session) pin(name string, token auth.Token, spec logs.QuerySpec) error {
+	s.mu.Lock()
+	s.token = token
+	s.mu.Unlock()
+
+	if _, err := s.getToken(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return pin.Save(name, pin.Context{LogsURL: s.logsURL, AuthURL: s.authURL, StartDate: spec.StartDate, EndDate: spec.EndDate, Token: s.token})
+}
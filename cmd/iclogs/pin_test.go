@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/auth"
+	"github.com/wooyey/iclogs/internal/platform/config"
+	"github.com/wooyey/iclogs/internal/platform/logs"
+	"github.com/wooyey/iclogs/internal/platform/pin"
+)
+
+func TestPinSession(t *testing.T) {
+	dir := t.TempDir()
+	pin.Dir = func() (string, error) { return dir, nil }
+
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprintf(w, `{"access_token":"token-%d","expires_in":3600}`, requests)
+	}))
+	defer srv.Close()
+
+	end := time.Now().Truncate(time.Second)
+	spec := logs.QuerySpec{StartDate: end.Add(-time.Hour), EndDate: end}
+
+	args := &CmdArgs{LogsURL: "https://logs.example.com", AuthURL: srv.URL, APIKey: "key"}
+	_, ok, err := restorePin("incident", args)
+	if err != nil || ok {
+		t.Fatalf("Got session: %v, error: %v, want none", ok, err)
+	}
+	if err := newSession(args, config.Config{}, config.Profile{}).pin("incident", auth.Token{}, spec); err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+
+	// Later run with different endpoint gets pinned one back, along with window and token
+	args = &CmdArgs{LogsURL: "https://other.example.com", AuthURL: "https://iam.example.com", APIKey: "key"}
+	c, ok, err := restorePin("incident", args)
+	if err != nil || !ok {
+		t.Fatalf("Got session: %v, error: %v", ok, err)
+	}
+	assert(t, args.LogsURL, "https://logs.example.com")
+	assert(t, args.AuthURL, srv.URL)
+	assert(t, c.StartDate.Equal(spec.StartDate), true)
+	assert(t, c.EndDate.Equal(spec.EndDate), true)
+
+	s := newSession(args, config.Config{}, config.Profile{})
+	if err := s.pin("incident", c.Token, logs.QuerySpec{StartDate: c.StartDate, EndDate: c.EndDate}); err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	token, _ := s.getToken()
+	assert(t, token, "token-1")
+	assert(t, requests, 1)
+}
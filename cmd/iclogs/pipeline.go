@@ -0,0 +1,392 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/netip"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/audit"
+	"github.com/wooyey/iclogs/internal/platform/auth"
+	"github.com/wooyey/iclogs/internal/platform/config"
+	"github.com/wooyey/iclogs/internal/platform/endpoint"
+	"github.com/wooyey/iclogs/internal/platform/enrich"
+	"github.com/wooyey/iclogs/internal/platform/geoip"
+	"github.com/wooyey/iclogs/internal/platform/hook"
+	"github.com/wooyey/iclogs/internal/platform/logs"
+	"github.com/wooyey/iclogs/internal/platform/logs/filter"
+	"github.com/wooyey/iclogs/internal/platform/redact"
+	"github.com/wooyey/iclogs/internal/platform/secrets"
+	"github.com/wooyey/iclogs/pkg/query"
+)
+
+var errDeadline = errors.New("deadline of the run exceeded")
+
+// Commands running until stopped, --deadline limits queries of one-shot runs only
+var longRunning = map[string]bool{commandServe: true, commandMCP: true, commandSlack: true, commandWatch: true, commandDash: true}
+
+// Logs search session reusing token until it expires, safe for concurrent use
+type session struct {
+	authURL  string
+	apiKey   string
+	logsURL  string
+	fallback string // Logs endpoint failed over to when logsURL cannot be connected, empty when none is left
+	audit    config.Audit
+	hooks    config.Hooks
+
+	profile  config.Profile // Guardrails checked against each query, unless override is set
+	override bool
+	history  []audit.Entry // Past queries estimating records of each query
+
+	deadline time.Time       // Of all queries of one-shot run, zero means none
+	stop     context.Context // Ends fetching when user stops it, keeping records received so far, nil means never
+
+	mu    sync.Mutex // Guards token and endpoints
+	token auth.Token
+}
+
+func newSession(args *CmdArgs, cfg config.Config, profile config.Profile) *session {
+	s := &session{authURL: args.AuthURL, apiKey: args.APIKey, logsURL: args.LogsURL, fallback: args.FallbackURL, audit: cfg.Audit, hooks: cfg.Hooks, profile: profile, override: args.Override}
+	if args.Deadline > 0 && !longRunning[args.Command] {
+		s.deadline = time.Now().Add(args.Deadline)
+	}
+
+	return s
+}
+
+func (s *session) getToken() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.token.Valid() {
+		token, err := s.newToken()
+		if err != nil {
+			return "", fmt.Errorf("cannot get token from '%s': %w", s.authURL, err)
+		}
+		s.token = token
+		slog.Debug("Token obtained", "auth_url", s.authURL, "expires_in", token.Expiration)
+	}
+
+	return s.token.Value, nil
+}
+
+// Renew token with refresh grant when IAM issued refresh token, API key is then no longer kept by the session.
+// Refresh failing while API key is still kept, ie. for token of pinned session, falls back to it.
+func (s *session) newToken() (auth.Token, error) {
+	if s.token.Refreshable() {
+		token, err := auth.RefreshToken(s.authURL, s.token)
+		if err == nil || s.apiKey == "" {
+			return token, err
+		}
+		slog.Warn("Cannot refresh token, getting new one with API key", "auth_url", s.authURL, "error", err)
+	}
+
+	token, err := auth.GetToken(s.authURL, s.apiKey)
+	if err == nil && token.Refreshable() {
+		s.apiKey = ""
+	}
+
+	return token, err
+}
+
+// Run logs query on behalf of client (empty for local user), write its audit entry and run hooks around it
+func (s *session) query(client, query string, spec logs.QuerySpec) (logs.Result, error) {
+	return s.run(client, query, spec, func(ctx context.Context, logsURL, token string, spec logs.QuerySpec) (logs.Result, int, error) {
+		l, err := logs.QueryLogsContext(ctx, logsURL, token, query, spec)
+		return l, len(l.Logs), err
+	})
+}
+
+// Stream logs query records batch by batch without keeping them, like query
+func (s *session) stream(client, query string, spec logs.QuerySpec, fn func([]logs.Log) error) (logs.Result, error) {
+	return s.run(client, query, spec, func(ctx context.Context, logsURL, token string, spec logs.QuerySpec) (logs.Result, int, error) {
+		count := 0
+		l, err := logs.StreamLogsContext(ctx, logsURL, token, query, spec, func(b []logs.Log) error {
+			count += len(b)
+			return fn(b)
+		})
+		return l, count, err
+	})
+}
+
+// Run Dataprime aggregation like query, returning its rows as table
+func (s *session) table(client, q string, spec logs.QuerySpec) (query.TableResult, error) {
+	var t query.TableResult
+	l, err := s.run(client, q, spec, func(ctx context.Context, logsURL, token string, spec logs.QuerySpec) (logs.Result, int, error) {
+		var err error
+		t, err = query.Table(ctx, logsURL, token, q, spec)
+		return logs.Result{Warnings: t.Warnings, Timings: t.Timings}, len(t.Rows), err
+	})
+	t.Timings = l.Timings
+
+	return t, err
+}
+
+func (s *session) run(client, query string, spec logs.QuerySpec, do func(ctx context.Context, logsURL, token string, spec logs.QuerySpec) (logs.Result, int, error)) (logs.Result, error) {
+	if err := applyGuardrails(s.profile, &spec, estimateCost(spec, nil, s.history, query), s.override); err != nil {
+		return logs.Result{}, fmt.Errorf("query not run: %w", err)
+	}
+
+	ctx := context.Background()
+	if s.stop != nil {
+		if s.stop.Err() != nil {
+			return logs.Result{}, errStopped
+		}
+		ctx = s.stop
+	}
+	if !s.deadline.IsZero() {
+		if time.Now().After(s.deadline) {
+			return logs.Result{}, errDeadline
+		}
+
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, s.deadline)
+		defer cancel()
+	}
+
+	logsURL := s.endpoint()
+	entry := audit.Entry{
+		User:      audit.CurrentUser(),
+		Client:    client,
+		Time:      time.Now(),
+		Endpoint:  logsURL,
+		Query:     query,
+		StartDate: spec.StartDate,
+		EndDate:   spec.EndDate,
+	}
+
+	if err := hook.Run(s.hooks.PreQuery, hook.Context{Event: hook.PreQuery, Entry: entry}, os.Stderr); err != nil {
+		return logs.Result{}, err
+	}
+
+	start := time.Now()
+	token, err := s.getToken()
+	if err != nil {
+		return logs.Result{}, err
+	}
+	tokenTime := time.Since(start)
+
+	slog.Debug("Running query", "endpoint", logsURL, "query", query, "tier", spec.Tier, "start", spec.StartDate, "end", spec.EndDate)
+	l, count, err := do(ctx, logsURL, token, spec)
+	if count == 0 && endpoint.ConnectFailed(err) {
+		if fallback := s.failover(logsURL); fallback != "" {
+			slog.Warn("Logs endpoint cannot be connected, failing over", "endpoint", logsURL, "fallback", fallback, "error", err)
+			logsURL, entry.Endpoint = fallback, fallback
+			l, count, err = do(ctx, logsURL, token, spec)
+		}
+	}
+	l.Timings.Token = tokenTime
+	slog.Debug("Query finished", "records", count, "took", time.Since(start), "error", err)
+	if errors.Is(err, context.DeadlineExceeded) {
+		err = fmt.Errorf("%w: %v", errDeadline, err)
+	}
+	if err != nil && s.stop != nil && s.stop.Err() != nil {
+		err = errStopped
+	}
+
+	entry.Count = count
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	if aErr := writeAudit(s.audit, entry); aErr != nil {
+		return logs.Result{}, fmt.Errorf("cannot write audit entry: %w", aErr)
+	}
+	if hErr := hook.Run(s.hooks.PostQuery, hook.Context{Event: hook.PostQuery, Entry: entry}, os.Stderr); hErr != nil {
+		return logs.Result{}, hErr
+	}
+
+	// Records received before user stopped fetching or deadline passed are kept
+	if errors.Is(err, errStopped) || errors.Is(err, errDeadline) {
+		return l, err
+	}
+	if err != nil {
+		return logs.Result{}, fmt.Errorf("cannot get logs from '%s': %w", logsURL, err)
+	}
+
+	return l, nil
+}
+
+// Logs endpoint queries are sent to
+func (s *session) endpoint() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.logsURL
+}
+
+// Switch failed logs endpoint to fallback one for the rest of the session, returning endpoint to use
+// or empty one when there is no fallback left. Concurrent queries failing on the same endpoint switch it once.
+func (s *session) failover(failed string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.logsURL == failed {
+		if s.fallback == "" {
+			return ""
+		}
+		s.logsURL, s.fallback = s.fallback, ""
+	}
+
+	return s.logsURL
+}
+
+// Client-side processing of found log records: enrichment, filters, dropped fields, flattening, secrets scan and redaction
+type pipeline struct {
+	aliases   config.Aliases
+	where     *filter.Filter
+	lookup    enrich.Table
+	enrichKey string
+	geoDB     *geoip.Reader
+	geoField  string
+	networks  []netip.Prefix
+	ipField   string
+	drop      []string
+	flatten   bool
+	arrays    string
+	secrets   bool
+	redactors redact.Set
+}
+
+func newPipeline(args *CmdArgs, cfg config.Config) (*pipeline, error) {
+	var err error
+
+	p := &pipeline{
+		aliases:   cfg.Aliases,
+		enrichKey: args.EnrichKey,
+		geoField:  args.GeoField,
+		ipField:   args.IPField,
+		drop:      dropPaths(args.DropFields, cfg.Aliases),
+		flatten:   args.Flatten,
+		arrays:    args.FlattenArrays,
+		secrets:   args.Secrets,
+	}
+
+	if args.Where != "" {
+		if p.where, err = filter.Parse(args.Where); err != nil {
+			return nil, fmt.Errorf("cannot parse filter expression '%s': %w", args.Where, err)
+		}
+	}
+
+	if args.Enrich != "" {
+		if p.lookup, err = enrich.Load(args.Enrich); err != nil {
+			return nil, fmt.Errorf("cannot load enrichment: %w", err)
+		}
+	}
+
+	if args.IPIn != "" {
+		if p.networks, err = parseNetworks(args.IPIn); err != nil {
+			return nil, err
+		}
+	}
+
+	if args.GeoIP != "" {
+		if p.geoDB, err = geoip.Open(args.GeoIP); err != nil {
+			return nil, fmt.Errorf("cannot load GeoIP database: %w", err)
+		}
+	}
+
+	if args.Flatten {
+		if err := validFlattenArrays(args.FlattenArrays); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.redactors, err = redact.New(args.Redact, cfg.Redactors); err != nil {
+		return nil, fmt.Errorf("cannot create redactors: %w", err)
+	}
+
+	return p, nil
+}
+
+// Records can be dropped by client-side filters
+func (p *pipeline) filters() bool {
+	return p.where != nil || len(p.networks) != 0
+}
+
+// Process log records, possible secrets are looked for before redaction
+func (p *pipeline) process(l []logs.Log) ([]logs.Log, []secrets.Finding, error) {
+	if p.lookup != nil {
+		if err := enrichLogs(l, p.lookup, p.enrichKey, p.aliases); err != nil {
+			return nil, nil, fmt.Errorf("cannot enrich logs: %w", err)
+		}
+	}
+
+	if p.geoDB != nil {
+		if err := geoipLogs(l, p.geoDB, p.geoField, p.aliases); err != nil {
+			return nil, nil, fmt.Errorf("cannot enrich logs with GeoIP: %w", err)
+		}
+	}
+
+	if p.where != nil {
+		l = filterLogs(l, p.where, p.aliases)
+	}
+
+	if p.networks != nil {
+		l = filterNetworks(l, p.networks, p.ipField, p.aliases)
+	}
+
+	if len(p.drop) != 0 {
+		if err := dropFields(l, p.drop); err != nil {
+			return nil, nil, fmt.Errorf("cannot drop fields: %w", err)
+		}
+	}
+
+	if p.flatten {
+		if err := flattenLogs(l, p.arrays); err != nil {
+			return nil, nil, fmt.Errorf("cannot flatten logs: %w", err)
+		}
+	}
+
+	var found []secrets.Finding
+	if p.secrets {
+		found = secrets.Summarize(l)
+	}
+
+	redactLogs(l, p.redactors)
+
+	return l, found, nil
+}
+
+// Runs logs query with client-side processing of found records
+type searchFunc func(client, query string, spec logs.QuerySpec) (logs.Result, error)
+
+func newSearch(s *session, p *pipeline) searchFunc {
+	return func(client, query string, spec logs.QuerySpec) (logs.Result, error) {
+		l, err := s.query(client, query, spec)
+		if err != nil {
+			return l, err
+		}
+
+		l.Logs, _, err = p.process(l.Logs)
+		return l, err
+	}
+}
+
+// Runs logs query with client-side processing, calling fn with each processed batch of records.
+// Returns secrets found in all batches.
+type streamFunc func(client, query string, spec logs.QuerySpec, fn func([]logs.Log) error) (logs.Result, []secrets.Finding, error)
+
+func newStream(s *session, p *pipeline) streamFunc {
+	return func(client, query string, spec logs.QuerySpec, fn func([]logs.Log) error) (logs.Result, []secrets.Finding, error) {
+		var found []secrets.Finding
+
+		l, err := s.stream(client, query, spec, func(b []logs.Log) error {
+			b, f, err := p.process(b)
+			if err != nil {
+				return err
+			}
+			found = secrets.Merge(found, f)
+
+			if len(b) == 0 {
+				return nil
+			}
+			return fn(b)
+		})
+
+		return l, found, err
+	}
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/auth"
+	"github.com/wooyey/iclogs/internal/platform/config"
+	"github.com/wooyey/iclogs/internal/platform/logs"
+	"github.com/wooyey/iclogs/pkg/query"
+	"github.com/wooyey/iclogs/tests"
+)
+
+func TestSessionDeadline(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/identity/token") {
+			fmt.Fprint(w, `{"access_token":"token","expires_in":3600}`)
+			return
+		}
+		select {
+		case <-time.After(300 * time.Millisecond):
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+
+	args := &CmdArgs{AuthURL: srv.URL, APIKey: "key", LogsURL: srv.URL, Deadline: 50 * time.Millisecond}
+	s := newSession(args, config.Config{}, config.Profile{})
+
+	start := time.Now()
+	_, err := s.query("", "app:web", logs.QuerySpec{})
+	if !errors.Is(err, errDeadline) {
+		t.Fatalf("Got error: %v, want: %v", err, errDeadline)
+	}
+	if d := time.Since(start); d > 250*time.Millisecond {
+		t.Errorf("Query ended after %v, want at deadline", d)
+	}
+
+	// No more queries after deadline
+	_, err = s.query("", "app:web", logs.QuerySpec{})
+	assertError(t, err, errDeadline)
+
+	// Long running commands are not limited
+	args.Command = commandServe
+	assert(t, newSession(args, config.Config{}, config.Profile{}).deadline.IsZero(), true)
+}
+
+func TestSessionDeadlinePartial(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/identity/token") {
+			fmt.Fprint(w, `{"access_token":"token","expires_in":3600}`)
+			return
+		}
+		fmt.Fprint(w, tests.LoadData("response_logs.txt"))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	defaultQueryURL := logs.GetQueryURL
+	logs.GetQueryURL = func(endpoint string) (string, error) { return endpoint, nil }
+	defer func() { logs.GetQueryURL = defaultQueryURL }()
+
+	s := newSession(&CmdArgs{AuthURL: srv.URL, APIKey: "key", LogsURL: srv.URL, Deadline: 200 * time.Millisecond}, config.Config{}, config.Profile{})
+
+	l, err := s.query("", "app:web", logs.QuerySpec{})
+	if !errors.Is(err, errDeadline) {
+		t.Fatalf("Got error: %v, want: %v", err, errDeadline)
+	}
+	if len(l.Logs) == 0 {
+		t.Error("Got no records received before deadline")
+	}
+}
+
+func TestSessionFailover(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/identity/token") {
+			fmt.Fprint(w, `{"access_token":"token","expires_in":3600}`)
+			return
+		}
+		fmt.Fprint(w, tests.LoadData("response_logs.txt"))
+	}))
+	defer srv.Close()
+
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	args := &CmdArgs{AuthURL: srv.URL, APIKey: "key", LogsURL: down.URL, FallbackURL: srv.URL}
+	s := newSession(args, config.Config{}, config.Profile{})
+
+	l, err := s.query("", "app:web", logs.QuerySpec{})
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	if len(l.Logs) == 0 {
+		t.Error("Expected records from fallback endpoint")
+	}
+	assert(t, s.endpoint(), srv.URL)
+
+	// Without fallback connection error is returned
+	s = newSession(&CmdArgs{AuthURL: srv.URL, APIKey: "key", LogsURL: down.URL}, config.Config{}, config.Profile{})
+	if _, err := s.query("", "app:web", logs.QuerySpec{}); err == nil {
+		t.Error("Expected connection error")
+	}
+	assert(t, s.endpoint(), down.URL)
+}
+
+func TestSessionRefresh(t *testing.T) {
+	var grants []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id, secret, ok := r.BasicAuth(); !ok || id != "bx" || secret != "bx" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"errorCode":"BXNIM0308E","errorMessage":"Refresh token not issued to anonymous client"}`)
+			return
+		}
+		r.ParseForm()
+		grants = append(grants, r.Form.Get("grant_type"))
+		if r.Form.Get("refresh_token") == "expired" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"errorCode":"BXNIM0407E","errorMessage":"Refresh token expired"}`)
+			return
+		}
+		fmt.Fprint(w, `{"access_token":"new","refresh_token":"next","expires_in":3600}`)
+	}))
+	defer srv.Close()
+
+	// Refresh of pinned token falls back to API key, which is dropped once refreshable token is issued
+	s := newSession(&CmdArgs{AuthURL: srv.URL, APIKey: "key"}, config.Config{}, config.Profile{})
+	s.token = auth.Token{Value: "old", Refresh: "expired"}
+
+	token, err := s.getToken()
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	assert(t, token, "new")
+	assert(t, s.apiKey, "")
+	assertDeepEqual(t, grants, []string{"refresh_token", "urn:ibm:params:oauth:grant-type:apikey"})
+
+	// Without API key token is only refreshed
+	grants = nil
+	s.token.Created = 0
+	if _, err := s.getToken(); err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	assertDeepEqual(t, grants, []string{"refresh_token"})
+
+	s.token = auth.Token{Value: "old", Refresh: "expired"}
+	if _, err := s.getToken(); err == nil {
+		t.Error("Expected error of failed refresh without API key")
+	}
+}
+
+func TestSessionGuardrails(t *testing.T) {
+	var limit string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/identity/token") {
+			fmt.Fprint(w, `{"access_token":"token","expires_in":3600}`)
+			return
+		}
+		b, _ := io.ReadAll(r.Body)
+		limit = string(b)
+		fmt.Fprint(w, tests.LoadData("response_logs.txt"))
+	}))
+	defer srv.Close()
+
+	args := &CmdArgs{AuthURL: srv.URL, APIKey: "key", LogsURL: srv.URL}
+	s := newSession(args, config.Config{}, config.Profile{MaxRange: "1h", MaxRecords: 10})
+
+	now := time.Now()
+	if _, err := s.query("client", "app:web", logs.QuerySpec{StartDate: now.Add(-2 * time.Hour), EndDate: now}); !errors.Is(err, errGuardrail) {
+		t.Fatalf("Got error: %v, want: %v", err, errGuardrail)
+	}
+
+	if _, err := s.query("client", "app:web", logs.QuerySpec{StartDate: now.Add(-time.Hour), EndDate: now, Limit: 1000}); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if !strings.Contains(limit, `"limit":10`) {
+		t.Errorf("Got request: %s, want limit capped to 10", limit)
+	}
+
+	args.Override = true
+	s = newSession(args, config.Config{}, config.Profile{MaxRange: "1h"})
+	if _, err := s.query("client", "app:web", logs.QuerySpec{StartDate: now.Add(-2 * time.Hour), EndDate: now}); err != nil {
+		t.Errorf("Got error: %v, want query overriding guardrails", err)
+	}
+}
+
+func TestSessionTable(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/identity/token") {
+			fmt.Fprint(w, `{"access_token":"token","expires_in":3600}`)
+			return
+		}
+		fmt.Fprintln(w, `data: {"result":{"results":[{"user_data":"{\"applicationname\":\"web\",\"_count\":3}","metadata":[],"labels":[]}]}}`)
+	}))
+	defer srv.Close()
+
+	s := newSession(&CmdArgs{AuthURL: srv.URL, APIKey: "key", LogsURL: srv.URL}, config.Config{}, config.Profile{})
+	got, err := s.table("", "source logs | countby $l.applicationname", logs.QuerySpec{})
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	assertDeepEqual(t, got.Rows, []query.Row{{"applicationname": "web", "_count": float64(3)}})
+}
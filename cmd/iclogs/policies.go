@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/wooyey/iclogs/internal/platform/logs/tier"
+	"github.com/wooyey/iclogs/internal/platform/policies"
+)
+
+// Policies command actions
+const policiesList = "list"
+
+var errPoliciesAction = errors.New("you need to provide policies action (list)")
+
+// Print policies in evaluation order with tier their records go to
+func printPolicies(out io.Writer, p []policies.Policy) error {
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "order\tname\ttier\tapplication\tsubsystem\tseverities")
+
+	for _, policy := range p {
+		name := policy.Name
+		if !policy.Enabled {
+			name += " (disabled)"
+		}
+		severities := "*"
+		if len(policy.LogRules.Severities) > 0 {
+			severities = strings.Join(policy.LogRules.Severities, ",")
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\n", policy.Order, name, policy.Tier(), policy.Application, policy.Subsystem, severities)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "\nRecords matching no enabled policy go to %s tier.\n", tier.PriorityInsights)
+
+	return nil
+}
+
+// List TCO policies of the instance
+func runPolicies(out io.Writer, args *CmdArgs, token func() (string, error)) error {
+	if strings.TrimSpace(args.Query) != policiesList {
+		return errPoliciesAction
+	}
+	if args.APIKey == "" {
+		return errMissingAPIKey
+	}
+	if args.LogsURL == "" {
+		return errMissingURL
+	}
+
+	p, err := policies.List(context.Background(), args.LogsURL, token)
+	if err != nil {
+		return err
+	}
+
+	return printPolicies(out, p)
+}
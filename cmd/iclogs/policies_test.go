@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRunPolicies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"policies":[
+{"name":"payments","priority":"type_high","enabled":true,"order":1,"application_rule":{"rule_type_id":"is","name":"payments"}},
+{"name":"noise","priority":"type_block","enabled":false,"order":2,"subsystem_rule":{"rule_type_id":"includes","name":"health"},"log_rules":{"severities":["debug","info"]}}
+]}`))
+	}))
+	defer server.Close()
+
+	token := func() (string, error) { return "Good_Token", nil }
+	args := CmdArgs{APIKey: "key", LogsURL: server.URL, Query: "list"}
+
+	out := bytes.Buffer{}
+	if err := runPolicies(&out, &args, token); err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	assert(t, out.String(), "order  name              tier               application  subsystem  severities\n"+
+		"1      payments          priority_insights  = payments   *          *\n"+
+		"2      noise (disabled)  blocked            *            *= health  debug,info\n"+
+		"\nRecords matching no enabled policy go to priority_insights tier.\n")
+
+	testCases := []struct {
+		name string
+		args CmdArgs
+		err  error
+	}{
+		{name: "MissingAction", args: CmdArgs{APIKey: "key", LogsURL: server.URL}, err: errPoliciesAction},
+		{name: "MissingAPIKey", args: CmdArgs{LogsURL: server.URL, Query: "list"}, err: errMissingAPIKey},
+		{name: "MissingURL", args: CmdArgs{APIKey: "key", Query: "list"}, err: errMissingURL},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assertError(t, runPolicies(&out, &tc.args, token), tc.err)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/wooyey/iclogs/internal/platform/logs"
+	"github.com/wooyey/iclogs/internal/platform/secrets"
+)
+
+const progressJSON = "json"
+
+// Progress phases, shards are queries of time windows or export chunks
+const (
+	phaseQuery   = "query"   // Query of shard started
+	phaseRecords = "records" // Records of shard, or batch of them in low memory mode, received
+	phaseProcess = "process"
+	phaseRender  = "render"
+	phaseDone    = "done"
+)
+
+type progressEvent struct {
+	Phase   string `json:"phase"`
+	Shard   int    `json:"shard,omitempty"`
+	Shards  int    `json:"shards"`
+	Percent int    `json:"percent"` // Of shards done
+	Records int    `json:"records"` // Received so far
+}
+
+// Reports progress as newline-delimited JSON events for wrappers rendering their own progress.
+// Nil progress reports nothing.
+type progress struct {
+	enc     *json.Encoder
+	shards  int
+	shard   int // Current one, from 1
+	done    int
+	records int
+}
+
+func newProgress(format string, w io.Writer, shards int) (*progress, error) {
+	switch format {
+	case "":
+		return nil, nil
+	case progressJSON:
+		return &progress{enc: json.NewEncoder(w), shards: max(shards, 1)}, nil
+	}
+
+	return nil, fmt.Errorf("unknown progress format '%s', use json", format)
+}
+
+func (p *progress) event(phase string) {
+	if p == nil {
+		return
+	}
+
+	e := progressEvent{Phase: phase, Shards: p.shards, Percent: 100 * p.done / p.shards, Records: p.records}
+	if phase == phaseQuery || phase == phaseRecords {
+		e.Shard = p.shard
+	}
+	if phase == phaseDone {
+		e.Percent = 100
+	}
+
+	// Progress is best effort, it does not fail the search
+	_ = p.enc.Encode(e)
+}
+
+func (p *progress) start() {
+	if p == nil {
+		return
+	}
+	p.shard = min(p.done+1, p.shards)
+	p.event(phaseQuery)
+}
+
+func (p *progress) received(records int) {
+	if p == nil {
+		return
+	}
+	p.records += records
+	p.event(phaseRecords)
+}
+
+// Shard finished, or skipped
+func (p *progress) finish() {
+	if p == nil {
+		return
+	}
+	p.done++
+}
+
+// Report each query as one shard
+func trackQuery(query queryFunc, p *progress) queryFunc {
+	if p == nil {
+		return query
+	}
+
+	return func(client, q string, spec logs.QuerySpec) (logs.Result, error) {
+		p.start()
+		l, err := query(client, q, spec)
+		if err == nil {
+			p.finish()
+			p.received(len(l.Logs))
+		}
+
+		return l, err
+	}
+}
+
+// Report each stream as one shard, with records of each batch
+func trackStream(stream streamFunc, p *progress) streamFunc {
+	if p == nil {
+		return stream
+	}
+
+	return func(client, q string, spec logs.QuerySpec, fn func([]logs.Log) error) (logs.Result, []secrets.Finding, error) {
+		p.start()
+		l, found, err := stream(client, q, spec, func(b []logs.Log) error {
+			p.received(len(b))
+			return fn(b)
+		})
+		if err == nil {
+			p.finish()
+		}
+
+		return l, found, err
+	}
+}
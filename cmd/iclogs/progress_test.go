@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/logs"
+	"github.com/wooyey/iclogs/internal/platform/secrets"
+)
+
+func decodeEvents(t *testing.T, b *bytes.Buffer) []progressEvent {
+	var events []progressEvent
+
+	dec := json.NewDecoder(b)
+	for dec.More() {
+		var e progressEvent
+		if err := dec.Decode(&e); err != nil {
+			t.Fatalf("Got unexpected error: %v", err)
+		}
+		events = append(events, e)
+	}
+
+	return events
+}
+
+func TestNewProgress(t *testing.T) {
+	p, err := newProgress("", &bytes.Buffer{}, 1)
+	if p != nil || err != nil {
+		t.Errorf("Got progress: %v, error: %v, want none", p, err)
+	}
+
+	// Nil progress is no-op
+	p.start()
+	p.received(10)
+	p.finish()
+	p.event(phaseDone)
+
+	if _, err = newProgress("text", &bytes.Buffer{}, 1); err == nil {
+		t.Error("Expected error of unknown format")
+	}
+}
+
+func TestTrackQuery(t *testing.T) {
+	day := func(d int) timeWindow {
+		return timeWindow{start: time.Date(2025, 1, d, 14, 0, 0, 0, time.UTC), end: time.Date(2025, 1, d, 14, 10, 0, 0, time.UTC)}
+	}
+	w := windows{day(10), day(11)}
+
+	buffer := bytes.Buffer{}
+	p, err := newProgress(progressJSON, &buffer, len(w))
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+
+	query := func(client, q string, spec logs.QuerySpec) (logs.Result, error) {
+		return logs.Result{Logs: make([]logs.Log, 3)}, nil
+	}
+	if _, err = queryWindows(trackQuery(query, p), w)("", "app:web", logs.QuerySpec{}); err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	p.event(phaseDone)
+
+	assertDeepEqual(t, decodeEvents(t, &buffer), []progressEvent{
+		{Phase: phaseQuery, Shard: 1, Shards: 2},
+		{Phase: phaseRecords, Shard: 1, Shards: 2, Percent: 50, Records: 3},
+		{Phase: phaseQuery, Shard: 2, Shards: 2, Percent: 50, Records: 3},
+		{Phase: phaseRecords, Shard: 2, Shards: 2, Percent: 100, Records: 6},
+		{Phase: phaseDone, Shards: 2, Percent: 100, Records: 6},
+	})
+}
+
+func TestTrackStream(t *testing.T) {
+	buffer := bytes.Buffer{}
+	p, _ := newProgress(progressJSON, &buffer, 1)
+
+	stream := func(client, q string, spec logs.QuerySpec, fn func([]logs.Log) error) (logs.Result, []secrets.Finding, error) {
+		if err := fn(make([]logs.Log, 2)); err != nil {
+			return logs.Result{}, nil, err
+		}
+		return logs.Result{}, nil, fn(make([]logs.Log, 5))
+	}
+	nop := func([]logs.Log) error { return nil }
+	if _, _, err := trackStream(stream, p)("", "app:web", logs.QuerySpec{}, nop); err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+
+	events := decodeEvents(t, &buffer)
+	assert(t, len(events), 3)
+	assertDeepEqual(t, events[2], progressEvent{Phase: phaseRecords, Shard: 1, Shards: 1, Records: 7})
+
+	// Failed shard is not done
+	buffer.Reset()
+	p, _ = newProgress(progressJSON, &buffer, 1)
+	failing := func(client, q string, spec logs.QuerySpec, fn func([]logs.Log) error) (logs.Result, []secrets.Finding, error) {
+		return logs.Result{}, nil, errors.New("boom")
+	}
+	if _, _, err := trackStream(failing, p)("", "app:web", logs.QuerySpec{}, nop); err == nil {
+		t.Fatal("Expected error of failing stream")
+	}
+	p.event(phaseRender)
+	assert(t, decodeEvents(t, &buffer)[1].Percent, 0)
+}
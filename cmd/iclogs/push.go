@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/wooyey/iclogs/pkg/ingest"
+)
+
+const (
+	pushFlushInterval = time.Second // Read records are sent at least this often, so slow script output shows up quickly
+	maxPushLine       = 1024 * 1024 // Longest line read by push command, in bytes
+)
+
+// Fields of JSON line overriding severity given by option
+var severityFields = []string{"severity", "level"}
+
+// Record of read line, JSON object lines are sent as structured data with their own severity when known
+func pushRecord(line string, base ingest.Record, now time.Time) ingest.Record {
+	r := base
+	r.Text, r.Time = line, now
+
+	if !strings.HasPrefix(line, "{") {
+		return r
+	}
+	var m map[string]any
+	if err := json.Unmarshal([]byte(line), &m); err != nil {
+		return r
+	}
+	for _, f := range severityFields {
+		if name, ok := m[f].(string); ok {
+			if s, err := ingest.Severity(name); err == nil {
+				r.Severity = s
+				break
+			}
+		}
+	}
+
+	return r
+}
+
+// Forward lines of input to ingestion endpoint in batches until input ends, printing pushed records count to info
+func runPush(in io.Reader, info io.Writer, args *CmdArgs, token func() (string, error), now func() time.Time) error {
+	if args.APIKey == "" {
+		return errMissingAPIKey
+	}
+
+	severity, err := ingest.Severity(args.IngestSeverity)
+	if err != nil {
+		return err
+	}
+
+	endpoint, err := ingestEndpoint(args)
+	if err != nil {
+		return err
+	}
+
+	c := &ingest.Client{Endpoint: endpoint, Token: token, Gzip: true}
+	base := ingest.Record{Application: args.App, Subsystem: args.Subsystem, Severity: severity}
+
+	lines := make(chan string)
+	readErr := make(chan error, 1)
+	go func() {
+		scanner := bufio.NewScanner(in)
+		scanner.Buffer(nil, maxPushLine)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		readErr <- scanner.Err()
+		close(lines)
+	}()
+
+	ticker := time.NewTicker(pushFlushInterval)
+	defer ticker.Stop()
+
+	var batch []ingest.Record
+	pushed := 0
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := c.Send(context.Background(), batch); err != nil {
+			return fmt.Errorf("cannot push records to '%s': %w", endpoint, err)
+		}
+		pushed += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				if err := flush(); err != nil {
+					return err
+				}
+				if err := <-readErr; err != nil {
+					return fmt.Errorf("cannot read input: %w", err)
+				}
+				fmt.Fprintf(info, "Pushed %d records to %s\n", pushed, endpoint)
+				return nil
+			}
+
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			batch = append(batch, pushRecord(line, base, now()))
+			if len(batch) >= ingest.DefaultBatchSize {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		case <-ticker.C:
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wooyey/iclogs/pkg/ingest"
+)
+
+func TestPushRecord(t *testing.T) {
+	now := time.Date(2025, 1, 11, 10, 0, 0, 0, time.UTC)
+	base := ingest.Record{Application: "script", Subsystem: "cron", Severity: ingest.Info}
+
+	testCases := []struct {
+		name     string
+		line     string
+		severity int
+	}{
+		{name: "Plain", line: "backup done", severity: ingest.Info},
+		{name: "JSONLevel", line: `{"level":"warn","msg":"disk 90%"}`, severity: ingest.Warning},
+		{name: "JSONSeverity", line: `{"severity":"Error","level":"info"}`, severity: ingest.Error},
+		{name: "JSONUnknown", line: `{"level":"loud"}`, severity: ingest.Info},
+		{name: "NotJSON", line: `{broken`, severity: ingest.Info},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := pushRecord(tc.line, base, now)
+			want := base
+			want.Text, want.Time, want.Severity = tc.line, now, tc.severity
+			assertDeepEqual(t, r, want)
+		})
+	}
+}
+
+func TestRunPush(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		sent []map[string]any
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var records []map[string]any
+		json.NewDecoder(zr).Decode(&records)
+		mu.Lock()
+		sent = append(sent, records...)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	token := func() (string, error) { return "Good_Token", nil }
+	now := func() time.Time { return time.UnixMilli(1736589600000) }
+	args := CmdArgs{APIKey: "key", IngressURL: server.URL, App: "script", Subsystem: "cron", IngestSeverity: "info"}
+
+	info := bytes.Buffer{}
+	in := strings.NewReader("first\n\n{\"level\":\"error\",\"msg\":\"second\"}\n")
+	if err := runPush(in, &info, &args, token, now); err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+
+	assert(t, info.String(), "Pushed 2 records to "+server.URL+"\n")
+	assertDeepEqual(t, sent, []map[string]any{
+		{"applicationName": "script", "subsystemName": "cron", "severity": float64(ingest.Info), "text": "first", "timestamp": float64(1736589600000)},
+		{"applicationName": "script", "subsystemName": "cron", "severity": float64(ingest.Error), "text": `{"level":"error","msg":"second"}`, "timestamp": float64(1736589600000)},
+	})
+
+	args.IngestSeverity = "loud"
+	if err := runPush(strings.NewReader(""), &info, &args, token, now); err == nil {
+		t.Error("Expected error of unknown severity")
+	}
+}
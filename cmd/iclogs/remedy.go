@@ -0,0 +1,216 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/wooyey/iclogs/internal/platform/api"
+	"github.com/wooyey/iclogs/internal/platform/auth"
+	"github.com/wooyey/iclogs/internal/platform/endpoint"
+	"github.com/wooyey/iclogs/internal/platform/i18n"
+	"github.com/wooyey/iclogs/internal/platform/logs"
+	"github.com/wooyey/iclogs/pkg/ingest"
+)
+
+const (
+	iamKeyNotFound = "BXNIM0415E" // IAM error code of API key which does not exist
+	cloudDomain    = ".cloud.ibm.com"
+	logsDomain     = ".logs" + cloudDomain
+	ingressLabel   = "ingress"
+	iamLabel       = "iam"
+)
+
+// Kinds of endpoints hosts, remedies only describe failures of IBM Cloud Logs and IAM endpoints, not ie. Slack or webhooks
+const (
+	otherHost = iota
+	logsHost
+	ingressHost
+	iamHost
+)
+
+// Known failure described for humans, with next steps to fix it
+type remedy struct {
+	message string
+	next    string
+}
+
+// Catalogue of known failure classes, the first matching one describes the error
+var remedies = []func(err error) (remedy, bool){
+	iamRemedy,
+	unreachableRemedy,
+	dnsRemedy,
+	forbiddenRemedy,
+	ingestForbiddenRemedy,
+	unauthorizedRemedy,
+	timeoutRemedy,
+}
+
+// Describe known failure instead of wrapped Go error, false when error is not known
+func explainError(err error) (remedy, bool) {
+	for _, r := range remedies {
+		if rem, ok := r(err); ok {
+			return rem, true
+		}
+	}
+
+	return remedy{}, false
+}
+
+// Print remedy followed by original error on one line
+func printRemedy(w io.Writer, r remedy, err error) {
+	fmt.Fprint(w, i18n.Sprintf("Error: %s\nNext steps: %s\n", r.message, r.next))
+	fmt.Fprint(w, i18n.Sprintf("Cause: %s\n", strings.ReplaceAll(err.Error(), "\n", " ")))
+}
+
+// Kind of endpoint host, by labels of IBM Cloud hostnames, ie. `<id>.ingress.<region>.logs.cloud.ibm.com`
+func hostKind(host string) int {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	labels := strings.Split(host, ".")
+
+	switch {
+	case strings.HasSuffix(host, logsDomain) && slices.Contains(labels, ingressLabel):
+		return ingressHost
+	case strings.HasSuffix(host, logsDomain):
+		return logsHost
+	case strings.HasSuffix(host, cloudDomain) && slices.Contains(labels, iamLabel):
+		return iamHost
+	}
+
+	return otherHost
+}
+
+// Kind of endpoint of failed request, logs endpoint when error does not tell its URL
+func requestKind(err error) int {
+	var e *url.Error
+	if !errors.As(err, &e) {
+		return logsHost
+	}
+
+	u, parseErr := url.Parse(e.URL)
+	if parseErr != nil {
+		return otherHost
+	}
+
+	return hostKind(u.Hostname())
+}
+
+func iamRemedy(err error) (remedy, bool) {
+	var e auth.GetTokenError
+	if !errors.As(err, &e) {
+		return remedy{}, false
+	}
+
+	if e.IAMCode == iamKeyNotFound {
+		return remedy{
+			message: i18n.T("IAM cannot find the API key, it is mistyped or was deleted"),
+			next:    i18n.T("create API key with 'ibmcloud iam api-key-create' and give it with --key, LOGS_API_KEY or profile api_key_env"),
+		}, true
+	}
+
+	return remedy{
+		message: i18n.Sprintf("IAM refused to issue token (%s: %s)", e.IAMCode, e.Message),
+		next:    i18n.T("check the API key and that --auth-url or profile auth_url is IAM endpoint of its account environment: public, private or test"),
+	}, true
+}
+
+func unreachableRemedy(err error) (remedy, bool) {
+	var e *endpoint.UnreachableError
+	if !errors.As(err, &e) {
+		return remedy{}, false
+	}
+
+	return remedy{
+		message: i18n.Sprintf("private endpoint %s is unreachable from this network", e.Host),
+		next:    i18n.T("connect to IBM Cloud private network, ie. VPN to VPC with virtual private endpoint, or use public endpoints without --private and private_endpoints; 'iclogs doctor' checks connectivity"),
+	}, true
+}
+
+func dnsRemedy(err error) (remedy, bool) {
+	var e *net.DNSError
+	if !errors.As(err, &e) {
+		return remedy{}, false
+	}
+
+	r := remedy{message: i18n.Sprintf("cannot resolve host %s", e.Name)}
+	switch hostKind(e.Name) {
+	case logsHost:
+		r.next = i18n.T("check instance ID and region in --logs-url or profile logs_url, and network and DNS settings; 'iclogs doctor' checks connectivity")
+	case ingressHost:
+		r.next = i18n.T("check --ingress-url, or instance ID and region in --logs-url it is derived from, and network and DNS settings; 'iclogs doctor' checks connectivity")
+	case iamHost:
+		r.next = i18n.T("check --auth-url or profile auth_url, and network and DNS settings; 'iclogs doctor' checks connectivity")
+	default:
+		return remedy{}, false
+	}
+
+	return r, true
+}
+
+func forbiddenRemedy(err error) (remedy, bool) {
+	if logsStatus(err) != http.StatusForbidden {
+		return remedy{}, false
+	}
+
+	return remedy{
+		message: i18n.T("logs instance denied access (HTTP 403)"),
+		next:    i18n.T("give user or service ID of the API key IAM access to the IBM Cloud Logs instance, Reader role to search and Manager role for management API commands; 'iclogs auth whoami' shows account of the key"),
+	}, true
+}
+
+func ingestForbiddenRemedy(err error) (remedy, bool) {
+	var e *ingest.HTTPError
+	if !errors.As(err, &e) || e.StatusCode != http.StatusForbidden {
+		return remedy{}, false
+	}
+
+	return remedy{
+		message: i18n.T("ingestion endpoint denied access (HTTP 403)"),
+		next:    i18n.T("give user or service ID of the API key Sender role of the IBM Cloud Logs instance to send records; 'iclogs auth whoami' shows account of the key"),
+	}, true
+}
+
+func unauthorizedRemedy(err error) (remedy, bool) {
+	if logsStatus(err) != http.StatusUnauthorized {
+		return remedy{}, false
+	}
+
+	return remedy{
+		message: i18n.T("logs instance rejected the token (HTTP 401), it expired or was issued for other environment"),
+		next:    i18n.T("run again to get new token, check system time, and that --auth-url or profile auth_url matches environment of the instance"),
+	}, true
+}
+
+func timeoutRemedy(err error) (remedy, bool) {
+	var e net.Error
+	if !errors.As(err, &e) || !e.Timeout() || requestKind(err) == otherHost {
+		return remedy{}, false
+	}
+
+	return remedy{
+		message: i18n.T("endpoint did not answer in time"),
+		next:    i18n.T("check network or VPN connection, use --idle-timeout for long archive scans or shorter --range"),
+	}, true
+}
+
+// HTTP status of error response of logs or management API endpoint, 0 when there is none
+func logsStatus(err error) int {
+	var (
+		l *logs.HTTPError
+		a *api.HTTPError
+	)
+
+	switch {
+	case errors.As(err, &l):
+		return l.StatusCode
+	case errors.As(err, &a):
+		return a.StatusCode
+	}
+
+	return 0
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/wooyey/iclogs/internal/platform/api"
+	"github.com/wooyey/iclogs/internal/platform/auth"
+	"github.com/wooyey/iclogs/internal/platform/endpoint"
+	"github.com/wooyey/iclogs/internal/platform/logs"
+	"github.com/wooyey/iclogs/pkg/ingest"
+)
+
+func TestExplainError(t *testing.T) {
+	dns := &net.DNSError{Err: "no such host", Name: "abc.api.eu-xx.logs.cloud.ibm.com", IsNotFound: true}
+
+	testCases := []struct {
+		name    string
+		err     error
+		message string
+	}{
+		{name: "KeyNotFound", err: auth.GetTokenError{Code: 400, Message: "Provided API key could not be found.", IAMCode: iamKeyNotFound}, message: "IAM cannot find the API key, it is mistyped or was deleted"},
+		{name: "IAM", err: auth.GetTokenError{Code: 400, Message: "Operation not allowed.", IAMCode: "BXNIM0102E"}, message: "IAM refused to issue token (BXNIM0102E: Operation not allowed.)"},
+		{name: "Private", err: &endpoint.UnreachableError{Host: "private.iam.cloud.ibm.com", Err: dns}, message: "private endpoint private.iam.cloud.ibm.com is unreachable from this network"},
+		{name: "DNS", err: fmt.Errorf("cannot POST data: %w", dns), message: "cannot resolve host abc.api.eu-xx.logs.cloud.ibm.com"},
+		{name: "Forbidden", err: fmt.Errorf("cannot get logs: %w", &logs.HTTPError{StatusCode: 403}), message: "logs instance denied access (HTTP 403)"},
+		{name: "SkewedForbidden", err: logs.ClockSkewError{Err: &logs.HTTPError{StatusCode: 403}}, message: "logs instance denied access (HTTP 403)"},
+		{name: "ForbiddenAPI", err: &api.HTTPError{StatusCode: 403}, message: "logs instance denied access (HTTP 403)"},
+		{name: "ForbiddenIngest", err: fmt.Errorf("cannot push records: %w", &ingest.HTTPError{StatusCode: 403}), message: "ingestion endpoint denied access (HTTP 403)"},
+		{name: "UnauthorizedIngest", err: &ingest.HTTPError{StatusCode: 401}},
+		{name: "DNSIngress", err: &net.DNSError{Err: "no such host", Name: "abc.ingress.eu-xx.logs.cloud.ibm.com."}, message: "cannot resolve host abc.ingress.eu-xx.logs.cloud.ibm.com."},
+		{name: "DNSIAM", err: &net.DNSError{Err: "no such host", Name: "iam.test.cloud.ibm.com"}, message: "cannot resolve host iam.test.cloud.ibm.com"},
+		{name: "DNSSlack", err: &url.Error{Op: "Post", URL: "https://hooks.slack.test/services/x", Err: &net.DNSError{Err: "no such host", Name: "hooks.slack.test"}}},
+		{name: "Unauthorized", err: &logs.HTTPError{StatusCode: 401}, message: "logs instance rejected the token (HTTP 401), it expired or was issued for other environment"},
+		{name: "Timeout", err: &net.OpError{Op: "dial", Err: context.DeadlineExceeded}, message: "endpoint did not answer in time"},
+		{name: "TimeoutLogs", err: &url.Error{Op: "Post", URL: "https://abc.api.eu-xx.logs.cloud.ibm.com/v1/query", Err: &net.OpError{Op: "dial", Err: context.DeadlineExceeded}}, message: "endpoint did not answer in time"},
+		{name: "TimeoutWebhook", err: &url.Error{Op: "Post", URL: "https://hooks.example.com/alert", Err: &net.OpError{Op: "dial", Err: context.DeadlineExceeded}}},
+		{name: "Unknown", err: errors.New("boom")},
+		{name: "ServerError", err: &logs.HTTPError{StatusCode: 500}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, ok := explainError(tc.err)
+			assert(t, ok, tc.message != "")
+			assert(t, r.message, tc.message)
+		})
+	}
+
+	out := &bytes.Buffer{}
+	printRemedy(out, remedy{message: "it failed", next: "fix it"}, errors.New("cannot run:\nboom"))
+	assert(t, out.String(), "Error: it failed\nNext steps: fix it\nCause: cannot run: boom\n")
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/logs"
+)
+
+const (
+	defaultListen     = "localhost:8080"
+	readHeaderTimeout = 10 * time.Second
+)
+
+// Search server, queries are limited to profile scope and maximum time range
+type server struct {
+	search   searchFunc
+	scope    string
+	maxRange time.Duration
+	keyNames []string
+	spec     logs.QuerySpec
+	refresh  time.Duration // Polling interval of tail
+	cache    *resultCache
+	limiter  *rateLimiter
+	layout   timeLayout
+}
+
+// Found record in JSON API, user data is kept as JSON when it is valid
+type apiRecord struct {
+	ID       string          `json:"id,omitempty"`
+	Time     time.Time       `json:"time"`
+	Severity string          `json:"severity"`
+	Labels   []string        `json:"labels,omitempty"`
+	Data     json.RawMessage `json:"data"`
+}
+
+type apiResult struct {
+	Query    string      `json:"query"`
+	Start    time.Time   `json:"start"`
+	End      time.Time   `json:"end"`
+	Records  []apiRecord `json:"records"`
+	Warnings []string    `json:"warnings,omitempty"`
+}
+
+type apiError struct {
+	Error string `json:"error"`
+}
+
+// Error with HTTP status code to respond with
+type requestError struct {
+	status int
+	err    error
+}
+
+func (e requestError) Error() string {
+	return e.err.Error()
+}
+
+func newAPIRecord(l *logs.Log) apiRecord {
+	data := json.RawMessage(l.UserData)
+	if !json.Valid(data) {
+		data, _ = json.Marshal(l.UserData)
+	}
+
+	return apiRecord{ID: l.ID, Time: l.Time, Severity: l.Severity, Labels: l.FormatLabels(), Data: data}
+}
+
+type searchResult struct {
+	query  string
+	spec   logs.QuerySpec
+	result logs.Result
+}
+
+func newAPIResult(res searchResult) apiResult {
+	a := apiResult{Query: res.query, Start: res.spec.StartDate, End: res.spec.EndDate, Records: []apiRecord{}, Warnings: res.result.Warnings}
+	for i := range res.result.Logs {
+		a.Records = append(a.Records, newAPIRecord(&res.result.Logs[i]))
+	}
+
+	return a
+}
+
+// Scoped request `query` and its `range` (not longer than maximum one)
+func (s *server) parseRequest(r *http.Request, defaultRange time.Duration) (string, time.Duration, error) {
+	query := strings.TrimSpace(r.FormValue("query"))
+	if query == "" {
+		return "", 0, requestError{http.StatusBadRequest, errMissingQuery}
+	}
+
+	window := defaultRange
+	if v := r.FormValue("range"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return "", 0, requestError{http.StatusBadRequest, fmt.Errorf("invalid range '%s'", v)}
+		}
+		if d > s.maxRange {
+			return "", 0, requestError{http.StatusBadRequest, fmt.Errorf("range cannot be longer than %s", s.maxRange)}
+		}
+		window = d
+	}
+
+	return scopeQuery(s.scope, query), window, nil
+}
+
+// Run search of request over last range, results are reused from cache when possible
+func (s *server) run(r *http.Request) (searchResult, error) {
+	query, window, err := s.parseRequest(r, s.maxRange)
+	if err != nil {
+		return searchResult{}, err
+	}
+
+	key := query + "\x00" + window.String()
+	if res, ok := s.cache.get(key); ok {
+		return res, nil
+	}
+
+	spec := s.spec
+	spec.EndDate = time.Now()
+	spec.StartDate = spec.EndDate.Add(-window)
+
+	l, err := s.search(clientAddr(r), query, spec)
+	if err != nil {
+		return searchResult{}, requestError{http.StatusBadGateway, err}
+	}
+
+	res := searchResult{query: query, spec: spec, result: l}
+	s.cache.put(key, res)
+
+	return res, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.Encode(v)
+}
+
+func errorStatus(err error) int {
+	if e, ok := err.(requestError); ok {
+		return e.status
+	}
+	if errors.Is(err, errGuardrail) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+func (s *server) handleQuery(w http.ResponseWriter, r *http.Request) {
+	res, err := s.run(r)
+	if err != nil {
+		writeJSON(w, errorStatus(err), apiError{Error: err.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newAPIResult(res))
+}
+
+var pageTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>iclogs</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+input[name=query] { width: 40em; }
+td { font-family: monospace; vertical-align: top; padding: 0 1em 0 0; white-space: pre-wrap; }
+.error { color: #b00; }
+</style>
+</head>
+<body>
+<form method="get" action="/">
+<input name="query" value="{{.Query}}" placeholder="lucene query" autofocus>
+<input name="range" value="{{.Range}}" size="6" title="time range, up to {{.MaxRange}}">
+<button type="submit">Search</button>
+</form>
+{{if .Error}}<p class="error">{{.Error}}</p>{{end}}
+{{if .Searched}}<p>{{len .Rows}} records</p>
+<table>
+{{range .Rows}}<tr><td>{{.Time}}</td><td>{{.Severity}}</td><td>{{.Message}}</td></tr>
+{{end}}</table>{{end}}
+</body>
+</html>
+`))
+
+type pageRow struct {
+	Time     string
+	Severity string
+	Message  string
+}
+
+type page struct {
+	Query    string
+	Range    string
+	MaxRange time.Duration
+	Error    string
+	Searched bool
+	Rows     []pageRow
+}
+
+func (s *server) handlePage(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+
+	p := page{Query: r.FormValue("query"), Range: r.FormValue("range"), MaxRange: s.maxRange}
+	if p.Range == "" {
+		p.Range = s.maxRange.String()
+	}
+
+	status := http.StatusOK
+	if p.Query != "" {
+		res, err := s.run(r)
+		if err != nil {
+			status = errorStatus(err)
+			p.Error = err.Error()
+		}
+		p.Searched = err == nil
+		for _, l := range res.result.Logs {
+			text, err := l.Message(s.keyNames)
+			if err != nil {
+				text = l.UserData
+			}
+			p.Rows = append(p.Rows, pageRow{Time: s.layout.format(l.Time), Severity: l.Severity, Message: text})
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if err := pageTemplate.Execute(w, p); err != nil {
+		slog.Error("Cannot render page", "error", err)
+	}
+}
+
+func (s *server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /query", s.handleQuery)
+	mux.HandleFunc("GET /tail", s.handleTail)
+	mux.HandleFunc("GET /", s.handlePage)
+	return s.limiter.wrap(mux)
+}
+
+// Serve web UI, JSON and SSE API until server fails
+func runServer(s *server, listen string) error {
+	srv := &http.Server{
+		Addr:              listen,
+		Handler:           s.routes(),
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	slog.Info("Serving", "url", "http://"+listen)
+	return srv.ListenAndServe()
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/logs"
+)
+
+func testServer(calls *[]string) *server {
+	return &server{
+		search: func(client, query string, spec logs.QuerySpec) (logs.Result, error) {
+			*calls = append(*calls, query)
+			if strings.Contains(query, "broken") {
+				return logs.Result{}, errors.New("upstream failure")
+			}
+			return logs.Result{Logs: []logs.Log{
+				{ID: "1", Severity: "Error", UserData: `{"message":"<b>boom</b>"}`},
+				{ID: "2", Severity: "Info", UserData: "plain text"},
+			}}, nil
+		},
+		scope:    "app:web",
+		maxRange: time.Hour,
+		keyNames: []string{"message"},
+	}
+}
+
+func TestServeQuery(t *testing.T) {
+
+	testCases := []struct {
+		name   string
+		target string
+		status int
+		query  string
+	}{
+		{name: "Search", target: "/query?query=error&range=15m", status: http.StatusOK, query: "(app:web) AND (error)"},
+		{name: "MissingQuery", target: "/query", status: http.StatusBadRequest},
+		{name: "InvalidRange", target: "/query?query=error&range=soon", status: http.StatusBadRequest},
+		{name: "TooLongRange", target: "/query?query=error&range=2h", status: http.StatusBadRequest},
+		{name: "UpstreamError", target: "/query?query=broken", status: http.StatusBadGateway, query: "(app:web) AND (broken)"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			rec := httptest.NewRecorder()
+			testServer(&calls).routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			assert(t, rec.Code, tt.status)
+			if tt.query != "" {
+				assertDeepEqual(t, calls, []string{tt.query})
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			var res apiResult
+			if err := json.NewDecoder(rec.Body).Decode(&res); err != nil {
+				t.Fatalf("Cannot decode response: %v", err)
+			}
+			assert(t, res.Query, tt.query)
+			assert(t, res.End.Sub(res.Start), 15*time.Minute)
+			assert(t, len(res.Records), 2)
+			assert(t, string(res.Records[0].Data), `{"message":"<b>boom</b>"}`)
+			assert(t, string(res.Records[1].Data), `"plain text"`)
+		})
+	}
+}
+
+func TestServePage(t *testing.T) {
+	var calls []string
+	rec := httptest.NewRecorder()
+	testServer(&calls).routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?query=error", nil))
+
+	assert(t, rec.Code, http.StatusOK)
+	body := rec.Body.String()
+	for _, want := range []string{"2 records", "&lt;b&gt;boom&lt;/b&gt;", "plain text", `value="1h0m0s"`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Page should contain %q:\n%s", want, body)
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	testServer(&calls).routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/missing", nil))
+	assert(t, rec.Code, http.StatusNotFound)
+}
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/cache"
+	"github.com/wooyey/iclogs/pkg/ingest"
+)
+
+const shipPollInterval = time.Second // Files are checked for appended lines this often
+
+var errMissingFiles = errors.New("you need to provide at least one --file to ship")
+
+// shipCheckpointPath returns default location of read positions of shipped files in user cache directory
+var shipCheckpointPath = func(files []string) (string, error) {
+	sorted := append([]string(nil), files...)
+	sort.Strings(sorted)
+
+	return cache.Path("ship", strings.Join(sorted, "\n"))
+}
+
+// Files from repeated --file flag
+type shipFiles []string
+
+func (f *shipFiles) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *shipFiles) Set(value string) error {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return errMissingFiles
+	}
+	path, err := filepath.Abs(value)
+	if err != nil {
+		return fmt.Errorf("cannot resolve file path: %w", err)
+	}
+	*f = append(*f, path)
+
+	return nil
+}
+
+// Tails files and sends their complete lines to ingestion endpoint, remembering read positions in checkpoint file
+type shipper struct {
+	client     *ingest.Client
+	base       ingest.Record
+	files      []string
+	checkpoint string
+	positions  map[string]shipPosition
+	now        func() time.Time
+}
+
+// Read position in file, file identity tells rotated file under the same path apart
+type shipPosition struct {
+	Offset int64  `json:"offset"`
+	File   string `json:"file,omitempty"` // Device and inode, empty when not known
+}
+
+// Shipper of files given by options. Files without checkpoint are shipped from their current end, or from the start
+// when they appear later.
+func newShipper(args *CmdArgs, token func() (string, error), now func() time.Time) (*shipper, error) {
+	if len(args.ShipFiles) == 0 {
+		return nil, errMissingFiles
+	}
+	if args.APIKey == "" {
+		return nil, errMissingAPIKey
+	}
+
+	severity, err := ingest.Severity(args.IngestSeverity)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint, err := ingestEndpoint(args)
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoint := args.Checkpoint
+	if checkpoint == "" {
+		if checkpoint, err = shipCheckpointPath(args.ShipFiles); err != nil {
+			return nil, err
+		}
+	}
+
+	s := &shipper{
+		client:     &ingest.Client{Endpoint: endpoint, Token: token, Gzip: true},
+		base:       ingest.Record{Application: args.App, Subsystem: args.Subsystem, Severity: severity},
+		files:      args.ShipFiles,
+		checkpoint: checkpoint,
+		positions:  map[string]shipPosition{},
+		now:        now,
+	}
+	s.base.Computer, _ = os.Hostname()
+
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	for _, f := range s.files {
+		if _, ok := s.positions[f]; ok {
+			continue
+		}
+		if fi, err := os.Stat(f); err == nil {
+			s.positions[f] = shipPosition{Offset: fi.Size(), File: fileID(fi)}
+		}
+	}
+
+	return s, nil
+}
+
+func (s *shipper) load() error {
+	data, ok, err := cache.Read(s.checkpoint)
+	if err != nil {
+		return fmt.Errorf("cannot read checkpoint: %w", err)
+	}
+	if !ok {
+		return nil
+	}
+	if err := json.Unmarshal(data, &s.positions); err != nil {
+		return fmt.Errorf("cannot parse checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+func (s *shipper) save() error {
+	data, err := json.Marshal(s.positions)
+	if err != nil {
+		return fmt.Errorf("cannot encode checkpoint: %w", err)
+	}
+
+	if err := cache.Write(s.checkpoint, data); err != nil {
+		return fmt.Errorf("cannot write checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+// Complete lines appended to file since position and position after them. File of other identity was rotated
+// and file smaller than offset was truncated, so it is read from the start.
+func readAppended(path string, pos shipPosition) ([]string, shipPosition, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, pos, err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, pos, err
+	}
+	if id := fileID(fi); id != pos.File {
+		if pos.File != "" {
+			pos.Offset = 0
+		}
+		pos.File = id
+	}
+	if fi.Size() < pos.Offset {
+		pos.Offset = 0
+	}
+	if fi.Size() == pos.Offset {
+		return nil, pos, nil
+	}
+
+	if _, err := f.Seek(pos.Offset, io.SeekStart); err != nil {
+		return nil, pos, err
+	}
+	buf := make([]byte, min(fi.Size()-pos.Offset, maxPushLine))
+	n, err := io.ReadFull(f, buf)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, pos, err
+	}
+	buf = buf[:n]
+
+	end := bytes.LastIndexByte(buf, '\n') + 1
+	if end == 0 {
+		if n < maxPushLine {
+			return nil, pos, nil // Line is still being written
+		}
+		end = n // Line too long for one record is split
+	}
+
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSuffix(string(buf[:end]), "\n"), "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	pos.Offset += int64(end)
+	return lines, pos, nil
+}
+
+// Send lines appended to files since the last pass, read positions are saved only after records are sent
+func (s *shipper) ship(ctx context.Context) (int, error) {
+	var records []ingest.Record
+	positions := map[string]shipPosition{}
+
+	for _, f := range s.files {
+		lines, pos, err := readAppended(f, s.positions[f])
+		if errors.Is(err, fs.ErrNotExist) {
+			continue // Not created yet or rotated away, it is read from the start when it appears
+		}
+		if err != nil {
+			return 0, fmt.Errorf("cannot read '%s': %w", f, err)
+		}
+		positions[f] = pos
+
+		for _, line := range lines {
+			records = append(records, pushRecord(line, s.base, s.now()))
+		}
+	}
+
+	if err := s.client.Send(ctx, records); err != nil {
+		return 0, fmt.Errorf("cannot ship records to '%s': %w", s.client.Endpoint, err)
+	}
+
+	changed := false
+	for f, pos := range positions {
+		if s.positions[f] != pos {
+			s.positions[f], changed = pos, true
+		}
+	}
+	if changed {
+		if err := s.save(); err != nil {
+			return len(records), err
+		}
+	}
+
+	return len(records), nil
+}
+
+// Ship appended lines every interval until interrupted, failed passes are reported and retried
+func runShip(s *shipper, info io.Writer, interval time.Duration) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	fmt.Fprintf(info, "Shipping %s to %s, checkpoint %s\n", strings.Join(s.files, ", "), s.client.Endpoint, s.checkpoint)
+
+	for {
+		n, err := s.ship(ctx)
+		if err != nil && ctx.Err() == nil {
+			slog.Warn("Cannot ship logs", "error", err)
+		}
+		if n > 0 {
+			fmt.Fprintf(info, "Shipped %d records\n", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadAppended(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, []byte("first\n\nsecond\npart"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := fileID(fi)
+
+	testCases := []struct {
+		name   string
+		offset int64
+		file   string
+		lines  []string
+		end    int64
+	}{
+		{name: "FromStart", offset: 0, lines: []string{"first", "second"}, end: 14},
+		{name: "FromMiddle", offset: 6, file: id, lines: []string{"second"}, end: 14},
+		{name: "IncompleteLine", offset: 14, file: id, lines: nil, end: 14},
+		{name: "AtEnd", offset: 18, file: id, lines: nil, end: 18},
+		{name: "Truncated", offset: 100, file: id, lines: []string{"first", "second"}, end: 14},
+		{name: "Rotated", offset: 6, file: "0:0", lines: []string{"first", "second"}, end: 14},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			lines, pos, err := readAppended(path, shipPosition{Offset: tc.offset, File: tc.file})
+			if err != nil {
+				t.Fatalf("Got unexpected error: %v", err)
+			}
+			assertDeepEqual(t, lines, tc.lines)
+			assertDeepEqual(t, pos, shipPosition{Offset: tc.end, File: id})
+		})
+	}
+}
+
+func TestShip(t *testing.T) {
+	var sent []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var records []map[string]any
+		json.NewDecoder(zr).Decode(&records)
+		for _, r := range records {
+			sent = append(sent, r["text"].(string))
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	existing, later := filepath.Join(dir, "app.log"), filepath.Join(dir, "later.log")
+	if err := os.WriteFile(existing, []byte("old\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	token := func() (string, error) { return "Good_Token", nil }
+	now := func() time.Time { return time.UnixMilli(1736589600000) }
+	args := CmdArgs{
+		APIKey: "key", IngressURL: server.URL, App: "shop", Subsystem: "host", IngestSeverity: "info",
+		ShipFiles: shipFiles{existing, later}, Checkpoint: filepath.Join(dir, "state", "checkpoint.json"),
+	}
+
+	s, err := newShipper(&args, token, now)
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+
+	appendFile := func(path, text string) {
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.WriteString(text)
+		f.Close()
+	}
+
+	appendFile(existing, "new\n")
+	appendFile(later, "created\n")
+	n, err := s.ship(context.Background())
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	assert(t, n, 2)
+	assertDeepEqual(t, sent, []string{"new", "created"})
+
+	appendFile(existing, "after restart\n")
+	s, err = newShipper(&args, token, now)
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	if _, err := s.ship(context.Background()); err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	assertDeepEqual(t, sent, []string{"new", "created", "after restart"})
+
+	if err := os.WriteFile(existing, []byte("rotated\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.ship(context.Background()); err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	assert(t, strings.Join(sent, ","), "new,created,after restart,rotated")
+
+	// Rotated file larger than read position of the previous one is read from the start too
+	if err := os.Rename(existing, existing+".1"); err != nil {
+		t.Fatal(err)
+	}
+	appendFile(existing, "rotated again, longer than before\n")
+	if _, err := s.ship(context.Background()); err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	if fi, err := os.Stat(existing); err == nil && fileID(fi) != "" {
+		assert(t, sent[len(sent)-1], "rotated again, longer than before")
+	}
+
+	args.ShipFiles = nil
+	_, err = newShipper(&args, token, now)
+	assertError(t, err, errMissingFiles)
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Separator of daily silence times of day, ie. 02:00-03:00
+const silenceSeparator = "-"
+
+// Period when watch command does not notify, repeated daily or one-off time window
+type silence struct {
+	daily    bool
+	from, to time.Duration // Times of day of daily silence
+	window   timeWindow
+}
+
+func (s silence) String() string {
+	if !s.daily {
+		return s.window.String()
+	}
+
+	day := time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC)
+	return day.Add(s.from).Format(timeOfDayFormats[0]) + silenceSeparator + day.Add(s.to).Format(timeOfDayFormats[0])
+}
+
+// Check if silence overlaps interval between start and end
+func (s silence) covers(start, end time.Time) bool {
+	if !s.daily {
+		return start.Before(s.window.end) && end.After(s.window.start)
+	}
+
+	// Silence started the day before may last past midnight
+	y, m, d := start.In(time.Local).Date()
+	for day := time.Date(y, m, d-1, 0, 0, 0, 0, time.Local); day.Before(end); day = day.AddDate(0, 0, 1) {
+		from, to := day.Add(s.from), day.Add(s.to)
+		if s.to <= s.from {
+			to = to.AddDate(0, 0, 1)
+		}
+		if start.Before(to) && end.After(from) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Silences from repeated option
+type silences []silence
+
+func (s *silences) String() string {
+	l := make([]string, len(*s))
+	for i, v := range *s {
+		l[i] = v.String()
+	}
+
+	return strings.Join(l, ", ")
+}
+
+func (s *silences) Set(value string) error {
+	v, err := parseSilence(value, time.Now())
+	if err != nil {
+		return err
+	}
+
+	*s = append(*s, v)
+
+	return nil
+}
+
+// Check if any silence overlaps interval between start and end
+func (s silences) covers(start, end time.Time) bool {
+	for _, v := range s {
+		if v.covers(start, end) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Parse daily `HH:MM-HH:MM` silence, passing midnight when it ends before start, or one-off `from..to` window
+func parseSilence(value string, now time.Time) (silence, error) {
+	value = strings.TrimSpace(value)
+
+	if strings.Contains(value, windowSeparator) {
+		start, end, err := parseWindow(value, now)
+		if err != nil {
+			return silence{}, err
+		}
+		return silence{window: timeWindow{start: start, end: end}}, nil
+	}
+
+	from, to, ok := strings.Cut(value, silenceSeparator)
+	if !ok {
+		return silence{}, fmt.Errorf("silence needs to be in HH:MM%sHH:MM or from%sto format, got '%s'", silenceSeparator, windowSeparator, value)
+	}
+
+	s := silence{daily: true}
+	for _, v := range []struct {
+		value string
+		to    *time.Duration
+	}{{from, &s.from}, {to, &s.to}} {
+		d, err := parseTimeOfDay(v.value)
+		if err != nil {
+			return silence{}, err
+		}
+		*v.to = d
+	}
+
+	if s.from == s.to {
+		return silence{}, fmt.Errorf("silence end needs to differ from its start, got '%s'", value)
+	}
+
+	return s, nil
+}
+
+// Time since midnight of time of day
+func parseTimeOfDay(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+
+	for _, f := range timeOfDayFormats {
+		if t, err := time.Parse(f, value); err == nil {
+			return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second, nil
+		}
+	}
+
+	return 0, fmt.Errorf("invalid time of day '%s', use HH:MM", value)
+}
+
+// Read silences file with one silence per line, skipping empty lines and # comments
+func loadSilences(path string, now time.Time) (silences, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var s silences
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		v, err := parseSilence(line, now)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		s = append(s, v)
+	}
+
+	return s, scanner.Err()
+}
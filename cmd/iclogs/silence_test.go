@@ -0,0 +1,110 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSilenceCovers(t *testing.T) {
+	now := time.Date(2025, 1, 11, 12, 0, 0, 0, time.Local)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, 1, day, hour, minute, 0, 0, time.Local)
+	}
+
+	testCases := []struct {
+		name    string
+		silence string
+		start   time.Time
+		end     time.Time
+		want    bool
+	}{
+		{name: "Daily", silence: "02:00-03:00", start: at(12, 2, 10), end: at(12, 2, 11), want: true},
+		{name: "DailyOtherDay", silence: "02:00-03:00", start: at(20, 2, 59), end: at(20, 3, 0), want: true},
+		{name: "DailyBefore", silence: "02:00-03:00", start: at(12, 1, 59), end: at(12, 2, 0), want: false},
+		{name: "DailyAfter", silence: "02:00-03:00", start: at(12, 3, 0), end: at(12, 3, 1), want: false},
+		{name: "DailyOverlap", silence: "02:00-03:00", start: at(12, 1, 55), end: at(12, 2, 5), want: true},
+		{name: "PastMidnight", silence: "23:30-00:30", start: at(12, 0, 10), end: at(12, 0, 11), want: true},
+		{name: "PastMidnightEvening", silence: "23:30 - 00:30", start: at(12, 23, 40), end: at(12, 23, 41), want: true},
+		{name: "PastMidnightOutside", silence: "23:30-00:30", start: at(12, 12, 0), end: at(12, 12, 1), want: false},
+		{name: "Seconds", silence: "02:00:30-02:01", start: at(12, 2, 0), end: at(12, 2, 1), want: true},
+		{name: "OneOff", silence: "2025-01-12T02:00..2025-01-12T04:00", start: at(12, 3, 0), end: at(12, 3, 1), want: true},
+		{name: "OneOffOtherDay", silence: "2025-01-12T02:00..2025-01-12T04:00", start: at(13, 3, 0), end: at(13, 3, 1), want: false},
+		{name: "OneOffTimesOfDay", silence: "22:00..01:00", start: at(12, 0, 30), end: at(12, 0, 31), want: true},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := parseSilence(tt.silence, now)
+			if err != nil {
+				t.Fatalf("Got an error: '%v'", err)
+			}
+			assert(t, s.covers(tt.start, tt.end), tt.want)
+		})
+	}
+}
+
+func TestParseSilenceErrors(t *testing.T) {
+	testCases := []struct {
+		name    string
+		silence string
+		want    string
+	}{
+		{name: "Format", silence: "02:00", want: "silence needs to be in HH:MM-HH:MM or from..to format, got '02:00'"},
+		{name: "TimeOfDay", silence: "2am-3am", want: "invalid time of day '2am', use HH:MM"},
+		{name: "Empty", silence: "02:00-02:00", want: "silence end needs to differ from its start, got '02:00-02:00'"},
+		{name: "Window", silence: "04:00..tomorrow", want: "invalid window time 'tomorrow': parsing time \"tomorrow\" as \"2006-01-02T15:04:05\": cannot parse \"tomorrow\" as \"2006\""},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseSilence(tt.silence, time.Now())
+			if err == nil {
+				t.Fatal("Should get an error!")
+			}
+			assert(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestSilencesFlag(t *testing.T) {
+	var s silences
+	for _, v := range []string{"02:00-03:00", "23:30-00:30"} {
+		if err := s.Set(v); err != nil {
+			t.Fatalf("Got an error: '%v'", err)
+		}
+	}
+	assert(t, s.String(), "02:00-03:00, 23:30-00:30")
+
+	if err := s.Set("noon"); err == nil {
+		t.Error("Should get an error for invalid silence!")
+	}
+}
+
+func TestLoadSilences(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "silences")
+	content := "# Nightly backup\n02:00-03:00\n\n2025-01-12T10:00..2025-01-12T11:00 # Upgrade\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := loadSilences(path, time.Now())
+	if err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+	assert(t, len(s), 2)
+	assert(t, s.covers(time.Date(2025, 1, 12, 10, 30, 0, 0, time.Local), time.Date(2025, 1, 12, 10, 31, 0, 0, time.Local)), true)
+	assert(t, s.covers(time.Date(2025, 1, 12, 11, 30, 0, 0, time.Local), time.Date(2025, 1, 12, 11, 31, 0, 0, time.Local)), false)
+
+	if err := os.WriteFile(path, []byte("02:00-03:00\nnoon\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadSilences(path, time.Now()); err == nil || err.Error() != "line 2: silence needs to be in HH:MM-HH:MM or from..to format, got 'noon'" {
+		t.Errorf("Got: %v, want error of line 2", err)
+	}
+
+	if _, err := loadSilences(filepath.Join(t.TempDir(), "missing"), time.Now()); err == nil {
+		t.Error("Should get an error for missing file!")
+	}
+}
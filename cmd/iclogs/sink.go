@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/logs"
+	"github.com/wooyey/iclogs/internal/platform/plugin"
+)
+
+// Start sink plugin for records of the query, its output is passed to out
+func openSink(out io.Writer, p plugin.Plugin, query string, spec logs.QuerySpec) (io.WriteCloser, error) {
+	env := []string{
+		"ICLOGS_QUERY=" + query,
+		"ICLOGS_FROM=" + spec.StartDate.UTC().Format(time.RFC3339),
+		"ICLOGS_TO=" + spec.EndDate.UTC().Format(time.RFC3339),
+	}
+
+	return p.Writer(env, out)
+}
+
+// Send records to sink plugin as JSON lines, query and time range are passed in environment
+func writeSink(out io.Writer, p plugin.Plugin, l []logs.Log, query string, spec logs.QuerySpec) error {
+	w, err := openSink(out, p, query, spec)
+	if err != nil {
+		return err
+	}
+
+	if err := encodeRecords(w, l); err != nil {
+		w.Close()
+		return err
+	}
+
+	return w.Close()
+}
+
+func printPlugins(w io.Writer, l []plugin.Plugin) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, p := range l {
+		fmt.Fprintf(tw, "%s\t%s\n", p.Name, p.Description)
+	}
+	tw.Flush()
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/logs"
+	"github.com/wooyey/iclogs/internal/platform/plugin"
+)
+
+func TestWriteSink(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh command not available")
+	}
+
+	path := filepath.Join(t.TempDir(), "echo")
+	script := "#!/bin/sh\necho \"$1 $ICLOGS_QUERY $ICLOGS_FROM $ICLOGS_TO\"\ncat\n"
+	if err := os.WriteFile(path, []byte(script), 0o700); err != nil {
+		t.Fatalf("Cannot write plugin: %v", err)
+	}
+
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	spec := logs.QuerySpec{StartDate: start, EndDate: start.Add(time.Hour)}
+	l := []logs.Log{{ID: "1", Time: start, Severity: "Info", UserData: `{"message":"hello"}`}}
+
+	out := bytes.Buffer{}
+	if err := writeSink(&out, plugin.Plugin{Name: "echo", Path: path}, l, "app:web", spec); err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+
+	assert(t, out.String(), "write app:web 2025-01-01T10:00:00Z 2025-01-01T11:00:00Z\n"+
+		`{"id":"1","time":"2025-01-01T10:00:00Z","severity":"Info","data":{"message":"hello"}}`+"\n")
+}
+
+func TestPrintPlugins(t *testing.T) {
+	out := bytes.Buffer{}
+	printPlugins(&out, []plugin.Plugin{{Name: "elastic", Description: "Index records in Elasticsearch"}, {Name: "s3"}})
+
+	assert(t, out.String(), "elastic  Index records in Elasticsearch\ns3       \n")
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/config"
+	"github.com/wooyey/iclogs/internal/platform/logs"
+	"github.com/wooyey/iclogs/internal/platform/slack"
+)
+
+const (
+	slackMaxBody    = 1024 * 1024
+	slackMaxRecords = 10
+	slackMaxLine    = 300
+	slackHelp       = "help"
+)
+
+// Mention of bot user at the beginning of message text
+var mentionRe = regexp.MustCompile(`^\s*<@[^>]+>\s*`)
+
+// Slack bot running whitelisted saved queries from slash commands and mentions
+type slackBot struct {
+	search   searchFunc
+	scope    string
+	secret   string
+	token    string
+	queries  map[string]config.SavedQuery
+	maxRange time.Duration
+	spec     logs.QuerySpec
+	keyNames []string
+	layout   timeLayout
+
+	now     func() time.Time
+	async   func(func())
+	respond func(string, slack.Message) error
+	post    func(string, slack.Message) error
+}
+
+func newSlackBot(cfg config.Config, search searchFunc, args *CmdArgs, profile config.Profile, spec logs.QuerySpec) (*slackBot, error) {
+	b := &slackBot{
+		search:   search,
+		scope:    profile.Scope,
+		secret:   args.SlackSecret,
+		token:    args.SlackToken,
+		queries:  map[string]config.SavedQuery{},
+		maxRange: maxQueryRange(args, profile),
+		spec:     spec,
+		keyNames: strings.Split(args.KeyNames, ","),
+		layout:   args.Times,
+		now:      time.Now,
+		async:    func(f func()) { go f() },
+		respond:  slack.Respond,
+		post:     slack.PostMessage,
+	}
+
+	for _, name := range cfg.Slack.Queries {
+		q, err := cfg.SavedQuery(name)
+		if err != nil {
+			return nil, err
+		}
+		b.queries[name] = q
+	}
+
+	return b, nil
+}
+
+func (b *slackBot) help() string {
+	names := make([]string, 0, len(b.queries))
+	for n := range b.queries {
+		names = append(names, n)
+	}
+	slices.Sort(names)
+
+	s := strings.Builder{}
+	s.WriteString("Available queries:\n")
+	for _, n := range names {
+		fmt.Fprintf(&s, "• `%s` %s\n", n, b.queries[n].Description)
+	}
+
+	return s.String()
+}
+
+// Run allowed saved query and format found records as message snippet
+func (b *slackBot) run(name, user string) string {
+	q, ok := b.queries[name]
+	if !ok {
+		return fmt.Sprintf("Unknown query `%s`. %s", name, b.help())
+	}
+
+	window := b.maxRange
+	if q.Range != "" {
+		d, err := time.ParseDuration(q.Range)
+		if err != nil {
+			return fmt.Sprintf("Invalid range of query `%s`: %v", name, err)
+		}
+		window = min(d, b.maxRange)
+	}
+
+	spec := b.spec
+	spec.EndDate = b.now()
+	spec.StartDate = spec.EndDate.Add(-window)
+
+	l, err := b.search("slack:"+user, scopeQuery(b.scope, q.Query), spec)
+	if err != nil {
+		return fmt.Sprintf("Query `%s` failed: %v", name, err)
+	}
+
+	s := strings.Builder{}
+	fmt.Fprintf(&s, "*%s*: %d records in last %s", name, len(l.Logs), window)
+	if len(l.Logs) == 0 {
+		return s.String()
+	}
+
+	s.WriteString("\n```\n")
+	for _, r := range l.Logs[:min(len(l.Logs), slackMaxRecords)] {
+		text, err := r.Message(b.keyNames)
+		if err != nil {
+			text = r.UserData
+		}
+		line := fmt.Sprintf("%s [%s] %s", b.layout.format(r.Time), r.Severity, strings.ReplaceAll(text, "```", "'''"))
+		fmt.Fprintln(&s, truncate(line, slackMaxLine))
+	}
+	s.WriteString("```")
+
+	return s.String()
+}
+
+// Read request body and check it was signed by Slack
+func (b *slackBot) verify(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, slackMaxBody))
+	if err != nil {
+		http.Error(w, "cannot read request", http.StatusBadRequest)
+		return nil, false
+	}
+
+	err = slack.Verify(b.secret, r.Header.Get("X-Slack-Request-Timestamp"), r.Header.Get("X-Slack-Signature"), body, b.now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return nil, false
+	}
+
+	return body, true
+}
+
+// Slash command gets immediate acknowledgement, results are sent to response URL when query finishes
+func (b *slackBot) handleCommand(w http.ResponseWriter, r *http.Request) {
+	body, ok := b.verify(w, r)
+	if !ok {
+		return
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, "cannot parse command", http.StatusBadRequest)
+		return
+	}
+
+	name := strings.TrimSpace(form.Get("text"))
+	if _, ok := b.queries[name]; !ok || name == slackHelp {
+		writeJSON(w, http.StatusOK, slack.Message{Text: b.help()})
+		return
+	}
+
+	user, responseURL := form.Get("user_name"), form.Get("response_url")
+	writeJSON(w, http.StatusOK, slack.Message{Text: fmt.Sprintf("Running `%s`...", name)})
+
+	b.async(func() {
+		if err := b.respond(responseURL, slack.Message{Text: b.run(name, user), ResponseType: "in_channel"}); err != nil {
+			slog.Error("Cannot respond to Slack command", "error", err)
+		}
+	})
+}
+
+type slackEvent struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	Event     struct {
+		Type    string `json:"type"`
+		User    string `json:"user"`
+		Text    string `json:"text"`
+		Channel string `json:"channel"`
+	} `json:"event"`
+}
+
+// Mentions of bot are answered in the same channel, retried deliveries are ignored as first one was acknowledged
+func (b *slackBot) handleEvent(w http.ResponseWriter, r *http.Request) {
+	body, ok := b.verify(w, r)
+	if !ok {
+		return
+	}
+
+	var e slackEvent
+	if err := json.Unmarshal(body, &e); err != nil {
+		http.Error(w, "cannot parse event", http.StatusBadRequest)
+		return
+	}
+
+	if e.Type == "url_verification" {
+		w.Header().Set("Content-Type", "text/plain")
+		io.WriteString(w, e.Challenge)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+
+	if e.Type != "event_callback" || e.Event.Type != "app_mention" || r.Header.Get("X-Slack-Retry-Num") != "" {
+		return
+	}
+
+	name := strings.TrimSpace(mentionRe.ReplaceAllString(e.Event.Text, ""))
+	b.async(func() {
+		text := b.help()
+		if _, ok := b.queries[name]; ok {
+			text = b.run(name, e.Event.User)
+		}
+		if err := b.post(b.token, slack.Message{Channel: e.Event.Channel, Text: text}); err != nil {
+			slog.Error("Cannot post Slack message", "error", err)
+		}
+	})
+}
+
+func (b *slackBot) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /slack/commands", b.handleCommand)
+	mux.HandleFunc("POST /slack/events", b.handleEvent)
+	return mux
+}
+
+// Serve Slack endpoints until server fails
+func runSlackBot(b *slackBot, listen string) error {
+	srv := &http.Server{
+		Addr:              listen,
+		Handler:           b.routes(),
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	slog.Info("Serving Slack bot", "url", "http://"+listen)
+	return srv.ListenAndServe()
+}
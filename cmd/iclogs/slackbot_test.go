@@ -0,0 +1,128 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/config"
+	"github.com/wooyey/iclogs/internal/platform/logs"
+	"github.com/wooyey/iclogs/internal/platform/slack"
+)
+
+type slackCall struct {
+	target string
+	msg    slack.Message
+}
+
+const slackTimestamp = "1735725600"
+
+func testSlackBot(t *testing.T, calls *[]slackCall, clients *[]string) *slackBot {
+	now := time.Unix(1735725600, 0)
+
+	cfg := config.Config{
+		Queries: map[string]config.SavedQuery{
+			"errors": {Query: "severity:error", Range: "15m", Description: "Recent errors"},
+			"secret": {Query: "app:vault"},
+		},
+		Slack: config.Slack{Queries: []string{"errors"}},
+	}
+	search := func(client, query string, spec logs.QuerySpec) (logs.Result, error) {
+		*clients = append(*clients, client+" "+query+" "+spec.EndDate.Sub(spec.StartDate).String())
+		return logs.Result{Logs: []logs.Log{{Time: now, Severity: "Error", UserData: `{"message":"boom"}`}}}, nil
+	}
+
+	b, err := newSlackBot(cfg, search, &CmdArgs{SlackSecret: "secret", SlackToken: "xoxb", TimeRange: time.Hour, KeyNames: "message"}, config.Profile{Scope: "app:web"}, logs.QuerySpec{})
+	if err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+
+	b.now = func() time.Time { return now }
+	b.async = func(f func()) { f() }
+	b.respond = func(u string, m slack.Message) error {
+		*calls = append(*calls, slackCall{u, m})
+		return nil
+	}
+	b.post = func(token string, m slack.Message) error {
+		*calls = append(*calls, slackCall{token, m})
+		return nil
+	}
+
+	return b
+}
+
+func signedRequest(b *slackBot, target, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+	req.Header.Set("X-Slack-Request-Timestamp", slackTimestamp)
+	req.Header.Set("X-Slack-Signature", slack.Sign(b.secret, slackTimestamp, []byte(body)))
+	return req
+}
+
+func TestSlackCommand(t *testing.T) {
+	var calls []slackCall
+	var clients []string
+	b := testSlackBot(t, &calls, &clients)
+
+	form := url.Values{"text": {"errors"}, "user_name": {"alice"}, "response_url": {"https://hooks.slack.com/r"}}
+	rec := httptest.NewRecorder()
+	b.routes().ServeHTTP(rec, signedRequest(b, "/slack/commands", form.Encode()))
+
+	assert(t, rec.Code, http.StatusOK)
+	assert(t, strings.Contains(rec.Body.String(), "Running `errors`"), true)
+	assertDeepEqual(t, clients, []string{"slack:alice (app:web) AND (severity:error) 15m0s"})
+	assert(t, len(calls), 1)
+	assert(t, calls[0].target, "https://hooks.slack.com/r")
+	assert(t, calls[0].msg.ResponseType, "in_channel")
+	assert(t, calls[0].msg.Text, "*errors*: 1 records in last 15m0s\n```\n"+
+		time.Unix(1735725600, 0).Format(timeStampFormat)+" [Error] boom\n```")
+
+	// Saved query not allowed for Slack is not run
+	form.Set("text", "secret")
+	rec = httptest.NewRecorder()
+	b.routes().ServeHTTP(rec, signedRequest(b, "/slack/commands", form.Encode()))
+	assert(t, strings.Contains(rec.Body.String(), "Available queries"), true)
+	assert(t, len(clients), 1)
+
+	// Unsigned request is rejected
+	req := signedRequest(b, "/slack/commands", form.Encode())
+	req.Header.Set("X-Slack-Signature", "v0=00")
+	rec = httptest.NewRecorder()
+	b.routes().ServeHTTP(rec, req)
+	assert(t, rec.Code, http.StatusUnauthorized)
+}
+
+func TestSlackEvents(t *testing.T) {
+	var calls []slackCall
+	var clients []string
+	b := testSlackBot(t, &calls, &clients)
+
+	rec := httptest.NewRecorder()
+	b.routes().ServeHTTP(rec, signedRequest(b, "/slack/events", `{"type":"url_verification","challenge":"abc"}`))
+	assert(t, rec.Body.String(), "abc")
+
+	mention := `{"type":"event_callback","event":{"type":"app_mention","user":"U1","text":"<@UBOT> errors","channel":"C1"}}`
+	rec = httptest.NewRecorder()
+	b.routes().ServeHTTP(rec, signedRequest(b, "/slack/events", mention))
+	assert(t, rec.Code, http.StatusOK)
+	assertDeepEqual(t, clients, []string{"slack:U1 (app:web) AND (severity:error) 15m0s"})
+	assert(t, len(calls), 1)
+	assert(t, calls[0].target, "xoxb")
+	assert(t, calls[0].msg.Channel, "C1")
+
+	// Retried delivery is ignored
+	req := signedRequest(b, "/slack/events", mention)
+	req.Header.Set("X-Slack-Retry-Num", "1")
+	rec = httptest.NewRecorder()
+	b.routes().ServeHTTP(rec, req)
+	assert(t, len(calls), 1)
+}
+
+func TestNewSlackBotMissingQuery(t *testing.T) {
+	cfg := config.Config{Slack: config.Slack{Queries: []string{"missing"}}}
+	if _, err := newSlackBot(cfg, nil, &CmdArgs{}, config.Profile{}, logs.QuerySpec{}); err == nil {
+		t.Error("Should get an error for allowed query missing in config!")
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const snippetSource = "source logs"
+
+// Placeholder of snippet pipeline, ie. `{app}`
+var snippetPlaceholder = regexp.MustCompile(`\{(\w+)\}`)
+
+// Patterns of parameters put into pipeline unquoted, other parameters are quoted within the pipeline
+var snippetParams = map[string]*regexp.Regexp{
+	"field":     regexp.MustCompile(`^[A-Za-z_]\w*(\.[A-Za-z_]\w*)*$`),
+	"limit":     regexp.MustCompile(`^[1-9]\d*$`),
+	"threshold": regexp.MustCompile(`^-?\d+(\.\d+)?$`),
+}
+
+var errParam = errors.New("snippet parameter needs to be given as name=value")
+
+// Dataprime pipeline with placeholders filled from --param options
+type snippet struct {
+	pipeline string
+	usage    string
+	table    bool // Pipeline aggregates records into rows
+}
+
+// Built-in snippets of common Dataprime pipelines
+var snippets = map[string]snippet{
+	"count-by-app":      {pipeline: "source logs | countby $l.applicationname", usage: "Records count per application.", table: true},
+	"count-by-severity": {pipeline: "source logs | countby $m.severity", usage: "Records count per severity.", table: true},
+	"errors-by-subsystem": {
+		pipeline: "source logs | filter $m.severity == ERROR || $m.severity == CRITICAL | countby $l.subsystemname",
+		usage:    "Error and critical records count per subsystem.",
+		table:    true,
+	},
+	"field-values": {
+		pipeline: "source logs | filter $l.applicationname == '{app}' | countby $d.{field}",
+		usage:    "Records count per value of user data field of application.",
+		table:    true,
+	},
+	"top-values": {
+		pipeline: "source logs | filter $l.applicationname == '{app}' | top {limit} $d.{field} by count()",
+		usage:    "The most frequent values of user data field of application.",
+		table:    true,
+	},
+	"slow": {
+		pipeline: "source logs | filter $l.applicationname == '{app}' && $d.{field}:number > {threshold}",
+		usage:    "Records of application with numeric user data field above threshold, ie. duration.",
+	},
+	"distinct": {pipeline: "source logs | distinct $d.{field}", usage: "Distinct values of user data field.", table: true},
+}
+
+// Snippet parameters from repeated name=value flag
+type params map[string]string
+
+func (p *params) String() string {
+	var pairs []string
+	for k, v := range *p {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, ",")
+}
+
+func (p *params) Set(value string) error {
+	name, v, ok := strings.Cut(value, "=")
+	if name = strings.TrimSpace(name); !ok || name == "" {
+		return errParam
+	}
+
+	if *p == nil {
+		*p = params{}
+	}
+	(*p)[name] = v
+
+	return nil
+}
+
+// Dataprime query of snippet with placeholders filled, scope and query are applied as Lucene stages before the pipeline
+func snippetQuery(name string, p params, scope, query string) (string, error) {
+	s, ok := snippets[name]
+	if !ok {
+		return "", fmt.Errorf("unknown snippet '%s', see snippets command", name)
+	}
+
+	var missing, invalid []string
+	pipeline := snippetPlaceholder.ReplaceAllStringFunc(s.pipeline, func(m string) string {
+		key := m[1 : len(m)-1]
+		v, ok := p[key]
+		if !ok {
+			missing = append(missing, key)
+			return m
+		}
+		if re, ok := snippetParams[key]; ok {
+			if !re.MatchString(v) {
+				invalid = append(invalid, key+"="+v)
+			}
+			return v
+		}
+		return dataprimeEscaper.Replace(v)
+	})
+	if len(missing) != 0 {
+		return "", fmt.Errorf("snippet '%s' needs parameters: %s", name, strings.Join(missing, ", "))
+	}
+	if len(invalid) != 0 {
+		return "", fmt.Errorf("invalid parameters of snippet '%s', field needs to be keypath and limit or threshold number: %s", name, strings.Join(invalid, ", "))
+	}
+
+	var stages []string
+	for _, clause := range []string{scope, query} {
+		if clause = strings.TrimSpace(clause); clause != "" {
+			stages = append(stages, "lucene "+dataprimeString(clause))
+		}
+	}
+	if len(stages) == 0 {
+		return pipeline, nil
+	}
+
+	rest := strings.TrimPrefix(pipeline, snippetSource)
+	return snippetSource + " | " + strings.Join(stages, " | ") + rest, nil
+}
+
+// List built-in snippets with their pipelines
+func printSnippets(w io.Writer) {
+	names := make([]string, 0, len(snippets))
+	for n := range snippets {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	for _, n := range names {
+		fmt.Fprintf(w, "%s\n        %s\n        %s\n", n, snippets[n].usage, snippets[n].pipeline)
+	}
+}
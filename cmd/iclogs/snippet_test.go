@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestParams(t *testing.T) {
+	p := params{}
+
+	for _, v := range []string{"app=web", "field=status=code", "empty="} {
+		if err := p.Set(v); err != nil {
+			t.Fatalf("Got unexpected error: %v", err)
+		}
+	}
+	assertDeepEqual(t, p, params{"app": "web", "field": "status=code", "empty": ""})
+	assert(t, p.String(), "app=web,empty=,field=status=code")
+
+	assertError(t, p.Set("app"), errParam)
+	assertError(t, p.Set("=web"), errParam)
+
+	// Zero value of flag
+	var z params
+	if err := z.Set("app=web"); err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	assert(t, z["app"], "web")
+}
+
+func TestSnippetQuery(t *testing.T) {
+	testCases := []struct {
+		name    string
+		snippet string
+		params  params
+		scope   string
+		query   string
+		want    string
+		err     bool
+	}{
+		{name: "Plain", snippet: "count-by-app", want: "source logs | countby $l.applicationname"},
+		{name: "Params", snippet: "top-values", params: params{"app": "web", "field": "status", "limit": "5"}, want: "source logs | filter $l.applicationname == 'web' | top 5 $d.status by count()"},
+		{name: "Quoted", snippet: "field-values", params: params{"app": "it's", "field": "code"}, want: `source logs | filter $l.applicationname == 'it\'s' | countby $d.code`},
+		{name: "ScopeAndQuery", snippet: "count-by-severity", scope: "env:prod", query: "error", want: "source logs | lucene 'env:prod' | lucene 'error' | countby $m.severity"},
+		{name: "Keypath", snippet: "slow", params: params{"app": "web", "field": "http.duration_ms", "threshold": "2.5"}, want: "source logs | filter $l.applicationname == 'web' && $d.http.duration_ms:number > 2.5"},
+		{name: "MissingParams", snippet: "slow", params: params{"app": "web"}, err: true},
+		{name: "FieldInjection", snippet: "distinct", params: params{"field": "x | block $d.y"}, err: true},
+		{name: "LimitInjection", snippet: "top-values", params: params{"app": "web", "field": "status", "limit": "5 $d.x by count() | limit 1"}, err: true},
+		{name: "ThresholdInjection", snippet: "slow", params: params{"app": "web", "field": "took", "threshold": "0 || true"}, err: true},
+		{name: "Unknown", snippet: "nope", err: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := snippetQuery(tc.snippet, tc.params, tc.scope, tc.query)
+			if (err != nil) != tc.err {
+				t.Fatalf("Got error: %v, want error: %v", err, tc.err)
+			}
+			assert(t, got, tc.want)
+		})
+	}
+}
+
+func TestPrintSnippets(t *testing.T) {
+	buffer := bytes.Buffer{}
+	printSnippets(&buffer)
+
+	assert(t, strings.HasPrefix(buffer.String(), "count-by-app\n        Records count per application.\n        source logs | countby $l.applicationname\n"), true)
+	assert(t, strings.Count(buffer.String(), "source logs"), len(snippets))
+}
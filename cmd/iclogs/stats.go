@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/wooyey/iclogs/internal/platform/config"
+	"github.com/wooyey/iclogs/internal/platform/logs"
+	"github.com/wooyey/iclogs/internal/platform/logs/filter"
+	"github.com/wooyey/iclogs/internal/platform/stats"
+)
+
+const defaultDurationUnit = time.Millisecond
+
+// Extracts duration from regular expression capture group in message or from record field
+type durationExtractor struct {
+	re    *regexp.Regexp
+	field string
+	unit  time.Duration
+}
+
+// Regular expression needs a capture group, anything else is a field name
+func newDurationExtractor(spec string, unit time.Duration) (*durationExtractor, error) {
+	if !strings.Contains(spec, "(") {
+		return &durationExtractor{field: spec, unit: unit}, nil
+	}
+
+	re, err := regexp.Compile(spec)
+	if err != nil {
+		return nil, fmt.Errorf("cannot compile duration expression: %w", err)
+	}
+
+	if re.NumSubexp() < 1 {
+		return nil, fmt.Errorf("duration expression '%s' needs a capture group", spec)
+	}
+
+	return &durationExtractor{re: re, unit: unit}, nil
+}
+
+// Parse value with unit (ie. `1.5s`) or plain number in default unit
+func (e *durationExtractor) parse(v string) (time.Duration, bool) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0, false
+	}
+
+	if unicode.IsLetter(rune(v[len(v)-1])) {
+		d, err := time.ParseDuration(v)
+		return d, err == nil
+	}
+
+	n, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return time.Duration(n * float64(e.unit)), true
+}
+
+func (e *durationExtractor) extract(l *logs.Log, keyNames []string, a config.Aliases) (time.Duration, bool) {
+	if e.re == nil {
+		v, ok := aliasResolver(filter.LogResolver(l), a)(e.field)
+		if !ok || v == nil {
+			return 0, false
+		}
+		return e.parse(fmt.Sprint(v))
+	}
+
+	text, err := l.Message(keyNames)
+	if err != nil {
+		text = l.UserData
+	}
+
+	m := e.re.FindStringSubmatch(text)
+	if m == nil {
+		return 0, false
+	}
+
+	return e.parse(m[1])
+}
+
+// Durations of log records in nanoseconds, records without duration are skipped
+func extractDurations(l []logs.Log, e *durationExtractor, keyNames []string, a config.Aliases) []float64 {
+	var values []float64
+
+	for i := range l {
+		if d, ok := e.extract(&l[i], keyNames, a); ok {
+			values = append(values, float64(d))
+		}
+	}
+
+	return values
+}
+
+func printPercentiles(w io.Writer, s stats.Summary, total int) {
+
+	fmt.Fprintf(w, "Durations: %d of %d records\n", s.Count, total)
+	if s.Count == 0 {
+		return
+	}
+
+	for _, v := range []struct {
+		name  string
+		value float64
+	}{
+		{"min", s.Min},
+		{"p50", s.P50},
+		{"p90", s.P90},
+		{"p99", s.P99},
+		{"max", s.Max},
+	} {
+		fmt.Fprintf(w, "%s: %s\n", v.name, time.Duration(v.value))
+	}
+
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/logs"
+	"github.com/wooyey/iclogs/internal/platform/stats"
+)
+
+func TestExtractDurations(t *testing.T) {
+	l := []logs.Log{
+		{UserData: `{"message":"GET / took 120ms","took":"0.5"}`},
+		{UserData: `{"message":"GET /api took 1.5s","took":1.2}`},
+		{UserData: `{"message":"GET /health took 30","took":"fast"}`},
+		{UserData: `{"message":"no duration"}`},
+	}
+	keyNames := []string{"message"}
+
+	testCases := []struct {
+		name string
+		spec string
+		unit time.Duration
+		want []float64
+	}{
+		{
+			name: "Regexp",
+			spec: `took (\S+)`,
+			unit: time.Millisecond,
+			want: []float64{float64(120 * time.Millisecond), float64(1500 * time.Millisecond), float64(30 * time.Millisecond)},
+		},
+		{
+			name: "Field",
+			spec: "json.took",
+			unit: time.Second,
+			want: []float64{float64(500 * time.Millisecond), float64(1200 * time.Millisecond)},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := newDurationExtractor(tt.spec, tt.unit)
+			assertError(t, err, nil)
+
+			got := extractDurations(l, e, keyNames, nil)
+			assertDeepEqual(t, got, tt.want)
+		})
+	}
+}
+
+func TestNewDurationExtractorErrors(t *testing.T) {
+	if _, err := newDurationExtractor(`took (\d+ms`, time.Millisecond); err == nil {
+		t.Error("Should get an error for broken expression!")
+	}
+
+	if _, err := newDurationExtractor(`took (?:\d+)ms`, time.Millisecond); err == nil {
+		t.Error("Should get an error for expression without capture group!")
+	}
+}
+
+func TestPrintPercentiles(t *testing.T) {
+	s := stats.Summary{
+		Count: 3,
+		Min:   float64(10 * time.Millisecond),
+		P50:   float64(20 * time.Millisecond),
+		P90:   float64(time.Second),
+		P99:   float64(time.Second),
+		Max:   float64(time.Second),
+	}
+	want := "Durations: 3 of 5 records\nmin: 10ms\np50: 20ms\np90: 1s\np99: 1s\nmax: 1s\n"
+
+	buffer := bytes.Buffer{}
+	printPercentiles(&buffer, s, 5)
+	assert(t, buffer.String(), want)
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+)
+
+var errStopped = errors.New("fetching stopped by user")
+
+// Typed followed by Enter on terminal stops fetching
+const stopKey = "p"
+
+const stoppedWarning = "fetching stopped by user, results are partial"
+
+// Exit code of process quit by second interrupt, like shell reports it
+const interruptedCode = 130
+
+// Stops fetching on first interrupt or stop key, keeping records received so far. Second interrupt quits.
+type stopper struct {
+	cancel  context.CancelFunc
+	out     io.Writer
+	quit    func(code int)
+	answers chan string // Other typed lines, taken by prompt waiting for them
+
+	once        sync.Once
+	mu          sync.Mutex
+	interrupted bool
+}
+
+func (s *stopper) stop() {
+	s.once.Do(func() {
+		fmt.Fprintln(s.out, "Stopping, records received so far are kept. Press Ctrl-C again to quit.")
+		s.cancel()
+	})
+}
+
+func (s *stopper) interrupt() {
+	s.mu.Lock()
+	second := s.interrupted
+	s.interrupted = true
+	s.mu.Unlock()
+
+	if second {
+		s.quit(interruptedCode)
+		return
+	}
+	s.stop()
+}
+
+// Lines typed on terminal, read by one goroutine for the whole run, so watchers and prompts never compete for them
+var (
+	terminalOnce  sync.Once
+	terminalInput chan string
+)
+
+func terminalLines() <-chan string {
+	terminalOnce.Do(func() {
+		terminalInput = make(chan string)
+		go func() {
+			scanner := bufio.NewScanner(os.Stdin)
+			for scanner.Scan() {
+				terminalInput <- scanner.Text()
+			}
+			close(terminalInput)
+		}()
+	})
+
+	return terminalInput
+}
+
+// Stop when stop key line is read, other lines are answers to prompt if any waits for them. Ends when done is closed.
+func (s *stopper) watchKeys(lines <-chan string, done <-chan struct{}) {
+	for {
+		var line string
+		var ok bool
+		select {
+		case <-done:
+			return
+		case line, ok = <-lines:
+			if !ok {
+				return
+			}
+		}
+
+		if strings.TrimSpace(line) == stopKey {
+			s.stop()
+			return
+		}
+
+		select {
+		case s.answers <- line:
+		default:
+		}
+	}
+}
+
+// Context of fetching which user can stop with Ctrl-C, or stop key when standard input is terminal.
+// Returned channel gets other lines typed meanwhile, ie. answers to prompts.
+// Returned function ends watching, interrupt then quits as usual.
+func watchStop(out io.Writer) (context.Context, <-chan string, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &stopper{cancel: cancel, out: out, quit: os.Exit, answers: make(chan string)}
+
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt)
+	go func() {
+		for range signals {
+			s.interrupt()
+		}
+	}()
+
+	done := make(chan struct{})
+	if isTerminal(os.Stdin) {
+		go s.watchKeys(terminalLines(), done)
+	}
+
+	return ctx, s.answers, func() {
+		signal.Stop(signals)
+		close(signals)
+		close(done)
+	}
+}
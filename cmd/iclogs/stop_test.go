@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/config"
+	"github.com/wooyey/iclogs/internal/platform/logs"
+	"github.com/wooyey/iclogs/tests"
+)
+
+func TestStopper(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	code := 0
+	s := &stopper{cancel: cancel, out: &bytes.Buffer{}, quit: func(c int) { code = c }}
+
+	s.interrupt()
+	assert(t, ctx.Err(), context.Canceled)
+	assert(t, code, 0)
+
+	s.interrupt()
+	assert(t, code, interruptedCode)
+}
+
+func TestStopperKeys(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	out := bytes.Buffer{}
+	s := &stopper{cancel: cancel, out: &out}
+
+	lines := make(chan string, 3)
+	lines <- "q"
+	lines <- " p "
+	lines <- "x"
+	s.watchKeys(lines, nil)
+	assert(t, ctx.Err(), context.Canceled)
+	assert(t, strings.Count(out.String(), "Stopping"), 1)
+
+	// Stop key after interrupt does not stop again
+	s.stop()
+	assert(t, strings.Count(out.String(), "Stopping"), 1)
+}
+
+func TestStopperAnswers(t *testing.T) {
+	_, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := &stopper{cancel: cancel, out: &bytes.Buffer{}, answers: make(chan string)}
+
+	// Lines typed while no prompt waits are dropped, so keep typing until it gets one
+	lines := make(chan string)
+	done := make(chan struct{})
+	ended := make(chan struct{})
+	go func() {
+		s.watchKeys(lines, done)
+		close(ended)
+	}()
+	answered := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-answered:
+				return
+			case lines <- "y":
+			}
+		}
+	}()
+
+	assert(t, <-s.answers, "y")
+	close(answered)
+
+	// Ended watching leaves further lines to others
+	close(done)
+	<-ended
+}
+
+func TestSessionStop(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/identity/token") {
+			fmt.Fprint(w, `{"access_token":"token","expires_in":3600}`)
+			return
+		}
+		fmt.Fprint(w, tests.LoadData("response_logs.txt"))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	defaultQueryURL := logs.GetQueryURL
+	logs.GetQueryURL = func(endpoint string) (string, error) { return endpoint, nil }
+	defer func() { logs.GetQueryURL = defaultQueryURL }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := newSession(&CmdArgs{AuthURL: srv.URL, APIKey: "key", LogsURL: srv.URL}, config.Config{}, config.Profile{})
+	s.stop = ctx
+	time.AfterFunc(200*time.Millisecond, cancel)
+
+	l, err := s.query("", "app:web", logs.QuerySpec{})
+	assertError(t, err, errStopped)
+	if len(l.Logs) == 0 {
+		t.Error("Got no records received before stop")
+	}
+
+	// No more queries after stop
+	if _, err = s.query("", "app:web", logs.QuerySpec{}); !errors.Is(err, errStopped) {
+		t.Errorf("Got error: %v, want: %v", err, errStopped)
+	}
+}
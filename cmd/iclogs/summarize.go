@@ -0,0 +1,207 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/config"
+	"github.com/wooyey/iclogs/internal/platform/i18n"
+	"github.com/wooyey/iclogs/internal/platform/logs"
+)
+
+const (
+	summaryTopGroups   = 10
+	summaryTopPatterns = 10
+	summaryBuckets     = 60
+	maxPatternLength   = 100
+)
+
+// Variable parts of messages replaced by placeholders, so messages differing only by them share pattern
+var patternMasks = []struct {
+	re      *regexp.Regexp
+	replace func(string) string
+}{
+	{regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`), placeholder("<uuid>")},
+	{regexp.MustCompile(`\b\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2})?`), placeholder("<time>")},
+	{regexp.MustCompile(`\b\d{1,3}(\.\d{1,3}){3}(:\d+)?\b`), placeholder("<ip>")},
+	{regexp.MustCompile(`\b(0x)?[0-9a-fA-F]{6,}\b`), hexPlaceholder},
+	{regexp.MustCompile(`\d+(\.\d+)?`), placeholder("<n>")},
+}
+
+func placeholder(p string) func(string) string {
+	return func(string) string { return p }
+}
+
+// Hexadecimal IDs mix digits and letters, words or plain numbers are kept
+func hexPlaceholder(s string) string {
+	if strings.HasPrefix(s, "0x") || strings.ContainsAny(s, "0123456789") && strings.ContainsAny(s, "abcdefABCDEF") {
+		return "<hex>"
+	}
+	return s
+}
+
+// Records sharing message pattern with their first and last occurrence
+type pattern struct {
+	text        string
+	count       int
+	first, last time.Time
+}
+
+// Message of record with variable parts, ie. numbers, IDs and addresses, replaced by placeholders
+func messagePattern(message string) string {
+	for _, m := range patternMasks {
+		message = m.re.ReplaceAllStringFunc(message, m.replace)
+	}
+
+	message = strings.Join(strings.Fields(message), " ")
+	if r := []rune(message); len(r) > maxPatternLength {
+		message = string(r[:maxPatternLength]) + "…"
+	}
+
+	return message
+}
+
+// Patterns of records messages from the most frequent one, records without message use their user data
+func topPatterns(l []logs.Log, keyNames []string) []pattern {
+	index := map[string]int{}
+	var patterns []pattern
+
+	for i := range l {
+		msg, err := l[i].Message(keyNames)
+		if err != nil || msg == "" {
+			msg = l[i].UserData
+		}
+		text := messagePattern(msg)
+
+		n, ok := index[text]
+		if !ok {
+			n = len(patterns)
+			index[text] = n
+			patterns = append(patterns, pattern{text: text, first: l[i].Time, last: l[i].Time})
+		}
+
+		p := &patterns[n]
+		p.count++
+		if l[i].Time.Before(p.first) {
+			p.first = l[i].Time
+		}
+		if l[i].Time.After(p.last) {
+			p.last = l[i].Time
+		}
+	}
+
+	slices.SortStableFunc(patterns, func(x, y pattern) int {
+		return cmp.Or(y.count-x.count, x.first.Compare(y.first))
+	})
+
+	return patterns
+}
+
+// Rank of severity in breakdown order from the most severe, other severities follow
+func severityIndex(severity string) int {
+	if i := logs.SeverityRank(severity); i >= 0 {
+		return len(logs.Severities) - 1 - i
+	}
+	return len(logs.Severities)
+}
+
+// Print incident window report: records stats, timeline per severity, top groups and top message patterns
+func printIncidentSummary(w io.Writer, query string, l []logs.Log, spec logs.QuerySpec, keyNames []string, groupBy string, a config.Aliases, layout timeLayout) {
+	if groupBy == "" {
+		groupBy = defaultDashGroup
+	}
+
+	window := spec.EndDate.Sub(spec.StartDate)
+	h := &histogram{start: spec.StartDate, end: spec.EndDate, buckets: summaryBuckets}
+
+	fmt.Fprint(w, i18n.Sprintf("Query: %s\n", query))
+	fmt.Fprint(w, i18n.Sprintf("Window: %s - %s (%s)\n", layout.format(spec.StartDate), layout.format(spec.EndDate), window))
+	fmt.Fprint(w, i18n.Sprintf("Records: %d", len(l)))
+	if window.Minutes() > 0 {
+		fmt.Fprint(w, i18n.Sprintf(", %s per minute", formatNumber(float64(len(l))/window.Minutes())))
+	}
+	fmt.Fprintln(w)
+
+	if len(l) == 0 {
+		return
+	}
+
+	times := make([]time.Time, len(l))
+	for i := range l {
+		times[i] = l[i].Time
+	}
+	first, last := slices.MinFunc(times, time.Time.Compare), slices.MaxFunc(times, time.Time.Compare)
+	fmt.Fprint(w, i18n.Sprintf("First: %s, last: %s\n", layout.format(first), layout.format(last)))
+
+	all := h.counts(times)
+	peak := slices.Index(all, slices.Max(all))
+	bucket := window / time.Duration(h.buckets)
+	fmt.Fprint(w, i18n.Sprintf("Peak: %d records in %s from %s\n", all[peak], bucket, layout.format(spec.StartDate.Add(time.Duration(peak)*bucket))))
+
+	severities := aggregate(l, nil, "severity", a)
+	slices.SortStableFunc(severities, func(x, y aggGroup) int {
+		return severityIndex(x.key) - severityIndex(y.key)
+	})
+
+	fmt.Fprint(w, i18n.Sprintf("\nTimeline (%s per character):\n", bucket))
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "all\t|%s|\t%d\n", sparkline(all), len(l))
+	for _, g := range severities {
+		fmt.Fprintf(tw, "%s\t|%s|\t%d\n", g.key, sparkline(h.counts(g.times)), g.count)
+	}
+	start, end := layout.format(spec.StartDate), layout.format(spec.EndDate)
+	axis := start + strings.Repeat(" ", max(h.buckets+2-len(start)-len(end), 1)) + end
+	fmt.Fprintf(tw, "\t%s\n", axis)
+	tw.Flush()
+
+	groups := aggregate(l, nil, groupBy, a)
+	slices.SortStableFunc(groups, func(x, y aggGroup) int {
+		return y.count - x.count
+	})
+
+	fmt.Fprintln(w)
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "top %s\tcount\tfirst\tlast\n", groupBy)
+	for _, g := range groups[:min(len(groups), summaryTopGroups)] {
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\n", g.key, g.count,
+			layout.format(slices.MinFunc(g.times, time.Time.Compare)), layout.format(slices.MaxFunc(g.times, time.Time.Compare)))
+	}
+	tw.Flush()
+
+	patterns := topPatterns(l, keyNames)
+
+	fmt.Fprint(w, i18n.Sprintf("\nTop patterns (%d distinct):\n", len(patterns)))
+	printPatterns(w, patterns, summaryTopPatterns, layout)
+}
+
+// Print patterns table with their first and last occurrence, limited to given number of patterns when it is positive
+func printPatterns(w io.Writer, patterns []pattern, limit int, layout timeLayout) {
+	if limit > 0 {
+		patterns = patterns[:min(len(patterns), limit)]
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "count\tfirst\tlast\tpattern")
+	for _, p := range patterns {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", p.count, layout.format(p.first), layout.format(p.last), p.text)
+	}
+	tw.Flush()
+}
+
+// Search records of incident window and print its report
+func runSummarize(out io.Writer, search searchFunc, query string, spec logs.QuerySpec, keyNames []string, groupBy string, a config.Aliases, layout timeLayout) error {
+	l, err := search("", query, spec)
+	if err != nil {
+		return err
+	}
+
+	printIncidentSummary(out, query, l.Logs, spec, keyNames, groupBy, a, layout)
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/logs"
+)
+
+func TestMessagePattern(t *testing.T) {
+	testCases := []struct {
+		name    string
+		message string
+		want    string
+	}{
+		{name: "Numbers", message: "request 17 took 250ms", want: "request <n> took <n>ms"},
+		{name: "Address", message: "cannot connect to 10.0.0.1:8080", want: "cannot connect to <ip>"},
+		{name: "UUID", message: "record 2875ffa6-d102-4043-b9dd-a8daf3f7d3c7 not found", want: "record <uuid> not found"},
+		{name: "Time", message: "expired at 2025-01-11T18:52:21.026Z", want: "expired at <time>"},
+		{name: "Hex", message: "commit 9f59b0b and 0xdeadbeef", want: "commit <hex> and <hex>"},
+		{name: "Words", message: "decade facade", want: "decade facade"},
+		{name: "Spaces", message: "  two\tspaces  ", want: "two spaces"},
+		{name: "Long", message: strings.Repeat("a", 120), want: strings.Repeat("a", maxPatternLength) + "…"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert(t, messagePattern(tt.message), tt.want)
+		})
+	}
+}
+
+func TestPrintIncidentSummary(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.Local)
+	spec := logs.QuerySpec{StartDate: start, EndDate: start.Add(time.Hour)}
+
+	l := []logs.Log{
+		{Time: start.Add(5 * time.Minute), Severity: "Info", UserData: `{"message":"request 17 took 250ms","app":"api"}`},
+		{Time: start.Add(30 * time.Minute), Severity: "Error", UserData: `{"message":"upstream 10.0.0.1:8080 timeout","app":"api"}`},
+		{Time: start.Add(31 * time.Minute), Severity: "Error", UserData: `{"message":"upstream 10.0.0.2:8080 timeout","app":"web"}`},
+		{Time: start.Add(31*time.Minute + time.Second), Severity: "Critical", UserData: `{"message":"upstream 10.0.0.2:8080 timeout","app":"web"}`},
+		{Time: start.Add(50 * time.Minute), Severity: "Info", UserData: `{"message":"request 18 took 12ms","app":"api"}`},
+		{Time: start.Add(59 * time.Minute), Severity: "Info", UserData: `{"app":"api"}`},
+	}
+
+	want := "Query: some query\n" +
+		"Window: 2025-01-01 10:00:00 - 2025-01-01 11:00:00 (1h0m0s)\n" +
+		"Records: 6, 0.1 per minute\n" +
+		"First: 2025-01-01 10:05:00, last: 2025-01-01 10:59:00\n" +
+		"Peak: 2 records in 1m0s from 2025-01-01 10:31:00\n" +
+		"\n" +
+		"Timeline (1m0s per character):\n" +
+		"all       |     ▄                        ▄█                  ▄        ▄|  6\n" +
+		"Critical  |                               █                            |  1\n" +
+		"Error     |                              ██                            |  2\n" +
+		"Info      |     █                                            █        █|  3\n" +
+		"          2025-01-01 10:00:00                        2025-01-01 11:00:00\n" +
+		"\n" +
+		"top json.app  count  first                last\n" +
+		"api           4      2025-01-01 10:05:00  2025-01-01 10:59:00\n" +
+		"web           2      2025-01-01 10:31:00  2025-01-01 10:31:01\n" +
+		"\n" +
+		"Top patterns (3 distinct):\n" +
+		"count  first                last                 pattern\n" +
+		"3      2025-01-01 10:30:00  2025-01-01 10:31:01  upstream <ip> timeout\n" +
+		"2      2025-01-01 10:05:00  2025-01-01 10:50:00  request <n> took <n>ms\n" +
+		"1      2025-01-01 10:59:00  2025-01-01 10:59:00  {\"app\":\"api\"}\n"
+
+	buffer := bytes.Buffer{}
+	printIncidentSummary(&buffer, "some query", l, spec, []string{"message"}, "json.app", nil, timeLayout{})
+	assert(t, buffer.String(), want)
+
+	buffer.Reset()
+	printIncidentSummary(&buffer, "some query", nil, spec, []string{"message"}, "", nil, timeLayout{})
+	assert(t, buffer.String(), "Query: some query\n"+
+		"Window: 2025-01-01 10:00:00 - 2025-01-01 11:00:00 (1h0m0s)\n"+
+		"Records: 0, 0 per minute\n")
+}
+
+func TestPrintPatterns(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.Local)
+
+	l := []logs.Log{
+		{Time: start.Add(2 * time.Minute), UserData: `{"message":"retry 2 of job 9f59b0b"}`},
+		{Time: start, UserData: `{"message":"started"}`},
+		{Time: start.Add(time.Minute), UserData: `{"message":"retry 1 of job 063873f"}`},
+		{Time: start.Add(3 * time.Minute), UserData: `{"log":"stopped"}`},
+	}
+	patterns := topPatterns(l, []string{"message", "log"})
+
+	buffer := bytes.Buffer{}
+	printPatterns(&buffer, patterns, 0, timeLayout{})
+	assert(t, buffer.String(), "count  first                last                 pattern\n"+
+		"2      2025-01-01 10:01:00  2025-01-01 10:02:00  retry <n> of job <hex>\n"+
+		"1      2025-01-01 10:00:00  2025-01-01 10:00:00  started\n"+
+		"1      2025-01-01 10:03:00  2025-01-01 10:03:00  stopped\n")
+
+	buffer.Reset()
+	printPatterns(&buffer, patterns, 1, timeLayout{})
+	assert(t, buffer.String(), "count  first                last                 pattern\n"+
+		"2      2025-01-01 10:01:00  2025-01-01 10:02:00  retry <n> of job <hex>\n")
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"github.com/wooyey/iclogs/internal/platform/config"
+	"github.com/wooyey/iclogs/internal/platform/logs"
+	"github.com/wooyey/iclogs/internal/platform/logs/filter"
+)
+
+// Previous intervals averaged into baseline rate
+const baselineIntervals = 10
+
+// Names of trigger expression: records count, rate per minute and baseline rate, and count of records matching filter
+var triggerNames = filter.Aggregation{Fields: []string{"count", "rate", "baseline"}, Functions: []string{"count"}}
+
+// Records of watched interval with its length and rate of previous ones
+type interval struct {
+	logs     []logs.Log
+	minutes  float64
+	baseline float64
+	aliases  config.Aliases
+}
+
+// Records per minute of interval, count itself for empty interval
+func (i interval) rate() float64 {
+	if i.minutes <= 0 {
+		return float64(len(i.logs))
+	}
+
+	return float64(len(i.logs)) / i.minutes
+}
+
+// Trigger expression of watch command, ie. `count(severity>=error) > 10 || rate > 2*baseline`
+type trigger struct {
+	expression string
+	filter     *filter.Filter
+}
+
+// Check if interval fulfils the expression
+func (t *trigger) holds(i interval) bool {
+	fields := func(name string) (any, bool) {
+		switch name {
+		case "count":
+			return float64(len(i.logs)), true
+		case "rate":
+			return i.rate(), true
+		case "baseline":
+			return i.baseline, true
+		}
+		return nil, false
+	}
+
+	count := func(_ string, where *filter.Filter) any {
+		if where == nil {
+			return float64(len(i.logs))
+		}
+		return float64(len(filterLogs(i.logs, where, i.aliases)))
+	}
+
+	return t.filter.Holds(fields, count)
+}
+
+// Parse trigger expression over count, count(<filter expression>), rate per minute and baseline rate
+func parseTrigger(expression string) (*trigger, error) {
+	f, err := triggerNames.Parse(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	return &trigger{expression: expression, filter: f}, nil
+}
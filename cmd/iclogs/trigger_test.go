@@ -0,0 +1,74 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/wooyey/iclogs/internal/platform/config"
+	"github.com/wooyey/iclogs/internal/platform/logs"
+)
+
+func TestTrigger(t *testing.T) {
+	l := []logs.Log{{Severity: "Info"}, {Severity: "Error"}, {Severity: "Critical"}, {Severity: "Warning"}, {Severity: "Info"}, {Severity: "Error", UserData: `{"status":503}`}}
+	i := interval{logs: l, minutes: 2, baseline: 1, aliases: config.Aliases{"status": "json.status"}}
+
+	testCases := []struct {
+		name       string
+		expression string
+		want       bool
+	}{
+		{name: "Count", expression: "count > 5", want: true},
+		{name: "CountEqual", expression: "count == 6", want: true},
+		{name: "CountFilter", expression: "count(severity>=error) > 2", want: true},
+		{name: "CountFilterBelow", expression: "count(severity>=error) > 3", want: false},
+		{name: "CountFilterParens", expression: `count((severity == "Info" || severity == "Warning") && id != ")") >= 3`, want: true},
+		{name: "CountAlias", expression: "count(status >= 500) == 1", want: true},
+		{name: "Rate", expression: "rate == 3", want: true},
+		{name: "Baseline", expression: "rate > 2*baseline", want: true},
+		{name: "BaselineAbove", expression: "rate > 4 * baseline", want: false},
+		{name: "Arithmetic", expression: "count - count(severity==info) / 2 == 5", want: true},
+		{name: "Negative", expression: "-count < -5", want: true},
+		{name: "Or", expression: "count(severity>=error) > 10 || rate > 2*baseline", want: true},
+		{name: "And", expression: "count(severity>=error) > 10 && rate > 2*baseline", want: false},
+		{name: "Not", expression: "!(count > 5)", want: false},
+		{name: "NotEqual", expression: "count != 6", want: false},
+		{name: "Grouping", expression: "(count > 10 || rate > 2) && baseline < 2", want: true},
+		{name: "DivisionByZero", expression: "count / 0 == 0", want: true},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := parseTrigger(tt.expression)
+			if err != nil {
+				t.Fatalf("Got an error: '%v'", err)
+			}
+			assert(t, w.holds(i), tt.want)
+			assert(t, w.expression, tt.expression)
+		})
+	}
+}
+
+func TestParseTriggerErrors(t *testing.T) {
+	testCases := []struct {
+		name       string
+		expression string
+		want       string
+	}{
+		{name: "Empty", expression: "", want: "unexpected end of expression"},
+		{name: "UnknownTerm", expression: "errors > 1", want: "unknown name 'errors' at position 0, use count, rate, baseline, count(<filter>) or number"},
+		{name: "MissingParen", expression: "(count > 1", want: "missing ')' at position 10"},
+		{name: "UnclosedCount", expression: `count(severity == ")"`, want: "missing ')' of count at position 21"},
+		{name: "BadFilter", expression: "count(severity ==) > 1", want: "cannot parse count filter: unexpected ')' at position 17"},
+		{name: "Trailing", expression: "count > 1 count", want: "unexpected 'count' at position 10"},
+		{name: "Operator", expression: "count > * 2", want: "unexpected '*' at position 8"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseTrigger(tt.expression)
+			if err == nil {
+				t.Fatal("Should get an error!")
+			}
+			assert(t, err.Error(), tt.want)
+		})
+	}
+}
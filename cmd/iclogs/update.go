@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/wooyey/iclogs/internal/platform/config"
+	"github.com/wooyey/iclogs/internal/platform/update"
+)
+
+// Check for newer release in background when enabled, returned function prints notice when the check is done already,
+// it never waits for it. Check failures are ignored, they should never stop logs search.
+func checkUpdate(c config.UpdateCheck, current string) func(w io.Writer) {
+	if !c.Enabled {
+		return func(io.Writer) {}
+	}
+
+	channel := c.Channel
+	if channel == "" {
+		channel = update.Stable
+	}
+
+	done := make(chan string, 1)
+	go func() {
+		latest, _ := update.Check(current, channel)
+		done <- latest
+	}()
+
+	return func(w io.Writer) {
+		select {
+		case latest := <-done:
+			if latest != "" {
+				fmt.Fprintf(w, "Notice: iclogs %s is available on %s channel, running %s\n", latest, channel, current)
+			}
+		default:
+		}
+	}
+}
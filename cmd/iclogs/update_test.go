@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/config"
+	"github.com/wooyey/iclogs/internal/platform/update"
+)
+
+func TestCheckUpdate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"tag_name": "v2.0.0"}]`))
+	}))
+	defer server.Close()
+	update.ReleasesURL = server.URL
+
+	dir := t.TempDir()
+	update.CachePath = func(channel string) (string, error) {
+		return filepath.Join(dir, channel+".json"), nil
+	}
+
+	testCases := []struct {
+		name   string
+		config config.UpdateCheck
+		want   string
+	}{
+		{name: "Disabled", config: config.UpdateCheck{}, want: ""},
+		{name: "Enabled", config: config.UpdateCheck{Enabled: true}, want: "Notice: iclogs v2.0.0 is available on stable channel, running v1.0.0\n"},
+		{name: "UnknownChannel", config: config.UpdateCheck{Enabled: true, Channel: "nightly"}, want: ""},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			out := bytes.Buffer{}
+			notice := checkUpdate(tt.config, "v1.0.0")
+			// Notice does not wait for the check, so it is printed only once the check is done
+			for start := time.Now(); out.Len() == 0 && tt.want != "" && time.Since(start) < time.Second; {
+				notice(&out)
+				time.Sleep(10 * time.Millisecond)
+			}
+			notice(&out)
+			assert(t, out.String(), tt.want)
+		})
+	}
+}
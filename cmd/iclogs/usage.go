@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/wooyey/iclogs/internal/platform/usage"
+)
+
+const (
+	usageDayFormat = "2006-01-02"
+	totalColumn    = "total"
+)
+
+// Ingested GB per day and tier
+type usageReport struct {
+	days  []string
+	tiers []string
+	gb    map[string]map[string]float64
+}
+
+// Sum entries by local day and tier
+func dailyUsage(entries []usage.Entry) usageReport {
+	r := usageReport{gb: map[string]map[string]float64{}}
+	tiers := map[string]bool{}
+
+	for _, e := range entries {
+		day := e.Time.Local().Format(usageDayFormat)
+		if r.gb[day] == nil {
+			r.gb[day] = map[string]float64{}
+			r.days = append(r.days, day)
+		}
+		t := e.Tier()
+		r.gb[day][t] += e.SizeGB
+		tiers[t] = true
+	}
+
+	for t := range tiers {
+		r.tiers = append(r.tiers, t)
+	}
+	sort.Strings(r.days)
+	sort.Strings(r.tiers)
+
+	return r
+}
+
+// Rows of report with header, per tier columns and total of each day, followed by totals row when asked
+func (r usageReport) rows(totals bool) [][]string {
+	header := append(append([]string{"day"}, r.tiers...), totalColumn)
+	rows := [][]string{header}
+	sums := make([]float64, len(r.tiers)+1)
+
+	for _, d := range r.days {
+		row := []string{d}
+		total := 0.0
+		for i, t := range r.tiers {
+			gb := r.gb[d][t]
+			row = append(row, formatGB(gb))
+			sums[i] += gb
+			total += gb
+		}
+		sums[len(r.tiers)] += total
+		rows = append(rows, append(row, formatGB(total)))
+	}
+
+	if totals {
+		row := []string{totalColumn}
+		for _, s := range sums {
+			row = append(row, formatGB(s))
+		}
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+func formatGB(gb float64) string {
+	return strconv.FormatFloat(gb, 'f', 3, 64)
+}
+
+// Print report as aligned table with totals, or as CSV without them
+func printDataUsage(out io.Writer, r usageReport, asCSV bool) error {
+	if asCSV {
+		w := csv.NewWriter(out)
+		w.WriteAll(r.rows(false))
+		return w.Error()
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, row := range r.rows(true) {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+
+	return w.Flush()
+}
+
+// Fetch data usage of range given as argument, last week by default, and print ingested GB per day and tier
+func runUsage(out io.Writer, args *CmdArgs, token func() (string, error)) error {
+	if args.APIKey == "" {
+		return errMissingAPIKey
+	}
+	if args.LogsURL == "" {
+		return errMissingURL
+	}
+
+	r := strings.TrimSpace(args.Query)
+	if r == "" {
+		r = usage.LastWeek
+	}
+	if err := usage.ValidRange(r); err != nil {
+		return err
+	}
+
+	entries, err := usage.Get(context.Background(), args.LogsURL, token, r)
+	if err != nil {
+		return err
+	}
+
+	return printDataUsage(out, dailyUsage(entries), args.UsageCSV)
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/usage"
+)
+
+func TestDailyUsage(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 12, 0, 0, 0, time.Local) }
+	entries := []usage.Entry{
+		{Time: day(10), SizeGB: 1, Dimensions: map[string]string{"priority": "high"}},
+		{Time: day(10), SizeGB: 0.5, Dimensions: map[string]string{"priority": "low"}},
+		{Time: day(11), SizeGB: 2, Dimensions: map[string]string{"priority": "high"}},
+		{Time: day(11), SizeGB: 0.25, Dimensions: map[string]string{"priority": "high"}},
+	}
+
+	r := dailyUsage(entries)
+
+	testCases := []struct {
+		name     string
+		csv      bool
+		expected string
+	}{
+		{
+			name: "Table",
+			expected: "day         priority_insights  store_and_search  total\n" +
+				"2025-01-10  1.000              0.500             1.500\n" +
+				"2025-01-11  2.250              0.000             2.250\n" +
+				"total       3.250              0.500             3.750\n",
+		},
+		{
+			name: "CSV",
+			csv:  true,
+			expected: "day,priority_insights,store_and_search,total\n" +
+				"2025-01-10,1.000,0.500,1.500\n" +
+				"2025-01-11,2.250,0.000,2.250\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			out := bytes.Buffer{}
+			if err := printDataUsage(&out, r, tc.csv); err != nil {
+				t.Fatalf("Got unexpected error: %v", err)
+			}
+			assert(t, out.String(), tc.expected)
+		})
+	}
+}
+
+func TestRunUsage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("range") != usage.Last30Days {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"entries":[{"timestamp":"2025-01-10T12:00:00Z","size_gb":1,"dimensions":[{"key":"priority","value":"medium"}]}]}`))
+	}))
+	defer server.Close()
+
+	token := func() (string, error) { return "Good_Token", nil }
+	args := CmdArgs{APIKey: "key", LogsURL: server.URL, Query: usage.Last30Days, UsageCSV: true}
+
+	out := bytes.Buffer{}
+	if err := runUsage(&out, &args, token); err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	day := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC).Local().Format(usageDayFormat)
+	assert(t, out.String(), "day,analyze_and_alert,total\n"+day+",1.000,1.000\n")
+
+	testCases := []struct {
+		name string
+		args CmdArgs
+	}{
+		{name: "MissingAPIKey", args: CmdArgs{LogsURL: server.URL}},
+		{name: "MissingURL", args: CmdArgs{APIKey: "key"}},
+		{name: "UnknownRange", args: CmdArgs{APIKey: "key", LogsURL: server.URL, Query: "yesterday"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := runUsage(&out, &tc.args, token); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/logs"
+	"github.com/wooyey/iclogs/internal/platform/logs/syntax"
+	"github.com/wooyey/iclogs/internal/platform/logs/tier"
+	"github.com/wooyey/iclogs/pkg/ingest"
+)
+
+const (
+	defaultVerifyTimeout = 2 * time.Minute
+	verifyPollInterval   = 2 * time.Second // Latency is measured with this precision
+	verifyTagPrefix      = "iclogs-verify-"
+)
+
+var (
+	errVerifyTimeout        = errors.New("record not found before timeout")
+	errInvalidVerifyTimeout = errors.New("verification timeout needs to be positive")
+)
+
+// Unique tag of verification record, so that only the record of this run is found
+var verifyTag = func() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return verifyTagPrefix + hex.EncodeToString(b)
+}
+
+// Ingest uniquely tagged record and poll search until it is found, printing end-to-end latency
+func runVerify(out io.Writer, args *CmdArgs, search searchFunc, token func() (string, error), now func() time.Time, interval time.Duration) error {
+	if args.APIKey == "" {
+		return errMissingAPIKey
+	}
+	if args.LogsURL == "" {
+		return errMissingURL
+	}
+	if args.VerifyTimeout <= 0 {
+		return errInvalidVerifyTimeout
+	}
+
+	severity, err := ingest.Severity(args.IngestSeverity)
+	if err != nil {
+		return err
+	}
+
+	endpoint, err := ingestEndpoint(args)
+	if err != nil {
+		return err
+	}
+
+	tag := verifyTag()
+	sent := now()
+	c := ingest.Client{Endpoint: endpoint, Token: token}
+	r := ingest.Record{Application: args.App, Subsystem: args.Subsystem, Severity: severity, Text: "iclogs pipeline verification " + tag, Time: sent}
+	if err := c.Send(context.Background(), []ingest.Record{r}); err != nil {
+		return fmt.Errorf("cannot send record to '%s': %w", endpoint, err)
+	}
+	fmt.Fprintf(out, "Record %s sent to %s at %s\n", tag, endpoint, args.Times.format(sent))
+
+	query := fmt.Sprintf("applicationname:\"%s\" AND subsystemname:\"%s\" AND \"%s\"", args.App, args.Subsystem, tag)
+	spec := logs.QuerySpec{Syntax: syntax.Lucene, Tier: tier.Archive, Limit: 1, StartDate: sent.Add(-time.Minute)}
+
+	deadline := sent.Add(args.VerifyTimeout)
+	for attempt := 1; ; attempt++ {
+		time.Sleep(interval)
+
+		spec.EndDate = now().Add(time.Minute) // Cover clock skew between this host and the service
+		l, err := search("", query, spec)
+		if err != nil {
+			return fmt.Errorf("cannot search for record: %w", err)
+		}
+		found := now()
+		if len(l.Logs) > 0 {
+			fmt.Fprintf(out, "Record found after %s (%d searches)\n", found.Sub(sent).Round(time.Millisecond), attempt)
+			return nil
+		}
+		if !found.Before(deadline) {
+			return fmt.Errorf("%w of %s (%d searches)", errVerifyTimeout, args.VerifyTimeout, attempt)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/logs"
+)
+
+func TestRunVerify(t *testing.T) {
+	var sent []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&sent)
+	}))
+	defer server.Close()
+
+	verifyTag = func() string { return verifyTagPrefix + "0011" }
+	token := func() (string, error) { return "Good_Token", nil }
+	start := time.Date(2025, 1, 11, 10, 0, 0, 0, time.Local)
+	args := CmdArgs{APIKey: "key", LogsURL: "https://logs", IngressURL: server.URL, App: "test", Subsystem: "verify", IngestSeverity: "info", VerifyTimeout: 10 * time.Second}
+
+	testCases := []struct {
+		name     string
+		foundAt  int
+		expected string
+		err      error
+	}{
+		{name: "Found", foundAt: 3, expected: "Record found after 9s (3 searches)\n"},
+		{name: "Timeout", foundAt: 100, err: errVerifyTimeout},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			clock := start
+			now := func() time.Time { return clock }
+			searches := 0
+			var queries []string
+			search := func(client, query string, spec logs.QuerySpec) (logs.Result, error) {
+				searches++
+				clock = clock.Add(3 * time.Second)
+				queries = append(queries, query)
+				if searches >= tc.foundAt {
+					return logs.Result{Logs: []logs.Log{{ID: "found"}}}, nil
+				}
+				return logs.Result{}, nil
+			}
+
+			out := bytes.Buffer{}
+			err := runVerify(&out, &args, search, token, now, time.Millisecond)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("Expected error %v, got: %v", tc.err, err)
+			}
+
+			assert(t, sent[0]["text"], any("iclogs pipeline verification iclogs-verify-0011"))
+			assert(t, queries[0], `applicationname:"test" AND subsystemname:"verify" AND "iclogs-verify-0011"`)
+			if tc.err == nil {
+				assert(t, out.String(), "Record iclogs-verify-0011 sent to "+server.URL+" at 2025-01-11 10:00:00\n"+tc.expected)
+			} else {
+				assert(t, searches, 4)
+			}
+		})
+	}
+
+	args.VerifyTimeout = 0
+	assertError(t, runVerify(&bytes.Buffer{}, &args, nil, token, time.Now, time.Millisecond), errInvalidVerifyTimeout)
+}
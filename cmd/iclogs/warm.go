@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/endpoint"
+)
+
+// Commands running queries repeatedly, their connections are warmed up before the first query
+var warmCommands = []string{commandDash, commandWatch, commandServe, commandSlack, commandMCP}
+
+// Keep idle connections of transport open between refreshes and establish them to endpoints in background,
+// so the first query does not wait for TLS handshakes, ie. through slow corporate proxy
+func warmUp(t *http.Transport, refresh time.Duration, endpoints ...string) {
+	if idle := 2 * refresh; idle > t.IdleConnTimeout {
+		t.IdleConnTimeout = idle
+	}
+
+	for _, e := range endpoints {
+		if e == "" {
+			continue
+		}
+		go func() {
+			start := time.Now()
+			err := endpoint.Warm(context.Background(), t, e)
+			slog.Debug("Connection warmed up", "endpoint", e, "took", time.Since(start), "error", err)
+		}()
+	}
+}
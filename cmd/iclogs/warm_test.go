@@ -0,0 +1,36 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWarmUp(t *testing.T) {
+	var conns atomic.Int32
+	srv := httptest.NewUnstartedServer(http.NotFoundHandler())
+	srv.Config.ConnState = func(c net.Conn, s http.ConnState) {
+		if s == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	srv.StartTLS()
+	defer srv.Close()
+
+	tr := srv.Client().Transport.(*http.Transport)
+	tr.IdleConnTimeout = 90 * time.Second
+
+	warmUp(tr, 5*time.Minute, srv.URL, "")
+	assert(t, tr.IdleConnTimeout, 10*time.Minute)
+
+	for i := 0; i < 100 && conns.Load() == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert(t, conns.Load(), int32(1))
+
+	warmUp(tr, time.Second)
+	assert(t, tr.IdleConnTimeout, 10*time.Minute)
+}
@@ -0,0 +1,266 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/wooyey/iclogs/internal/platform/cache"
+	"github.com/wooyey/iclogs/internal/platform/config"
+	"github.com/wooyey/iclogs/internal/platform/logs"
+	"github.com/wooyey/iclogs/internal/platform/notify"
+)
+
+const watchKeyPrefix = "iclogs-"
+
+// Watches records count per interval, notifying when it goes above threshold or trigger expression holds and when it clears
+type watcher struct {
+	search      searchFunc
+	query       string
+	threshold   int
+	when        *trigger
+	aliases     config.Aliases
+	notifiers   []notify.Notifier
+	source      string
+	silences    silences
+	silenceFile string
+	layout      timeLayout
+
+	state string // Path of persisted state, none when empty
+
+	triggered    bool
+	rates        []float64
+	fileSilences silences
+}
+
+// State of watcher persisted between its runs, so restart neither alerts again nor forgets baseline
+type watchState struct {
+	Triggered bool      `json:"triggered"`
+	Rates     []float64 `json:"rates,omitempty"`
+}
+
+// watchStatePath returns default location of persisted state of watched query in user cache directory
+var watchStatePath = func(key string) (string, error) {
+	dir, err := cache.Dir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "watch-"+strings.TrimPrefix(key, watchKeyPrefix)+".json"), nil
+}
+
+func (w *watcher) load() error {
+	data, ok, err := cache.Read(w.state)
+	if err != nil {
+		return fmt.Errorf("cannot read watch state: %w", err)
+	}
+	if !ok {
+		return nil
+	}
+
+	var s watchState
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("cannot parse watch state: %w", err)
+	}
+	w.triggered, w.rates = s.Triggered, s.Rates
+
+	return nil
+}
+
+func (w *watcher) save() error {
+	data, err := json.Marshal(watchState{Triggered: w.triggered, Rates: w.rates})
+	if err != nil {
+		return fmt.Errorf("cannot encode watch state: %w", err)
+	}
+
+	if err := cache.Write(w.state, data); err != nil {
+		return fmt.Errorf("cannot write watch state: %w", err)
+	}
+
+	return nil
+}
+
+// Key identifying condition of watched query in alerting services
+func (w *watcher) key() string {
+	sum := sha256.Sum256([]byte(w.query))
+	return watchKeyPrefix + hex.EncodeToString(sum[:8])
+}
+
+func (w *watcher) notify(triggered bool, e notify.Event) {
+	for _, n := range w.notifiers {
+		var err error
+		if triggered {
+			err = n.Trigger(e)
+		} else {
+			err = n.Resolve(e)
+		}
+		if err != nil {
+			slog.Error("Cannot send notification", "error", err)
+		}
+	}
+}
+
+// Check one interval and print its summary line
+func (w *watcher) check(out io.Writer, spec logs.QuerySpec) error {
+	l, err := w.search("", w.query, spec)
+	if err != nil {
+		return err
+	}
+
+	count := len(l.Logs)
+	triggered := count > w.threshold
+	silenced := w.silenced(spec.StartDate, spec.EndDate)
+
+	if w.when != nil {
+		i := interval{logs: l.Logs, minutes: spec.EndDate.Sub(spec.StartDate).Minutes(), aliases: w.aliases}
+		i.baseline = w.baseline(i.rate())
+		triggered = w.when.holds(i)
+		// Noise of silenced intervals stays out of baseline
+		if !silenced {
+			w.rates = append(w.rates, i.rate())
+		}
+		if len(w.rates) > baselineIntervals {
+			w.rates = w.rates[len(w.rates)-baselineIntervals:]
+		}
+	}
+
+	state := ""
+	if triggered != w.triggered {
+		state = " RESOLVED"
+		if triggered {
+			state = " TRIGGERED"
+		}
+	}
+	if silenced {
+		state += " SILENCED"
+	}
+	fmt.Fprintf(out, "%s count: %d%s%s\n", w.layout.format(spec.EndDate), count, severityBreakdown(l.Logs), state)
+
+	if triggered != w.triggered && !silenced {
+		condition := fmt.Sprintf("threshold %d", w.threshold)
+		if w.when != nil {
+			condition = "when " + w.when.expression
+		}
+		w.triggered = triggered
+		w.notify(triggered, notify.Event{
+			Key:     w.key(),
+			Summary: fmt.Sprintf("iclogs watch: %d records (%s) between %s and %s", count, condition, w.layout.format(spec.StartDate), w.layout.format(spec.EndDate)),
+			Details: w.query,
+			Source:  w.source,
+		})
+	}
+
+	if w.state != "" {
+		if err := w.save(); err != nil {
+			slog.Warn("Cannot save watch state", "error", err)
+		}
+	}
+
+	return nil
+}
+
+// Check if interval is silenced, silence file is read again each time so it can be changed while watching
+func (w *watcher) silenced(start, end time.Time) bool {
+	if w.silenceFile != "" {
+		s, err := loadSilences(w.silenceFile, time.Now())
+		if err != nil {
+			slog.Warn("Cannot read silence file, keeping previous silences", "error", err)
+		} else {
+			w.fileSilences = s
+		}
+	}
+
+	return w.silences.covers(start, end) || w.fileSilences.covers(start, end)
+}
+
+// Average rate of previous intervals, current rate until there are some
+func (w *watcher) baseline(rate float64) float64 {
+	if len(w.rates) == 0 {
+		return rate
+	}
+
+	sum := 0.0
+	for _, r := range w.rates {
+		sum += r
+	}
+
+	return sum / float64(len(w.rates))
+}
+
+// Compact records count per severity, ie. ` E:3 W:12 I:240`, empty when there are no records.
+// Severities follow from the most severe, unknown ones in alphabetical order.
+func severityBreakdown(l []logs.Log) string {
+	counts := map[string]int{}
+	for _, r := range l {
+		counts[r.Severity]++
+	}
+
+	names := make([]string, 0, len(counts))
+	for s := range counts {
+		names = append(names, s)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if x, y := severityIndex(names[i]), severityIndex(names[j]); x != y {
+			return x < y
+		}
+		return names[i] < names[j]
+	})
+
+	b := strings.Builder{}
+	for _, s := range names {
+		if c := counts[s]; c > 0 {
+			fmt.Fprintf(&b, " %s:%d", severityLetter(s), c)
+		}
+	}
+
+	return b.String()
+}
+
+// First letter of severity, ? for records without one
+func severityLetter(severity string) string {
+	r, _ := utf8.DecodeRuneInString(severity)
+	if r == utf8.RuneError {
+		return "?"
+	}
+
+	return string(unicode.ToUpper(r))
+}
+
+// Check consecutive intervals until interrupted, failed checks are reported and skipped
+func runWatch(w *watcher, interval time.Duration, spec logs.QuerySpec) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	spec.EndDate = time.Now()
+	spec.StartDate = spec.EndDate.Add(-interval)
+
+	for {
+		if err := w.check(os.Stdout, spec); err != nil {
+			slog.Warn("Cannot check interval", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		spec.StartDate = spec.EndDate
+		spec.EndDate = time.Now()
+	}
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/logs"
+	"github.com/wooyey/iclogs/internal/platform/notify"
+)
+
+type notification struct {
+	triggered bool
+	event     notify.Event
+}
+
+type testNotifier struct {
+	sent *[]notification
+}
+
+func (n testNotifier) Trigger(e notify.Event) error {
+	*n.sent = append(*n.sent, notification{true, e})
+	return nil
+}
+
+func (n testNotifier) Resolve(e notify.Event) error {
+	*n.sent = append(*n.sent, notification{false, e})
+	return nil
+}
+
+func TestWatcherTrigger(t *testing.T) {
+	counts := []int{2, 2, 6, 3, 2}
+	var sent []notification
+	n := 0
+
+	when, err := parseTrigger("count(severity>=error) > 2 || rate > 2*baseline")
+	if err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+
+	w := &watcher{
+		search: func(client, query string, spec logs.QuerySpec) (logs.Result, error) {
+			l := make([]logs.Log, counts[n])
+			for i := range l {
+				l[i].Severity = "Info"
+			}
+			if n == 3 {
+				l[0].Severity, l[1].Severity, l[2].Severity = "Error", "Critical", "Error"
+			}
+			n++
+			return logs.Result{Logs: l}, nil
+		},
+		query:     "applicationname:payments",
+		when:      when,
+		notifiers: []notify.Notifier{testNotifier{&sent}},
+	}
+
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.Local)
+	out := bytes.Buffer{}
+	for i := range counts {
+		spec := logs.QuerySpec{StartDate: start.Add(time.Duration(i) * time.Minute), EndDate: start.Add(time.Duration(i+1) * time.Minute)}
+		if err := w.check(&out, spec); err != nil {
+			t.Fatalf("Got an error: '%v'", err)
+		}
+	}
+
+	assert(t, out.String(), "2025-01-01 10:01:00 count: 2 I:2\n"+
+		"2025-01-01 10:02:00 count: 2 I:2\n"+
+		"2025-01-01 10:03:00 count: 6 I:6 TRIGGERED\n"+
+		"2025-01-01 10:04:00 count: 3 C:1 E:2\n"+
+		"2025-01-01 10:05:00 count: 2 I:2 RESOLVED\n")
+
+	assert(t, len(sent), 2)
+	assert(t, sent[0].event.Summary, "iclogs watch: 6 records (when count(severity>=error) > 2 || rate > 2*baseline) between 2025-01-01 10:02:00 and 2025-01-01 10:03:00")
+	assertDeepEqual(t, w.rates, []float64{2, 2, 6, 3, 2})
+}
+
+func TestWatcherSilence(t *testing.T) {
+	counts := []int{5, 5, 5, 1}
+	var sent []notification
+	n := 0
+
+	silence, err := parseSilence("10:00-10:02", time.Now())
+	if err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+
+	w := &watcher{
+		search: func(client, query string, spec logs.QuerySpec) (logs.Result, error) {
+			l := make([]logs.Log, counts[n])
+			n++
+			return logs.Result{Logs: l}, nil
+		},
+		query:     "severity:error",
+		threshold: 2,
+		silences:  silences{silence},
+		notifiers: []notify.Notifier{testNotifier{&sent}},
+	}
+
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.Local)
+	out := bytes.Buffer{}
+	for i := range counts {
+		spec := logs.QuerySpec{StartDate: start.Add(time.Duration(i) * time.Minute), EndDate: start.Add(time.Duration(i+1) * time.Minute)}
+		if err := w.check(&out, spec); err != nil {
+			t.Fatalf("Got an error: '%v'", err)
+		}
+	}
+
+	assert(t, out.String(), "2025-01-01 10:01:00 count: 5 ?:5 TRIGGERED SILENCED\n"+
+		"2025-01-01 10:02:00 count: 5 ?:5 TRIGGERED SILENCED\n"+
+		"2025-01-01 10:03:00 count: 5 ?:5 TRIGGERED\n"+
+		"2025-01-01 10:04:00 count: 1 ?:1 RESOLVED\n")
+
+	assert(t, len(sent), 2)
+	assert(t, sent[0].event.Summary, "iclogs watch: 5 records (threshold 2) between 2025-01-01 10:02:00 and 2025-01-01 10:03:00")
+}
+
+func TestWatcherState(t *testing.T) {
+	var sent []notification
+	count := 5
+
+	newWatcher := func() *watcher {
+		when, err := parseTrigger("count > 2 || rate > 2*baseline")
+		if err != nil {
+			t.Fatalf("Got an error: '%v'", err)
+		}
+		return &watcher{
+			search: func(client, query string, spec logs.QuerySpec) (logs.Result, error) {
+				return logs.Result{Logs: make([]logs.Log, count)}, nil
+			},
+			query:     "severity:error",
+			when:      when,
+			notifiers: []notify.Notifier{testNotifier{&sent}},
+			state:     filepath.Join(t.TempDir(), "iclogs", "watch.json"),
+		}
+	}
+
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.Local)
+	spec := logs.QuerySpec{StartDate: start, EndDate: start.Add(time.Minute)}
+
+	w := newWatcher()
+	if err := w.load(); err != nil {
+		t.Fatalf("Got an error for missing state: '%v'", err)
+	}
+	if err := w.check(io.Discard, spec); err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+	assert(t, len(sent), 1)
+
+	restarted := newWatcher()
+	restarted.state = w.state
+	if err := restarted.load(); err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+	assert(t, restarted.triggered, true)
+	assertDeepEqual(t, restarted.rates, []float64{5})
+
+	if err := restarted.check(io.Discard, spec); err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+	assert(t, len(sent), 1)
+
+	count = 1
+	if err := restarted.check(io.Discard, spec); err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+	assert(t, len(sent), 2)
+	assert(t, sent[1].triggered, false)
+
+	if err := os.WriteFile(w.state, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.load(); err == nil {
+		t.Error("Should get an error for corrupted state!")
+	}
+}
+
+func TestWatchStatePath(t *testing.T) {
+	w := &watcher{query: "severity:error"}
+	path, err := watchStatePath(w.key())
+	if err != nil {
+		t.Skipf("No user cache directory: %v", err)
+	}
+	assert(t, filepath.Base(path), "watch-"+strings.TrimPrefix(w.key(), watchKeyPrefix)+".json")
+}
+
+func TestSeverityBreakdown(t *testing.T) {
+	l := []logs.Log{{Severity: "Info"}, {Severity: "Fatal"}, {Severity: "Error"}, {Severity: "Info"}, {Severity: ""}, {Severity: "audit"}}
+	assert(t, severityBreakdown(l), " E:1 I:2 ?:1 F:1 A:1")
+	assert(t, severityBreakdown(nil), "")
+}
+
+func TestWatcherCheck(t *testing.T) {
+	counts := []int{1, 3, 5, 2}
+	var sent []notification
+	n := 0
+
+	w := &watcher{
+		search: func(client, query string, spec logs.QuerySpec) (logs.Result, error) {
+			l := make([]logs.Log, counts[n])
+			for i := range l {
+				l[i].Severity = []string{"Info", "Error", "Info", "Warning", "Critical"}[i]
+			}
+			n++
+			return logs.Result{Logs: l}, nil
+		},
+		query:     "severity:error",
+		threshold: 2,
+		notifiers: []notify.Notifier{testNotifier{&sent}},
+		source:    "host",
+	}
+
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.Local)
+	out := bytes.Buffer{}
+	for i := range counts {
+		spec := logs.QuerySpec{StartDate: start.Add(time.Duration(i) * time.Minute), EndDate: start.Add(time.Duration(i+1) * time.Minute)}
+		if err := w.check(&out, spec); err != nil {
+			t.Fatalf("Got an error: '%v'", err)
+		}
+	}
+
+	assert(t, out.String(), "2025-01-01 10:01:00 count: 1 I:1\n"+
+		"2025-01-01 10:02:00 count: 3 E:1 I:2 TRIGGERED\n"+
+		"2025-01-01 10:03:00 count: 5 C:1 E:1 W:1 I:2\n"+
+		"2025-01-01 10:04:00 count: 2 E:1 I:1 RESOLVED\n")
+
+	assert(t, len(sent), 2)
+	assert(t, sent[0].triggered, true)
+	assert(t, sent[0].event.Summary, "iclogs watch: 3 records (threshold 2) between 2025-01-01 10:01:00 and 2025-01-01 10:02:00")
+	assert(t, sent[1].triggered, false)
+	assert(t, sent[0].event.Key, sent[1].event.Key)
+	assert(t, sent[0].event.Key, w.key())
+
+	w.search = func(client, query string, spec logs.QuerySpec) (logs.Result, error) {
+		return logs.Result{}, errors.New("upstream failure")
+	}
+	if err := w.check(&out, logs.QuerySpec{}); err == nil {
+		t.Error("Should get an error for failed search!")
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/webhooks"
+)
+
+// Webhooks command actions
+const (
+	webhooksList = "list"
+	webhooksTest = "test"
+)
+
+var errWebhooksAction = errors.New("you need to provide webhooks action (list or test <id>)")
+
+// Notification sent by test action, shaped like alert so receivers templating alert fields show something
+type testNotification struct {
+	AlertName   string `json:"alert_name"`
+	Description string `json:"description"`
+	Severity    string `json:"severity"`
+	Timestamp   string `json:"timestamp"`
+}
+
+// Print outbound integrations with their type and URL
+func printWebhooks(out io.Writer, w []webhooks.Webhook) error {
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "name\tid\ttype\turl")
+
+	for _, webhook := range w {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", webhook.Name, webhook.ID, webhook.Type, webhook.URL)
+	}
+
+	return tw.Flush()
+}
+
+// List outbound integrations of the instance or send test notification to one of them
+func runWebhooks(out io.Writer, args *CmdArgs, token func() (string, error), now time.Time) error {
+	action, id, _ := strings.Cut(strings.TrimSpace(args.Query), " ")
+	id = strings.TrimSpace(id)
+	if !(action == webhooksList && id == "") && !(action == webhooksTest && id != "") {
+		return errWebhooksAction
+	}
+	if args.APIKey == "" {
+		return errMissingAPIKey
+	}
+	if args.LogsURL == "" {
+		return errMissingURL
+	}
+
+	if action == webhooksList {
+		w, err := webhooks.List(context.Background(), args.LogsURL, token)
+		if err != nil {
+			return err
+		}
+		return printWebhooks(out, w)
+	}
+
+	w, err := webhooks.Get(context.Background(), args.LogsURL, token, id)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(testNotification{
+		AlertName:   "iclogs webhook test",
+		Description: fmt.Sprintf("Test notification of '%s' integration sent by iclogs", w.Name),
+		Severity:    "info",
+		Timestamp:   now.UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return err
+	}
+
+	status, err := webhooks.Fire(context.Background(), w, payload)
+	if err != nil {
+		return fmt.Errorf("cannot fire '%s': %w", w.Name, err)
+	}
+	fmt.Fprintf(out, "Test notification sent to '%s' (%s), receiver answered with HTTP %d\n", w.Name, w.URL, status)
+
+	return nil
+}
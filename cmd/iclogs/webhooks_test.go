@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRunWebhooks(t *testing.T) {
+	var fired testNotification
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&fired)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer receiver.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hook := `{"id":"w1","name":"chat","type":"generic","url":"` + receiver.URL + `"}`
+		switch r.URL.Path {
+		case "/v1/outgoing_webhooks":
+			w.Write([]byte(`{"outgoing_webhooks":[` + hook + `]}`))
+		case "/v1/outgoing_webhooks/w1":
+			w.Write([]byte(hook))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	token := func() (string, error) { return "Good_Token", nil }
+	now := time.Date(2025, 1, 11, 10, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		query    string
+		expected string
+	}{
+		{
+			name:  "List",
+			query: "list",
+			expected: "name  id  type     url\n" +
+				"chat  w1  generic  " + receiver.URL + "\n",
+		},
+		{
+			name:     "Test",
+			query:    "test w1",
+			expected: "Test notification sent to 'chat' (" + receiver.URL + "), receiver answered with HTTP 202\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			args := CmdArgs{APIKey: "key", LogsURL: server.URL, Query: tc.query}
+			out := bytes.Buffer{}
+			if err := runWebhooks(&out, &args, token, now); err != nil {
+				t.Fatalf("Got unexpected error: %v", err)
+			}
+			assert(t, out.String(), tc.expected)
+		})
+	}
+	assert(t, fired.AlertName, "iclogs webhook test")
+	assert(t, fired.Timestamp, "2025-01-11T10:00:00Z")
+
+	for _, q := range []string{"", "test", "list w1", "fire w1"} {
+		args := CmdArgs{APIKey: "key", LogsURL: server.URL, Query: q}
+		assertError(t, runWebhooks(&bytes.Buffer{}, &args, token, now), errWebhooksAction)
+	}
+
+	args := CmdArgs{APIKey: "key", LogsURL: server.URL, Query: "test missing"}
+	if err := runWebhooks(&bytes.Buffer{}, &args, token, now); err == nil {
+		t.Error("Expected error of missing webhook")
+	}
+}
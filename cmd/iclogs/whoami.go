@@ -0,0 +1,67 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/auth"
+)
+
+// Auth command actions
+const authWhoami = "whoami"
+
+var errAuthAction = errors.New("you need to provide auth action (whoami)")
+
+// Obtain IAM token for the API key and print whom it identifies
+func runAuth(out io.Writer, args *CmdArgs) error {
+	if strings.TrimSpace(args.Query) != authWhoami {
+		return errAuthAction
+	}
+
+	if args.APIKey == "" {
+		return errMissingAPIKey
+	}
+
+	token, err := auth.GetToken(args.AuthURL, args.APIKey)
+	if err != nil {
+		return fmt.Errorf("cannot get token from '%s': %w", args.AuthURL, err)
+	}
+
+	c, err := auth.ParseClaims(token.Value)
+	if err != nil {
+		return err
+	}
+
+	printClaims(out, c)
+
+	return nil
+}
+
+func printClaims(out io.Writer, c auth.Claims) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(w, "Subject:\t%s\n", subjectOf(c))
+	if c.Name != "" {
+		fmt.Fprintf(w, "Name:\t%s\n", c.Name)
+	}
+	fmt.Fprintf(w, "IAM ID:\t%s\n", c.IAMID)
+	fmt.Fprintf(w, "Account:\t%s\n", c.Account.ID)
+	fmt.Fprintf(w, "Issued:\t%s\n", time.Unix(c.IssuedAt, 0).UTC().Format(time.RFC3339))
+	fmt.Fprintf(w, "Expires:\t%s\n", time.Unix(c.Expiry, 0).UTC().Format(time.RFC3339))
+	fmt.Fprintf(w, "Scopes:\t%s\n", strings.Join(c.Scopes(), ", "))
+
+	w.Flush()
+}
+
+// Subject of token claims with its type, if any
+func subjectOf(c auth.Claims) string {
+	if c.SubjectType == "" {
+		return c.Subject
+	}
+
+	return c.Subject + " (" + c.SubjectType + ")"
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRunAuth(t *testing.T) {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"iam_id":"iam-ServiceId-123","sub":"ServiceId-123","sub_type":"ServiceId",` +
+		`"account":{"bss":"abc123"},"iat":1735155510,"exp":1735159110,"scope":"ibm openid"}`))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"access_token":"header.%s.signature","expires_in":3600}`, payload)
+	}))
+	defer srv.Close()
+
+	out := &bytes.Buffer{}
+	args := &CmdArgs{Query: "whoami", AuthURL: srv.URL, APIKey: "key"}
+	if err := runAuth(out, args); err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+
+	want := `Subject:  ServiceId-123 (ServiceId)
+IAM ID:   iam-ServiceId-123
+Account:  abc123
+Issued:   2024-12-25T19:38:30Z
+Expires:  2024-12-25T20:38:30Z
+Scopes:   ibm, openid
+`
+	assert(t, out.String(), want)
+
+	assertError(t, runAuth(out, &CmdArgs{Query: "login", AuthURL: srv.URL, APIKey: "key"}), errAuthAction)
+	assertError(t, runAuth(out, &CmdArgs{Query: "whoami", AuthURL: srv.URL}), errMissingAPIKey)
+}
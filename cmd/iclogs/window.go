@@ -0,0 +1,289 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/i18n"
+	"github.com/wooyey/iclogs/internal/platform/logs"
+	"github.com/wooyey/iclogs/internal/platform/secrets"
+)
+
+const (
+	windowSeparator = ".."
+	windowLabel     = "window" // Label of records found in one of multiple windows
+	defaultDays     = 7
+)
+
+// Time of day formats of window ends, taken as today
+var timeOfDayFormats = []string{"15:04", "15:04:05"}
+
+// Time window of query
+type timeWindow struct {
+	start time.Time
+	end   time.Time
+}
+
+func (w timeWindow) String() string {
+	return w.start.Format(timeStampFormat) + windowSeparator + w.end.Format(timeStampFormat)
+}
+
+// Time windows from repeated option, each setting start and end time
+type windows []timeWindow
+
+func (w *windows) String() string {
+	l := make([]string, len(*w))
+	for i, v := range *w {
+		l[i] = v.String()
+	}
+
+	return strings.Join(l, ", ")
+}
+
+func (w *windows) Set(value string) error {
+	start, end, err := parseWindow(value, time.Now())
+	if err != nil {
+		return err
+	}
+
+	*w = append(*w, timeWindow{start: start, end: end})
+
+	return nil
+}
+
+// Time range covering all windows, sorting them by start
+func (w windows) span() (time.Time, time.Time) {
+	sort.Slice(w, func(i, j int) bool { return w[i].start.Before(w[j].start) })
+
+	start, end := w[0].start, w[0].end
+	for _, v := range w[1:] {
+		if v.end.After(end) {
+			end = v.end
+		}
+	}
+
+	return start, end
+}
+
+// Time searched by all windows, without gaps between them
+func (w windows) duration() time.Duration {
+	var d time.Duration
+	for _, v := range w {
+		d += v.end.Sub(v.start)
+	}
+
+	return d
+}
+
+// Parse `from..to` window, ends are time options or times of day, end before start with times of day goes to next day
+func parseWindow(value string, now time.Time) (time.Time, time.Time, error) {
+	from, to, ok := strings.Cut(value, windowSeparator)
+	if !ok {
+		return time.Time{}, time.Time{}, fmt.Errorf("window needs to be in from%sto format, got '%s'", windowSeparator, value)
+	}
+
+	start, startOfDay, err := parseWindowTime(from, now)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	end, endOfDay, err := parseWindowTime(to, now)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	if startOfDay && endOfDay && end.Before(start) {
+		end = end.AddDate(0, 0, 1)
+	}
+
+	if !end.After(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("window end needs to be after its start, got '%s'", value)
+	}
+
+	return start, end, nil
+}
+
+// Parse window end, reporting whether it is time of day
+func parseWindowTime(value string, now time.Time) (time.Time, bool, error) {
+	value = strings.TrimSpace(value)
+
+	for _, f := range timeOfDayFormats {
+		if t, err := time.ParseInLocation(f, value, time.Local); err == nil {
+			y, m, d := now.In(time.Local).Date()
+			return time.Date(y, m, d, t.Hour(), t.Minute(), t.Second(), 0, time.Local), true, nil
+		}
+	}
+
+	t, err := parseTimeAt(value, now)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid window time '%s': %w", value, err)
+	}
+
+	return t, false, nil
+}
+
+// Windows of the same time of day on each of last days, the latest one is the last window which already started
+func dailyWindows(value string, days int, now time.Time) (windows, error) {
+	if days <= 0 {
+		return nil, fmt.Errorf("number of days needs to be positive, got %d", days)
+	}
+
+	start, end, err := parseWindow(value, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if start.After(now) {
+		start, end = start.AddDate(0, 0, -1), end.AddDate(0, 0, -1)
+	}
+
+	w := make(windows, days)
+	for i := range w {
+		shift := i - days + 1
+		w[i] = timeWindow{start: start.AddDate(0, 0, shift), end: end.AddDate(0, 0, shift)}
+	}
+
+	return w, nil
+}
+
+// Search window of time options:
+//   - no --from and --to: range before now
+//   - --from only: until now, or range after it when range was given
+//   - --to only: range before it
+//   - --from and --to: between them, range is ignored
+func searchWindow(from, to time.Time, timeRange time.Duration, ranged bool, now time.Time) (time.Time, time.Time, error) {
+	if timeRange < 0 {
+		return time.Time{}, time.Time{}, errNegativeRange
+	}
+
+	start, end := from, to
+	switch {
+	case start.IsZero() && end.IsZero():
+		end = now
+		start = end.Add(-timeRange)
+	case start.IsZero():
+		start = end.Add(-timeRange)
+	case end.IsZero() && ranged:
+		end = start.Add(timeRange)
+	case end.IsZero():
+		end = now
+	}
+
+	if !start.Before(end) {
+		return time.Time{}, time.Time{}, errInvertedRange
+	}
+
+	return start, end, nil
+}
+
+// Snap window to interval boundaries, start down and end up, so it covers given window
+func alignWindow(start, end time.Time, interval time.Duration) (time.Time, time.Time) {
+	if interval <= 0 {
+		return start, end
+	}
+
+	if aligned := end.Truncate(interval); !aligned.Equal(end) {
+		end = aligned.Add(interval)
+	}
+
+	return start.Truncate(interval), end
+}
+
+type queryFunc func(client, query string, spec logs.QuerySpec) (logs.Result, error)
+
+// Query each window separately, labeling records with their window. Results are joined in windows order.
+// When user stops fetching, records received so far are returned with errStopped.
+func queryWindows(query queryFunc, w windows) queryFunc {
+	return func(client, q string, spec logs.QuerySpec) (logs.Result, error) {
+		var all logs.Result
+
+		for i, v := range w {
+			spec.StartDate, spec.EndDate = v.start, v.end
+
+			l, err := query(client, q, spec)
+			if errors.Is(err, errStopped) {
+				labelWindow(l.Logs, v)
+				all.Logs = append(all.Logs, l.Logs...)
+				all.Timings = addTimings(all.Timings, l.Timings)
+				return all, err
+			}
+			if errors.Is(err, errDeadline) && (i > 0 || len(l.Logs) > 0) {
+				labelWindow(l.Logs, v)
+				all.Logs = append(all.Logs, l.Logs...)
+				all.Warnings = append(all.Warnings, partialWarning(i, len(w)))
+				return all, nil
+			}
+			if err != nil {
+				return logs.Result{}, fmt.Errorf("window %s: %w", v, err)
+			}
+
+			labelWindow(l.Logs, v)
+			all.Logs = append(all.Logs, l.Logs...)
+			all.Warnings = append(all.Warnings, l.Warnings...)
+			all.Timings = addTimings(all.Timings, l.Timings)
+		}
+
+		return all, nil
+	}
+}
+
+// Stream each window separately like queryWindows
+func streamWindows(stream streamFunc, w windows) streamFunc {
+	return func(client, q string, spec logs.QuerySpec, fn func([]logs.Log) error) (logs.Result, []secrets.Finding, error) {
+		var (
+			all   logs.Result
+			found []secrets.Finding
+		)
+
+		for i, v := range w {
+			spec.StartDate, spec.EndDate = v.start, v.end
+
+			streamed := 0
+			l, f, err := stream(client, q, spec, func(b []logs.Log) error {
+				streamed += len(b)
+				labelWindow(b, v)
+				return fn(b)
+			})
+			if errors.Is(err, errStopped) {
+				return all, secrets.Merge(found, f), err
+			}
+			if errors.Is(err, errDeadline) && (i > 0 || streamed > 0) {
+				all.Warnings = append(all.Warnings, partialWarning(i, len(w)))
+				return all, secrets.Merge(found, f), nil
+			}
+			if err != nil {
+				return logs.Result{}, nil, fmt.Errorf("window %s: %w", v, err)
+			}
+
+			found = secrets.Merge(found, f)
+			all.Warnings = append(all.Warnings, l.Warnings...)
+			all.Timings = addTimings(all.Timings, l.Timings)
+		}
+
+		return all, found, nil
+	}
+}
+
+// Warning of results cut by deadline, with records of the window in progress received so far
+func partialWarning(done, total int) string {
+	return i18n.Sprintf("deadline exceeded after %d of %d windows, results are partial", done, total)
+}
+
+// Warning of single query results cut by deadline
+func deadlineWarning() string {
+	return i18n.T("deadline exceeded, results are partial")
+}
+
+func labelWindow(l []logs.Log, w timeWindow) {
+	label := logs.KeyValue{Key: windowLabel, Value: w.String()}
+	for i := range l {
+		l[i].Labels = append(l[i].Labels[:len(l[i].Labels):len(l[i].Labels)], label)
+	}
+}
+
+func addTimings(a, b logs.Timings) logs.Timings {
+	return logs.Timings{Token: a.Token + b.Token, FirstByte: a.FirstByte + b.FirstByte, Request: a.Request + b.Request, Parse: a.Parse + b.Parse}
+}
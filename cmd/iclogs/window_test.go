@@ -0,0 +1,226 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/logs"
+)
+
+func TestParseWindow(t *testing.T) {
+	now := time.Date(2025, 1, 11, 18, 30, 0, 0, time.Local)
+	day := func(d, h, m int) time.Time {
+		return time.Date(2025, 1, d, h, m, 0, 0, time.Local)
+	}
+
+	testCases := []struct {
+		name  string
+		input string
+		start time.Time
+		end   time.Time
+		err   bool
+	}{
+		{name: "TimesOfDay", input: "14:00..14:15", start: day(11, 14, 0), end: day(11, 14, 15)},
+		{name: "OverMidnight", input: "23:50..00:10", start: day(11, 23, 50), end: day(12, 0, 10)},
+		{name: "Timestamps", input: "2025-01-10T14:00..2025-01-10T14:20", start: day(10, 14, 0), end: day(10, 14, 20)},
+		{name: "Mixed", input: "2025-01-10T14:00..09:00", start: day(10, 14, 0), end: day(11, 9, 0)},
+		{name: "NoSeparator", input: "14:00", err: true},
+		{name: "EndBeforeStart", input: "2025-01-10T14:00..2025-01-10T13:00", err: true},
+		{name: "InvalidTime", input: "noon..14:00", err: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			start, end, err := parseWindow(tc.input, now)
+			if (err != nil) != tc.err {
+				t.Fatalf("Got error: %v, want error: %v", err, tc.err)
+			}
+			assert(t, start.Equal(tc.start), true)
+			assert(t, end.Equal(tc.end), true)
+		})
+	}
+}
+
+func TestWindowsFlag(t *testing.T) {
+	w := windows{}
+
+	for _, v := range []string{"2025-01-11T14:00..2025-01-11T14:20", "2025-01-10T14:00..2025-01-10T14:30"} {
+		if err := w.Set(v); err != nil {
+			t.Fatalf("Got unexpected error: %v", err)
+		}
+	}
+
+	start, end := w.span()
+	assert(t, start.Equal(time.Date(2025, 1, 10, 14, 0, 0, 0, time.Local)), true)
+	assert(t, end.Equal(time.Date(2025, 1, 11, 14, 20, 0, 0, time.Local)), true)
+	assert(t, w[0].start.Equal(start), true)
+}
+
+func TestQueryWindows(t *testing.T) {
+	day := func(d int) timeWindow {
+		return timeWindow{start: time.Date(2025, 1, d, 14, 0, 0, 0, time.UTC), end: time.Date(2025, 1, d, 14, 10, 0, 0, time.UTC)}
+	}
+	w := windows{day(10), day(11)}
+
+	var specs []logs.QuerySpec
+	query := func(client, q string, spec logs.QuerySpec) (logs.Result, error) {
+		specs = append(specs, spec)
+		shared := []logs.KeyValue{{Key: "applicationname", Value: "web"}}
+		return logs.Result{
+			Logs:     []logs.Log{{ID: spec.StartDate.Format("02"), Labels: shared}, {Labels: shared}},
+			Warnings: []string{"warning"},
+			Timings:  logs.Timings{Request: time.Second},
+		}, nil
+	}
+
+	l, err := queryWindows(query, w)("", "app:web", logs.QuerySpec{Limit: 10})
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+
+	assert(t, len(specs), 2)
+	assert(t, specs[1].StartDate, day(11).start)
+	assert(t, specs[1].Limit, 10)
+	assert(t, len(l.Logs), 4)
+	assert(t, l.Logs[2].ID, "11")
+	assertDeepEqual(t, l.Logs[3].Labels, []logs.KeyValue{{Key: "applicationname", Value: "web"}, {Key: windowLabel, Value: day(11).String()}})
+	assertDeepEqual(t, l.Logs[0].Labels[1], logs.KeyValue{Key: windowLabel, Value: day(10).String()})
+	assert(t, len(l.Warnings), 2)
+	assert(t, l.Timings.Request, 2*time.Second)
+
+	failing := func(client, q string, spec logs.QuerySpec) (logs.Result, error) {
+		return logs.Result{}, errors.New("boom")
+	}
+	if _, err = queryWindows(failing, w)("", "app:web", logs.QuerySpec{}); err == nil {
+		t.Error("Expected error of failing window")
+	}
+
+	// Deadline during second window leaves results of the first one
+	calls := 0
+	late := func(client, q string, spec logs.QuerySpec) (logs.Result, error) {
+		if calls++; calls > 1 {
+			return logs.Result{}, errDeadline
+		}
+		return query(client, q, spec)
+	}
+	l, err = queryWindows(late, windows{day(10), day(11), day(12)})("", "app:web", logs.QuerySpec{})
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	assert(t, len(l.Logs), 2)
+	assert(t, l.Warnings[len(l.Warnings)-1], "deadline exceeded after 1 of 3 windows, results are partial")
+
+	// Deadline during the only window keeps its records received so far
+	partial := func(client, q string, spec logs.QuerySpec) (logs.Result, error) {
+		return logs.Result{Logs: []logs.Log{{ID: "partial"}}}, errDeadline
+	}
+	l, err = queryWindows(partial, windows{day(10)})("", "app:web", logs.QuerySpec{})
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	assert(t, len(l.Logs), 1)
+	assert(t, l.Warnings[0], "deadline exceeded after 0 of 1 windows, results are partial")
+
+	// Stop during second window keeps its records received so far
+	calls = 0
+	stopped := func(client, q string, spec logs.QuerySpec) (logs.Result, error) {
+		if calls++; calls > 1 {
+			return logs.Result{Logs: []logs.Log{{ID: "partial"}}}, errStopped
+		}
+		return query(client, q, spec)
+	}
+	l, err = queryWindows(stopped, windows{day(10), day(11), day(12)})("", "app:web", logs.QuerySpec{})
+	assertError(t, err, errStopped)
+	assert(t, len(l.Logs), 3)
+	assert(t, l.Logs[2].ID, "partial")
+}
+
+func TestDailyWindows(t *testing.T) {
+	now := time.Date(2025, 1, 11, 18, 30, 0, 0, time.Local)
+	at := func(d, h, m int) time.Time {
+		return time.Date(2025, 1, d, h, m, 0, 0, time.Local)
+	}
+
+	w, err := dailyWindows("03:00..03:30", 3, now)
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	assert(t, len(w), 3)
+	assert(t, w[0].start.Equal(at(9, 3, 0)), true)
+	assert(t, w[2].start.Equal(at(11, 3, 0)), true)
+	assert(t, w[2].end.Equal(at(11, 3, 30)), true)
+
+	// Today's window has not started yet
+	w, err = dailyWindows("23:50..00:10", 2, now)
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	assert(t, w[0].start.Equal(at(9, 23, 50)), true)
+	assert(t, w[1].start.Equal(at(10, 23, 50)), true)
+	assert(t, w[1].end.Equal(at(11, 0, 10)), true)
+
+	if _, err = dailyWindows("03:00..03:30", 0, now); err == nil {
+		t.Error("Expected error for no days")
+	}
+	if _, err = dailyWindows("03:00", 7, now); err == nil {
+		t.Error("Expected error for invalid window")
+	}
+}
+
+func TestSearchWindow(t *testing.T) {
+	now := time.Date(2025, 1, 11, 14, 0, 0, 0, time.UTC)
+	from := time.Date(2025, 1, 11, 12, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 11, 13, 0, 0, 0, time.UTC)
+	var none time.Time
+
+	testCases := []struct {
+		name      string
+		from      time.Time
+		to        time.Time
+		timeRange time.Duration
+		ranged    bool
+		start     time.Time
+		end       time.Time
+		err       error
+	}{
+		{name: "DefaultRange", from: none, to: none, timeRange: defaultTimeRange, start: now.Add(-defaultTimeRange), end: now},
+		{name: "Range", from: none, to: none, timeRange: 15 * time.Minute, ranged: true, start: now.Add(-15 * time.Minute), end: now},
+		{name: "FromUntilNow", from: from, to: none, timeRange: defaultTimeRange, start: from, end: now},
+		{name: "FromWithRange", from: from, to: none, timeRange: 30 * time.Minute, ranged: true, start: from, end: from.Add(30 * time.Minute)},
+		{name: "FromWithDefaultValueRange", from: from, to: none, timeRange: time.Hour, ranged: true, start: from, end: from.Add(time.Hour)},
+		{name: "ToWithDefaultRange", from: none, to: to, timeRange: defaultTimeRange, start: to.Add(-defaultTimeRange), end: to},
+		{name: "ToWithRange", from: none, to: to, timeRange: 10 * time.Minute, ranged: true, start: to.Add(-10 * time.Minute), end: to},
+		{name: "FromAndTo", from: from, to: to, timeRange: defaultTimeRange, start: from, end: to},
+		{name: "FromAndToIgnoreRange", from: from, to: to, timeRange: 5 * time.Minute, ranged: true, start: from, end: to},
+		{name: "NegativeRange", from: none, to: none, timeRange: -time.Hour, err: errNegativeRange},
+		{name: "FromAfterTo", from: to, to: from, timeRange: defaultTimeRange, err: errInvertedRange},
+		{name: "FromInFuture", from: now.Add(time.Hour), to: none, timeRange: defaultTimeRange, err: errInvertedRange},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			start, end, err := searchWindow(tc.from, tc.to, tc.timeRange, tc.ranged, now)
+			assertError(t, err, tc.err)
+			assert(t, start, tc.start)
+			assert(t, end, tc.end)
+		})
+	}
+}
+
+func TestAlignWindow(t *testing.T) {
+	start := time.Date(2025, 1, 11, 14, 3, 20, 0, time.UTC)
+	end := time.Date(2025, 1, 11, 14, 12, 0, 0, time.UTC)
+
+	gotStart, gotEnd := alignWindow(start, end, 5*time.Minute)
+	assert(t, gotStart, time.Date(2025, 1, 11, 14, 0, 0, 0, time.UTC))
+	assert(t, gotEnd, time.Date(2025, 1, 11, 14, 15, 0, 0, time.UTC))
+
+	gotStart, gotEnd = alignWindow(gotStart, gotEnd, 5*time.Minute)
+	assert(t, gotStart, time.Date(2025, 1, 11, 14, 0, 0, 0, time.UTC))
+	assert(t, gotEnd, time.Date(2025, 1, 11, 14, 15, 0, 0, time.UTC))
+
+	gotStart, gotEnd = alignWindow(start, end, 0)
+	assert(t, gotStart, start)
+	assert(t, gotEnd, end)
+}
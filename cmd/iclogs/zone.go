@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// Zone suffix of displayed times in UTC mode, `Z` for UTC
+const zoneSuffix = "Z07:00"
+
+const defaultPrecision = "s"
+
+// Fraction of seconds added to displayed times per precision unit
+var precisions = map[string]string{
+	"s":  "",
+	"ms": ".000",
+	"us": ".000000",
+	"ns": ".000000000",
+}
+
+// Location and layout of displayed times, zero value shows local times with whole seconds
+type timeLayout struct {
+	loc    *time.Location // Displayed times are converted to, none keeps their own
+	layout string
+}
+
+// Layout of displayed times with fraction of seconds of given precision, in UTC with zone suffix when utc is set
+func newTimeLayout(precision string, utc bool) (timeLayout, error) {
+	fraction, ok := precisions[precision]
+	if !ok {
+		return timeLayout{}, fmt.Errorf("unknown precision '%s', use s, ms, us or ns", precision)
+	}
+
+	l := timeLayout{layout: timeStampFormat + fraction}
+	if utc {
+		l.loc, l.layout = time.UTC, l.layout+zoneSuffix
+	}
+
+	return l, nil
+}
+
+// Time in location of displayed times
+func (l timeLayout) in(t time.Time) time.Time {
+	if l.loc == nil {
+		return t
+	}
+
+	return t.In(l.loc)
+}
+
+// Layout of displayed timestamps
+func (l timeLayout) stamp() string {
+	if l.layout == "" {
+		return timeStampFormat
+	}
+
+	return l.layout
+}
+
+func (l timeLayout) format(t time.Time) string {
+	return l.in(t).Format(l.stamp())
+}
+
+// Handle time options in UTC: options without zone were already parsed as local time, so their wall clock
+// is taken as UTC. Times are displayed in UTC by layout of newTimeLayout.
+func useUTC(args *CmdArgs) {
+	args.StartTime = timestamp(wallClockUTC(time.Time(args.StartTime)))
+	args.EndTime = timestamp(wallClockUTC(time.Time(args.EndTime)))
+	for i, w := range args.Windows {
+		args.Windows[i] = timeWindow{start: wallClockUTC(w.start), end: wallClockUTC(w.end)}
+	}
+}
+
+// Same wall clock time in UTC for local time, time with explicit zone is kept
+func wallClockUTC(t time.Time) time.Time {
+	if t.IsZero() || t.Location() != time.Local {
+		return t
+	}
+
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+// Keep zone given explicitly in parsed time, even when its offset matches local zone
+func explicitZone(t time.Time) time.Time {
+	name, offset := t.Zone()
+	if offset == 0 {
+		return t.UTC()
+	}
+
+	return t.In(time.FixedZone(name, offset))
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTime(t *testing.T) {
+	local := time.Local
+	defer func() { time.Local = local }()
+	time.Local = time.FixedZone("CET", 3600)
+	// Still 11th in UTC, already 12th locally
+	now := time.Date(2025, 1, 11, 23, 30, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name  string
+		input string
+		want  time.Time
+		err   bool
+	}{
+		{name: "Minutes", input: "2025-01-11T18:00", want: time.Date(2025, 1, 11, 18, 0, 0, 0, time.Local)},
+		{name: "Seconds", input: "2025-01-11T18:00:05", want: time.Date(2025, 1, 11, 18, 0, 5, 0, time.Local)},
+		{name: "Milliseconds", input: "2025-01-11T18:00:05.123", want: time.Date(2025, 1, 11, 18, 0, 5, 123000000, time.Local)},
+		{name: "RFC3339", input: "2025-01-11T18:00:05Z", want: time.Date(2025, 1, 11, 18, 0, 5, 0, time.UTC)},
+		{name: "RFC3339Nano", input: "2025-01-11T18:00:05.000000123+02:00", want: time.Date(2025, 1, 11, 16, 0, 5, 123, time.UTC)},
+		{name: "Date", input: "2025-01-11", want: time.Date(2025, 1, 11, 0, 0, 0, 0, time.Local)},
+		{name: "Now", input: "now", want: now},
+		{name: "Today", input: "today", want: time.Date(2025, 1, 12, 0, 0, 0, 0, time.Local)},
+		{name: "Yesterday", input: "yesterday", want: time.Date(2025, 1, 11, 0, 0, 0, 0, time.Local)},
+		{name: "Unknown", input: "tomorrow", err: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseTimeAt(tc.input, now)
+			if (err != nil) != tc.err {
+				t.Fatalf("Got error: %v, want error: %v", err, tc.err)
+			}
+			assert(t, got.Equal(tc.want), true)
+		})
+	}
+}
+
+func TestTimeLayout(t *testing.T) {
+	stamp := time.Date(2025, 1, 11, 18, 0, 5, 123456789, time.FixedZone("CET", 3600))
+
+	testCases := []struct {
+		unit string
+		utc  bool
+		want string
+	}{
+		{unit: "s", want: "2025-01-11 18:00:05"},
+		{unit: "ms", want: "2025-01-11 18:00:05.123"},
+		{unit: "us", want: "2025-01-11 18:00:05.123456"},
+		{unit: "ns", want: "2025-01-11 18:00:05.123456789"},
+		{unit: "s", utc: true, want: "2025-01-11 17:00:05Z"},
+		{unit: "ms", utc: true, want: "2025-01-11 17:00:05.123Z"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.unit, func(t *testing.T) {
+			l, err := newTimeLayout(tc.unit, tc.utc)
+			if err != nil {
+				t.Fatalf("Got unexpected error: %v", err)
+			}
+			assert(t, l.format(stamp), tc.want)
+		})
+	}
+
+	assert(t, timeLayout{}.format(stamp), "2025-01-11 18:00:05")
+
+	if _, err := newTimeLayout("m", false); err == nil {
+		t.Error("Expected error for unknown precision")
+	}
+}
+
+func TestUseUTC(t *testing.T) {
+	local := time.Local
+	defer func() { time.Local = local }()
+
+	time.Local = time.FixedZone("CET", 3600)
+	start, err := parseTime("2025-01-11T18:00")
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	end, err := parseTime("2025-01-11T19:00:00+01:00")
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+
+	args := &CmdArgs{StartTime: timestamp(start), EndTime: timestamp(end)}
+	useUTC(args)
+
+	assert(t, time.Time(args.StartTime), time.Date(2025, 1, 11, 18, 0, 0, 0, time.UTC))
+	assert(t, time.Time(args.EndTime).Equal(time.Date(2025, 1, 11, 18, 0, 0, 0, time.UTC)), true)
+}
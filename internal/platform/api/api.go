@@ -0,0 +1,117 @@
+// Package api to call IBM Cloud Logs management APIs, ie. to read policies, enrichments or data usage
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var Timeout = time.Duration(30) * time.Second // HTTP request timeout - default 30 seconds
+
+// HTTPError of management API response
+type HTTPError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("got HTTP error code: %d, message: '%s'", e.StatusCode, e.Message)
+}
+
+// Get body of resource at path of logs endpoint, ie. `/v1/policies`, with optional query parameters.
+// With CacheTTL the response is cached, and stale cached response is used when endpoint cannot be reached.
+func Get(ctx context.Context, endpoint string, token func() (string, error), path string, query url.Values) ([]byte, error) {
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	if CacheTTL <= 0 {
+		return Do(ctx, http.MethodGet, endpoint, token, path, nil)
+	}
+
+	resource := strings.TrimSuffix(endpoint, "/") + path
+	cached, age, ok := loadCached(resource)
+	if ok && !Refresh && age < CacheTTL {
+		return cached, nil
+	}
+
+	body, err := Do(ctx, http.MethodGet, endpoint, token, path, nil)
+	var unreachable *url.Error
+	if err != nil && ok && errors.As(err, &unreachable) {
+		return cached, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	saveCached(resource, body) // Failing cache only makes next call slower
+
+	return body, nil
+}
+
+// Do request with method at path of logs endpoint, path can have query string. Body is sent as JSON when given.
+// Token is obtained only when request is sent. Response body is returned for any successful status.
+func Do(ctx context.Context, method, endpoint string, token func() (string, error), path string, body []byte) ([]byte, error) {
+	p, query, _ := strings.Cut(path, "?")
+	u, err := url.JoinPath(endpoint, p)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create URL: %w", err)
+	}
+	if query != "" {
+		u += "?" + query
+	}
+
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, r)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create %s request: %w", method, err)
+	}
+	t, err := token()
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("authorization", "Bearer "+t)
+	if body != nil {
+		req.Header.Add("content-type", "application/json")
+	}
+
+	c := http.Client{Timeout: Timeout}
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("cannot %s %s: %w", method, p, err)
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read body: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, &HTTPError{StatusCode: resp.StatusCode, Message: string(b)}
+	}
+
+	return b, nil
+}
+
+// GetJSON decodes JSON resource at path of logs endpoint into v
+func GetJSON(ctx context.Context, endpoint string, token func() (string, error), path string, query url.Values, v any) error {
+	body, err := Get(ctx, endpoint, token, path, query)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("cannot parse %s: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/wooyey/iclogs/tests"
+)
+
+func TestGetJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("authorization") != "Bearer Good_Token" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("forbidden"))
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/things":
+			w.Write([]byte(`{"name":"` + r.URL.Query().Get("kind") + `"}`))
+		default:
+			w.Write([]byte(`{broken`))
+		}
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		name  string
+		token string
+		path  string
+		want  string
+		err   string
+	}{
+		{name: "Good", token: "Good_Token", path: "/v1/things", want: "logs"},
+		{name: "HTTPError", token: "Bad_Token", path: "/v1/things", err: "got HTTP error code: 403, message: 'forbidden'"},
+		{name: "BadJSON", token: "Good_Token", path: "/v1/broken", err: "cannot parse /v1/broken"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var v struct{ Name string }
+			err := GetJSON(context.Background(), server.URL, tests.TokenOf(tc.token), tc.path, url.Values{"kind": {"logs"}}, &v)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Errorf("Expected error containing: %s, got: %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Got unexpected error: %v", err)
+			}
+			if v.Name != tc.want {
+				t.Errorf("Got: %s, want: %s", v.Name, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/cache"
+)
+
+// CacheTTL is how long GET responses are reused before they are fetched again, 0 disables caching
+var CacheTTL time.Duration
+
+// Refresh fetches GET responses even when cached ones are fresh, fetched responses are cached still
+var Refresh bool
+
+// CachePath returns location of cached response for given resource URL in user cache directory
+var CachePath = func(resource string) (string, error) {
+	return cache.Path("api", resource)
+}
+
+type cachedResponse struct {
+	Fetched time.Time `json:"fetched"`
+	Body    string    `json:"body"`
+}
+
+// Cached response of resource with its age, false when there is none
+func loadCached(resource string) ([]byte, time.Duration, bool) {
+	path, err := CachePath(resource)
+	if err != nil {
+		return nil, 0, false
+	}
+
+	data, ok, err := cache.Read(path)
+	if err != nil || !ok {
+		return nil, 0, false
+	}
+
+	var c cachedResponse
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, 0, false
+	}
+
+	return []byte(c.Body), time.Since(c.Fetched), true
+}
+
+func saveCached(resource string, body []byte) error {
+	path, err := CachePath(resource)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(cachedResponse{Fetched: time.Now(), Body: string(body)})
+	if err != nil {
+		return fmt.Errorf("cannot encode response: %w", err)
+	}
+
+	if err := cache.Write(path, data); err != nil {
+		return fmt.Errorf("cannot write cached response: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/wooyey/iclogs/tests"
+)
+
+func TestGetCached(t *testing.T) {
+	dir := t.TempDir()
+	defaultPath := CachePath
+	CachePath = func(resource string) (string, error) { return filepath.Join(dir, "api.json"), nil }
+	defer func() { CachePath, CacheTTL, Refresh = defaultPath, 0, false }()
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(`{"calls":` + strconv.Itoa(calls) + `}`))
+	}))
+
+	get := func() string {
+		t.Helper()
+		body, err := Get(context.Background(), server.URL, tests.TokenOf("Good_Token"), "/v1/things", nil)
+		if err != nil {
+			t.Fatalf("Got unexpected error: %v", err)
+		}
+		return string(body)
+	}
+
+	testCases := []struct {
+		name    string
+		refresh bool
+		ttl     time.Duration
+		want    string
+		calls   int
+	}{
+		{name: "Fetched", ttl: time.Minute, want: `{"calls":1}`, calls: 1},
+		{name: "Fresh", ttl: time.Minute, want: `{"calls":1}`, calls: 1},
+		{name: "Refresh", ttl: time.Minute, refresh: true, want: `{"calls":2}`, calls: 2},
+		{name: "Stale", ttl: time.Nanosecond, want: `{"calls":3}`, calls: 3},
+		{name: "Disabled", want: `{"calls":4}`, calls: 4},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			CacheTTL, Refresh = tc.ttl, tc.refresh
+			if got := get(); got != tc.want || calls != tc.calls {
+				t.Errorf("Got: %s after %d calls, want: %s after %d", got, calls, tc.want, tc.calls)
+			}
+		})
+	}
+
+	server.Close()
+	CacheTTL, Refresh = time.Nanosecond, false
+	if got := get(); got != `{"calls":3}` {
+		t.Errorf("Expected stale cached response when endpoint is unreachable, got: %s", got)
+	}
+}
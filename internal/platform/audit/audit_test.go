@@ -0,0 +1,108 @@
+package audit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var entry = Entry{
+	User:      "tester",
+	Time:      time.Date(2025, 1, 11, 19, 0, 0, 0, time.UTC),
+	Endpoint:  "https://logs.cloud.ibm.com",
+	Query:     "some query",
+	StartDate: time.Date(2025, 1, 11, 18, 0, 0, 0, time.UTC),
+	EndDate:   time.Date(2025, 1, 11, 19, 0, 0, 0, time.UTC),
+	Count:     42,
+}
+
+func TestWriteFile(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "audit.log")
+
+	for range 2 {
+		if err := WriteFile(p, entry); err != nil {
+			t.Fatalf("Got an error: '%v'", err)
+		}
+	}
+
+	data, err := os.ReadFile(p)
+	if err != nil {
+		t.Fatalf("Cannot read audit file: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Want 2 entries, got: %d", len(lines))
+	}
+
+	var got Entry
+	if err := json.Unmarshal([]byte(lines[1]), &got); err != nil {
+		t.Fatalf("Cannot unmarshal entry: %v", err)
+	}
+
+	if got != entry {
+		t.Errorf("\nGot:\t%+v\nWant:\t%+v", got, entry)
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "audit.log")
+
+	if got, err := ReadFile(p); err != nil || got != nil {
+		t.Fatalf("Missing file should mean no entries, got: %v, '%v'", got, err)
+	}
+
+	if err := WriteFile(p, entry); err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+	f, _ := os.OpenFile(p, os.O_APPEND|os.O_WRONLY, 0o600)
+	f.WriteString("broken line\n")
+	f.Close()
+
+	got, err := ReadFile(p)
+	if err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+	if len(got) != 1 || got[0] != entry {
+		t.Errorf("\nGot:\t%+v\nWant:\t%+v", got, []Entry{entry})
+	}
+}
+
+func TestSend(t *testing.T) {
+
+	testCases := []struct {
+		name   string
+		status int
+		err    bool
+	}{
+		{name: "Accepted", status: 202, err: false},
+		{name: "Failed", status: 500, err: true},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Entry
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				json.NewDecoder(r.Body).Decode(&got)
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			err := Send(server.URL, entry)
+
+			if tt.err != (err != nil) {
+				t.Fatalf("Want error: %v, got: '%v'", tt.err, err)
+			}
+
+			if got != entry {
+				t.Errorf("\nGot:\t%+v\nWant:\t%+v", got, entry)
+			}
+		})
+	}
+}
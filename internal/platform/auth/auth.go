@@ -6,14 +6,28 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 )
 
 const tokenPath = "/identity/token"
 
+// Refresh token value IAM returns when refresh grant is not available for the client
+const refreshNotSupported = "not_supported"
+
+// IAM client credentials of IBM Cloud CLI, IAM issues refresh tokens only to identified clients
+const (
+	clientID     = "bx"
+	clientSecret = "bx"
+)
+
+// Token is refreshed this long before it expires, so requests in flight can still use it
+const expirationMargin = time.Minute
+
 // Token Response
 type Token struct {
 	Value      string `json:"access_token"`
+	Refresh    string `json:"refresh_token"`
 	Expiration int    `json:"expires_in"`
 	Created    int64
 }
@@ -29,6 +43,7 @@ type GetTokenError struct {
 	Code    int
 	Message string
 	Details string
+	IAMCode string // IAM error code, ie. `BXNIM0415E`
 }
 
 var GetNow = func() time.Time {
@@ -39,21 +54,53 @@ var GetAuthURL = func(endpoint string) (string, error) {
 	return url.JoinPath(endpoint, tokenPath)
 }
 
+// Valid checks if token is set and not (almost) expired
+func (t Token) Valid() bool {
+	return t.Value != "" && GetNow().Add(expirationMargin).Unix() < t.Created+int64(t.Expiration)
+}
+
+// Refreshable checks if token can be renewed with refresh grant, without API key
+func (t Token) Refreshable() bool {
+	return t.Refresh != "" && t.Refresh != refreshNotSupported
+}
+
 func (e GetTokenError) Error() string {
 	return fmt.Sprintf("cannot get token. error code: %v, message: %v, details: %v", e.Code, e.Message, e.Details)
 }
 
 func GetToken(endpoint, key string) (Token, error) {
 
-	token := Token{}
-
 	data := url.Values{}
 	data.Add("grant_type", "urn:ibm:params:oauth:grant-type:apikey")
 	data.Add("apikey", key)
 
+	return requestToken(endpoint, data)
+}
+
+// RefreshToken gets new token using refresh token of the previous one
+func RefreshToken(endpoint string, t Token) (Token, error) {
+
+	data := url.Values{}
+	data.Add("grant_type", "refresh_token")
+	data.Add("refresh_token", t.Refresh)
+
+	return requestToken(endpoint, data)
+}
+
+func requestToken(endpoint string, data url.Values) (Token, error) {
+
+	token := Token{}
+
 	addr, _ := GetAuthURL(endpoint)
 
-	resp, err := http.PostForm(addr, data)
+	req, err := http.NewRequest(http.MethodPost, addr, strings.NewReader(data.Encode()))
+	if err != nil {
+		return token, fmt.Errorf("cannot create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(clientID, clientSecret)
+
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return token, fmt.Errorf("cannot POST data: %w", err)
 	}
@@ -64,7 +111,7 @@ func GetToken(endpoint, key string) (Token, error) {
 		if err = json.NewDecoder(resp.Body).Decode(&e); err != nil {
 			return token, fmt.Errorf("cannot decode error message with status %d from JSON: %w", resp.StatusCode, err)
 		}
-		return token, GetTokenError{resp.StatusCode, e.Message, e.Details}
+		return token, GetTokenError{resp.StatusCode, e.Message, e.Details, e.Code}
 	}
 
 	err = json.NewDecoder(resp.Body).Decode(&token)
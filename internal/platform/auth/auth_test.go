@@ -7,6 +7,7 @@ import (
 	"log"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -21,6 +22,13 @@ var tokenResp = `{
 	"scope": "ibm openid"
 }`
 
+var refreshedResp = `{
+	"access_token": "Refreshed_Token",
+	"refresh_token": "Next_Refresh_Token",
+	"token_type": "Bearer",
+	"expires_in": 3600
+}`
+
 var errorMethod = `{
 	"errorCode": "BXNIM0060E",
 	"errorMessage": "Web application exception 'javax.ws.rs.ClientErrorException' during request processing.",
@@ -75,7 +83,10 @@ func mockServer() *httptest.Server {
 			fmt.Fprintln(w, e)
 		}
 
-		switch {
+		switch id, secret, _ := r.BasicAuth(); {
+		case id != clientID || secret != clientSecret:
+			w.WriteHeader(400)
+			fmt.Fprintln(w, httpError("Missing client credentials", fmt.Sprintf("Given client: %s", id)))
 		case r.Method != "POST":
 			w.WriteHeader(405)
 			fmt.Fprintln(w, errorMethod)
@@ -87,6 +98,14 @@ func mockServer() *httptest.Server {
 				w.WriteHeader(403)
 				fmt.Fprintln(w, httpError("Wrong API Key", fmt.Sprintf("Given Key: %s", k)))
 			}
+		case r.Form.Get("grant_type") == "refresh_token":
+			if k := r.Form.Get("refresh_token"); k == "GOOD_REFRESH_TOKEN" {
+				w.WriteHeader(200)
+				fmt.Fprintln(w, refreshedResp)
+			} else {
+				w.WriteHeader(400)
+				fmt.Fprintln(w, httpError("Invalid refresh token", fmt.Sprintf("Given token: %s", k)))
+			}
 		case r.Form.Get("grant_type") == "":
 			w.WriteHeader(400)
 			fmt.Fprintln(w, errorBadReq)
@@ -109,8 +128,8 @@ func TestGetToken(t *testing.T) {
 		want  Token
 		err   any
 	}{
-		{name: "GoodAPIKey", input: "GOOD_API_KEY", want: Token{Value: "API_Token", Expiration: 3600, Created: 1234}, err: nil},
-		{name: "BadAPIKey", input: "BAD_API_KEY", want: Token{}, err: GetTokenError{403, "Wrong API Key", "Given Key: BAD_API_KEY"}},
+		{name: "GoodAPIKey", input: "GOOD_API_KEY", want: Token{Value: "API_Token", Refresh: "not_supported", Expiration: 3600, Created: 1234}, err: nil},
+		{name: "BadAPIKey", input: "BAD_API_KEY", want: Token{}, err: GetTokenError{403, "Wrong API Key", "Given Key: BAD_API_KEY", "test_error"}},
 	}
 
 	server := mockServer()
@@ -146,3 +165,100 @@ func TestGetToken(t *testing.T) {
 		})
 	}
 }
+
+func TestTokenValid(t *testing.T) {
+
+	token := Token{Value: "API_Token", Expiration: 3600, Created: 1000}
+
+	testCases := []struct {
+		name  string
+		token Token
+		now   int64
+		want  bool
+	}{
+		{name: "Fresh", token: token, now: 1000, want: true},
+		{name: "AlmostExpired", token: token, now: 4570, want: false},
+		{name: "Expired", token: token, now: 5000, want: false},
+		{name: "Empty", token: Token{}, now: 0, want: false},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			GetNow = func() time.Time {
+				return time.Unix(tt.now, 0)
+			}
+
+			if got := tt.token.Valid(); got != tt.want {
+				t.Errorf("Got: %v, Want: %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRefreshToken(t *testing.T) {
+
+	server := mockServer()
+	defer server.Close()
+
+	GetNow = func() time.Time {
+		return time.Unix(1234, 0)
+	}
+
+	got, err := RefreshToken(server.URL, Token{Value: "API_Token", Refresh: "GOOD_REFRESH_TOKEN"})
+	if err != nil {
+		t.Fatalf("Got unexpected error: '%v'", err)
+	}
+
+	want := Token{Value: "Refreshed_Token", Refresh: "Next_Refresh_Token", Expiration: 3600, Created: 1234}
+	if got != want {
+		t.Errorf("Got: '%+v', Want: '%+v'", got, want)
+	}
+
+	wantErr := GetTokenError{400, "Invalid refresh token", "Given token: EXPIRED", "test_error"}
+	if _, err = RefreshToken(server.URL, Token{Refresh: "EXPIRED"}); !errors.Is(err, wantErr) {
+		t.Errorf("Got error: '%v', Want error: '%v'", err, wantErr)
+	}
+}
+
+func TestTokenRefreshable(t *testing.T) {
+
+	testCases := []struct {
+		name  string
+		token Token
+		want  bool
+	}{
+		{name: "Refresh", token: Token{Refresh: "Next_Refresh_Token"}, want: true},
+		{name: "NotSupported", token: Token{Refresh: "not_supported"}, want: false},
+		{name: "Empty", token: Token{}, want: false},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.token.Refreshable(); got != tt.want {
+				t.Errorf("Got: %v, Want: %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientCredentials(t *testing.T) {
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get("Authorization"))
+		fmt.Fprintln(w, refreshedResp)
+	}))
+	defer server.Close()
+
+	if _, err := GetToken(server.URL, "GOOD_API_KEY"); err != nil {
+		t.Fatalf("Got unexpected error: '%v'", err)
+	}
+	if _, err := RefreshToken(server.URL, Token{Refresh: "GOOD_REFRESH_TOKEN"}); err != nil {
+		t.Fatalf("Got unexpected error: '%v'", err)
+	}
+
+	// Basic bx:bx
+	want := []string{"Basic Yng6Yng=", "Basic Yng6Yng="}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Got: '%v', Want: '%v'", got, want)
+	}
+}
@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Claims of IAM access token, decoded from its JWT payload without signature verification
+type Claims struct {
+	Subject     string  `json:"sub"`
+	SubjectType string  `json:"sub_type"` // ie. ServiceId, empty for users
+	IAMID       string  `json:"iam_id"`
+	Name        string  `json:"name"`
+	Account     Account `json:"account"`
+	Scope       string  `json:"scope"` // Space separated scopes
+	IssuedAt    int64   `json:"iat"`
+	Expiry      int64   `json:"exp"`
+}
+
+// Account the identity belongs to
+type Account struct {
+	ID string `json:"bss"`
+}
+
+// ParseClaims decodes claims from access token value
+func ParseClaims(token string) (Claims, error) {
+	c := Claims{}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return c, fmt.Errorf("cannot parse token: expected 3 parts, got %d", len(parts))
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return c, fmt.Errorf("cannot decode token payload: %w", err)
+	}
+
+	if err = json.Unmarshal(data, &c); err != nil {
+		return c, fmt.Errorf("cannot parse token claims: %w", err)
+	}
+
+	return c, nil
+}
+
+// Scopes returns list of token scopes
+func (c Claims) Scopes() []string {
+	return strings.Fields(c.Scope)
+}
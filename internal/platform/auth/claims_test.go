@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+func jwt(payload string) string {
+	return "eyJhbGciOiJSUzI1NiJ9." + base64.RawURLEncoding.EncodeToString([]byte(payload)) + ".signature"
+}
+
+func TestParseClaims(t *testing.T) {
+
+	testCases := []struct {
+		name  string
+		token string
+		want  Claims
+		err   bool
+	}{
+		{name: "Valid", token: jwt(`{"scope":"ibm openid","exp":1735159110}`), want: Claims{Scope: "ibm openid", Expiry: 1735159110}},
+		{
+			name:  "ServiceID",
+			token: jwt(`{"iam_id":"iam-ServiceId-123","sub":"ServiceId-123","sub_type":"ServiceId","account":{"valid":true,"bss":"abc123"},"iat":1735155510,"exp":1735159110,"scope":"ibm openid"}`),
+			want:  Claims{Subject: "ServiceId-123", SubjectType: "ServiceId", IAMID: "iam-ServiceId-123", Account: Account{ID: "abc123"}, Scope: "ibm openid", IssuedAt: 1735155510, Expiry: 1735159110},
+		},
+		{name: "NotJWT", token: "API_Token", err: true},
+		{name: "BadPayload", token: "a.!!!.c", err: true},
+		{name: "NotJSON", token: jwt("scope"), err: true},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseClaims(tt.token)
+
+			if (err != nil) != tt.err {
+				t.Fatalf("Got error: '%v', Want error: %v", err, tt.err)
+			}
+
+			if got != tt.want {
+				t.Errorf("Got: '%+v', Want: '%+v'", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScopes(t *testing.T) {
+	c := Claims{Scope: "ibm openid logs.write"}
+
+	if got := c.Scopes(); len(got) != 3 || got[2] != "logs.write" {
+		t.Errorf("Got scopes: %v, Want 3 scopes ending with logs.write", got)
+	}
+}
@@ -0,0 +1,61 @@
+// Package cache to keep files of iclogs in user cache directory
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const (
+	dirName  = "iclogs"
+	dirMode  = 0o700
+	fileMode = 0o600 // Cached files may keep tokens and log lines
+)
+
+// Dir returns iclogs directory in user cache directory, joined with subdirectories
+func Dir(sub ...string) (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot find user cache directory: %w", err)
+	}
+
+	return filepath.Join(append([]string{dir, dirName}, sub...)...), nil
+}
+
+// Path returns location of cached JSON file of kind for given key, key is hashed so any value fits in file name
+func Path(kind, key string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(dir, kind+"-"+hex.EncodeToString(sum[:6])+".json"), nil
+}
+
+// Read cached file, reporting whether it exists
+func Read(path string) ([]byte, bool, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	return data, true, nil
+}
+
+// Write cached file readable by user only, creating its directory
+func Write(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), dirMode); err != nil {
+		return fmt.Errorf("cannot create cache directory: %w", err)
+	}
+
+	return os.WriteFile(path, data, fileMode)
+}
@@ -0,0 +1,53 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPath(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	a, err := Path("labels", "https://logs.example.com")
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	b, _ := Path("labels", "https://other.example.com")
+
+	if a == b {
+		t.Errorf("Got the same path for different keys: '%s'", a)
+	}
+	if name := filepath.Base(a); !strings.HasPrefix(name, "labels-") || len(name) != len("labels-")+12+len(".json") {
+		t.Errorf("Got: '%s', Want: labels-<hash>.json", name)
+	}
+	if got, want := filepath.Base(filepath.Dir(a)), dirName; got != want {
+		t.Errorf("Got: '%s', Want: '%s'", got, want)
+	}
+}
+
+func TestReadWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "cached.json")
+
+	if data, ok, err := Read(path); data != nil || ok || err != nil {
+		t.Fatalf("Got: '%s', %v, %v, Want nothing cached", data, ok, err)
+	}
+
+	if err := Write(path, []byte(`{"a":1}`)); err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+
+	data, ok, err := Read(path)
+	if string(data) != `{"a":1}` || !ok || err != nil {
+		t.Errorf("Got: '%s', %v, %v, Want: '{\"a\":1}', true, <nil>", data, ok, err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	if got := info.Mode().Perm(); got != fileMode {
+		t.Errorf("Got: '%v', Want: '%v'", got, os.FileMode(fileMode))
+	}
+}
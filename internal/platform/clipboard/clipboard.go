@@ -0,0 +1,63 @@
+// Package clipboard to copy text into system clipboard using OS tools
+package clipboard
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+var errNoTool = errors.New("no clipboard tool found, install one of: ")
+
+// Clipboard tools per OS, first one available in PATH is used
+var tools = map[string][][]string{
+	"darwin":  {{"pbcopy"}},
+	"windows": {{"clip.exe"}},
+}
+
+var unixTools = [][]string{
+	{"xclip", "-selection", "clipboard"},
+	{"xsel", "--clipboard", "--input"},
+}
+
+var waylandTools = [][]string{
+	{"wl-copy"},
+}
+
+func candidates(goos string, getenv func(string) string) [][]string {
+	if t, ok := tools[goos]; ok {
+		return t
+	}
+
+	if getenv("WAYLAND_DISPLAY") != "" {
+		return slices.Concat(waylandTools, unixTools)
+	}
+
+	return unixTools
+}
+
+// Write text into clipboard
+func Write(text string) error {
+	c := candidates(runtime.GOOS, os.Getenv)
+
+	names := make([]string, len(c))
+	for i, t := range c {
+		names[i] = t[0]
+		if _, err := exec.LookPath(t[0]); err != nil {
+			continue
+		}
+
+		cmd := exec.Command(t[0], t[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("cannot copy with '%s': %w, output: '%s'", t[0], err, out)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("%w%s", errNoTool, strings.Join(names, ", "))
+}
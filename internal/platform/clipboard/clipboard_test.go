@@ -0,0 +1,31 @@
+package clipboard
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestCandidates(t *testing.T) {
+
+	testCases := []struct {
+		name string
+		goos string
+		env  map[string]string
+		want [][]string
+	}{
+		{name: "MacOS", goos: "darwin", env: map[string]string{}, want: [][]string{{"pbcopy"}}},
+		{name: "Windows", goos: "windows", env: map[string]string{}, want: [][]string{{"clip.exe"}}},
+		{name: "X11", goos: "linux", env: map[string]string{}, want: unixTools},
+		{name: "Wayland", goos: "linux", env: map[string]string{"WAYLAND_DISPLAY": "wayland-0"}, want: slices.Concat(waylandTools, unixTools)},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got := candidates(tt.goos, func(k string) string { return tt.env[k] })
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("\nGot:\t%+v\nWant:\t%+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package config
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	dirMode  = 0o700
+	fileMode = 0o600
+)
+
+// Shareable returns copy of configuration without machine specific and secret settings.
+// Audit is dropped, as webhook address commonly carries credentials and file location is local.
+// Hooks, shared queries source and plugins directory are dropped too, so imported bundle never runs commands
+// or fetches queries its receiver did not set up.
+func (c Config) Shareable() Config {
+	c.Audit = Audit{}
+	c.Hooks = Hooks{}
+	c.QueriesSource = ""
+	c.PluginsDir = ""
+	return c
+}
+
+// Merge adds settings from other configuration, entries with the same name are replaced.
+// Audit settings are kept and default profile is only set when missing.
+func (c *Config) Merge(o Config) {
+	if c.DefaultProfile == "" {
+		c.DefaultProfile = o.DefaultProfile
+	}
+
+	c.Aliases = mergeMap(c.Aliases, o.Aliases)
+	c.Redactors = mergeMap(c.Redactors, o.Redactors)
+	c.Profiles = mergeMap(c.Profiles, o.Profiles)
+	c.Queries = mergeMap(c.Queries, o.Queries)
+
+	for _, q := range o.Slack.Queries {
+		if !contains(c.Slack.Queries, q) {
+			c.Slack.Queries = append(c.Slack.Queries, q)
+		}
+	}
+}
+
+func mergeMap[M ~map[string]V, V any](dst, src M) M {
+	if len(src) == 0 {
+		return dst
+	}
+
+	if dst == nil {
+		dst = make(M, len(src))
+	}
+	for k, v := range src {
+		dst[k] = v
+	}
+
+	return dst
+}
+
+func contains(l []string, s string) bool {
+	for _, v := range l {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Save configuration to given file, empty path means default location
+func Save(path string, c Config) error {
+	if path == "" {
+		p, err := DefaultPath()
+		if err != nil {
+			return err
+		}
+		path = p
+	}
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot encode config: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), dirMode); err != nil {
+		return fmt.Errorf("cannot create config directory: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), fileMode); err != nil {
+		return fmt.Errorf("cannot write config file: %w", err)
+	}
+
+	return nil
+}
+
+// WriteBundle writes shareable part of configuration as gzipped tar archive with config file inside
+func WriteBundle(w io.Writer, c Config) error {
+	data, err := json.MarshalIndent(c.Shareable(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot encode config: %w", err)
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	h := &tar.Header{Name: fileName, Mode: fileMode, Size: int64(len(data)), ModTime: time.Now()}
+	if err := tw.WriteHeader(h); err != nil {
+		return fmt.Errorf("cannot write bundle: %w", err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("cannot write bundle: %w", err)
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("cannot write bundle: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("cannot write bundle: %w", err)
+	}
+
+	return nil
+}
+
+// ReadBundle reads configuration from gzipped tar archive written by WriteBundle
+func ReadBundle(r io.Reader) (Config, error) {
+	cfg := Config{}
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return cfg, fmt.Errorf("cannot read bundle: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		h, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return cfg, fmt.Errorf("cannot read bundle: %s not found", fileName)
+		}
+		if err != nil {
+			return cfg, fmt.Errorf("cannot read bundle: %w", err)
+		}
+
+		if h.Name != fileName {
+			continue
+		}
+
+		if err := json.NewDecoder(tr).Decode(&cfg); err != nil {
+			return cfg, fmt.Errorf("cannot parse bundle config: %w", err)
+		}
+
+		return cfg.Shareable(), nil
+	}
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestBundle(t *testing.T) {
+	cfg := Config{
+		Aliases:        Aliases{"ns": "json.kubernetes.namespace_name"},
+		Audit:          Audit{File: "/var/log/iclogs.log", Webhook: "https://hooks.example.com/secret-token"},
+		Hooks:          Hooks{PreQuery: []string{"/usr/local/bin/approve"}},
+		QueriesSource:  "https://queries.example.com/team.json",
+		PluginsDir:     "/home/user/plugins",
+		DefaultProfile: "prod",
+		Profiles:       map[string]Profile{"prod": {LogsURL: "https://prod.logs.cloud.ibm.com", Scope: "app:web"}},
+		Queries:        map[string]SavedQuery{"errors": {Query: "severity:error", Range: "15m"}},
+		Slack:          Slack{Queries: []string{"errors"}},
+	}
+
+	b := bytes.Buffer{}
+	if err := WriteBundle(&b, cfg); err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+
+	got, err := ReadBundle(&b)
+	if err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+
+	want := cfg
+	want.Audit, want.Hooks, want.QueriesSource, want.PluginsDir = Audit{}, Hooks{}, "", ""
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("\nGot:\t%+v\nWant:\t%+v", got, want)
+	}
+
+	if _, err := ReadBundle(bytes.NewBufferString("not a bundle")); err == nil {
+		t.Error("Should get an error for broken bundle!")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	cfg := Config{
+		Audit:          Audit{File: "audit.log"},
+		DefaultProfile: "dev",
+		Profiles:       map[string]Profile{"dev": {Scope: "env:dev"}, "prod": {Scope: "old"}},
+		Slack:          Slack{Queries: []string{"errors"}},
+	}
+
+	cfg.Merge(Config{
+		Aliases:        Aliases{"ns": "json.kubernetes.namespace_name"},
+		DefaultProfile: "prod",
+		Profiles:       map[string]Profile{"prod": {Scope: "env:prod"}},
+		Queries:        map[string]SavedQuery{"slow": {Query: "duration:>1000"}},
+		Slack:          Slack{Queries: []string{"errors", "slow"}},
+	})
+
+	want := Config{
+		Aliases:        Aliases{"ns": "json.kubernetes.namespace_name"},
+		Audit:          Audit{File: "audit.log"},
+		DefaultProfile: "dev",
+		Profiles:       map[string]Profile{"dev": {Scope: "env:dev"}, "prod": {Scope: "env:prod"}},
+		Queries:        map[string]SavedQuery{"slow": {Query: "duration:>1000"}},
+		Slack:          Slack{Queries: []string{"errors", "slow"}},
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("\nGot:\t%+v\nWant:\t%+v", cfg, want)
+	}
+}
+
+func TestSave(t *testing.T) {
+	cfg := Config{Aliases: Aliases{"ns": "json.kubernetes.namespace_name"}}
+	path := filepath.Join(t.TempDir(), "iclogs", "config.json")
+
+	if err := Save(path, cfg); err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+
+	got, err := Load(path)
+	if err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+	if !reflect.DeepEqual(got, cfg) {
+		t.Errorf("\nGot:\t%+v\nWant:\t%+v", got, cfg)
+	}
+}
@@ -0,0 +1,222 @@
+// Package config to load iclogs configuration file
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	dirName  = "iclogs"
+	fileName = "config.json"
+)
+
+// IAMEndpoints are IBM Cloud IAM endpoints usable by name as profile `auth_url`
+var IAMEndpoints = map[string]string{
+	"public":  "https://iam.cloud.ibm.com",
+	"private": "https://private.iam.cloud.ibm.com",
+	"test":    "https://iam.test.cloud.ibm.com",
+}
+
+// Aliases maps short field names to full ones, ie. `ns` to `json.kubernetes.namespace_name`
+type Aliases map[string]string
+
+// Profile groups settings for one IBM Cloud Logs instance
+type Profile struct {
+	LogsURL string `json:"logs_url"`
+	AuthURL string `json:"auth_url"` // IAM endpoint URL or name of one of IAMEndpoints, ie. `private`
+	Scope   string `json:"scope"`    // Query clause ANDed with every query
+
+	FallbackLogsURL string `json:"fallback_logs_url"` // Used when LogsURL cannot be connected, ie. public endpoint of private LogsURL
+
+	Account   string `json:"account"`     // IBM Cloud account ID of the instance, informational
+	APIKeyEnv string `json:"api_key_env"` // Environment variable with API key of the account, when API key is not given
+
+	PrivateEndpoints bool `json:"private_endpoints"` // Use `private.` hostnames of logs, ingestion and IAM endpoints
+
+	DashboardURL string `json:"dashboard_url"` // Web UI address, derived from LogsURL when empty
+
+	ToolScopes map[string]string `json:"tool_scopes"` // MCP tool name to query clause ANDed with its queries, on top of Scope
+
+	MaxRange   string `json:"max_range"`   // Longest allowed query window, ie. `24h`, no limit when empty
+	MaxRecords int    `json:"max_records"` // Records budget of one query, no limit when 0
+	Retention  string `json:"retention"`   // How long records are kept, ie. `720h`, used to explain empty results
+}
+
+// Audit settings, every executed query is recorded to file and/or webhook
+type Audit struct {
+	File    string `json:"file"`
+	Webhook string `json:"webhook"`
+}
+
+// Hooks are commands with arguments run before and after each query, receiving JSON context on stdin
+type Hooks struct {
+	PreQuery  []string `json:"pre_query"` // Failing command stops the query
+	PostQuery []string `json:"post_query"`
+}
+
+// UpdateCheck settings, opt-in check for newer release on stable (default) or beta channel
+type UpdateCheck struct {
+	Enabled bool   `json:"enabled"`
+	Channel string `json:"channel"`
+}
+
+// SavedQuery is named query with its time range, ie. `1h`
+type SavedQuery struct {
+	Query       string `json:"query"`
+	Range       string `json:"range"`
+	Description string `json:"description"`
+}
+
+// Slack bot settings
+type Slack struct {
+	Queries []string `json:"queries"` // Names of saved queries allowed to run from Slack
+}
+
+// Config file content
+type Config struct {
+	Aliases        Aliases               `json:"aliases"`
+	Audit          Audit                 `json:"audit"`
+	Hooks          Hooks                 `json:"hooks"`
+	Redactors      map[string]string     `json:"redactors"` // Custom redactors, name to regular expression
+	DefaultProfile string                `json:"default_profile"`
+	Profiles       map[string]Profile    `json:"profiles"`
+	Queries        map[string]SavedQuery `json:"queries"`
+	QueriesSource  string                `json:"queries_source"` // Shared queries location, HTTP(S) address or git+<repository>#<path>
+	PluginsDir     string                `json:"plugins_dir"`    // Sink plugins location, plugins directory in user config directory when empty
+	Slack          Slack                 `json:"slack"`
+	UpdateCheck    UpdateCheck           `json:"update_check"`
+}
+
+// DefaultPath returns location of configuration file in user config directory
+var DefaultPath = func() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot find user config directory: %w", err)
+	}
+
+	return filepath.Join(dir, dirName, fileName), nil
+}
+
+// Load configuration from given file. Empty path means default location, which doesn't need to exist.
+func Load(path string) (Config, error) {
+	cfg := Config{}
+
+	optional := path == ""
+	if optional {
+		p, err := DefaultPath()
+		if err != nil {
+			return cfg, err
+		}
+		path = p
+	}
+
+	data, err := os.ReadFile(path)
+	if optional && errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("cannot read config file: %w", err)
+	}
+
+	if err = json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("cannot parse config file '%s': %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// Expand replaces alias at the beginning of dot separated field name
+func (a Aliases) Expand(name string) string {
+	if v, ok := a[name]; ok {
+		return v
+	}
+
+	if head, tail, ok := strings.Cut(name, "."); ok {
+		if v, ok := a[head]; ok {
+			return v + "." + tail
+		}
+	}
+
+	return name
+}
+
+// SavedQuery returns saved query with given name
+func (c Config) SavedQuery(name string) (SavedQuery, error) {
+	q, ok := c.Queries[name]
+	if !ok {
+		return SavedQuery{}, fmt.Errorf("saved query '%s' not found in config", name)
+	}
+
+	return q, nil
+}
+
+// AddQueries adds shared saved queries, local ones with the same name take precedence
+func (c *Config) AddQueries(shared map[string]SavedQuery) {
+	if len(shared) == 0 {
+		return
+	}
+
+	q := make(map[string]SavedQuery, len(shared)+len(c.Queries))
+	for k, v := range shared {
+		q[k] = v
+	}
+	for k, v := range c.Queries {
+		q[k] = v
+	}
+	c.Queries = q
+}
+
+// IAMURL returns IAM endpoint URL of given URL or endpoint name
+func IAMURL(s string) string {
+	if u, ok := IAMEndpoints[s]; ok {
+		return u
+	}
+
+	return s
+}
+
+// ProfileNames returns names of all profiles, sorted
+func (c Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for n := range c.Profiles {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// UseProfile makes existing profile the default one
+func (c *Config) UseProfile(name string) error {
+	if _, ok := c.Profiles[name]; !ok {
+		return fmt.Errorf("profile '%s' not found in config", name)
+	}
+	c.DefaultProfile = name
+
+	return nil
+}
+
+// Profile returns profile with given name or default one if name is empty
+func (c Config) Profile(name string) (Profile, error) {
+	if name == "" {
+		name = c.DefaultProfile
+	}
+
+	if name == "" {
+		return Profile{}, nil
+	}
+
+	p, ok := c.Profiles[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("profile '%s' not found in config", name)
+	}
+
+	return p, nil
+}
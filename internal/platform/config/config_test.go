@@ -0,0 +1,200 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeConfig(t testing.TB, content string) string {
+	t.Helper()
+
+	p := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+		t.Fatalf("Cannot write config file: %v", err)
+	}
+
+	return p
+}
+
+func TestLoad(t *testing.T) {
+
+	testCases := []struct {
+		name    string
+		content string
+		want    Config
+		err     bool
+	}{
+		{name: "Empty", content: `{}`, want: Config{}, err: false},
+		{name: "Aliases", content: `{"aliases": {"ns": "json.kubernetes.namespace_name"}}`, want: Config{Aliases: Aliases{"ns": "json.kubernetes.namespace_name"}}, err: false},
+		{name: "ToolScopes", content: `{"profiles": {"prod": {"scope": "app:web", "tool_scopes": {"query": "severity:error"}}}}`, want: Config{Profiles: map[string]Profile{"prod": {Scope: "app:web", ToolScopes: map[string]string{"query": "severity:error"}}}}, err: false},
+		{name: "Broken", content: `{"aliases": `, want: Config{}, err: true},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Load(writeConfig(t, tt.content))
+
+			if tt.err != (err != nil) {
+				t.Fatalf("Want error: %v, got: '%v'", tt.err, err)
+			}
+
+			if !tt.err && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("\nGot:\t%+v\nWant:\t%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadMissing(t *testing.T) {
+
+	missing := filepath.Join(t.TempDir(), "missing.json")
+
+	defaultPath := DefaultPath
+	DefaultPath = func() (string, error) {
+		return missing, nil
+	}
+	t.Cleanup(func() { DefaultPath = defaultPath })
+
+	if _, err := Load(""); err != nil {
+		t.Errorf("Missing default config should be ignored, got: '%v'", err)
+	}
+
+	if _, err := Load(missing); err == nil {
+		t.Error("Missing explicit config should return an error!")
+	}
+}
+
+func TestExpand(t *testing.T) {
+
+	aliases := Aliases{
+		"ns":  "json.kubernetes.namespace_name",
+		"k8s": "json.kubernetes",
+	}
+
+	testCases := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "Alias", input: "ns", want: "json.kubernetes.namespace_name"},
+		{name: "Prefix", input: "k8s.pod_name", want: "json.kubernetes.pod_name"},
+		{name: "NoAlias", input: "severity", want: "severity"},
+		{name: "NoPrefixAlias", input: "json.ns", want: "json.ns"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := aliases.Expand(tt.input); got != tt.want {
+				t.Errorf("\nGot:\t%s\nWant:\t%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProfile(t *testing.T) {
+
+	cfg := Config{
+		DefaultProfile: "prod",
+		Profiles: map[string]Profile{
+			"prod": {LogsURL: "https://prod.logs.cloud.ibm.com", Scope: "applicationname:prod-*"},
+			"dev":  {LogsURL: "https://dev.logs.cloud.ibm.com"},
+		},
+	}
+
+	testCases := []struct {
+		name   string
+		config Config
+		input  string
+		want   Profile
+		err    bool
+	}{
+		{name: "Named", config: cfg, input: "dev", want: cfg.Profiles["dev"], err: false},
+		{name: "Default", config: cfg, input: "", want: cfg.Profiles["prod"], err: false},
+		{name: "NoProfiles", config: Config{}, input: "", want: Profile{}, err: false},
+		{name: "Unknown", config: cfg, input: "test", want: Profile{}, err: true},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.config.Profile(tt.input)
+
+			if tt.err != (err != nil) {
+				t.Fatalf("Want error: %v, got: '%v'", tt.err, err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("\nGot:\t%+v\nWant:\t%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSavedQuery(t *testing.T) {
+	cfg := Config{Queries: map[string]SavedQuery{"errors": {Query: "severity:error", Range: "15m"}}}
+
+	got, err := cfg.SavedQuery("errors")
+	if err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+	if got != cfg.Queries["errors"] {
+		t.Errorf("\nGot:\t%+v\nWant:\t%+v", got, cfg.Queries["errors"])
+	}
+
+	if _, err := cfg.SavedQuery("missing"); err == nil {
+		t.Error("Should get an error for missing saved query!")
+	}
+}
+
+func TestAddQueries(t *testing.T) {
+	cfg := Config{Queries: map[string]SavedQuery{"errors": {Query: "severity:error", Range: "1h"}}}
+
+	cfg.AddQueries(map[string]SavedQuery{
+		"errors": {Query: "severity:error", Range: "15m"},
+		"slow":   {Query: "duration:>1000"},
+	})
+
+	want := map[string]SavedQuery{
+		"errors": {Query: "severity:error", Range: "1h"},
+		"slow":   {Query: "duration:>1000"},
+	}
+	if !reflect.DeepEqual(cfg.Queries, want) {
+		t.Errorf("\nGot:\t%+v\nWant:\t%+v", cfg.Queries, want)
+	}
+}
+
+func TestIAMURL(t *testing.T) {
+	testCases := []struct {
+		input string
+		want  string
+	}{
+		{input: "private", want: "https://private.iam.cloud.ibm.com"},
+		{input: "test", want: "https://iam.test.cloud.ibm.com"},
+		{input: "https://iam.example.com", want: "https://iam.example.com"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := IAMURL(tt.input); got != tt.want {
+				t.Errorf("Got: %s, Want: %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUseProfile(t *testing.T) {
+	cfg := Config{DefaultProfile: "prod", Profiles: map[string]Profile{"prod": {}, "dev": {}}}
+
+	if got := cfg.ProfileNames(); !reflect.DeepEqual(got, []string{"dev", "prod"}) {
+		t.Errorf("Got names: %v", got)
+	}
+
+	if err := cfg.UseProfile("dev"); err != nil || cfg.DefaultProfile != "dev" {
+		t.Errorf("Got default: %s, error: '%v'", cfg.DefaultProfile, err)
+	}
+
+	if err := cfg.UseProfile("test"); err == nil || cfg.DefaultProfile != "dev" {
+		t.Errorf("Should get an error for missing profile, got default: %s", cfg.DefaultProfile)
+	}
+}
@@ -0,0 +1,300 @@
+// Package cos to upload files to IBM Cloud Object Storage or other S3-compatible storage
+package cos
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	scheme          = "cos://"
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+	amzDateFormat   = "20060102T150405Z"
+	amzDayFormat    = "20060102"
+)
+
+// PartSize of multipart upload, smaller files are uploaded with one request
+var PartSize int64 = 16 * 1024 * 1024
+
+var UploadTimeout = time.Duration(5) * time.Minute // HTTP upload timeout per request - default 5 minutes
+
+var GetNow = func() time.Time {
+	return time.Now()
+}
+
+var errInvalidLocation = errors.New("location needs to be in cos://bucket/prefix/ format")
+
+// Location of uploaded objects
+type Location struct {
+	Bucket string
+	Prefix string
+}
+
+// ParseLocation from cos://bucket/prefix/ URI
+func ParseLocation(uri string) (Location, error) {
+	rest, ok := strings.CutPrefix(uri, scheme)
+	if !ok {
+		return Location{}, errInvalidLocation
+	}
+
+	bucket, prefix, _ := strings.Cut(rest, "/")
+	if bucket == "" {
+		return Location{}, errInvalidLocation
+	}
+
+	return Location{Bucket: bucket, Prefix: prefix}, nil
+}
+
+// Signer authorizes request to storage
+type Signer func(r *http.Request) error
+
+// Bearer authorizes with IAM token, as IBM COS does
+func Bearer(token func() (string, error)) Signer {
+	return func(r *http.Request) error {
+		t, err := token()
+		if err != nil {
+			return err
+		}
+		r.Header.Set("Authorization", "Bearer "+t)
+		return nil
+	}
+}
+
+// SigV4 authorizes with HMAC credentials using AWS Signature Version 4, payload is not signed
+func SigV4(accessKey, secretKey, region string) Signer {
+	return func(r *http.Request) error {
+		now := GetNow().UTC()
+		date := now.Format(amzDateFormat)
+		day := now.Format(amzDayFormat)
+
+		r.Header.Set("X-Amz-Date", date)
+		r.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+
+		names := []string{"host"}
+		for n := range r.Header {
+			if n := strings.ToLower(n); strings.HasPrefix(n, "x-amz-") || n == "content-type" {
+				names = append(names, n)
+			}
+		}
+		sort.Strings(names)
+
+		headers := strings.Builder{}
+		for _, n := range names {
+			v := r.Header.Get(n)
+			if n == "host" {
+				v = r.URL.Host
+			}
+			fmt.Fprintf(&headers, "%s:%s\n", n, strings.TrimSpace(v))
+		}
+		signed := strings.Join(names, ";")
+
+		canonical := strings.Join([]string{
+			r.Method,
+			r.URL.EscapedPath(),
+			canonicalQuery(r.URL.Query()),
+			headers.String(),
+			signed,
+			unsignedPayload,
+		}, "\n")
+
+		scope := day + "/" + region + "/s3/aws4_request"
+		toSign := "AWS4-HMAC-SHA256\n" + date + "\n" + scope + "\n" + hexSHA256([]byte(canonical))
+
+		key := hmacSHA256([]byte("AWS4"+secretKey), day)
+		for _, s := range []string{region, "s3", "aws4_request"} {
+			key = hmacSHA256(key, s)
+		}
+
+		r.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+			accessKey, scope, signed, hex.EncodeToString(hmacSHA256(key, toSign))))
+
+		return nil
+	}
+}
+
+func canonicalQuery(q url.Values) string {
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		for _, v := range q[k] {
+			parts = append(parts, escape(k)+"="+escape(v))
+		}
+	}
+
+	return strings.Join(parts, "&")
+}
+
+func escape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+func hexSHA256(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// Client uploads objects to storage endpoint, ie. https://s3.us-south.cloud-object-storage.appdomain.cloud
+type Client struct {
+	Endpoint string
+	Sign     Signer
+	SSE      string // Server-side encryption algorithm, ie. AES256, empty means none
+}
+
+func (c *Client) objectURL(bucket, key string, query url.Values) string {
+	u := strings.TrimSuffix(c.Endpoint, "/") + "/" + bucket + "/" + (&url.URL{Path: key}).EscapedPath()
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	return u
+}
+
+func (c *Client) do(method, u string, body []byte, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequest(method, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("cannot create request: %w", err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	if err = c.Sign(req); err != nil {
+		return nil, fmt.Errorf("cannot sign request: %w", err)
+	}
+
+	client := http.Client{Timeout: UploadTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("cannot send request: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("storage returned HTTP error code %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+
+	return resp, nil
+}
+
+func (c *Client) sseHeader() http.Header {
+	h := http.Header{}
+	if c.SSE != "" {
+		h.Set("X-Amz-Server-Side-Encryption", c.SSE)
+	}
+	return h
+}
+
+// Upload object of given size, it is split into parts when bigger than part size
+func (c *Client) Upload(bucket, key string, r io.Reader, size int64) error {
+	if size <= PartSize {
+		body, err := io.ReadAll(r)
+		if err != nil {
+			return fmt.Errorf("cannot read object: %w", err)
+		}
+
+		resp, err := c.do(http.MethodPut, c.objectURL(bucket, key, nil), body, c.sseHeader())
+		if err != nil {
+			return fmt.Errorf("cannot upload object '%s': %w", key, err)
+		}
+		return resp.Body.Close()
+	}
+
+	return c.uploadMultipart(bucket, key, r)
+}
+
+type initiateResult struct {
+	UploadID string `xml:"UploadId"`
+}
+
+type completedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+type completeUpload struct {
+	XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+	Parts   []completedPart `xml:"Part"`
+}
+
+func (c *Client) uploadMultipart(bucket, key string, r io.Reader) error {
+	resp, err := c.do(http.MethodPost, c.objectURL(bucket, key, url.Values{"uploads": {""}}), nil, c.sseHeader())
+	if err != nil {
+		return fmt.Errorf("cannot start multipart upload of '%s': %w", key, err)
+	}
+	defer resp.Body.Close()
+
+	var init initiateResult
+	if err = xml.NewDecoder(resp.Body).Decode(&init); err != nil {
+		return fmt.Errorf("cannot decode multipart upload: %w", err)
+	}
+
+	done := completeUpload{}
+	buf := make([]byte, PartSize)
+
+	for n := 1; ; n++ {
+		size, err := io.ReadFull(r, buf)
+		if err == io.EOF {
+			break
+		}
+		if err != nil && err != io.ErrUnexpectedEOF {
+			c.abort(bucket, key, init.UploadID)
+			return fmt.Errorf("cannot read object: %w", err)
+		}
+
+		q := url.Values{"partNumber": {strconv.Itoa(n)}, "uploadId": {init.UploadID}}
+		resp, perr := c.do(http.MethodPut, c.objectURL(bucket, key, q), buf[:size], nil)
+		if perr != nil {
+			c.abort(bucket, key, init.UploadID)
+			return fmt.Errorf("cannot upload part %d of '%s': %w", n, key, perr)
+		}
+		resp.Body.Close()
+		done.Parts = append(done.Parts, completedPart{PartNumber: n, ETag: resp.Header.Get("ETag")})
+
+		if err == io.ErrUnexpectedEOF {
+			break
+		}
+	}
+
+	body, err := xml.Marshal(done)
+	if err != nil {
+		return fmt.Errorf("cannot marshal multipart upload: %w", err)
+	}
+
+	resp, err = c.do(http.MethodPost, c.objectURL(bucket, key, url.Values{"uploadId": {init.UploadID}}), body, nil)
+	if err != nil {
+		c.abort(bucket, key, init.UploadID)
+		return fmt.Errorf("cannot complete multipart upload of '%s': %w", key, err)
+	}
+
+	return resp.Body.Close()
+}
+
+// Abort multipart upload, so storage doesn't keep its parts
+func (c *Client) abort(bucket, key, uploadID string) {
+	if resp, err := c.do(http.MethodDelete, c.objectURL(bucket, key, url.Values{"uploadId": {uploadID}}), nil, nil); err == nil {
+		resp.Body.Close()
+	}
+}
@@ -0,0 +1,101 @@
+// Package crash to keep diagnostic reports of panics in user cache directory, ready to attach to bug report
+package crash
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/cache"
+)
+
+const (
+	subDir     = "crash"
+	filePrefix = "crash-"
+	fileExt    = ".json"
+	timeFormat = "20060102T150405.000"
+)
+
+// DefaultDir returns crash reports directory in user cache directory
+var DefaultDir = func() (string, error) {
+	return cache.Dir(subDir)
+}
+
+// Report describes one crash, arguments need to be stripped from secrets before
+type Report struct {
+	Time      time.Time `json:"time"`
+	Version   string    `json:"version"`
+	GoVersion string    `json:"go_version"`
+	Platform  string    `json:"platform"`
+	Args      []string  `json:"args"`
+	Panic     string    `json:"panic"`
+	Stack     string    `json:"stack"`
+	Log       []string  `json:"log,omitempty"` // Recent log lines before the crash
+}
+
+// NewReport of panic value with current goroutine stack and runtime details
+func NewReport(version string, args []string, p any, stack []byte) Report {
+	return Report{
+		Time:      time.Now().UTC(),
+		Version:   version,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+		Args:      args,
+		Panic:     fmt.Sprint(p),
+		Stack:     string(stack),
+	}
+}
+
+// Write report as JSON file in directory, returns its path
+func Write(dir string, r Report) (string, error) {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("cannot encode crash report: %w", err)
+	}
+
+	path := filepath.Join(dir, filePrefix+r.Time.Format(timeFormat)+fileExt)
+	if err := cache.Write(path, data); err != nil {
+		return "", fmt.Errorf("cannot write crash report: %w", err)
+	}
+
+	return path, nil
+}
+
+// Latest returns the most recent report in directory, ok is false when there is none
+func Latest(dir string) (r Report, ok bool, err error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return r, false, nil
+	}
+	if err != nil {
+		return r, false, fmt.Errorf("cannot read crash directory: %w", err)
+	}
+
+	var names []string
+	for _, e := range entries {
+		if n := e.Name(); strings.HasPrefix(n, filePrefix) && strings.HasSuffix(n, fileExt) {
+			names = append(names, n)
+		}
+	}
+	if len(names) == 0 {
+		return r, false, nil
+	}
+	sort.Strings(names)
+
+	data, err := os.ReadFile(filepath.Join(dir, names[len(names)-1]))
+	if err != nil {
+		return r, false, fmt.Errorf("cannot read crash report: %w", err)
+	}
+	if err := json.Unmarshal(data, &r); err != nil {
+		return r, false, fmt.Errorf("cannot parse crash report: %w", err)
+	}
+
+	return r, true, nil
+}
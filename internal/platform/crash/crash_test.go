@@ -0,0 +1,36 @@
+package crash
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWriteLatest(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "crash")
+
+	if _, ok, err := Latest(dir); ok || err != nil {
+		t.Fatalf("Missing directory should mean no reports, got: %v, '%v'", ok, err)
+	}
+
+	older := NewReport("v1.0.0", []string{"-r", "1h"}, "first", []byte("stack"))
+	older.Time = time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	newer := NewReport("v1.0.0", []string{"--where", "x"}, "index out of range", []byte("goroutine 1"))
+	newer.Time = older.Time.Add(time.Minute)
+	newer.Log = []string{"level=DEBUG msg=\"Running query\""}
+
+	for _, r := range []Report{newer, older} {
+		if _, err := Write(dir, r); err != nil {
+			t.Fatalf("Got an error: '%v'", err)
+		}
+	}
+
+	got, ok, err := Latest(dir)
+	if err != nil || !ok {
+		t.Fatalf("Want latest report, got: %v, '%v'", ok, err)
+	}
+	if !reflect.DeepEqual(got, newer) {
+		t.Errorf("\nGot:\t%+v\nWant:\t%+v", got, newer)
+	}
+}
@@ -0,0 +1,45 @@
+// Package dashboard to build links to IBM Cloud Logs web UI
+package dashboard
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/logs/syntax"
+)
+
+const (
+	apiLabel   = "api."
+	queryPath  = "/#/query-new/logs"
+	timeFormat = "2006-01-02T15:04:05.000Z"
+)
+
+// BaseURL derives dashboard address from API endpoint by dropping `api` host label
+func BaseURL(logsURL string) (string, error) {
+	u, err := url.Parse(logsURL)
+	if err != nil {
+		return "", fmt.Errorf("cannot parse logs URL: %w", err)
+	}
+
+	if u.Host == "" {
+		return "", fmt.Errorf("logs URL '%s' has no host", logsURL)
+	}
+
+	if i := strings.Index(u.Host, "."+apiLabel); i > 0 {
+		u.Host = u.Host[:i+1] + u.Host[i+1+len(apiLabel):]
+	}
+
+	return u.Scheme + "://" + u.Host, nil
+}
+
+// QueryURL builds dashboard link opening the same search
+func QueryURL(base, query string, s syntax.Syntax, start, end time.Time) string {
+	v := url.Values{}
+	v.Set("query", query)
+	v.Set("querySyntax", string(s))
+	v.Set("time", fmt.Sprintf("from:%s,to:%s", start.UTC().Format(timeFormat), end.UTC().Format(timeFormat)))
+
+	return strings.TrimSuffix(base, "/") + queryPath + "?" + v.Encode()
+}
@@ -0,0 +1,49 @@
+package dashboard
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/logs/syntax"
+)
+
+func TestBaseURL(t *testing.T) {
+
+	testCases := []struct {
+		name  string
+		input string
+		want  string
+		err   bool
+	}{
+		{name: "APIEndpoint", input: "https://1234-abcd.api.eu-de.logs.cloud.ibm.com", want: "https://1234-abcd.eu-de.logs.cloud.ibm.com", err: false},
+		{name: "PathAndSlash", input: "https://1234-abcd.api.eu-de.logs.cloud.ibm.com/v1/", want: "https://1234-abcd.eu-de.logs.cloud.ibm.com", err: false},
+		{name: "NoAPILabel", input: "https://logs.example.com", want: "https://logs.example.com", err: false},
+		{name: "NoHost", input: "logs", want: "", err: true},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BaseURL(tt.input)
+
+			if tt.err != (err != nil) {
+				t.Fatalf("Want error: %v, got: '%v'", tt.err, err)
+			}
+
+			if got != tt.want {
+				t.Errorf("\nGot:\t%s\nWant:\t%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryURL(t *testing.T) {
+	start := time.Date(2025, 1, 11, 18, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+
+	got := QueryURL("https://1234-abcd.eu-de.logs.cloud.ibm.com/", "kubernetes.pod_name:some-pod*", syntax.Lucene, start, end)
+	want := "https://1234-abcd.eu-de.logs.cloud.ibm.com/#/query-new/logs?query=kubernetes.pod_name%3Asome-pod%2A&querySyntax=lucene&time=from%3A2025-01-11T18%3A00%3A00.000Z%2Cto%3A2025-01-11T19%3A00%3A00.000Z"
+
+	if got != want {
+		t.Errorf("\nGot:\t%s\nWant:\t%s", got, want)
+	}
+}
@@ -0,0 +1,76 @@
+// Package e2m to read events-to-metrics definitions of IBM Cloud Logs instance, which derive metrics from records
+package e2m
+
+import (
+	"context"
+	"sort"
+
+	"github.com/wooyey/iclogs/internal/platform/api"
+)
+
+const e2mPath = "/v1/events2metrics"
+
+// Query selects records metric is derived from
+type Query struct {
+	Lucene       string   `json:"lucene"`
+	Applications []string `json:"applicationname_filters"`
+	Subsystems   []string `json:"subsystemname_filters"`
+	Severities   []string `json:"severity_filters"`
+}
+
+// Label of metric taken from record field
+type Label struct {
+	Target string `json:"target_label"`
+	Source string `json:"source_field"`
+}
+
+// Aggregation of field values making one metric, ie. `max`
+type Aggregation struct {
+	Enabled bool   `json:"enabled"`
+	Type    string `json:"agg_type"`
+	Metric  string `json:"target_metric_name"`
+}
+
+// Field of records aggregated into metrics
+type Field struct {
+	BaseMetric   string        `json:"target_base_metric_name"`
+	Source       string        `json:"source_field"`
+	Aggregations []Aggregation `json:"aggregations"`
+}
+
+// Definition of metrics derived from records matching query
+type Definition struct {
+	ID                string  `json:"id"`
+	Name              string  `json:"name"`
+	Description       string  `json:"description"`
+	Type              string  `json:"type"`
+	PermutationsLimit int     `json:"permutations_limit"`
+	Query             Query   `json:"logs_query"`
+	Labels            []Label `json:"metric_labels"`
+	Fields            []Field `json:"metric_fields"`
+	Created           string  `json:"create_time"`
+	Updated           string  `json:"update_time"`
+}
+
+type response struct {
+	Definitions []Definition `json:"events2metrics"`
+}
+
+// List definitions of the instance sorted by name
+func List(ctx context.Context, endpoint string, token func() (string, error)) ([]Definition, error) {
+	var r response
+	if err := api.GetJSON(ctx, endpoint, token, e2mPath, nil, &r); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(r.Definitions, func(i, j int) bool { return r.Definitions[i].Name < r.Definitions[j].Name })
+
+	return r.Definitions, nil
+}
+
+// Get definition by its ID
+func Get(ctx context.Context, endpoint string, token func() (string, error), id string) (Definition, error) {
+	var d Definition
+	err := api.GetJSON(ctx, endpoint, token, e2mPath+"/"+id, nil, &d)
+
+	return d, err
+}
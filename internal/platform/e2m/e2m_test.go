@@ -0,0 +1,56 @@
+package e2m
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/wooyey/iclogs/tests"
+)
+
+const definition = `{"id":"e1","name":"latency","logs_query":{"lucene":"path:/pay","applicationname_filters":["shop"]},
+"metric_labels":[{"target_label":"status","source_field":"status_code"}],
+"metric_fields":[{"target_base_metric_name":"pay_latency","source_field":"took","aggregations":[{"enabled":true,"agg_type":"max","target_metric_name":"pay_latency_max"}]}]}`
+
+func TestListAndGet(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case e2mPath:
+			w.Write([]byte(`{"events2metrics":[{"id":"e2","name":"errors"},` + definition + `]}`))
+		case e2mPath + "/e1":
+			w.Write([]byte(definition))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	defs, err := List(context.Background(), server.URL, tests.TokenOf("Good_Token"))
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	if len(defs) != 2 || defs[0].Name != "errors" || defs[1].Name != "latency" {
+		t.Fatalf("Expected definitions sorted by name, got: %v", defs)
+	}
+
+	d, err := Get(context.Background(), server.URL, tests.TokenOf("Good_Token"), "e1")
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	want := Definition{
+		ID:     "e1",
+		Name:   "latency",
+		Query:  Query{Lucene: "path:/pay", Applications: []string{"shop"}},
+		Labels: []Label{{Target: "status", Source: "status_code"}},
+		Fields: []Field{{BaseMetric: "pay_latency", Source: "took", Aggregations: []Aggregation{{Enabled: true, Type: "max", Metric: "pay_latency_max"}}}},
+	}
+	if !reflect.DeepEqual(d, want) {
+		t.Errorf("Got: %+v, want: %+v", d, want)
+	}
+
+	if _, err := Get(context.Background(), server.URL, tests.TokenOf("Good_Token"), "missing"); err == nil {
+		t.Error("Expected error of missing definition")
+	}
+}
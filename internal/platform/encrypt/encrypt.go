@@ -0,0 +1,62 @@
+// Package encrypt to encrypt written data with external tools, like age or GnuPG
+package encrypt
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"github.com/wooyey/iclogs/internal/platform/stdin"
+)
+
+// Encrypter wraps writer, so data written to it lands encrypted in the underlying one
+type Encrypter interface {
+	// Writer needs to be closed to flush encrypted data
+	Writer(w io.Writer) (io.WriteCloser, error)
+	// Extension of encrypted file, ie. `.age`
+	Extension() string
+}
+
+// Encryption methods by name, each creates encrypter from method argument
+var methods = map[string]func(arg string) Encrypter{
+	"age": func(recipients string) Encrypter {
+		return &command{name: "age", args: []string{"--encrypt", "--recipients-file", recipients}, ext: ".age"}
+	},
+	"gpg": func(recipient string) Encrypter {
+		return &command{name: "gpg", args: []string{"--batch", "--yes", "--trust-model", "always", "--encrypt", "--recipient", recipient}, ext: ".gpg"}
+	},
+}
+
+// New encrypter from `method:argument` spec, ie. `age:recipients.txt` or `gpg:security@example.com`
+func New(spec string) (Encrypter, error) {
+	name, arg, ok := strings.Cut(spec, ":")
+	if !ok || arg == "" {
+		return nil, fmt.Errorf("encryption needs to be in method:argument format, got '%s'", spec)
+	}
+
+	m, ok := methods[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown encryption method '%s'", name)
+	}
+
+	return m(arg), nil
+}
+
+// Encryption with external command reading plain data from stdin and writing encrypted one to stdout
+type command struct {
+	name string
+	args []string
+	ext  string
+}
+
+func (c *command) Extension() string {
+	return c.ext
+}
+
+func (c *command) Writer(w io.Writer) (io.WriteCloser, error) {
+	cmd := exec.Command(c.name, c.args...)
+	cmd.Stdout = w
+
+	return stdin.Writer(cmd, c.name)
+}
@@ -0,0 +1,73 @@
+package encrypt
+
+import (
+	"bytes"
+	"io"
+	"os/exec"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+
+	testCases := []struct {
+		name string
+		spec string
+		ext  string
+		err  bool
+	}{
+		{name: "Age", spec: "age:recipients.txt", ext: ".age"},
+		{name: "GPG", spec: "gpg:security@example.com", ext: ".gpg"},
+		{name: "Unknown", spec: "zip:secret", err: true},
+		{name: "MissingArgument", spec: "age", err: true},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New(tt.spec)
+			if tt.err != (err != nil) {
+				t.Fatalf("Want error: %v, got: '%v'", tt.err, err)
+			}
+			if err == nil && got.Extension() != tt.ext {
+				t.Errorf("Got extension: %s, want: %s", got.Extension(), tt.ext)
+			}
+		})
+	}
+}
+
+func TestCommandWriter(t *testing.T) {
+	if _, err := exec.LookPath("tr"); err != nil {
+		t.Skip("tr command not available")
+	}
+
+	// Fake encryption with upper casing
+	c := &command{name: "tr", args: []string{"a-z", "A-Z"}}
+
+	out := bytes.Buffer{}
+	w, err := c.Writer(&out)
+	if err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+	io.WriteString(w, "secret data\n")
+	if err = w.Close(); err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+
+	if out.String() != "SECRET DATA\n" {
+		t.Errorf("Got: %q", out.String())
+	}
+}
+
+func TestCommandWriterFailure(t *testing.T) {
+	if _, err := exec.LookPath("false"); err != nil {
+		t.Skip("false command not available")
+	}
+
+	w, err := (&command{name: "false"}).Writer(io.Discard)
+	if err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+	w.Write([]byte("data"))
+	if err = w.Close(); err == nil {
+		t.Error("Should get an error for failed command!")
+	}
+}
@@ -0,0 +1,184 @@
+// Package endpoint to use private service endpoints of IBM Cloud, reachable only from IBM Cloud private network
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	privateLabel = "private"
+	httpPort     = "80"
+	httpsPort    = "443"
+)
+
+// Service labels of logs instance hostnames, private label follows them, ie. `<id>.api.private.<region>.logs.cloud.ibm.com`
+var serviceLabels = []string{"api", "ingress"}
+
+var DialTimeout = time.Duration(5) * time.Second // Connectivity check timeout - default 5 seconds
+
+var WarmTimeout = time.Duration(30) * time.Second // Connection warm-up timeout, covering TLS handshake through proxy - default 30 seconds
+
+// UnreachableError of private endpoint, explaining it is reachable only from private network
+type UnreachableError struct {
+	Host string
+	Err  error
+}
+
+func (e *UnreachableError) Error() string {
+	return fmt.Sprintf("private endpoint %s is unreachable from current network, it is reachable only from IBM Cloud "+
+		"(VPC with virtual private endpoint or classic infrastructure), connect there or use public endpoint: %v", e.Host, e.Err)
+}
+
+func (e *UnreachableError) Unwrap() error {
+	return e.Err
+}
+
+// Private returns private endpoint URL of public logs, ingestion or IAM endpoint, private ones are kept
+func Private(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("cannot parse endpoint: %w", err)
+	}
+	if u.Host == "" || IsPrivate(endpoint) {
+		return endpoint, nil
+	}
+
+	parts := strings.Split(u.Hostname(), ".")
+	switch i := slices.IndexFunc(parts, func(p string) bool { return slices.Contains(serviceLabels, p) }); {
+	case i > 0:
+		parts = slices.Insert(parts, i+1, privateLabel)
+	case parts[0] == "iam":
+		parts = slices.Insert(parts, 0, privateLabel)
+	default:
+		return "", fmt.Errorf("cannot derive private endpoint from '%s', give it explicitly", endpoint)
+	}
+
+	host := strings.Join(parts, ".")
+	if port := u.Port(); port != "" {
+		host = net.JoinHostPort(host, port)
+	}
+	u.Host = host
+
+	return u.String(), nil
+}
+
+// IsPrivate tells if endpoint URL is private one
+func IsPrivate(endpoint string) bool {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return false
+	}
+
+	return isPrivateHost(u.Hostname())
+}
+
+func isPrivateHost(host string) bool {
+	return slices.Contains(strings.Split(host, "."), privateLabel)
+}
+
+// Explain failed connections of transport to private endpoints with UnreachableError
+func Explain(t *http.Transport) {
+	dial := t.DialContext
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+
+	t.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		return conn, explain(addr, err)
+	}
+}
+
+func explain(addr string, err error) error {
+	host, _, splitErr := net.SplitHostPort(addr)
+	if splitErr != nil {
+		host = addr
+	}
+
+	var unreachable *UnreachableError
+	if err == nil || !isPrivateHost(host) || errors.As(err, &unreachable) {
+		return err
+	}
+
+	return &UnreachableError{Host: host, Err: err}
+}
+
+// ConnectFailed tells if error is failure to resolve or connect to endpoint host, before any request was sent
+func ConnectFailed(err error) bool {
+	var (
+		unreachable *UnreachableError
+		dns         *net.DNSError
+		op          *net.OpError
+	)
+
+	switch {
+	case errors.As(err, &unreachable), errors.As(err, &dns):
+		return true
+	case errors.As(err, &op):
+		return op.Op == "dial"
+	}
+
+	return false
+}
+
+// Warm establishes connection of transport to host of endpoint URL with HEAD request, so it is kept open for
+// next requests, response status does not matter
+func Warm(ctx context.Context, t http.RoundTripper, endpoint string) error {
+	ctx, cancel := context.WithTimeout(ctx, WarmTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("cannot create HEAD request: %w", err)
+	}
+
+	resp, err := (&http.Client{Transport: t}).Do(req)
+	if err != nil {
+		return err
+	}
+	io.Copy(io.Discard, resp.Body)
+
+	return resp.Body.Close()
+}
+
+// Check resolves and connects to host of endpoint URL within DialTimeout, returning connection time
+func Check(ctx context.Context, endpoint string) (time.Duration, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return 0, fmt.Errorf("cannot parse endpoint: %w", err)
+	}
+	if u.Hostname() == "" {
+		return 0, fmt.Errorf("endpoint '%s' has no host", endpoint)
+	}
+
+	port := u.Port()
+	switch {
+	case port != "":
+	case u.Scheme == "http":
+		port = httpPort
+	default:
+		port = httpsPort
+	}
+	addr := net.JoinHostPort(u.Hostname(), port)
+
+	ctx, cancel := context.WithTimeout(ctx, DialTimeout)
+	defer cancel()
+
+	start := time.Now()
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return 0, explain(addr, err)
+	}
+	conn.Close()
+
+	return time.Since(start), nil
+}
@@ -0,0 +1,138 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPrivate(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+		want  string
+		err   bool
+	}{
+		{name: "Logs", input: "https://abc.api.eu-de.logs.cloud.ibm.com", want: "https://abc.api.private.eu-de.logs.cloud.ibm.com"},
+		{name: "Ingress", input: "https://abc.ingress.eu-de.logs.cloud.ibm.com/logs", want: "https://abc.ingress.private.eu-de.logs.cloud.ibm.com/logs"},
+		{name: "IAM", input: "https://iam.cloud.ibm.com", want: "https://private.iam.cloud.ibm.com"},
+		{name: "Port", input: "https://iam.test.cloud.ibm.com:8443", want: "https://private.iam.test.cloud.ibm.com:8443"},
+		{name: "AlreadyPrivate", input: "https://private.iam.cloud.ibm.com", want: "https://private.iam.cloud.ibm.com"},
+		{name: "Empty", input: "", want: ""},
+		{name: "Unknown", input: "https://logs.example.com", err: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Private(tc.input)
+			if (err != nil) != tc.err {
+				t.Fatalf("Expected error: %v, got: %v", tc.err, err)
+			}
+			if got != tc.want {
+				t.Errorf("Got: %s, want: %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestExplain(t *testing.T) {
+	failed := errors.New("connection refused")
+
+	var unreachable *UnreachableError
+	if err := explain("abc.api.private.eu-de.logs.cloud.ibm.com:443", failed); !errors.As(err, &unreachable) || !errors.Is(err, failed) {
+		t.Errorf("Expected unreachable private endpoint error, got: %v", err)
+	}
+	if err := explain("abc.api.eu-de.logs.cloud.ibm.com:443", failed); err != failed {
+		t.Errorf("Expected public endpoint error unchanged, got: %v", err)
+	}
+	if err := explain("private.iam.cloud.ibm.com:443", nil); err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	// Transport dialing private host gets explained error
+	tr := &http.Transport{DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) { return nil, failed }}
+	Explain(tr)
+	c := http.Client{Transport: tr}
+	if _, err := c.Get("https://private.iam.cloud.ibm.com"); !errors.As(err, &unreachable) {
+		t.Errorf("Expected unreachable private endpoint error, got: %v", err)
+	}
+}
+
+func TestConnectFailed(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "Unreachable", err: &UnreachableError{Host: "private.iam.cloud.ibm.com", Err: errors.New("timeout")}, want: true},
+		{name: "DNS", err: fmt.Errorf("cannot query: %w", &net.DNSError{Name: "abc.api.eu-de.logs.cloud.ibm.com"}), want: true},
+		{name: "Dial", err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}, want: true},
+		{name: "Read", err: &net.OpError{Op: "read", Err: errors.New("connection reset")}, want: false},
+		{name: "Other", err: errors.New("got HTTP error code: 500"), want: false},
+		{name: "None", err: nil, want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := ConnectFailed(tc.err); got != tc.want {
+				t.Errorf("Got: %v, want: %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	if _, err := Check(context.Background(), server.URL); err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	addr := server.Listener.Addr().String()
+	server.Close()
+	if _, err := Check(context.Background(), "http://"+addr); err == nil {
+		t.Error("Expected error of closed endpoint")
+	}
+
+	if _, err := Check(context.Background(), "/no/host"); err == nil {
+		t.Error("Expected error of endpoint without host")
+	}
+}
+
+func TestWarm(t *testing.T) {
+	conns := 0
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	srv.Config.ConnState = func(c net.Conn, s http.ConnState) {
+		if s == http.StateNew {
+			conns++
+		}
+	}
+	srv.StartTLS()
+	defer srv.Close()
+
+	tr := srv.Client().Transport
+	if err := Warm(context.Background(), tr, srv.URL); err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+
+	// Request after warm-up reuses its connection
+	resp, err := (&http.Client{Transport: tr}).Get(srv.URL + "/v1/query")
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if conns != 1 {
+		t.Errorf("Got %d connections, want: 1", conns)
+	}
+
+	if err := Warm(context.Background(), tr, "https://\x00"); err == nil {
+		t.Error("Expected error of invalid endpoint")
+	}
+}
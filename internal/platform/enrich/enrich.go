@@ -0,0 +1,98 @@
+// Package enrich to join local lookup tables onto log records
+package enrich
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Key of object added to user data with enriched fields
+const Key = "enrichment"
+
+var errNotObject = errors.New("user data is not a JSON object")
+
+// Table maps lookup key value to fields added to record
+type Table map[string]map[string]string
+
+// Load lookup table from CSV (first column is the key, header row names fields) or JSON file
+// (object with key values as names and objects of fields as values)
+func Load(path string) (Table, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open lookup table: %w", err)
+	}
+	defer f.Close()
+
+	t := make(Table)
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		raw := make(map[string]map[string]any)
+		if err := json.NewDecoder(f).Decode(&raw); err != nil {
+			return nil, fmt.Errorf("cannot decode lookup table '%s': %w", path, err)
+		}
+
+		for k, fields := range raw {
+			t[k] = make(map[string]string, len(fields))
+			for n, v := range fields {
+				t[k][n] = fmt.Sprint(v)
+			}
+		}
+
+		return t, nil
+	}
+
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read lookup table '%s': %w", path, err)
+	}
+
+	if len(rows) == 0 || len(rows[0]) < 2 {
+		return nil, fmt.Errorf("lookup table '%s' needs header with key and at least one field column", path)
+	}
+
+	header := rows[0]
+	for _, row := range rows[1:] {
+		fields := make(map[string]string, len(header)-1)
+		for i := 1; i < len(header); i++ {
+			fields[header[i]] = row[i]
+		}
+		t[row[0]] = fields
+	}
+
+	return t, nil
+}
+
+// Apply adds fields found for key value to user data JSON object
+func (t Table) Apply(userData, value string) (string, error) {
+	fields, ok := t[value]
+	if !ok {
+		return userData, nil
+	}
+
+	return Add(userData, Key, fields)
+}
+
+// Add fields as object with given name to user data JSON object, original formatting is kept
+func Add(userData, name string, fields map[string]string) (string, error) {
+	j, err := json.Marshal(map[string]any{name: fields})
+	if err != nil {
+		return userData, fmt.Errorf("cannot marshal enriched fields: %w", err)
+	}
+
+	ud := strings.TrimSpace(userData)
+	if !strings.HasPrefix(ud, "{") || !strings.HasSuffix(ud, "}") {
+		return userData, errNotObject
+	}
+
+	body := strings.TrimSpace(ud[1 : len(ud)-1])
+	if body == "" {
+		return string(j), nil
+	}
+
+	return "{" + body + "," + string(j[1:]), nil
+}
@@ -0,0 +1,83 @@
+package enrich
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+var table = Table{
+	"10.10.10.10": {"datacenter": "dal10", "owner": "team-a"},
+}
+
+func writeFile(t testing.TB, name, content string) string {
+	t.Helper()
+
+	p := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+		t.Fatalf("Cannot write file: %v", err)
+	}
+
+	return p
+}
+
+func TestLoad(t *testing.T) {
+
+	testCases := []struct {
+		name    string
+		file    string
+		content string
+		want    Table
+		err     bool
+	}{
+		{name: "CSV", file: "nodes.csv", content: "node,datacenter,owner\n10.10.10.10,dal10,team-a\n", want: table, err: false},
+		{name: "JSON", file: "nodes.json", content: `{"10.10.10.10": {"datacenter": "dal10", "owner": "team-a"}}`, want: table, err: false},
+		{name: "CSVNoFields", file: "nodes.csv", content: "node\n10.10.10.10\n", want: nil, err: true},
+		{name: "BrokenJSON", file: "nodes.json", content: `{"10.10.10.10": `, want: nil, err: true},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Load(writeFile(t, tt.file, tt.content))
+
+			if tt.err != (err != nil) {
+				t.Fatalf("Want error: %v, got: '%v'", tt.err, err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("\nGot:\t%+v\nWant:\t%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+
+	testCases := []struct {
+		name     string
+		userData string
+		value    string
+		want     string
+		err      bool
+	}{
+		{name: "Enriched", userData: `{"node_name": "10.10.10.10"}`, value: "10.10.10.10", want: `{"node_name": "10.10.10.10","enrichment":{"datacenter":"dal10","owner":"team-a"}}`, err: false},
+		{name: "NotFound", userData: `{"node_name": "10.1.1.1"}`, value: "10.1.1.1", want: `{"node_name": "10.1.1.1"}`, err: false},
+		{name: "EmptyObject", userData: `{ }`, value: "10.10.10.10", want: `{"enrichment":{"datacenter":"dal10","owner":"team-a"}}`, err: false},
+		{name: "NotObject", userData: `"text"`, value: "10.10.10.10", want: `"text"`, err: true},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := table.Apply(tt.userData, tt.value)
+
+			if tt.err != (err != nil) {
+				t.Fatalf("Want error: %v, got: '%v'", tt.err, err)
+			}
+
+			if got != tt.want {
+				t.Errorf("\nGot:\t%s\nWant:\t%s", got, tt.want)
+			}
+		})
+	}
+}
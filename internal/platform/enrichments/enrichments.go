@@ -0,0 +1,61 @@
+// Package enrichments to read enrichments of IBM Cloud Logs instance, which add data to records with given field
+package enrichments
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	"github.com/wooyey/iclogs/internal/platform/api"
+)
+
+const enrichmentsPath = "/v1/enrichments"
+
+// Enrichment types
+const (
+	GeoIP        = "geo_ip"
+	SuspiciousIP = "suspicious_ip"
+	Custom       = "custom_enrichment"
+)
+
+// Enrichment of records with field, ie. GeoIP of `client_ip`
+type Enrichment struct {
+	ID    int    `json:"id"`
+	Field string `json:"field_name"`
+	Type  struct {
+		GeoIP        *struct{} `json:"geo_ip"`
+		SuspiciousIP *struct{} `json:"suspicious_ip"`
+		Custom       *struct {
+			ID int `json:"id"`
+		} `json:"custom_enrichment"`
+	} `json:"enrichment_type"`
+}
+
+// Kind returns enrichment type name, custom enrichment with its ID, ie. `custom_enrichment:3`
+func (e Enrichment) Kind() string {
+	switch {
+	case e.Type.GeoIP != nil:
+		return GeoIP
+	case e.Type.SuspiciousIP != nil:
+		return SuspiciousIP
+	case e.Type.Custom != nil:
+		return Custom + ":" + strconv.Itoa(e.Type.Custom.ID)
+	default:
+		return "unknown"
+	}
+}
+
+type response struct {
+	Enrichments []Enrichment `json:"enrichments"`
+}
+
+// List enrichments of the instance sorted by field
+func List(ctx context.Context, endpoint string, token func() (string, error)) ([]Enrichment, error) {
+	var r response
+	if err := api.GetJSON(ctx, endpoint, token, enrichmentsPath, nil, &r); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(r.Enrichments, func(i, j int) bool { return r.Enrichments[i].Field < r.Enrichments[j].Field })
+
+	return r.Enrichments, nil
+}
@@ -0,0 +1,51 @@
+package enrichments
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/wooyey/iclogs/tests"
+)
+
+const enrichmentsResponse = `{"enrichments":[
+{"id":2,"field_name":"src_ip","enrichment_type":{"suspicious_ip":{}}},
+{"id":1,"field_name":"client_ip","enrichment_type":{"geo_ip":{}}},
+{"id":3,"field_name":"user_id","enrichment_type":{"custom_enrichment":{"id":7}}}
+]}`
+
+func TestList(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != enrichmentsPath || r.Header.Get("authorization") != "Bearer Good_Token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(enrichmentsResponse))
+	}))
+	defer server.Close()
+
+	e, err := List(context.Background(), server.URL, tests.TokenOf("Good_Token"))
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+
+	want := []struct{ field, kind string }{
+		{field: "client_ip", kind: GeoIP},
+		{field: "src_ip", kind: SuspiciousIP},
+		{field: "user_id", kind: "custom_enrichment:7"},
+	}
+	if len(e) != len(want) {
+		t.Fatalf("Got %d enrichments, want: %d", len(e), len(want))
+	}
+	for i, w := range want {
+		if e[i].Field != w.field || e[i].Kind() != w.kind {
+			t.Errorf("Got: %s %s, want: %s %s", e[i].Field, e[i].Kind(), w.field, w.kind)
+		}
+	}
+
+	if _, err := List(context.Background(), server.URL, tests.TokenOf("Bad_Token")); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("Expected HTTP error, got: %v", err)
+	}
+}
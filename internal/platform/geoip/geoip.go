@@ -0,0 +1,355 @@
+// Package geoip to look up IP addresses in MaxMind DB (mmdb) files, ie. GeoLite2 Country or ASN
+package geoip
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"net/netip"
+	"os"
+)
+
+// MaxMind DB format types
+const (
+	typeExtended = iota
+	typePointer
+	typeString
+	typeDouble
+	typeBytes
+	typeUint16
+	typeUint32
+	typeMap
+	typeInt32
+	typeUint64
+	typeUint128
+	typeArray
+	typeContainer
+	typeEndMarker
+	typeBool
+	typeFloat
+)
+
+const dataSeparatorSize = 16
+
+var metadataMarker = []byte("\xab\xcd\xefMaxMind.com")
+
+var (
+	errNoMetadata = errors.New("cannot find MaxMind DB metadata")
+	errCorrupted  = errors.New("MaxMind DB data is corrupted")
+)
+
+// Reader of MaxMind DB loaded into memory
+type Reader struct {
+	buf        []byte
+	data       []byte // data section
+	nodeCount  uint
+	recordSize uint
+	ipVersion  uint
+	ipv4Start  uint
+}
+
+type decoder struct {
+	data []byte
+}
+
+// Open loads MaxMind DB file
+func Open(path string) (*Reader, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read MaxMind DB: %w", err)
+	}
+
+	return New(buf)
+}
+
+// New creates Reader from MaxMind DB content
+func New(buf []byte) (*Reader, error) {
+	i := bytes.LastIndex(buf, metadataMarker)
+	if i < 0 {
+		return nil, errNoMetadata
+	}
+
+	d := decoder{buf[i+len(metadataMarker):]}
+	v, _, err := d.decode(0)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode metadata: %w", err)
+	}
+
+	meta, ok := v.(map[string]any)
+	if !ok {
+		return nil, errNoMetadata
+	}
+
+	r := &Reader{buf: buf}
+	r.nodeCount = toUint(meta["node_count"])
+	r.recordSize = toUint(meta["record_size"])
+	r.ipVersion = toUint(meta["ip_version"])
+
+	if r.recordSize != 24 && r.recordSize != 28 && r.recordSize != 32 {
+		return nil, fmt.Errorf("unsupported record size: %d", r.recordSize)
+	}
+
+	treeSize := r.nodeCount * r.recordSize / 4
+	if treeSize+dataSeparatorSize > uint(i) {
+		return nil, errCorrupted
+	}
+	r.data = buf[treeSize+dataSeparatorSize : i]
+
+	// IPv4 addresses live in IPv6 tree after 96 zero bits
+	if r.ipVersion == 6 {
+		for n := 0; n < 96 && r.ipv4Start < r.nodeCount; n++ {
+			if r.ipv4Start, err = r.record(r.ipv4Start, 0); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return r, nil
+}
+
+func toUint(v any) uint {
+	switch n := v.(type) {
+	case uint64:
+		return uint(n)
+	}
+	return 0
+}
+
+// Read left (bit 0) or right (bit 1) record of search tree node
+func (r *Reader) record(node uint, bit byte) (uint, error) {
+	size := r.recordSize / 4 // node size in bytes
+	off := node * size
+	if off+size > uint(len(r.buf)) {
+		return 0, errCorrupted
+	}
+	b := r.buf[off : off+size]
+
+	switch r.recordSize {
+	case 24:
+		b = b[3*uint(bit):]
+		return uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2]), nil
+	case 28:
+		if bit == 0 {
+			return uint(b[3]&0xf0)<<20 | uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2]), nil
+		}
+		return uint(b[3]&0x0f)<<24 | uint(b[4])<<16 | uint(b[5])<<8 | uint(b[6]), nil
+	default:
+		return uint(binary.BigEndian.Uint32(b[4*uint(bit):])), nil
+	}
+}
+
+// Lookup returns record for IP address, second value reports if address was found
+func (r *Reader) Lookup(ip netip.Addr) (map[string]any, bool, error) {
+	ip = ip.Unmap()
+
+	node := uint(0)
+	if ip.Is4() && r.ipVersion == 6 {
+		node = r.ipv4Start
+	}
+	if ip.Is6() && r.ipVersion == 4 {
+		return nil, false, fmt.Errorf("cannot look up IPv6 address in IPv4 database")
+	}
+
+	addr := ip.AsSlice()
+	for i := 0; i < len(addr)*8 && node < r.nodeCount; i++ {
+		bit := addr[i/8] >> (7 - i%8) & 1
+
+		var err error
+		if node, err = r.record(node, bit); err != nil {
+			return nil, false, err
+		}
+	}
+
+	if node <= r.nodeCount {
+		return nil, false, nil
+	}
+
+	off := node - r.nodeCount - dataSeparatorSize
+	d := decoder{r.data}
+	v, _, err := d.decode(off)
+	if err != nil {
+		return nil, false, err
+	}
+
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil, false, errCorrupted
+	}
+
+	return m, true, nil
+}
+
+func (d *decoder) bytes(off, n uint) ([]byte, error) {
+	if off+n > uint(len(d.data)) {
+		return nil, errCorrupted
+	}
+	return d.data[off : off+n], nil
+}
+
+func uintFrom(b []byte) uint64 {
+	var v uint64
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+	return v
+}
+
+// Decode value at offset, returns value and offset of next one
+func (d *decoder) decode(off uint) (any, uint, error) {
+	ctrl, err := d.bytes(off, 1)
+	if err != nil {
+		return nil, 0, err
+	}
+	off++
+
+	typ := uint(ctrl[0] >> 5)
+
+	if typ == typePointer {
+		ss := uint(ctrl[0]>>3) & 0x3
+		b, err := d.bytes(off, ss+1)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		p := uint(uintFrom(b))
+		switch ss {
+		case 0:
+			p |= uint(ctrl[0]&0x7) << 8
+		case 1:
+			p = (p | uint(ctrl[0]&0x7)<<16) + 2048
+		case 2:
+			p = (p | uint(ctrl[0]&0x7)<<24) + 526336
+		}
+
+		v, _, err := d.decode(p)
+		return v, off + ss + 1, err
+	}
+
+	if typ == typeExtended {
+		b, err := d.bytes(off, 1)
+		if err != nil {
+			return nil, 0, err
+		}
+		typ = 7 + uint(b[0])
+		off++
+	}
+
+	size := uint(ctrl[0] & 0x1f)
+	if size >= 29 {
+		n := size - 28
+		b, err := d.bytes(off, n)
+		if err != nil {
+			return nil, 0, err
+		}
+		off += n
+
+		switch n {
+		case 1:
+			size = 29 + uint(uintFrom(b))
+		case 2:
+			size = 285 + uint(uintFrom(b))
+		default:
+			size = 65821 + uint(uintFrom(b))
+		}
+	}
+
+	switch typ {
+	case typeMap:
+		m := make(map[string]any, size)
+		for range size {
+			k, next, err := d.decode(off)
+			if err != nil {
+				return nil, 0, err
+			}
+			key, ok := k.(string)
+			if !ok {
+				return nil, 0, errCorrupted
+			}
+
+			v, next, err := d.decode(next)
+			if err != nil {
+				return nil, 0, err
+			}
+
+			m[key] = v
+			off = next
+		}
+		return m, off, nil
+	case typeArray:
+		a := make([]any, 0, size)
+		for range size {
+			v, next, err := d.decode(off)
+			if err != nil {
+				return nil, 0, err
+			}
+			a = append(a, v)
+			off = next
+		}
+		return a, off, nil
+	case typeBool:
+		return size != 0, off, nil
+	case typeContainer, typeEndMarker:
+		return nil, off, nil
+	}
+
+	b, err := d.bytes(off, size)
+	if err != nil {
+		return nil, 0, err
+	}
+	off += size
+
+	switch typ {
+	case typeString:
+		return string(b), off, nil
+	case typeDouble:
+		if size != 8 {
+			return nil, 0, errCorrupted
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), off, nil
+	case typeFloat:
+		if size != 4 {
+			return nil, 0, errCorrupted
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), off, nil
+	case typeBytes:
+		return b, off, nil
+	case typeUint16, typeUint32, typeUint64:
+		return uintFrom(b), off, nil
+	case typeInt32:
+		return int64(int32(uint32(uintFrom(b)))), off, nil
+	case typeUint128:
+		return new(big.Int).SetBytes(b).String(), off, nil
+	}
+
+	return nil, 0, fmt.Errorf("unknown MaxMind DB data type: %d", typ)
+}
+
+// Summary picks country, city and autonomous system fields from lookup record
+func Summary(record map[string]any) map[string]string {
+	s := make(map[string]string)
+
+	pick := func(name string, path ...string) {
+		var v any = record
+		for _, p := range path {
+			m, ok := v.(map[string]any)
+			if !ok {
+				return
+			}
+			if v, ok = m[p]; !ok {
+				return
+			}
+		}
+		s[name] = fmt.Sprint(v)
+	}
+
+	pick("country", "registered_country", "iso_code")
+	pick("country", "country", "iso_code")
+	pick("city", "city", "names", "en")
+	pick("asn", "autonomous_system_number")
+	pick("as_org", "autonomous_system_organization")
+
+	return s
+}
@@ -0,0 +1,163 @@
+package geoip
+
+import (
+	"encoding/binary"
+	"net/netip"
+	"reflect"
+	"testing"
+)
+
+func encString(s string) []byte {
+	if len(s) < 29 {
+		return append([]byte{typeString<<5 | byte(len(s))}, s...)
+	}
+	return append([]byte{typeString<<5 | 29, byte(len(s) - 29)}, s...)
+}
+
+func encUint32(v uint32) []byte {
+	return binary.BigEndian.AppendUint32([]byte{typeUint32<<5 | 4}, v)
+}
+
+func encUint16(v uint16) []byte {
+	return binary.BigEndian.AppendUint16([]byte{typeUint16<<5 | 2}, v)
+}
+
+func encPointer(p uint16) []byte {
+	return []byte{typePointer<<5 | byte(p>>8), byte(p)}
+}
+
+func encMap(pairs ...[]byte) []byte {
+	b := []byte{typeMap<<5 | byte(len(pairs)/2)}
+	for _, p := range pairs {
+		b = append(b, p...)
+	}
+	return b
+}
+
+func putRecord(node []byte, size int, bit int, v uint32) {
+	switch size {
+	case 24:
+		b := node[3*bit:]
+		b[0], b[1], b[2] = byte(v>>16), byte(v>>8), byte(v)
+	case 28:
+		if bit == 0 {
+			node[0], node[1], node[2] = byte(v>>16), byte(v>>8), byte(v)
+			node[3] |= byte(v>>24) << 4
+		} else {
+			node[4], node[5], node[6] = byte(v>>16), byte(v>>8), byte(v)
+			node[3] |= byte(v>>24) & 0x0f
+		}
+	default:
+		binary.BigEndian.PutUint32(node[4*bit:], v)
+	}
+}
+
+// Build database with single network given by prefix bits
+func buildDB(prefix []int, ipVersion uint16, recordSize int) []byte {
+	nodeCount := uint32(len(prefix))
+	nodeSize := recordSize / 4
+
+	// Data section: shared string used by pointer, then the record
+	shared := encString("US")
+	record := encMap(
+		encString("country"), encMap(encString("iso_code"), encPointer(0)),
+		encString("autonomous_system_number"), encUint32(13335),
+		encString("autonomous_system_organization"), encString("CLOUDFLARENET"),
+	)
+	data := append(shared, record...)
+
+	tree := make([]byte, int(nodeCount)*nodeSize)
+	for i, bit := range prefix {
+		next := uint32(i + 1)
+		if i == len(prefix)-1 {
+			next = nodeCount + dataSeparatorSize + uint32(len(shared))
+		}
+
+		node := tree[i*nodeSize : (i+1)*nodeSize]
+		putRecord(node, recordSize, bit, next)
+		putRecord(node, recordSize, 1-bit, nodeCount)
+	}
+
+	meta := encMap(
+		encString("node_count"), encUint32(nodeCount),
+		encString("record_size"), encUint16(uint16(recordSize)),
+		encString("ip_version"), encUint16(ipVersion),
+	)
+
+	db := append(tree, make([]byte, dataSeparatorSize)...)
+	db = append(db, data...)
+	db = append(db, metadataMarker...)
+	return append(db, meta...)
+}
+
+// Bits of 10.0.0.0/8
+var prefix = []int{0, 0, 0, 0, 1, 0, 1, 0}
+
+func TestLookup(t *testing.T) {
+
+	v6prefix := append(make([]int, 96), prefix...)
+
+	want := map[string]any{
+		"country":                        map[string]any{"iso_code": "US"},
+		"autonomous_system_number":       uint64(13335),
+		"autonomous_system_organization": "CLOUDFLARENET",
+	}
+
+	testCases := []struct {
+		name  string
+		db    []byte
+		ip    string
+		found bool
+	}{
+		{name: "Record24", db: buildDB(prefix, 4, 24), ip: "10.1.2.3", found: true},
+		{name: "Record28", db: buildDB(prefix, 4, 28), ip: "10.1.2.3", found: true},
+		{name: "Record32", db: buildDB(prefix, 4, 32), ip: "10.1.2.3", found: true},
+		{name: "NotFound", db: buildDB(prefix, 4, 24), ip: "11.1.2.3", found: false},
+		{name: "IPv4InIPv6", db: buildDB(v6prefix, 6, 24), ip: "10.1.2.3", found: true},
+		{name: "IPv4MappedInIPv6", db: buildDB(v6prefix, 6, 28), ip: "::ffff:10.1.2.3", found: true},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := New(tt.db)
+			if err != nil {
+				t.Fatalf("Cannot open database: %v", err)
+			}
+
+			got, found, err := r.Lookup(netip.MustParseAddr(tt.ip))
+			if err != nil {
+				t.Fatalf("Got an error: '%v'", err)
+			}
+
+			if found != tt.found {
+				t.Fatalf("Want found: %v, got: %v", tt.found, found)
+			}
+
+			if found && !reflect.DeepEqual(got, want) {
+				t.Errorf("\nGot:\t%+v\nWant:\t%+v", got, want)
+			}
+		})
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	if _, err := New([]byte("not a database")); err == nil {
+		t.Error("Should get an error!")
+	}
+}
+
+func TestSummary(t *testing.T) {
+	record := map[string]any{
+		"country":                        map[string]any{"iso_code": "PL"},
+		"registered_country":             map[string]any{"iso_code": "DE"},
+		"city":                           map[string]any{"names": map[string]any{"en": "Warsaw"}},
+		"autonomous_system_number":       uint64(5617),
+		"autonomous_system_organization": "Orange Polska",
+	}
+
+	want := map[string]string{"country": "PL", "city": "Warsaw", "asn": "5617", "as_org": "Orange Polska"}
+
+	if got := Summary(record); !reflect.DeepEqual(got, want) {
+		t.Errorf("\nGot:\t%+v\nWant:\t%+v", got, want)
+	}
+}
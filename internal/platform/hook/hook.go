@@ -0,0 +1,58 @@
+// Package hook to run configured commands around query lifecycle, each receiving JSON context on stdin
+package hook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/audit"
+)
+
+// Query lifecycle events
+const (
+	PreQuery  = "pre_query"
+	PostQuery = "post_query"
+)
+
+var RunTimeout = time.Duration(30) * time.Second // Hook command timeout - default 30 seconds
+
+// Context given to hook, query details with lifecycle event name
+type Context struct {
+	Event string `json:"event"`
+	audit.Entry
+}
+
+// Run hook command with context as JSON on stdin, its output is passed to out.
+// Failing command (non-zero exit) is reported as error, so pre-query hook can stop the query.
+func Run(command []string, c Context, out io.Writer) error {
+	if len(command) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("cannot marshal hook context: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), RunTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = out
+
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s hook %s failed: %w: %s", c.Event, command[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package hook
+
+import (
+	"bytes"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/audit"
+)
+
+func TestRun(t *testing.T) {
+	if _, err := exec.LookPath("cat"); err != nil {
+		t.Skip("cat command not available")
+	}
+
+	c := Context{Event: PostQuery, Entry: audit.Entry{User: "jane", Time: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC), Query: "app:web", Count: 3}}
+
+	out := bytes.Buffer{}
+	if err := Run([]string{"cat"}, c, &out); err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+
+	want := `{"event":"post_query","user":"jane","timestamp":"2025-01-01T10:00:00Z","endpoint":"","query":"app:web",` +
+		`"start_date":"0001-01-01T00:00:00Z","end_date":"0001-01-01T00:00:00Z","result_count":3}`
+	if out.String() != want {
+		t.Errorf("\nGot:\t%s\nWant:\t%s", out.String(), want)
+	}
+}
+
+func TestRunFailure(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh command not available")
+	}
+
+	err := Run([]string{"sh", "-c", "echo missing ticket >&2; exit 1"}, Context{Event: PreQuery}, &bytes.Buffer{})
+	if err == nil {
+		t.Fatal("Should get an error for failed hook!")
+	}
+	if got := err.Error(); got != "pre_query hook sh failed: exit status 1: missing ticket" {
+		t.Errorf("Got: %s", got)
+	}
+
+	if err := Run(nil, Context{Event: PreQuery}, &bytes.Buffer{}); err != nil {
+		t.Errorf("Empty hook should be skipped, got: '%v'", err)
+	}
+}
@@ -0,0 +1,195 @@
+package i18n
+
+var de = map[string]string{
+	// Usage
+	"Usage of %s: [command] [options] <lucene query> [-- <lucene query> ...]": "Verwendung von %s: [Befehl] [Optionen] <Lucene-Abfrage> [-- <Lucene-Abfrage> ...]",
+	"Commands:":     "Befehle:",
+	"Options:":      "Optionen:",
+	" (default %s)": " (Standard: %s)",
+
+	// Commands
+	"Call management API of the instance with the same token, ie. GET /v1/alerts, printing response JSON. For endpoints without own command.":                                           "Management-API der Instanz mit demselben Token aufrufen, z. B. GET /v1/alerts, und das Antwort-JSON ausgeben. Für Endpunkte ohne eigenen Befehl.",
+	"Compare found records, without their ID and time, with --golden file in export format, exiting with status 1 on drift.":                                                            "Gefundene Datensätze ohne ihre ID und Zeit mit der --golden-Datei im Exportformat vergleichen, bei Abweichung mit Status 1 beenden.",
+	"Print identity, account, expiry and scopes of IAM token obtained for the API key.":                                                                                                 "Identität, Konto, Ablauf und Scopes des für den API-Schlüssel ausgestellten IAM-Tokens ausgeben.",
+	"Print issue-ready report with version, platform and the latest crash diagnostic report.":                                                                                           "Bericht für ein Issue mit Version, Plattform und dem neuesten Absturz-Diagnosebericht ausgeben.",
+	"Export profiles, saved queries, aliases and redactors (without audit settings) as bundle, or merge bundle into configuration file.":                                                "Profile, gespeicherte Abfragen, Aliase und Redaktoren (ohne Audit-Einstellungen) als Paket exportieren oder ein Paket in die Konfigurationsdatei übernehmen.",
+	"List profiles with their account and endpoints, print profile in use, or persist default profile like kubectl contexts.":                                                           "Profile mit ihrem Konto und ihren Endpunkten auflisten, das verwendete Profil ausgeben oder das Standardprofil wie kubectl-Kontexte festlegen.",
+	"Show terminal dashboard (rate per severity, top applications, latest errors) refreshed until interrupted.":                                                                         "Terminal-Dashboard (Rate je Schweregrad, häufigste Anwendungen, neueste Fehler) anzeigen, bis es unterbrochen wird.",
+	"Print reference of commands and options as man page or Markdown, generated from the same metadata as this usage.":                                                                  "Referenz der Befehle und Optionen als Manpage oder Markdown ausgeben, erzeugt aus denselben Metadaten wie diese Verwendung.",
+	"Check connectivity to IAM, logs and ingestion endpoints, explaining private endpoints unreachable from current network.":                                                           "Verbindung zu IAM-, Logs- und Ingestion-Endpunkten prüfen und aus dem aktuellen Netzwerk nicht erreichbare private Endpunkte erklären.",
+	"List events-to-metrics definitions of the instance with their query and metrics, or show one with its filters, labels and fields.":                                                 "Events-to-Metrics-Definitionen der Instanz mit ihrer Abfrage und ihren Metriken auflisten oder eine mit ihren Filtern, Labels und Feldern anzeigen.",
+	"List enrichments of the instance: enriched record fields with enrichment type (geo_ip, suspicious_ip or custom).":                                                                  "Anreicherungen der Instanz auflisten: angereicherte Datensatzfelder mit Anreicherungstyp (geo_ip, suspicious_ip oder custom).",
+	"Write found records as JSON lines or CSV files, one per time range chunk, optionally uploaded to object storage.":                                                                  "Gefundene Datensätze als JSON-Zeilen oder CSV-Dateien schreiben, eine pro Abschnitt des Zeitraums, optional in Object Storage hochgeladen.",
+	"Print one full record by its ID. Time range options need to cover record timestamp.":                                                                                               "Einen vollständigen Datensatz anhand seiner ID ausgeben. Die Zeitraumoptionen müssen den Zeitstempel des Datensatzes abdecken.",
+	"Send test record with --message through ingestion API of the logs instance, to verify ingestion and search end-to-end.":                                                            "Testdatensatz mit --message über die Ingestion-API der Logs-Instanz senden, um Ingestion und Suche durchgängig zu prüfen.",
+	"List distinct application and subsystem label values found in time range, or cached values of one label for shell completion.":                                                     "Unterschiedliche Werte der Labels application und subsystem im Zeitraum auflisten oder zwischengespeicherte Werte eines Labels für die Shell-Vervollständigung.",
+	"Serve read-only query, tail and stats tools over Model Context Protocol (stdio) within profile scope and time range.":                                                              "Schreibgeschützte Werkzeuge für Abfrage, Tail und Statistik über das Model Context Protocol (stdio) innerhalb des Profilbereichs und Zeitraums bereitstellen.",
+	"Open the search in IBM Cloud Logs dashboard using default browser.":                                                                                                                "Die Suche im IBM Cloud Logs-Dashboard im Standardbrowser öffnen.",
+	"List output sink plugins found in plugins directory, usable with --sink option.":                                                                                                   "Im Plugin-Verzeichnis gefundene Ausgabe-Plugins auflisten, verwendbar mit der Option --sink.",
+	"List TCO policies of the instance in evaluation order with tier (priority insights, analyze and alert, store and search) of matched records.":                                      "TCO-Richtlinien der Instanz in Auswertungsreihenfolge mit der Stufe (priority insights, analyze and alert, store and search) passender Datensätze auflisten.",
+	"Forward lines or NDJSON read from standard input to ingestion API of the logs instance, labeled with --app and --subsystem.":                                                       "Von der Standardeingabe gelesene Zeilen oder NDJSON an die Ingestion-API der Logs-Instanz weiterleiten, mit --app und --subsystem gekennzeichnet.",
+	"Serve web UI and REST API (/query and /tail with server-sent events) running searches within profile scope and time range.":                                                        "Web-UI und REST-API (/query und /tail mit Server-Sent Events) bereitstellen, die Suchen innerhalb des Profilbereichs und Zeitraums ausführen.",
+	"Tail --file paths and forward appended lines to ingestion API of the logs instance until interrupted, resuming from checkpointed positions.":                                       "--file-Pfade verfolgen und angehängte Zeilen bis zur Unterbrechung an die Ingestion-API der Logs-Instanz weiterleiten, fortgesetzt ab gespeicherten Positionen.",
+	"Serve Slack slash command (/slack/commands) and mentions (/slack/events) running saved queries allowed in configuration.":                                                          "Slack-Slash-Befehl (/slack/commands) und Erwähnungen (/slack/events) bereitstellen, die in der Konfiguration erlaubte gespeicherte Abfragen ausführen.",
+	"List built-in Dataprime snippets usable with --snippet option, with their parameters in braces.":                                                                                   "Eingebaute Dataprime-Snippets auflisten, verwendbar mit der Option --snippet, mit ihren Parametern in geschweiften Klammern.",
+	"Report incident window at once: records stats, timeline per severity, top applications (or other --group-by field) and top message patterns with their first and last occurrence.": "Vorfallszeitraum auf einmal auswerten: Datensatzstatistik, Zeitachse je Schweregrad, häufigste Anwendungen (oder anderes --group-by-Feld) und häufigste Nachrichtenmuster mit ihrem ersten und letzten Auftreten.",
+	"Report ingested GB per day and TCO policy tier from data usage API, last week by default.":                                                                                         "Aufgenommene GB pro Tag und TCO-Richtlinienstufe aus der Data-Usage-API ausgeben, standardmäßig für die letzte Woche.",
+	"Send uniquely tagged record through ingestion API and search for it until found or --verify-timeout, reporting end-to-end latency.":                                                "Eindeutig markierten Datensatz über die Ingestion-API senden und nach ihm suchen, bis er gefunden wird oder --verify-timeout abläuft, und die Latenz von Ende zu Ende melden.",
+	"Count records every refresh interval, notifying when count goes above threshold or trigger expression holds and when it clears.":                                                   "Datensätze in jedem Aktualisierungsintervall zählen und benachrichtigen, wenn die Anzahl den Schwellenwert überschreitet oder der Trigger-Ausdruck zutrifft und wenn dies endet.",
+	"List outbound integrations of the instance, or send test notification to URL of one, verifying alert notification channel.":                                                        "Ausgehende Integrationen der Instanz auflisten oder eine Testbenachrichtigung an die URL einer davon senden, um den Benachrichtigungskanal für Alarme zu prüfen.",
+
+	// Options
+	"Authorization Endpoint URL, or public, private or test IAM endpoint.":                                                                      "URL des Autorisierungsendpunkts oder public, private bzw. test als IAM-Endpunkt.",
+	"Show comma separated aggregations (sum, avg, min, max) of numeric fields instead of records, ie. avg(json.response_time),max(json.bytes).": "Kommagetrennte Aggregationen (sum, avg, min, max) numerischer Felder statt Datensätzen anzeigen, z. B. avg(json.response_time),max(json.bytes).",
+	"Snap time range start down and end up to multiples of interval, ie. 5m.":                                                                   "Beginn des Zeitraums ab- und Ende auf Vielfache des Intervalls aufrunden, z. B. 5m.",
+	"Application name of records sent by ingest, push, ship and verify-pipeline commands.":                                                      "Anwendungsname der von den Befehlen ingest, push, ship und verify-pipeline gesendeten Datensätze.",
+	"Configuration file path. Overrides ICLOGS_CONFIG environment variable.":                                                                    "Pfad der Konfigurationsdatei. Überschreibt die Umgebungsvariable ICLOGS_CONFIG.",
+	"Time to reuse serve command results of the same query and range, 0 disables cache.":                                                        "Dauer, für die Ergebnisse des serve-Befehls für dieselbe Abfrage und denselben Zeitraum wiederverwendet werden, 0 deaktiviert den Cache.",
+	"JSON file with read positions of ship command or state of watch command, in user cache directory by default.":                              "JSON-Datei mit Lesepositionen des ship-Befehls oder Zustand des watch-Befehls, standardmäßig im Cache-Verzeichnis des Benutzers.",
+	"Time range of one export command file, 0 means whole time range.":                                                                          "Zeitraum einer Datei des export-Befehls, 0 bedeutet den ganzen Zeitraum.",
+	"Show records compactly on narrow terminals: time of day, single character severity glyph, and shortened ID and labels when shown.":         "Datensätze auf schmalen Terminals kompakt anzeigen: Uhrzeit, einzelnes Zeichen für den Schweregrad sowie gekürzte ID und Labels, wenn angezeigt.",
+	"Copy printed records to system clipboard.":                                                                                                 "Ausgegebene Datensätze in die Zwischenablage des Systems kopieren.",
+	"Print usage command report as CSV, without totals row.":                                                                                    "Bericht des usage-Befehls als CSV ohne Summenzeile ausgeben.",
+	"Search the same time of day window from..to, ie. 03:00..03:30, on each of last days.":                                                      "Denselben Tageszeitraum von..bis, z. B. 03:00..03:30, an jedem der letzten Tage durchsuchen.",
+	"JSON request body of api command, @file reads it from file and @- from standard input.":                                                    "JSON-Anfragekörper des api-Befehls, @file liest ihn aus einer Datei und @- von der Standardeingabe.",
+	"Number of last days searched with daily window.":                                                                                           "Anzahl der mit dem täglichen Zeitfenster durchsuchten letzten Tage.",
+	"Time budget shared by all queries of one-shot run, ie. export chunks or windows, 0 means no limit. Each request keeps its own timeout, serve, mcp, slackbot, watch and dash commands are not limited.": "Zeitbudget aller Abfragen eines einmaligen Laufs, z. B. Exportabschnitte oder Zeitfenster, 0 bedeutet keine Begrenzung. Jede Anfrage behält ihr eigenes Timeout, die Befehle serve, mcp, slackbot, watch und dash werden nicht begrenzt.",
+	"Log debug messages like --verbose, with their source code location.":                                                                          "Debug-Meldungen wie --verbose mit ihrer Position im Quellcode protokollieren.",
+	"Comma separated user data paths removed from records before output and export, ie. kubernetes.annotations,tag,file.":                          "Kommagetrennte Pfade der Benutzerdaten, die vor Ausgabe und Export aus Datensätzen entfernt werden, z. B. kubernetes.annotations,tag,file.",
+	"Unit of extracted durations given without one.":                                                                                               "Einheit extrahierter Dauern, die ohne Einheit angegeben sind.",
+	"Encrypt export command files before writing with method age:<recipients file> or gpg:<recipient>.":                                            "Dateien des export-Befehls vor dem Schreiben mit der Methode age:<Empfängerdatei> oder gpg:<Empfänger> verschlüsseln.",
+	"CSV or JSON lookup table file joined onto records as enrichment user data object.":                                                            "CSV- oder JSON-Nachschlagetabelle, die als Objekt der Benutzerdaten zur Anreicherung an Datensätze angefügt wird.",
+	"Record field used as lookup key for enrichment, ie. json.node_name.":                                                                          "Datensatzfeld als Nachschlageschlüssel für die Anreicherung, z. B. json.node_name.",
+	"Print resolved query request, endpoint, tier, time window in UTC and token subject without running it, for support tickets.":                  "Aufgelöste Abfrageanfrage, Endpunkt, Stufe, Zeitfenster in UTC und Subjekt des Tokens ausgeben, ohne sie auszuführen, für Support-Tickets.",
+	"Format of export command files: ndjson or csv.":                                                                                               "Format der Dateien des export-Befehls: ndjson oder csv.",
+	"Regular expression with capture group matched on message (ie. 'took (\\d+)ms') or record field with duration.":                                "Regulärer Ausdruck mit Erfassungsgruppe, angewendet auf die Nachricht (z. B. 'took (\\d+)ms'), oder Datensatzfeld mit Dauer.",
+	"Start time for log search in format 2006-01-02T15:04, with optional seconds and their fraction, RFC3339 time, date, now, today or yesterday.": "Startzeit der Logsuche im Format 2006-01-02T15:04, mit optionalen Sekunden und deren Bruchteil, RFC3339-Zeit, Datum, now, today oder yesterday.",
+	"Logs endpoint URL failed over to when logs endpoint cannot be connected, ie. public one of private endpoint.":                                 "Logs-Endpunkt-URL, auf die umgeschaltet wird, wenn keine Verbindung zum Logs-Endpunkt möglich ist, z. B. der öffentliche des privaten Endpunkts.",
+	"Log path tailed by ship command. Can be repeated.":                                                                                            "Vom ship-Befehl verfolgter Logpfad. Kann wiederholt werden.",
+	"Rewrite user data as single level object with dotted keys, ie. kubernetes.labels.app, before output and export.":                              "Benutzerdaten vor Ausgabe und Export als einstufiges Objekt mit punktierten Schlüsseln umschreiben, z. B. kubernetes.labels.app.",
+	"Arrays handling mode of flattened user data: index (tags.0), join (comma separated string) or keep.":                                          "Behandlung von Arrays in abgeflachten Benutzerdaten: index (tags.0), join (kommagetrennte Zeichenkette) oder keep.",
+	"Receive query responses of any size without confirmation.":                                                                                    "Abfrageantworten beliebiger Größe ohne Bestätigung empfangen.",
+	"MaxMind DB file (ie. GeoLite2 Country or ASN) for GeoIP enrichment.":                                                                          "MaxMind-DB-Datei (z. B. GeoLite2 Country oder ASN) für die GeoIP-Anreicherung.",
+	"Record field with IP address for GeoIP enrichment, ie. json.client_ip.":                                                                       "Datensatzfeld mit IP-Adresse für die GeoIP-Anreicherung, z. B. json.client_ip.",
+	"Golden file of assert command with expected records in export format.":                                                                        "Golden-Datei des assert-Befehls mit erwarteten Datensätzen im Exportformat.",
+	"Record field to group records count and aggregations by, ie. json.service.":                                                                   "Datensatzfeld, nach dem Anzahl der Datensätze und Aggregationen gruppiert werden, z. B. json.service.",
+	"Show sparkline of records volume over time range next to each group.":                                                                         "Sparkline des Datensatzvolumens über den Zeitraum neben jeder Gruppe anzeigen.",
+	"Abort query only when no data, keepalives included, arrives for duration, instead of after 3 minutes request timeout.":                        "Abfrage erst abbrechen, wenn für diese Dauer keine Daten, einschließlich Keepalives, ankommen, statt nach dem Anfrage-Timeout von 3 Minuten.",
+	"Ingestion endpoint URL of ingest, push, ship and verify-pipeline commands, derived from logs endpoint by default.":                            "Ingestion-Endpunkt-URL der Befehle ingest, push, ship und verify-pipeline, standardmäßig vom Logs-Endpunkt abgeleitet.",
+	"Record field with IP address for network filter, ie. json.client_ip.":                                                                         "Datensatzfeld mit IP-Adresse für den Netzwerkfilter, z. B. json.client_ip.",
+	"Keep records with IP address within comma separated networks, ie. 10.0.0.0/8,192.168.0.0/16.":                                                 "Datensätze mit IP-Adresse innerhalb kommagetrennter Netzwerke behalten, z. B. 10.0.0.0/8,192.168.0.0/16.",
+	"Show record as JSON.": "Datensatz als JSON anzeigen.",
+	"API Key to use. Overrides LOG_API_KEY environment variable.":                                                                                                                                        "Zu verwendender API-Schlüssel. Überschreibt die Umgebungsvariable LOG_API_KEY.",
+	"URL of IBM Cloud Log Endpoint. Overrides LOGS_ENDPOINT environment variable.":                                                                                                                       "URL des IBM Cloud Logs-Endpunkts. Überschreibt die Umgebungsvariable LOGS_ENDPOINT.",
+	"Print link to the same search in IBM Cloud Logs dashboard.":                                                                                                                                         "Link zu derselben Suche im IBM Cloud Logs-Dashboard ausgeben.",
+	"Listen address of serve and slackbot commands.":                                                                                                                                                     "Lauschadresse der Befehle serve und slackbot.",
+	"Print, export or send records to sink as they arrive without keeping them, ordered only within received batches.":                                                                                   "Datensätze beim Eintreffen ausgeben, exportieren oder an ein Ausgabe-Plugin senden, ohne sie zu behalten, nur innerhalb empfangener Stapel sortiert.",
+	"Comma separated message field names.":                                                                                                                                                               "Kommagetrennte Namen der Nachrichtenfelder.",
+	"Limit reading of export command responses to rate, ie. 10MB/s or 512KiB/s, so exports don't saturate VPN links.":                                                                                    "Lesen der Antworten des export-Befehls auf eine Rate begrenzen, z. B. 10MB/s oder 512KiB/s, damit Exporte VPN-Verbindungen nicht auslasten.",
+	"Truncate displayed message or JSON longer than bytes, 0 means no limit.":                                                                                                                            "Angezeigte Nachricht oder angezeigtes JSON kürzen, wenn länger als die Anzahl Bytes, 0 bedeutet keine Begrenzung.",
+	"Query response size, ie. 500MB, over which search or export asks to continue on terminal, otherwise aborts. 1GiB by default.":                                                                       "Größe der Abfrageantwort, z. B. 500MB, ab der Suche oder Export im Terminal nachfragt, ob fortgefahren werden soll, sonst abbricht. Standardmäßig 1GiB.",
+	"Message text of record sent by ingest command, JSON object is sent as structured data.":                                                                                                             "Nachrichtentext des vom ingest-Befehl gesendeten Datensatzes, ein JSON-Objekt wird als strukturierte Daten gesendet.",
+	"Opsgenie API key to create and close alerts from watch command.":                                                                                                                                    "Opsgenie-API-Schlüssel zum Erstellen und Schließen von Alarmen durch den watch-Befehl.",
+	"Routing key of PagerDuty Events API integration to create and resolve incidents from watch command.":                                                                                                "Routing-Schlüssel der PagerDuty-Events-API-Integration zum Erstellen und Auflösen von Vorfällen durch den watch-Befehl.",
+	"Output directory of export command files.":                                                                                                                                                          "Ausgabeverzeichnis der Dateien des export-Befehls.",
+	"Run queries exceeding maximum range and records of the profile.":                                                                                                                                    "Abfragen ausführen, die maximalen Zeitraum und maximale Datensätze des Profils überschreiten.",
+	"Configuration profile to use. Overrides ICLOGS_PROFILE environment variable.":                                                                                                                       "Zu verwendendes Konfigurationsprofil. Überschreibt die Umgebungsvariable ICLOGS_PROFILE.",
+	"Snippet parameter as name=value, filling {name} placeholder. Can be repeated.":                                                                                                                      "Snippet-Parameter als name=value, füllt den Platzhalter {name}. Kann wiederholt werden.",
+	"Show message patterns, messages with numbers, IDs, addresses and times masked, with records count and first and last occurrence instead of records.":                                                "Nachrichtenmuster statt Datensätzen anzeigen, Nachrichten mit maskierten Zahlen, IDs, Adressen und Zeiten, mit Anzahl der Datensätze sowie erstem und letztem Auftreten.",
+	"Show percentiles of extracted durations instead of records.":                                                                                                                                        "Perzentile extrahierter Dauern statt Datensätzen anzeigen.",
+	"Precision unit of displayed times: s, ms, us or ns.":                                                                                                                                                "Genauigkeit angezeigter Zeiten: s, ms, us oder ns.",
+	"Use private endpoints of logs instance and IAM, reachable only from IBM Cloud private network.":                                                                                                     "Private Endpunkte der Logs-Instanz und von IAM verwenden, nur aus dem privaten Netzwerk von IBM Cloud erreichbar.",
+	"Write newline-delimited progress events (phase, shard, percent of shards done, records so far) to standard error in format, only json is supported.":                                                "Zeilenweise Fortschrittsereignisse (Phase, Abschnitt, Prozent erledigter Abschnitte, bisherige Datensätze) im angegebenen Format auf die Standardfehlerausgabe schreiben, nur json wird unterstützt.",
+	"Lucene query to run. Can be repeated, all queries are OR-combined.":                                                                                                                                 "Auszuführende Lucene-Abfrage. Kann wiederholt werden, alle Abfragen werden mit OR verknüpft.",
+	"Relative time for log search, before now or end time, or after start time when only it is given.":                                                                                                   "Relative Zeit der Logsuche, vor jetzt oder der Endzeit oder nach der Startzeit, wenn nur diese angegeben ist.",
+	"Maximum requests per minute from one client of serve command, 0 means no limit.":                                                                                                                    "Maximale Anfragen pro Minute eines Clients des serve-Befehls, 0 bedeutet keine Begrenzung.",
+	"Comma separated names of redactors hiding sensitive data (built-in: creditcard, email, ip).":                                                                                                        "Kommagetrennte Namen von Redaktoren, die sensible Daten verbergen (eingebaut: creditcard, email, ip).",
+	"Refresh interval of dash and watch commands and serve command tail.":                                                                                                                                "Aktualisierungsintervall der Befehle dash und watch sowie des Tails des serve-Befehls.",
+	"Fetch management API listings and label values instead of using cached ones.":                                                                                                                       "Listen der Management-API und Labelwerte neu abrufen, statt zwischengespeicherte zu verwenden.",
+	"Fetch shared saved queries from configured source instead of using cached copy.":                                                                                                                    "Gemeinsame gespeicherte Abfragen von der konfigurierten Quelle abrufen, statt die zwischengespeicherte Kopie zu verwenden.",
+	"Retries of failed export command query, each querying failed time window in halves.":                                                                                                                "Wiederholungen einer fehlgeschlagenen Abfrage des export-Befehls, die das fehlgeschlagene Zeitfenster jeweils in Hälften abfragen.",
+	"Run saved query with given name from configuration file, ANDed with given query.":                                                                                                                   "Gespeicherte Abfrage mit dem angegebenen Namen aus der Konfigurationsdatei ausführen, mit der angegebenen Abfrage per AND verknüpft.",
+	"Warn about records containing likely secrets.":                                                                                                                                                      "Vor Datensätzen warnen, die wahrscheinlich Geheimnisse enthalten.",
+	"JSON file with array of {\"name\", \"type\"} columns of CSV export, instead of columns inferred from records.":                                                                                      "JSON-Datei mit einem Array von Spalten {\"name\", \"type\"} des CSV-Exports, statt aus Datensätzen abgeleiteter Spalten.",
+	"Number of first records whose fields make columns of CSV export.":                                                                                                                                   "Anzahl der ersten Datensätze, deren Felder die Spalten des CSV-Exports bilden.",
+	"Pin endpoint, time window and token under session name on first use and reuse them in later runs with the same name.":                                                                               "Endpunkt, Zeitfenster und Token bei der ersten Verwendung unter dem Sitzungsnamen festhalten und in späteren Läufen mit demselben Namen wiederverwenden.",
+	"Severity name of records sent by ingest, push, ship and verify-pipeline commands: debug, verbose, info, warning, error or critical. JSON lines pushed with severity or level field keep their own.": "Schweregrad der von den Befehlen ingest, push, ship und verify-pipeline gesendeten Datensätze: debug, verbose, info, warning, error oder critical. Mit push gesendete JSON-Zeilen mit Feld severity oder level behalten ihren eigenen.",
+	"Show record ID.": "Datensatz-ID anzeigen.",
+	"Show only record label with given key, ie. applicationname. Can be repeated.": "Nur das Datensatzlabel mit dem angegebenen Schlüssel anzeigen, z. B. applicationname. Kann wiederholt werden.",
+	"Show record labels.":    "Datensatzlabels anzeigen.",
+	"Show record severity.":  "Schweregrad des Datensatzes anzeigen.",
+	"Show record timestamp.": "Zeitstempel des Datensatzes anzeigen.",
+	"Daily window as HH:MM-HH:MM or one-off from..to window when watch command does not notify, ie. maintenance. Silenced intervals are marked in interval lines. Can be repeated.": "Tägliches Zeitfenster als HH:MM-HH:MM oder einmaliges Zeitfenster von..bis, in dem der watch-Befehl nicht benachrichtigt, z. B. Wartung. Stummgeschaltete Intervalle werden in den Intervallzeilen markiert. Kann wiederholt werden.",
+	"File path with silence windows of watch command, one per line with # comments, read again each interval.":                                                                      "Dateipfad mit Stummschaltungsfenstern des watch-Befehls, eines pro Zeile mit #-Kommentaren, in jedem Intervall neu gelesen.",
+	"Send found records as JSON lines to sink plugin with given name instead of printing them.":                                                                                     "Gefundene Datensätze als JSON-Zeilen an das Ausgabe-Plugin mit dem angegebenen Namen senden, statt sie auszugeben.",
+	"Slack app signing secret. Overrides SLACK_SIGNING_SECRET environment variable.":                                                                                                "Signing Secret der Slack-App. Überschreibt die Umgebungsvariable SLACK_SIGNING_SECRET.",
+	"Slack bot token. Overrides SLACK_BOT_TOKEN environment variable.":                                                                                                              "Slack-Bot-Token. Überschreibt die Umgebungsvariable SLACK_BOT_TOKEN.",
+	"Run built-in Dataprime snippet name (see snippets command), query is then applied as Lucene stage before the snippet pipeline.":                                                "Eingebautes Dataprime-Snippet mit Namen ausführen (siehe Befehl snippets), die Abfrage wird dann als Lucene-Stufe vor der Snippet-Pipeline angewendet.",
+	"Object storage endpoint URL for upload, ie. https://s3.us-south.cloud-object-storage.appdomain.cloud.":                                                                         "Object-Storage-Endpunkt-URL für das Hochladen, z. B. https://s3.us-south.cloud-object-storage.appdomain.cloud.",
+	"Subsystem name of records sent by ingest, push, ship and verify-pipeline commands.":                                                                                            "Subsystemname der von den Befehlen ingest, push, ship und verify-pipeline gesendeten Datensätze.",
+	"Print records count, time range and timings of query phases to standard error.":                                                                                                "Anzahl der Datensätze, Zeitraum und Zeiten der Abfragephasen auf die Standardfehlerausgabe ausgeben.",
+	"End time for log search in range format 2006-01-02T15:04, with optional seconds and their fraction, RFC3339 time, date, now, today or yesterday.":                              "Endzeit der Logsuche im Bereichsformat 2006-01-02T15:04, mit optionalen Sekunden und deren Bruchteil, RFC3339-Zeit, Datum, now, today oder yesterday.",
+	"Records count per interval above which watch command triggers.":                                                                                                                "Anzahl der Datensätze pro Intervall, ab der der watch-Befehl auslöst.",
+	"Write found records to golden file of assert command instead of comparing them.":                                                                                               "Gefundene Datensätze in die Golden-Datei des assert-Befehls schreiben, statt sie zu vergleichen.",
+	"Upload export command files to location in cos://bucket/prefix/ format.":                                                                                                       "Dateien des export-Befehls an einen Ort im Format cos://bucket/prefix/ hochladen.",
+	"Server-side encryption algorithm of uploaded files, ie. AES256.":                                                                                                               "Serverseitiger Verschlüsselungsalgorithmus hochgeladener Dateien, z. B. AES256.",
+	"Parse time options, query and display all times in UTC, shown with explicit zone suffix.":                                                                                      "Zeitoptionen interpretieren, abfragen und alle Zeiten in UTC anzeigen, mit ausdrücklicher Zonenangabe.",
+	"Log debug messages of internal steps, ie. queries run and tokens obtained, to standard error.":                                                                                 "Debug-Meldungen interner Schritte, z. B. ausgeführte Abfragen und erhaltene Tokens, auf die Standardfehlerausgabe protokollieren.",
+	"Time verify-pipeline command waits for sent record to be found.":                                                                                                               "Wartezeit des verify-pipeline-Befehls, bis der gesendete Datensatz gefunden wird.",
+	"Show binary version.": "Version des Programms anzeigen.",
+	"Client-side filter expression over id, severity, timestamp, label.<key> and json.<path> fields.":                                                                                                                                             "Clientseitiger Filterausdruck über die Felder id, severity, timestamp, label.<key> und json.<path>.",
+	"Trigger expression of watch command evaluated per interval instead of --threshold, over count, count(<filter expression>), rate per minute and baseline rate of previous intervals, ie. 'count(severity>=error) > 10 || rate > 2*baseline'.": "Trigger-Ausdruck des watch-Befehls, je Intervall statt --threshold ausgewertet, über count, count(<Filterausdruck>), rate pro Minute und baseline-Rate vorheriger Intervalle, z. B. 'count(severity>=error) > 10 || rate > 2*baseline'.",
+	"Time window from..to setting start and end time at once, ie. 14:00..14:15 for today or 2006-01-02T15:04..2006-01-02T15:20. Can be repeated, search then queries each window and labels records with it.":                                     "Zeitfenster von..bis, das Start- und Endzeit auf einmal setzt, z. B. 14:00..14:15 für heute oder 2006-01-02T15:04..2006-01-02T15:20. Kann wiederholt werden, die Suche fragt dann jedes Zeitfenster ab und kennzeichnet Datensätze damit.",
+	"Run expensive archive scans without confirmation.": "Aufwendige Archivsuchen ohne Bestätigung ausführen.",
+
+	// Summary and warnings
+	"Summary:":                         "Zusammenfassung:",
+	"- records: %d\n":                  "- Datensätze: %d\n",
+	"- time range: %s - %s (%s, %v)\n": "- Zeitraum: %s - %s (%s, %v)\n",
+	"- timings: token %v, first byte %v, request %v, parse %v, process %v, render %v, total %v\n": "- Zeiten: Token %v, erstes Byte %v, Anfrage %v, Parsen %v, Verarbeitung %v, Ausgabe %v, gesamt %v\n",
+	"Warnings:":                "Warnungen:",
+	"No records found, hints:": "Keine Datensätze gefunden, Hinweise:",
+	"time range starts in the future (%s), check --from, --to and --window":              "der Zeitraum beginnt in der Zukunft (%s), prüfe --from, --to und --window",
+	"invalid retention of profile: %v":                                                   "ungültige Aufbewahrungsdauer des Profils: %v",
+	"time range ends before retention of %s tier (%s), records are no longer kept":       "der Zeitraum endet vor der Aufbewahrungsdauer der Stufe %s (%s), die Datensätze werden nicht mehr aufbewahrt",
+	"service warned about the query: %s":                                                 "der Dienst hat vor der Abfrage gewarnt: %s",
+	"%d record(s) found were removed by client-side filters (--where, --ip-in)":          "%d gefundene Datensätze wurden durch clientseitige Filter (--where, --ip-in) entfernt",
+	"profile scope '%s' is ANDed with the query, records outside of it are not searched": "der Profilbereich '%s' wird per AND mit der Abfrage verknüpft, Datensätze außerhalb davon werden nicht durchsucht",
+	"Possible secrets:":                 "Mögliche Geheimnisse:",
+	"- %s: %d record(s), first at %s\n": "- %s: %d Datensätze, erstmals um %s\n",
+	"Link: %s\n":                        "Verknüpfung: %s\n",
+	"%s\ndid you mean %s?":              "%s\nmeintest du %s?",
+	" or ":                              " oder ",
+	"deadline exceeded after %d of %d windows, results are partial": "Frist nach %d von %d Zeitfenstern überschritten, die Ergebnisse sind unvollständig",
+	"deadline exceeded, results are partial":                        "Frist überschritten, die Ergebnisse sind unvollständig",
+	"Query: %s\n":                                                   "Abfrage: %s\n",
+	"Window: %s - %s (%s)\n":                                        "Zeitfenster: %s - %s (%s)\n",
+	"Records: %d":                                                   "Datensätze: %d",
+	", %s per minute":                                               ", %s pro Minute",
+	"First: %s, last: %s\n":                                         "Erster: %s, letzter: %s\n",
+	"Peak: %d records in %s from %s\n":                              "Spitze: %d Datensätze in %s ab %s\n",
+	"\nTimeline (%s per character):\n":                              "\nZeitachse (%s pro Zeichen):\n",
+	"\nTop patterns (%d distinct):\n":                               "\nHäufigste Muster (%d verschiedene):\n",
+
+	// Errors
+	"Error: %s\nNext steps: %s\n": "Fehler: %s\nNächste Schritte: %s\n",
+	"Cause: %s\n":                 "Ursache: %s\n",
+	"IAM cannot find the API key, it is mistyped or was deleted":                                                                    "IAM findet den API-Schlüssel nicht, er ist falsch geschrieben oder wurde gelöscht",
+	"create API key with 'ibmcloud iam api-key-create' and give it with --key, LOGS_API_KEY or profile api_key_env":                 "erstelle einen API-Schlüssel mit 'ibmcloud iam api-key-create' und gib ihn mit --key, LOGS_API_KEY oder api_key_env des Profils an",
+	"IAM refused to issue token (%s: %s)":                                                                                           "IAM hat die Ausstellung des Tokens verweigert (%s: %s)",
+	"check the API key and that --auth-url or profile auth_url is IAM endpoint of its account environment: public, private or test": "prüfe den API-Schlüssel und ob --auth-url oder auth_url des Profils der IAM-Endpunkt der Umgebung seines Kontos ist: public, private oder test",
+	"private endpoint %s is unreachable from this network":                                                                          "privater Endpunkt %s ist aus diesem Netzwerk nicht erreichbar",
+	"connect to IBM Cloud private network, ie. VPN to VPC with virtual private endpoint, or use public endpoints without --private and private_endpoints; 'iclogs doctor' checks connectivity": "verbinde dich mit dem privaten Netzwerk von IBM Cloud, z. B. per VPN zur VPC mit Virtual Private Endpoint, oder nutze öffentliche Endpunkte ohne --private und private_endpoints; 'iclogs doctor' prüft die Verbindung",
+	"cannot resolve host %s": "Host %s kann nicht aufgelöst werden",
+	"check instance ID and region in --logs-url or profile logs_url, and network and DNS settings; 'iclogs doctor' checks connectivity": "prüfe Instanz-ID und Region in --logs-url oder logs_url des Profils sowie Netzwerk- und DNS-Einstellungen; 'iclogs doctor' prüft die Verbindung",
+	"logs instance denied access (HTTP 403)": "die Logs-Instanz hat den Zugriff verweigert (HTTP 403)",
+	"give user or service ID of the API key IAM access to the IBM Cloud Logs instance, Reader role to search and Manager role for management API commands; 'iclogs auth whoami' shows account of the key": "gib dem Benutzer oder der Service-ID des API-Schlüssels IAM-Zugriff auf die IBM Cloud Logs-Instanz, die Rolle Reader zum Suchen und Manager für Befehle der Management-API; 'iclogs auth whoami' zeigt das Konto des Schlüssels",
+	"logs instance rejected the token (HTTP 401), it expired or was issued for other environment":                                                                                                         "die Logs-Instanz hat das Token abgelehnt (HTTP 401), es ist abgelaufen oder wurde für eine andere Umgebung ausgestellt",
+	"run again to get new token, check system time, and that --auth-url or profile auth_url matches environment of the instance":                                                                          "führe den Befehl erneut aus, um ein neues Token zu erhalten, prüfe die Systemzeit und ob --auth-url oder auth_url des Profils zur Umgebung der Instanz passt",
+	"endpoint did not answer in time": "der Endpunkt hat nicht rechtzeitig geantwortet",
+	"check network or VPN connection, use --idle-timeout for long archive scans or shorter --range":                                                      "prüfe Netzwerk- oder VPN-Verbindung, nutze --idle-timeout für lange Archivsuchen oder einen kürzeren --range",
+	"check --ingress-url, or instance ID and region in --logs-url it is derived from, and network and DNS settings; 'iclogs doctor' checks connectivity": "prüfe --ingress-url oder Instanz-ID und Region in --logs-url, von dem sie abgeleitet wird, sowie Netzwerk- und DNS-Einstellungen; 'iclogs doctor' prüft die Verbindung",
+	"check --auth-url or profile auth_url, and network and DNS settings; 'iclogs doctor' checks connectivity":                                            "prüfe --auth-url oder auth_url des Profils sowie Netzwerk- und DNS-Einstellungen; 'iclogs doctor' prüft die Verbindung",
+	"ingestion endpoint denied access (HTTP 403)": "der Ingestion-Endpunkt hat den Zugriff verweigert (HTTP 403)",
+	"give user or service ID of the API key Sender role of the IBM Cloud Logs instance to send records; 'iclogs auth whoami' shows account of the key": "gib dem Benutzer oder der Service-ID des API-Schlüssels die Rolle Sender der IBM Cloud Logs-Instanz, um Datensätze zu senden; 'iclogs auth whoami' zeigt das Konto des Schlüssels",
+}
@@ -0,0 +1,69 @@
+// Package i18n to translate CLI messages with catalog of the language detected from locale environment variables
+package i18n
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// English messages are the catalogs keys, so it needs no catalog
+const English = "en"
+
+// Environment variables with language, the first one set is used
+var localeVars = []string{"ICLOGS_LANG", "LC_ALL", "LC_MESSAGES", "LANG"}
+
+// Catalogs of translated messages by language, keyed by English message or its format
+var catalogs = map[string]map[string]string{
+	"de": de,
+	"pl": pl,
+}
+
+// Lang of translated messages, messages missing from its catalog stay in English
+var Lang = English
+
+// Detect language from locale environment variables, ie. `pl_PL.UTF-8`, English when it has no catalog
+func Detect(getenv func(string) string) string {
+	for _, v := range localeVars {
+		locale := getenv(v)
+		if locale == "" {
+			continue
+		}
+
+		lang, _, _ := strings.Cut(strings.ToLower(locale), ".")
+		lang, _, _ = strings.Cut(lang, "_")
+		lang, _, _ = strings.Cut(lang, "-")
+		if _, ok := catalogs[lang]; ok {
+			return lang
+		}
+		return English
+	}
+
+	return English
+}
+
+// Languages with catalogs, in alphabetical order
+func Languages() []string {
+	return slices.Sorted(maps.Keys(catalogs))
+}
+
+// Translated tells if catalog of language has message
+func Translated(lang, msg string) bool {
+	_, ok := catalogs[lang][msg]
+	return ok
+}
+
+// T translates message to Lang
+func T(msg string) string {
+	if t, ok := catalogs[Lang][msg]; ok {
+		return t
+	}
+
+	return msg
+}
+
+// Sprintf formats according to format translated to Lang
+func Sprintf(format string, a ...any) string {
+	return fmt.Sprintf(T(format), a...)
+}
@@ -0,0 +1,79 @@
+package i18n
+
+import (
+	"reflect"
+	"regexp"
+	"sort"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	testCases := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{name: "Lang", env: map[string]string{"LANG": "pl_PL.UTF-8"}, want: "pl"},
+		{name: "Messages", env: map[string]string{"LC_MESSAGES": "de_DE", "LANG": "pl_PL.UTF-8"}, want: "de"},
+		{name: "Override", env: map[string]string{"ICLOGS_LANG": "en", "LC_ALL": "de_AT.UTF-8"}, want: English},
+		{name: "Posix", env: map[string]string{"LC_ALL": "C", "LANG": "pl_PL.UTF-8"}, want: English},
+		{name: "Unknown", env: map[string]string{"LANG": "fr_FR.UTF-8"}, want: English},
+		{name: "None", env: map[string]string{}, want: English},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Detect(func(k string) string { return tc.env[k] }); got != tc.want {
+				t.Errorf("Got: %s, want: %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	defer func() { Lang = English }()
+
+	Lang = "pl"
+	if got := Sprintf("cannot resolve host %s", "logs"); got != "nie można rozwiązać nazwy hosta logs" {
+		t.Errorf("Got: %s", got)
+	}
+	if got := T("Not translated"); got != "Not translated" {
+		t.Errorf("Expected message without translation in English, got: %s", got)
+	}
+
+	Lang = English
+	if got := T("Options:"); got != "Options:" {
+		t.Errorf("Got: %s", got)
+	}
+
+	if !Translated("de", "Options:") || Translated("de", "Not translated") || Translated(English, "Options:") {
+		t.Error("Expected only messages of catalog translated")
+	}
+	if got := Languages(); !reflect.DeepEqual(got, []string{"de", "pl"}) {
+		t.Errorf("Got languages: %v", got)
+	}
+}
+
+// Every catalog translates the same messages, keeping their format verbs
+func TestCatalogs(t *testing.T) {
+	verbs := regexp.MustCompile(`%[a-z]`)
+	keys := func(c map[string]string) []string {
+		k := make([]string, 0, len(c))
+		for m := range c {
+			k = append(k, m)
+		}
+		sort.Strings(k)
+		return k
+	}
+
+	for lang, c := range catalogs {
+		if !reflect.DeepEqual(keys(c), keys(pl)) {
+			t.Errorf("Catalog %s has other messages than pl one", lang)
+		}
+		for m, tr := range c {
+			if !reflect.DeepEqual(verbs.FindAllString(m, -1), verbs.FindAllString(tr, -1)) {
+				t.Errorf("Catalog %s changes format verbs of: %s", lang, m)
+			}
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package i18n
+
+var pl = map[string]string{
+	// Usage
+	"Usage of %s: [command] [options] <lucene query> [-- <lucene query> ...]": "Użycie %s: [polecenie] [opcje] <zapytanie lucene> [-- <zapytanie lucene> ...]",
+	"Commands:":     "Polecenia:",
+	"Options:":      "Opcje:",
+	" (default %s)": " (domyślnie %s)",
+
+	// Commands
+	"Call management API of the instance with the same token, ie. GET /v1/alerts, printing response JSON. For endpoints without own command.":                                           "Wywołaj API zarządzania instancji tym samym tokenem, np. GET /v1/alerts, wypisując JSON odpowiedzi. Dla punktów końcowych bez własnego polecenia.",
+	"Compare found records, without their ID and time, with --golden file in export format, exiting with status 1 on drift.":                                                            "Porównaj znalezione rekordy, bez ich ID i czasu, z plikiem --golden w formacie eksportu, kończąc ze statusem 1 przy rozbieżności.",
+	"Print identity, account, expiry and scopes of IAM token obtained for the API key.":                                                                                                 "Wypisz tożsamość, konto, czas wygaśnięcia i zakresy tokenu IAM uzyskanego dla klucza API.",
+	"Print issue-ready report with version, platform and the latest crash diagnostic report.":                                                                                           "Wypisz raport gotowy do zgłoszenia z wersją, platformą i najnowszym raportem diagnostycznym awarii.",
+	"Export profiles, saved queries, aliases and redactors (without audit settings) as bundle, or merge bundle into configuration file.":                                                "Eksportuj profile, zapisane zapytania, aliasy i redaktory (bez ustawień audytu) jako pakiet lub scal pakiet z plikiem konfiguracji.",
+	"List profiles with their account and endpoints, print profile in use, or persist default profile like kubectl contexts.":                                                           "Wypisz profile z ich kontem i punktami końcowymi, wypisz używany profil lub utrwal domyślny profil jak konteksty kubectl.",
+	"Show terminal dashboard (rate per severity, top applications, latest errors) refreshed until interrupted.":                                                                         "Pokaż pulpit w terminalu (częstość według ważności, najczęstsze aplikacje, najnowsze błędy) odświeżany do przerwania.",
+	"Print reference of commands and options as man page or Markdown, generated from the same metadata as this usage.":                                                                  "Wypisz dokumentację poleceń i opcji jako stronę man lub Markdown, wygenerowaną z tych samych metadanych co ten opis użycia.",
+	"Check connectivity to IAM, logs and ingestion endpoints, explaining private endpoints unreachable from current network.":                                                           "Sprawdź łączność z punktami końcowymi IAM, logów i ingestii, wyjaśniając prywatne punkty końcowe nieosiągalne z bieżącej sieci.",
+	"List events-to-metrics definitions of the instance with their query and metrics, or show one with its filters, labels and fields.":                                                 "Wypisz definicje events-to-metrics instancji z ich zapytaniem i metrykami lub pokaż jedną z jej filtrami, etykietami i polami.",
+	"List enrichments of the instance: enriched record fields with enrichment type (geo_ip, suspicious_ip or custom).":                                                                  "Wypisz wzbogacenia instancji: wzbogacane pola rekordów z typem wzbogacenia (geo_ip, suspicious_ip lub custom).",
+	"Write found records as JSON lines or CSV files, one per time range chunk, optionally uploaded to object storage.":                                                                  "Zapisz znalezione rekordy jako linie JSON lub pliki CSV, po jednym na fragment zakresu czasu, opcjonalnie wysyłane do magazynu obiektów.",
+	"Print one full record by its ID. Time range options need to cover record timestamp.":                                                                                               "Wypisz jeden pełny rekord według jego ID. Opcje zakresu czasu muszą obejmować znacznik czasu rekordu.",
+	"Send test record with --message through ingestion API of the logs instance, to verify ingestion and search end-to-end.":                                                            "Wyślij rekord testowy z --message przez API ingestii instancji logów, aby sprawdzić ingestię i wyszukiwanie od początku do końca.",
+	"List distinct application and subsystem label values found in time range, or cached values of one label for shell completion.":                                                     "Wypisz różne wartości etykiet application i subsystem znalezione w zakresie czasu lub zapamiętane wartości jednej etykiety do uzupełniania w powłoce.",
+	"Serve read-only query, tail and stats tools over Model Context Protocol (stdio) within profile scope and time range.":                                                              "Udostępnij narzędzia tylko do odczytu do zapytań, śledzenia i statystyk przez Model Context Protocol (stdio) w zakresie profilu i zakresie czasu.",
+	"Open the search in IBM Cloud Logs dashboard using default browser.":                                                                                                                "Otwórz wyszukiwanie w pulpicie IBM Cloud Logs w domyślnej przeglądarce.",
+	"List output sink plugins found in plugins directory, usable with --sink option.":                                                                                                   "Wypisz wtyczki wyjścia znalezione w katalogu wtyczek, do użycia z opcją --sink.",
+	"List TCO policies of the instance in evaluation order with tier (priority insights, analyze and alert, store and search) of matched records.":                                      "Wypisz polityki TCO instancji w kolejności ich stosowania z poziomem (priority insights, analyze and alert, store and search) pasujących rekordów.",
+	"Forward lines or NDJSON read from standard input to ingestion API of the logs instance, labeled with --app and --subsystem.":                                                       "Przekaż linie lub NDJSON odczytane ze standardowego wejścia do API ingestii instancji logów, oznaczone przez --app i --subsystem.",
+	"Serve web UI and REST API (/query and /tail with server-sent events) running searches within profile scope and time range.":                                                        "Udostępnij interfejs WWW i REST API (/query i /tail ze zdarzeniami wysyłanymi przez serwer) wykonujące wyszukiwania w zakresie profilu i zakresie czasu.",
+	"Tail --file paths and forward appended lines to ingestion API of the logs instance until interrupted, resuming from checkpointed positions.":                                       "Śledź ścieżki --file i przekazuj dopisywane linie do API ingestii instancji logów do przerwania, wznawiając od zapisanych pozycji.",
+	"Serve Slack slash command (/slack/commands) and mentions (/slack/events) running saved queries allowed in configuration.":                                                          "Udostępnij polecenie ukośnikowe Slack (/slack/commands) i wzmianki (/slack/events) uruchamiające zapisane zapytania dozwolone w konfiguracji.",
+	"List built-in Dataprime snippets usable with --snippet option, with their parameters in braces.":                                                                                   "Wypisz wbudowane fragmenty Dataprime do użycia z opcją --snippet, z ich parametrami w nawiasach klamrowych.",
+	"Report incident window at once: records stats, timeline per severity, top applications (or other --group-by field) and top message patterns with their first and last occurrence.": "Podsumuj od razu okno incydentu: statystyki rekordów, oś czasu według ważności, najczęstsze aplikacje (lub inne pole --group-by) i najczęstsze wzorce komunikatów z ich pierwszym i ostatnim wystąpieniem.",
+	"Report ingested GB per day and TCO policy tier from data usage API, last week by default.":                                                                                         "Podaj ilość GB przyjętych dziennie i poziom polityki TCO z API użycia danych, domyślnie za ostatni tydzień.",
+	"Send uniquely tagged record through ingestion API and search for it until found or --verify-timeout, reporting end-to-end latency.":                                                "Wyślij rekord z unikalnym znacznikiem przez API ingestii i szukaj go do znalezienia lub upływu --verify-timeout, podając opóźnienie od początku do końca.",
+	"Count records every refresh interval, notifying when count goes above threshold or trigger expression holds and when it clears.":                                                   "Zliczaj rekordy co interwał odświeżania, powiadamiając, gdy liczba przekroczy próg lub wyrażenie wyzwalacza jest spełnione oraz gdy to ustąpi.",
+	"List outbound integrations of the instance, or send test notification to URL of one, verifying alert notification channel.":                                                        "Wypisz integracje wychodzące instancji lub wyślij powiadomienie testowe na URL jednej z nich, sprawdzając kanał powiadomień o alertach.",
+
+	// Options
+	"Authorization Endpoint URL, or public, private or test IAM endpoint.":                                                                      "URL punktu końcowego autoryzacji lub punkt końcowy IAM public, private albo test.",
+	"Show comma separated aggregations (sum, avg, min, max) of numeric fields instead of records, ie. avg(json.response_time),max(json.bytes).": "Pokaż rozdzielone przecinkami agregacje (sum, avg, min, max) pól liczbowych zamiast rekordów, np. avg(json.response_time),max(json.bytes).",
+	"Snap time range start down and end up to multiples of interval, ie. 5m.":                                                                   "Wyrównaj początek zakresu czasu w dół, a koniec w górę do wielokrotności interwału, np. 5m.",
+	"Application name of records sent by ingest, push, ship and verify-pipeline commands.":                                                      "Nazwa aplikacji rekordów wysyłanych przez polecenia ingest, push, ship i verify-pipeline.",
+	"Configuration file path. Overrides ICLOGS_CONFIG environment variable.":                                                                    "Ścieżka pliku konfiguracji. Nadpisuje zmienną środowiskową ICLOGS_CONFIG.",
+	"Time to reuse serve command results of the same query and range, 0 disables cache.":                                                        "Czas ponownego użycia wyników polecenia serve dla tego samego zapytania i zakresu, 0 wyłącza pamięć podręczną.",
+	"JSON file with read positions of ship command or state of watch command, in user cache directory by default.":                              "Plik JSON z pozycjami odczytu polecenia ship lub stanem polecenia watch, domyślnie w katalogu pamięci podręcznej użytkownika.",
+	"Time range of one export command file, 0 means whole time range.":                                                                          "Zakres czasu jednego pliku polecenia export, 0 oznacza cały zakres czasu.",
+	"Show records compactly on narrow terminals: time of day, single character severity glyph, and shortened ID and labels when shown.":         "Pokaż rekordy zwięźle w wąskich terminalach: godzina, jednoznakowy symbol ważności oraz skrócone ID i etykiety, gdy są pokazywane.",
+	"Copy printed records to system clipboard.":                                                                                                 "Skopiuj wypisane rekordy do schowka systemowego.",
+	"Print usage command report as CSV, without totals row.":                                                                                    "Wypisz raport polecenia usage jako CSV, bez wiersza sum.",
+	"Search the same time of day window from..to, ie. 03:00..03:30, on each of last days.":                                                      "Przeszukaj to samo okno pory dnia od..do, np. 03:00..03:30, w każdym z ostatnich dni.",
+	"JSON request body of api command, @file reads it from file and @- from standard input.":                                                    "Treść żądania JSON polecenia api, @plik odczytuje ją z pliku, a @- ze standardowego wejścia.",
+	"Number of last days searched with daily window.":                                                                                           "Liczba ostatnich dni przeszukiwanych dziennym oknem.",
+	"Time budget shared by all queries of one-shot run, ie. export chunks or windows, 0 means no limit. Each request keeps its own timeout, serve, mcp, slackbot, watch and dash commands are not limited.": "Budżet czasu wspólny dla wszystkich zapytań jednorazowego uruchomienia, np. fragmentów eksportu lub okien, 0 oznacza brak limitu. Każde żądanie zachowuje własny limit czasu, polecenia serve, mcp, slackbot, watch i dash nie są ograniczane.",
+	"Log debug messages like --verbose, with their source code location.":                                                                          "Loguj komunikaty debugowania jak --verbose, z ich miejscem w kodzie źródłowym.",
+	"Comma separated user data paths removed from records before output and export, ie. kubernetes.annotations,tag,file.":                          "Rozdzielone przecinkami ścieżki danych użytkownika usuwane z rekordów przed wypisaniem i eksportem, np. kubernetes.annotations,tag,file.",
+	"Unit of extracted durations given without one.":                                                                                               "Jednostka wyodrębnionych czasów trwania podanych bez jednostki.",
+	"Encrypt export command files before writing with method age:<recipients file> or gpg:<recipient>.":                                            "Szyfruj pliki polecenia export przed zapisem metodą age:<plik odbiorców> lub gpg:<odbiorca>.",
+	"CSV or JSON lookup table file joined onto records as enrichment user data object.":                                                            "Plik tabeli wyszukiwania CSV lub JSON dołączany do rekordów jako obiekt danych użytkownika wzbogacenia.",
+	"Record field used as lookup key for enrichment, ie. json.node_name.":                                                                          "Pole rekordu używane jako klucz wyszukiwania wzbogacenia, np. json.node_name.",
+	"Print resolved query request, endpoint, tier, time window in UTC and token subject without running it, for support tickets.":                  "Wypisz rozwiązane żądanie zapytania, punkt końcowy, poziom, okno czasu w UTC i podmiot tokenu bez uruchamiania go, do zgłoszeń do wsparcia.",
+	"Format of export command files: ndjson or csv.":                                                                                               "Format plików polecenia export: ndjson lub csv.",
+	"Regular expression with capture group matched on message (ie. 'took (\\d+)ms') or record field with duration.":                                "Wyrażenie regularne z grupą przechwytującą dopasowywane do komunikatu (np. 'took (\\d+)ms') lub pole rekordu z czasem trwania.",
+	"Start time for log search in format 2006-01-02T15:04, with optional seconds and their fraction, RFC3339 time, date, now, today or yesterday.": "Czas początku wyszukiwania logów w formacie 2006-01-02T15:04, z opcjonalnymi sekundami i ich ułamkiem, czas RFC3339, data, now, today lub yesterday.",
+	"Logs endpoint URL failed over to when logs endpoint cannot be connected, ie. public one of private endpoint.":                                 "URL punktu końcowego logów używany, gdy nie można połączyć się z punktem końcowym logów, np. publiczny odpowiednik prywatnego.",
+	"Log path tailed by ship command. Can be repeated.":                                                                                            "Ścieżka logu śledzona przez polecenie ship. Może być powtarzana.",
+	"Rewrite user data as single level object with dotted keys, ie. kubernetes.labels.app, before output and export.":                              "Przepisz dane użytkownika jako jednopoziomowy obiekt z kluczami rozdzielonymi kropkami, np. kubernetes.labels.app, przed wypisaniem i eksportem.",
+	"Arrays handling mode of flattened user data: index (tags.0), join (comma separated string) or keep.":                                          "Tryb obsługi tablic spłaszczonych danych użytkownika: index (tags.0), join (ciąg rozdzielony przecinkami) lub keep.",
+	"Receive query responses of any size without confirmation.":                                                                                    "Odbieraj odpowiedzi zapytań dowolnego rozmiaru bez potwierdzenia.",
+	"MaxMind DB file (ie. GeoLite2 Country or ASN) for GeoIP enrichment.":                                                                          "Plik bazy MaxMind (np. GeoLite2 Country lub ASN) do wzbogacenia GeoIP.",
+	"Record field with IP address for GeoIP enrichment, ie. json.client_ip.":                                                                       "Pole rekordu z adresem IP do wzbogacenia GeoIP, np. json.client_ip.",
+	"Golden file of assert command with expected records in export format.":                                                                        "Plik wzorcowy polecenia assert z oczekiwanymi rekordami w formacie eksportu.",
+	"Record field to group records count and aggregations by, ie. json.service.":                                                                   "Pole rekordu, według którego grupowane są liczba rekordów i agregacje, np. json.service.",
+	"Show sparkline of records volume over time range next to each group.":                                                                         "Pokaż wykres liniowy liczby rekordów w zakresie czasu obok każdej grupy.",
+	"Abort query only when no data, keepalives included, arrives for duration, instead of after 3 minutes request timeout.":                        "Przerwij zapytanie dopiero, gdy przez podany czas nie nadejdą żadne dane, łącznie z keepalive, zamiast po 3-minutowym limicie czasu żądania.",
+	"Ingestion endpoint URL of ingest, push, ship and verify-pipeline commands, derived from logs endpoint by default.":                            "URL punktu końcowego ingestii poleceń ingest, push, ship i verify-pipeline, domyślnie wyprowadzany z punktu końcowego logów.",
+	"Record field with IP address for network filter, ie. json.client_ip.":                                                                         "Pole rekordu z adresem IP dla filtra sieci, np. json.client_ip.",
+	"Keep records with IP address within comma separated networks, ie. 10.0.0.0/8,192.168.0.0/16.":                                                 "Zachowaj rekordy z adresem IP w rozdzielonych przecinkami sieciach, np. 10.0.0.0/8,192.168.0.0/16.",
+	"Show record as JSON.": "Pokaż rekord jako JSON.",
+	"API Key to use. Overrides LOG_API_KEY environment variable.":                                                                                                                                        "Klucz API do użycia. Nadpisuje zmienną środowiskową LOG_API_KEY.",
+	"URL of IBM Cloud Log Endpoint. Overrides LOGS_ENDPOINT environment variable.":                                                                                                                       "URL punktu końcowego IBM Cloud Logs. Nadpisuje zmienną środowiskową LOGS_ENDPOINT.",
+	"Print link to the same search in IBM Cloud Logs dashboard.":                                                                                                                                         "Wypisz link do tego samego wyszukiwania w pulpicie IBM Cloud Logs.",
+	"Listen address of serve and slackbot commands.":                                                                                                                                                     "Adres nasłuchu poleceń serve i slackbot.",
+	"Print, export or send records to sink as they arrive without keeping them, ordered only within received batches.":                                                                                   "Wypisuj, eksportuj lub wysyłaj rekordy do wtyczki wyjścia w miarę ich nadchodzenia bez przechowywania, uporządkowane tylko w obrębie odebranych partii.",
+	"Comma separated message field names.":                                                                                                                                                               "Rozdzielone przecinkami nazwy pól komunikatu.",
+	"Limit reading of export command responses to rate, ie. 10MB/s or 512KiB/s, so exports don't saturate VPN links.":                                                                                    "Ogranicz odczyt odpowiedzi polecenia export do podanej szybkości, np. 10MB/s lub 512KiB/s, aby eksport nie wysycał łączy VPN.",
+	"Truncate displayed message or JSON longer than bytes, 0 means no limit.":                                                                                                                            "Skracaj wyświetlany komunikat lub JSON dłuższy niż podana liczba bajtów, 0 oznacza brak limitu.",
+	"Query response size, ie. 500MB, over which search or export asks to continue on terminal, otherwise aborts. 1GiB by default.":                                                                       "Rozmiar odpowiedzi zapytania, np. 500MB, powyżej którego wyszukiwanie lub eksport pyta w terminalu o kontynuację, a w przeciwnym razie przerywa. Domyślnie 1GiB.",
+	"Message text of record sent by ingest command, JSON object is sent as structured data.":                                                                                                             "Treść komunikatu rekordu wysyłanego przez polecenie ingest, obiekt JSON jest wysyłany jako dane strukturalne.",
+	"Opsgenie API key to create and close alerts from watch command.":                                                                                                                                    "Klucz API Opsgenie do tworzenia i zamykania alertów przez polecenie watch.",
+	"Routing key of PagerDuty Events API integration to create and resolve incidents from watch command.":                                                                                                "Klucz routingu integracji PagerDuty Events API do tworzenia i rozwiązywania incydentów przez polecenie watch.",
+	"Output directory of export command files.":                                                                                                                                                          "Katalog wyjściowy plików polecenia export.",
+	"Run queries exceeding maximum range and records of the profile.":                                                                                                                                    "Uruchamiaj zapytania przekraczające maksymalny zakres i liczbę rekordów profilu.",
+	"Configuration profile to use. Overrides ICLOGS_PROFILE environment variable.":                                                                                                                       "Profil konfiguracji do użycia. Nadpisuje zmienną środowiskową ICLOGS_PROFILE.",
+	"Snippet parameter as name=value, filling {name} placeholder. Can be repeated.":                                                                                                                      "Parametr fragmentu jako nazwa=wartość, wypełniający symbol zastępczy {name}. Może być powtarzany.",
+	"Show message patterns, messages with numbers, IDs, addresses and times masked, with records count and first and last occurrence instead of records.":                                                "Pokaż wzorce komunikatów, komunikaty z zamaskowanymi liczbami, ID, adresami i czasami, z liczbą rekordów oraz pierwszym i ostatnim wystąpieniem zamiast rekordów.",
+	"Show percentiles of extracted durations instead of records.":                                                                                                                                        "Pokaż percentyle wyodrębnionych czasów trwania zamiast rekordów.",
+	"Precision unit of displayed times: s, ms, us or ns.":                                                                                                                                                "Dokładność wyświetlanych czasów: s, ms, us lub ns.",
+	"Use private endpoints of logs instance and IAM, reachable only from IBM Cloud private network.":                                                                                                     "Używaj prywatnych punktów końcowych instancji logów i IAM, osiągalnych tylko z prywatnej sieci IBM Cloud.",
+	"Write newline-delimited progress events (phase, shard, percent of shards done, records so far) to standard error in format, only json is supported.":                                                "Zapisuj zdarzenia postępu rozdzielone nowymi liniami (faza, fragment, procent ukończonych fragmentów, dotychczasowe rekordy) na standardowe wyjście błędów w podanym formacie, obsługiwany jest tylko json.",
+	"Lucene query to run. Can be repeated, all queries are OR-combined.":                                                                                                                                 "Zapytanie Lucene do uruchomienia. Może być powtarzane, wszystkie zapytania są łączone przez OR.",
+	"Relative time for log search, before now or end time, or after start time when only it is given.":                                                                                                   "Względny czas wyszukiwania logów, przed teraz lub czasem końca albo po czasie początku, gdy podano tylko jego.",
+	"Maximum requests per minute from one client of serve command, 0 means no limit.":                                                                                                                    "Maksymalna liczba żądań na minutę od jednego klienta polecenia serve, 0 oznacza brak limitu.",
+	"Comma separated names of redactors hiding sensitive data (built-in: creditcard, email, ip).":                                                                                                        "Rozdzielone przecinkami nazwy redaktorów ukrywających wrażliwe dane (wbudowane: creditcard, email, ip).",
+	"Refresh interval of dash and watch commands and serve command tail.":                                                                                                                                "Interwał odświeżania poleceń dash i watch oraz śledzenia w poleceniu serve.",
+	"Fetch management API listings and label values instead of using cached ones.":                                                                                                                       "Pobierz ponownie listy API zarządzania i wartości etykiet zamiast używać zapamiętanych.",
+	"Fetch shared saved queries from configured source instead of using cached copy.":                                                                                                                    "Pobierz współdzielone zapisane zapytania ze skonfigurowanego źródła zamiast używać zapamiętanej kopii.",
+	"Retries of failed export command query, each querying failed time window in halves.":                                                                                                                "Ponowienia nieudanego zapytania polecenia export, każde odpytuje nieudane okno czasu w połowach.",
+	"Run saved query with given name from configuration file, ANDed with given query.":                                                                                                                   "Uruchom zapisane zapytanie o podanej nazwie z pliku konfiguracji, połączone przez AND z podanym zapytaniem.",
+	"Warn about records containing likely secrets.":                                                                                                                                                      "Ostrzegaj o rekordach prawdopodobnie zawierających sekrety.",
+	"JSON file with array of {\"name\", \"type\"} columns of CSV export, instead of columns inferred from records.":                                                                                      "Plik JSON z tablicą kolumn {\"name\", \"type\"} eksportu CSV zamiast kolumn wywnioskowanych z rekordów.",
+	"Number of first records whose fields make columns of CSV export.":                                                                                                                                   "Liczba pierwszych rekordów, których pola tworzą kolumny eksportu CSV.",
+	"Pin endpoint, time window and token under session name on first use and reuse them in later runs with the same name.":                                                                               "Przypnij punkt końcowy, okno czasu i token pod nazwą sesji przy pierwszym użyciu i używaj ich ponownie w kolejnych uruchomieniach z tą samą nazwą.",
+	"Severity name of records sent by ingest, push, ship and verify-pipeline commands: debug, verbose, info, warning, error or critical. JSON lines pushed with severity or level field keep their own.": "Ważność rekordów wysyłanych przez polecenia ingest, push, ship i verify-pipeline: debug, verbose, info, warning, error lub critical. Linie JSON wysyłane przez push z polem severity lub level zachowują własną.",
+	"Show record ID.": "Pokaż ID rekordu.",
+	"Show only record label with given key, ie. applicationname. Can be repeated.": "Pokaż tylko etykietę rekordu o podanym kluczu, np. applicationname. Może być powtarzana.",
+	"Show record labels.":    "Pokaż etykiety rekordu.",
+	"Show record severity.":  "Pokaż ważność rekordu.",
+	"Show record timestamp.": "Pokaż znacznik czasu rekordu.",
+	"Daily window as HH:MM-HH:MM or one-off from..to window when watch command does not notify, ie. maintenance. Silenced intervals are marked in interval lines. Can be repeated.": "Dzienne okno jako HH:MM-HH:MM lub jednorazowe okno od..do, w którym polecenie watch nie powiadamia, np. prace serwisowe. Wyciszone interwały są oznaczane w liniach interwałów. Może być powtarzana.",
+	"File path with silence windows of watch command, one per line with # comments, read again each interval.":                                                                      "Ścieżka pliku z oknami wyciszenia polecenia watch, po jednym w linii z komentarzami #, odczytywanego ponownie w każdym interwale.",
+	"Send found records as JSON lines to sink plugin with given name instead of printing them.":                                                                                     "Wysyłaj znalezione rekordy jako linie JSON do wtyczki wyjścia o podanej nazwie zamiast je wypisywać.",
+	"Slack app signing secret. Overrides SLACK_SIGNING_SECRET environment variable.":                                                                                                "Sekret podpisywania aplikacji Slack. Nadpisuje zmienną środowiskową SLACK_SIGNING_SECRET.",
+	"Slack bot token. Overrides SLACK_BOT_TOKEN environment variable.":                                                                                                              "Token bota Slack. Nadpisuje zmienną środowiskową SLACK_BOT_TOKEN.",
+	"Run built-in Dataprime snippet name (see snippets command), query is then applied as Lucene stage before the snippet pipeline.":                                                "Uruchom wbudowany fragment Dataprime o podanej nazwie (zobacz polecenie snippets), zapytanie jest wtedy stosowane jako etap Lucene przed potokiem fragmentu.",
+	"Object storage endpoint URL for upload, ie. https://s3.us-south.cloud-object-storage.appdomain.cloud.":                                                                         "URL punktu końcowego magazynu obiektów do wysyłania, np. https://s3.us-south.cloud-object-storage.appdomain.cloud.",
+	"Subsystem name of records sent by ingest, push, ship and verify-pipeline commands.":                                                                                            "Nazwa podsystemu rekordów wysyłanych przez polecenia ingest, push, ship i verify-pipeline.",
+	"Print records count, time range and timings of query phases to standard error.":                                                                                                "Wypisz liczbę rekordów, zakres czasu i czasy faz zapytania na standardowe wyjście błędów.",
+	"End time for log search in range format 2006-01-02T15:04, with optional seconds and their fraction, RFC3339 time, date, now, today or yesterday.":                              "Czas końca wyszukiwania logów w formacie zakresu 2006-01-02T15:04, z opcjonalnymi sekundami i ich ułamkiem, czas RFC3339, data, now, today lub yesterday.",
+	"Records count per interval above which watch command triggers.":                                                                                                                "Liczba rekordów na interwał, powyżej której polecenie watch się wyzwala.",
+	"Write found records to golden file of assert command instead of comparing them.":                                                                                               "Zapisz znalezione rekordy do pliku wzorcowego polecenia assert zamiast je porównywać.",
+	"Upload export command files to location in cos://bucket/prefix/ format.":                                                                                                       "Wysyłaj pliki polecenia export do lokalizacji w formacie cos://bucket/prefix/.",
+	"Server-side encryption algorithm of uploaded files, ie. AES256.":                                                                                                               "Algorytm szyfrowania po stronie serwera wysyłanych plików, np. AES256.",
+	"Parse time options, query and display all times in UTC, shown with explicit zone suffix.":                                                                                      "Interpretuj opcje czasu, odpytuj i wyświetlaj wszystkie czasy w UTC, z jawnym oznaczeniem strefy.",
+	"Log debug messages of internal steps, ie. queries run and tokens obtained, to standard error.":                                                                                 "Loguj komunikaty debugowania wewnętrznych kroków, np. uruchomione zapytania i uzyskane tokeny, na standardowe wyjście błędów.",
+	"Time verify-pipeline command waits for sent record to be found.":                                                                                                               "Czas oczekiwania polecenia verify-pipeline na znalezienie wysłanego rekordu.",
+	"Show binary version.": "Pokaż wersję programu.",
+	"Client-side filter expression over id, severity, timestamp, label.<key> and json.<path> fields.":                                                                                                                                             "Wyrażenie filtra po stronie klienta na polach id, severity, timestamp, label.<key> i json.<path>.",
+	"Trigger expression of watch command evaluated per interval instead of --threshold, over count, count(<filter expression>), rate per minute and baseline rate of previous intervals, ie. 'count(severity>=error) > 10 || rate > 2*baseline'.": "Wyrażenie wyzwalacza polecenia watch obliczane dla każdego interwału zamiast --threshold, na count, count(<wyrażenie filtra>), rate na minutę i bazowej częstości baseline poprzednich interwałów, np. 'count(severity>=error) > 10 || rate > 2*baseline'.",
+	"Time window from..to setting start and end time at once, ie. 14:00..14:15 for today or 2006-01-02T15:04..2006-01-02T15:20. Can be repeated, search then queries each window and labels records with it.":                                     "Okno czasu od..do ustawiające naraz czas początku i końca, np. 14:00..14:15 dla dziś lub 2006-01-02T15:04..2006-01-02T15:20. Może być powtarzana, wyszukiwanie odpytuje wtedy każde okno i oznacza nim rekordy.",
+	"Run expensive archive scans without confirmation.": "Uruchamiaj kosztowne przeszukiwania archiwum bez potwierdzenia.",
+
+	// Summary and warnings
+	"Summary:":                         "Podsumowanie:",
+	"- records: %d\n":                  "- rekordy: %d\n",
+	"- time range: %s - %s (%s, %v)\n": "- zakres czasu: %s - %s (%s, %v)\n",
+	"- timings: token %v, first byte %v, request %v, parse %v, process %v, render %v, total %v\n": "- czasy: token %v, pierwszy bajt %v, żądanie %v, parsowanie %v, przetwarzanie %v, wyświetlanie %v, łącznie %v\n",
+	"Warnings:":                "Ostrzeżenia:",
+	"No records found, hints:": "Nie znaleziono rekordów, wskazówki:",
+	"time range starts in the future (%s), check --from, --to and --window":              "zakres czasu zaczyna się w przyszłości (%s), sprawdź --from, --to i --window",
+	"invalid retention of profile: %v":                                                   "nieprawidłowy okres przechowywania profilu: %v",
+	"time range ends before retention of %s tier (%s), records are no longer kept":       "zakres czasu kończy się przed okresem przechowywania poziomu %s (%s), rekordy nie są już przechowywane",
+	"service warned about the query: %s":                                                 "usługa ostrzegła o zapytaniu: %s",
+	"%d record(s) found were removed by client-side filters (--where, --ip-in)":          "%d znalezionych rekordów usunęły filtry po stronie klienta (--where, --ip-in)",
+	"profile scope '%s' is ANDed with the query, records outside of it are not searched": "zakres profilu '%s' jest łączony przez AND z zapytaniem, rekordy spoza niego nie są przeszukiwane",
+	"Possible secrets:":                 "Możliwe sekrety:",
+	"- %s: %d record(s), first at %s\n": "- %s: %d rekordów, pierwszy o %s\n",
+	"Link: %s\n":                        "Odnośnik: %s\n",
+	"%s\ndid you mean %s?":              "%s\nczy chodziło o %s?",
+	" or ":                              " lub ",
+	"deadline exceeded after %d of %d windows, results are partial": "przekroczono termin po %d z %d okien, wyniki są częściowe",
+	"deadline exceeded, results are partial":                        "przekroczono termin, wyniki są częściowe",
+	"Query: %s\n":                                                   "Zapytanie: %s\n",
+	"Window: %s - %s (%s)\n":                                        "Okno: %s - %s (%s)\n",
+	"Records: %d":                                                   "Rekordy: %d",
+	", %s per minute":                                               ", %s na minutę",
+	"First: %s, last: %s\n":                                         "Pierwszy: %s, ostatni: %s\n",
+	"Peak: %d records in %s from %s\n":                              "Szczyt: %d rekordów w %s od %s\n",
+	"\nTimeline (%s per character):\n":                              "\nOś czasu (%s na znak):\n",
+	"\nTop patterns (%d distinct):\n":                               "\nNajczęstsze wzorce (%d różnych):\n",
+
+	// Errors
+	"Error: %s\nNext steps: %s\n": "Błąd: %s\nCo dalej: %s\n",
+	"Cause: %s\n":                 "Przyczyna: %s\n",
+	"IAM cannot find the API key, it is mistyped or was deleted":                                                                    "IAM nie może znaleźć klucza API, jest błędnie wpisany lub został usunięty",
+	"create API key with 'ibmcloud iam api-key-create' and give it with --key, LOGS_API_KEY or profile api_key_env":                 "utwórz klucz API poleceniem 'ibmcloud iam api-key-create' i podaj go przez --key, LOGS_API_KEY lub api_key_env profilu",
+	"IAM refused to issue token (%s: %s)":                                                                                           "IAM odmówił wydania tokenu (%s: %s)",
+	"check the API key and that --auth-url or profile auth_url is IAM endpoint of its account environment: public, private or test": "sprawdź klucz API oraz czy --auth-url lub auth_url profilu to punkt końcowy IAM środowiska jego konta: public, private lub test",
+	"private endpoint %s is unreachable from this network":                                                                          "prywatny punkt końcowy %s jest nieosiągalny z tej sieci",
+	"connect to IBM Cloud private network, ie. VPN to VPC with virtual private endpoint, or use public endpoints without --private and private_endpoints; 'iclogs doctor' checks connectivity": "połącz się z prywatną siecią IBM Cloud, np. przez VPN do VPC z wirtualnym prywatnym punktem końcowym, lub użyj publicznych punktów końcowych bez --private i private_endpoints; 'iclogs doctor' sprawdza łączność",
+	"cannot resolve host %s": "nie można rozwiązać nazwy hosta %s",
+	"check instance ID and region in --logs-url or profile logs_url, and network and DNS settings; 'iclogs doctor' checks connectivity": "sprawdź ID instancji i region w --logs-url lub logs_url profilu oraz ustawienia sieci i DNS; 'iclogs doctor' sprawdza łączność",
+	"logs instance denied access (HTTP 403)": "instancja logów odmówiła dostępu (HTTP 403)",
+	"give user or service ID of the API key IAM access to the IBM Cloud Logs instance, Reader role to search and Manager role for management API commands; 'iclogs auth whoami' shows account of the key": "nadaj użytkownikowi lub ID usługi klucza API dostęp IAM do instancji IBM Cloud Logs, rolę Reader do wyszukiwania i Manager do poleceń API zarządzania; 'iclogs auth whoami' pokazuje konto klucza",
+	"logs instance rejected the token (HTTP 401), it expired or was issued for other environment":                                                                                                         "instancja logów odrzuciła token (HTTP 401), wygasł lub został wydany dla innego środowiska",
+	"run again to get new token, check system time, and that --auth-url or profile auth_url matches environment of the instance":                                                                          "uruchom ponownie, aby uzyskać nowy token, sprawdź czas systemowy oraz czy --auth-url lub auth_url profilu pasuje do środowiska instancji",
+	"endpoint did not answer in time": "punkt końcowy nie odpowiedział na czas",
+	"check network or VPN connection, use --idle-timeout for long archive scans or shorter --range":                                                      "sprawdź połączenie sieciowe lub VPN, użyj --idle-timeout dla długich przeszukiwań archiwum lub krótszego --range",
+	"check --ingress-url, or instance ID and region in --logs-url it is derived from, and network and DNS settings; 'iclogs doctor' checks connectivity": "sprawdź --ingress-url lub ID instancji i region w --logs-url, z którego jest wyprowadzany, oraz ustawienia sieci i DNS; 'iclogs doctor' sprawdza łączność",
+	"check --auth-url or profile auth_url, and network and DNS settings; 'iclogs doctor' checks connectivity":                                            "sprawdź --auth-url lub auth_url profilu oraz ustawienia sieci i DNS; 'iclogs doctor' sprawdza łączność",
+	"ingestion endpoint denied access (HTTP 403)": "punkt końcowy ingestii odmówił dostępu (HTTP 403)",
+	"give user or service ID of the API key Sender role of the IBM Cloud Logs instance to send records; 'iclogs auth whoami' shows account of the key": "nadaj użytkownikowi lub ID usługi klucza API rolę Sender instancji IBM Cloud Logs, aby wysyłać rekordy; 'iclogs auth whoami' pokazuje konto klucza",
+}
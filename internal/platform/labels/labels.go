@@ -0,0 +1,117 @@
+// Package labels to collect distinct application and subsystem label values counted by logs service,
+// cached for shell completion
+package labels
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/cache"
+	"github.com/wooyey/iclogs/pkg/query"
+)
+
+// Label keys values are collected for
+const (
+	Application = "applicationname"
+	Subsystem   = "subsystemname"
+)
+
+// Aggregation stage of Dataprime query counting records by application and subsystem, rows are collected by Collect
+const Aggregation = "groupby $l.applicationname as " + Application + ", $l.subsystemname as " + Subsystem +
+	" aggregate count() as " + countColumn
+
+const countColumn = "count"
+
+// CacheTTL is how long cached values are used before they need to be fetched again
+var CacheTTL = 15 * time.Minute
+
+// CachePath returns location of cached values for given logs endpoint in user cache directory
+var CachePath = func(endpoint string) (string, error) {
+	return cache.Path("labels", endpoint)
+}
+
+// Values are records count per label value, by label key
+type Values struct {
+	Fetched time.Time                 `json:"fetched"`
+	Counts  map[string]map[string]int `json:"counts"`
+}
+
+// Collect application and subsystem values from rows of Aggregation
+func Collect(rows []query.Row) Values {
+	v := Values{Fetched: time.Now(), Counts: map[string]map[string]int{Application: {}, Subsystem: {}}}
+
+	for _, row := range rows {
+		count, _ := row[countColumn].(float64)
+		for key, counts := range v.Counts {
+			if value, ok := row[key].(string); ok && value != "" {
+				counts[value] += int(count)
+			}
+		}
+	}
+
+	return v
+}
+
+// Names returns values of label key, the most frequent first
+func (v Values) Names(key string) []string {
+	counts := v.Counts[key]
+
+	names := make([]string, 0, len(counts))
+	for n := range counts {
+		names = append(names, n)
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
+	return names
+}
+
+// Load cached values of endpoint, reporting whether they are fresher than CacheTTL
+func Load(endpoint string) (Values, bool, error) {
+	v := Values{}
+
+	path, err := CachePath(endpoint)
+	if err != nil {
+		return v, false, err
+	}
+
+	data, ok, err := cache.Read(path)
+	if err != nil {
+		return v, false, fmt.Errorf("cannot read cached labels: %w", err)
+	}
+	if !ok {
+		return v, false, nil
+	}
+
+	if err = json.Unmarshal(data, &v); err != nil {
+		return v, false, fmt.Errorf("cannot parse cached labels: %w", err)
+	}
+
+	return v, time.Since(v.Fetched) < CacheTTL, nil
+}
+
+// Save values of endpoint to cache
+func Save(endpoint string, v Values) error {
+	path, err := CachePath(endpoint)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("cannot encode labels: %w", err)
+	}
+
+	if err := cache.Write(path, data); err != nil {
+		return fmt.Errorf("cannot write cached labels: %w", err)
+	}
+
+	return nil
+}
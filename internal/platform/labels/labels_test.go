@@ -0,0 +1,61 @@
+package labels
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/wooyey/iclogs/pkg/query"
+)
+
+func row(app, subsystem string, count float64) query.Row {
+	return query.Row{Application: app, Subsystem: subsystem, countColumn: count}
+}
+
+func TestCollect(t *testing.T) {
+	rows := []query.Row{row("web", "api", 3), row("payments", "api", 2), row("web", "worker", 1), row("web", "", 1), {}}
+
+	v := Collect(rows)
+
+	if got, want := v.Names(Application), []string{"web", "payments"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Got: %v, Want: %v", got, want)
+	}
+	if got, want := v.Names(Subsystem), []string{"api", "worker"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Got: %v, Want: %v", got, want)
+	}
+	if got := v.Counts[Application]["web"]; got != 5 {
+		t.Errorf("Got: %d, Want: 5", got)
+	}
+	if got := v.Names("unknown"); len(got) != 0 {
+		t.Errorf("Got: %v, Want no names", got)
+	}
+}
+
+func TestCache(t *testing.T) {
+	dir := t.TempDir()
+	CachePath = func(endpoint string) (string, error) { return filepath.Join(dir, "labels.json"), nil }
+
+	if _, fresh, err := Load("https://logs.example.com"); fresh || err != nil {
+		t.Fatalf("Got fresh: %v, error: %v, want none", fresh, err)
+	}
+
+	want := Collect([]query.Row{row("web", "api", 1)})
+	if err := Save("https://logs.example.com", want); err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+
+	got, fresh, err := Load("https://logs.example.com")
+	if err != nil || !fresh {
+		t.Fatalf("Got fresh: %v, error: %v", fresh, err)
+	}
+	if !reflect.DeepEqual(got.Counts, want.Counts) {
+		t.Errorf("Got: %v, Want: %v", got.Counts, want.Counts)
+	}
+
+	CacheTTL = 0
+	defer func() { CacheTTL = 15 * time.Minute }()
+	if _, fresh, _ = Load("https://logs.example.com"); fresh {
+		t.Error("Expected stale cache")
+	}
+}
@@ -0,0 +1,144 @@
+// Package library to fetch team shared saved queries from HTTP or git location, keeping local cached copy
+package library
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/cache"
+	"github.com/wooyey/iclogs/internal/platform/config"
+)
+
+const gitPrefix = "git+"
+
+var FetchTimeout = time.Duration(30) * time.Second // HTTP fetch timeout - default 30 seconds
+
+// CacheTTL is how long cached copy is used before fetching the source again
+var CacheTTL = time.Hour
+
+// CachePath returns location of cached copy for given source in user cache directory
+var CachePath = func(source string) (string, error) {
+	return cache.Path("queries", source)
+}
+
+// GitCommand runs git with given arguments, replaceable in tests
+var GitCommand = func(args ...string) error {
+	out, err := exec.Command("git", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
+
+// Queries returns saved queries from source, which is HTTP(S) address of JSON file or
+// `git+<repository>#<path>` pointing to the file in git repository. Cached copy is used when fresher than CacheTTL,
+// unless refresh is requested, and as fallback when source cannot be fetched.
+func Queries(source string, refresh bool) (map[string]config.SavedQuery, error) {
+	path, err := CachePath(source)
+	if err != nil {
+		return nil, err
+	}
+
+	if info, err := os.Stat(path); err == nil && !refresh && time.Since(info.ModTime()) < CacheTTL {
+		return read(path)
+	}
+
+	data, err := fetch(source)
+	if err != nil {
+		if q, cerr := read(path); cerr == nil {
+			return q, nil
+		}
+		return nil, err
+	}
+
+	q, err := parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse queries from %s: %w", source, err)
+	}
+
+	if err := cache.Write(path, data); err != nil {
+		return nil, fmt.Errorf("cannot write cached queries: %w", err)
+	}
+
+	return q, nil
+}
+
+func parse(data []byte) (map[string]config.SavedQuery, error) {
+	q := map[string]config.SavedQuery{}
+	if err := json.Unmarshal(data, &q); err != nil {
+		return nil, err
+	}
+
+	return q, nil
+}
+
+func read(path string) (map[string]config.SavedQuery, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read cached queries: %w", err)
+	}
+
+	q, err := parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse cached queries: %w", err)
+	}
+
+	return q, nil
+}
+
+func fetch(source string) ([]byte, error) {
+	if repo, ok := strings.CutPrefix(source, gitPrefix); ok {
+		return fetchGit(repo)
+	}
+
+	c := http.Client{Timeout: FetchTimeout}
+	resp, err := c.Get(source)
+	if err != nil {
+		return nil, fmt.Errorf("cannot fetch queries: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("cannot fetch queries: %s returned %s", source, resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read queries: %w", err)
+	}
+
+	return data, nil
+}
+
+// Shallow clone of the repository to read one file from it
+func fetchGit(spec string) ([]byte, error) {
+	repo, file, ok := strings.Cut(spec, "#")
+	if !ok || file == "" {
+		return nil, fmt.Errorf("git queries source needs to be in git+<repository>#<path> format, got '%s'", spec)
+	}
+
+	dir, err := os.MkdirTemp("", "iclogs-queries-")
+	if err != nil {
+		return nil, fmt.Errorf("cannot create clone directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := GitCommand("clone", "--quiet", "--depth", "1", repo, dir); err != nil {
+		return nil, fmt.Errorf("cannot clone queries repository: %w", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(file)))
+	if err != nil {
+		return nil, fmt.Errorf("cannot read queries from repository: %w", err)
+	}
+
+	return data, nil
+}
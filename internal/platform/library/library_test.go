@@ -0,0 +1,94 @@
+package library
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/wooyey/iclogs/internal/platform/config"
+)
+
+const catalog = `{"errors": {"query": "severity:error", "range": "15m", "description": "Recent errors"}}`
+
+var want = map[string]config.SavedQuery{"errors": {Query: "severity:error", Range: "15m", Description: "Recent errors"}}
+
+func useCache(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	CachePath = func(source string) (string, error) {
+		return filepath.Join(dir, "queries.json"), nil
+	}
+}
+
+func TestQueriesHTTP(t *testing.T) {
+	useCache(t)
+
+	status, calls := http.StatusOK, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(status)
+		w.Write([]byte(catalog))
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		name    string
+		status  int
+		refresh bool
+		calls   int
+	}{
+		{name: "Fetched", status: http.StatusOK, refresh: false, calls: 1},
+		{name: "Cached", status: http.StatusOK, refresh: false, calls: 1},
+		{name: "Refreshed", status: http.StatusOK, refresh: true, calls: 2},
+		{name: "Fallback", status: http.StatusInternalServerError, refresh: true, calls: 3},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			status = tt.status
+
+			got, err := Queries(server.URL+"/queries.json", tt.refresh)
+			if err != nil {
+				t.Fatalf("Got an error: '%v'", err)
+			}
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("\nGot:\t%+v\nWant:\t%+v", got, want)
+			}
+			if calls != tt.calls {
+				t.Errorf("Want %d calls, got: %d", tt.calls, calls)
+			}
+		})
+	}
+}
+
+func TestQueriesGit(t *testing.T) {
+	useCache(t)
+
+	var args []string
+	GitCommand = func(a ...string) error {
+		args = a
+		dir := filepath.Join(a[len(a)-1], "catalog")
+		os.MkdirAll(dir, 0o700)
+		return os.WriteFile(filepath.Join(dir, "queries.json"), []byte(catalog), 0o600)
+	}
+
+	got, err := Queries("git+https://git.example.com/team/queries.git#catalog/queries.json", true)
+	if err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("\nGot:\t%+v\nWant:\t%+v", got, want)
+	}
+	if args[4] != "https://git.example.com/team/queries.git" {
+		t.Errorf("Want clone of repository, got: %v", args)
+	}
+
+	useCache(t)
+	if _, err := Queries("git+https://git.example.com/team/queries.git", true); err == nil {
+		t.Error("Should get an error for missing file path!")
+	}
+}
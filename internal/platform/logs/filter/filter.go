@@ -0,0 +1,653 @@
+// Package filter to evaluate client-side expressions over log records
+package filter
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/wooyey/iclogs/internal/platform/logs"
+)
+
+// Field names and prefixes available for log records
+const (
+	idField        = "id"
+	severityField  = "severity"
+	timestampField = "timestamp"
+	labelPrefix    = "label."
+	jsonPrefix     = "json."
+)
+
+const timeFormat = "2006-01-02T15:04:05.000000" // Fixed width to allow string comparison
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenOperator
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+// Resolver returns value of the field with given name and if it exists
+type Resolver func(name string) (any, bool)
+
+// Aggregate returns value of function over set of records, over records matching where filter when it is given
+type Aggregate func(name string, where *Filter) any
+
+// Values expression is evaluated with, fields of record or of records set and aggregate functions of the set
+type env struct {
+	resolve   Resolver
+	aggregate Aggregate
+}
+
+type node interface {
+	eval(e env) any
+}
+
+type literal struct {
+	value any
+}
+
+type field struct {
+	name string
+}
+
+type not struct {
+	x node
+}
+
+type logical struct {
+	op   string
+	l, r node
+}
+
+type comparison struct {
+	op       string
+	l, r     node
+	re       *regexp.Regexp
+	severity bool
+}
+
+type arithmetic struct {
+	op   string
+	l, r node
+}
+
+type call struct {
+	name  string
+	where *Filter // Records aggregated, all when nil
+}
+
+// Filter is parsed expression ready to be matched against records
+type Filter struct {
+	root node
+}
+
+var operators = []string{"==", "!=", "<=", ">=", "=~", "!~", "&&", "||", "<", ">", "!", "(", ")", "+", "-", "*", "/"}
+
+func tokenize(s string) ([]token, error) {
+	var tokens []token
+
+	for i := 0; i < len(s); {
+		c := rune(s[i])
+
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '"' || c == '\'':
+			j := i + 1
+			var b strings.Builder
+			for ; j < len(s) && s[j] != s[i]; j++ {
+				if s[j] == '\\' && j+1 < len(s) {
+					j++
+				}
+				b.WriteByte(s[j])
+			}
+			if j >= len(s) {
+				return nil, fmt.Errorf("unterminated string at position %d", i)
+			}
+			tokens = append(tokens, token{tokenString, b.String(), i})
+			i = j + 1
+		case unicode.IsDigit(c) || c == '-' && i+1 < len(s) && unicode.IsDigit(rune(s[i+1])) && !afterOperand(tokens):
+			j := i + 1
+			for j < len(s) && (unicode.IsDigit(rune(s[j])) || s[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, token{tokenNumber, s[i:j], i})
+			i = j
+		case isIdentChar(c):
+			j := i
+			for j < len(s) && (isIdentChar(rune(s[j])) || s[j] == '-' || unicode.IsDigit(rune(s[j]))) {
+				j++
+			}
+			tokens = append(tokens, token{tokenIdent, s[i:j], i})
+			i = j
+		default:
+			op := ""
+			for _, o := range operators {
+				if strings.HasPrefix(s[i:], o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected character '%c' at position %d", c, i)
+			}
+			tokens = append(tokens, token{tokenOperator, op, i})
+			i += len(op)
+		}
+	}
+
+	return append(tokens, token{tokenEOF, "", len(s)}), nil
+}
+
+// Minus after operand is subtraction, ie. `count-1`, otherwise it starts negative number
+func afterOperand(tokens []token) bool {
+	if len(tokens) == 0 {
+		return false
+	}
+
+	t := tokens[len(tokens)-1]
+	return t.kind != tokenOperator || t.value == ")"
+}
+
+func isIdentChar(c rune) bool {
+	return unicode.IsLetter(c) || c == '_' || c == '.' || c == '$'
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+	names  *Aggregation // Fields and functions of records set, record fields when nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) accept(op string) bool {
+	if t := p.peek(); t.kind == tokenOperator && t.value == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) acceptAny(ops ...string) (string, bool) {
+	for _, op := range ops {
+		if p.accept(op) {
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *parser) parseOr() (node, error) {
+	l, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.accept("||") {
+		r, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l = logical{"||", l, r}
+	}
+
+	return l, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	l, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.accept("&&") {
+		r, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		l = logical{"&&", l, r}
+	}
+
+	return l, nil
+}
+
+func (p *parser) parseNot() (node, error) {
+	if p.accept("!") {
+		x, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return not{x}, nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	l, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+
+	t := p.peek()
+	if t.kind != tokenOperator {
+		return l, nil
+	}
+
+	switch t.value {
+	case "==", "!=", "<", "<=", ">", ">=", "=~", "!~":
+		p.next()
+	default:
+		return l, nil
+	}
+
+	r, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+
+	c := comparison{op: t.value, l: l, r: r}
+
+	// Severity compares by rank and takes bare names, ie. severity>=error
+	if f, ok := l.(field); ok && f.name == severityField {
+		c.severity = true
+		if n, ok := r.(field); ok && logs.SeverityRank(n.name) >= 0 {
+			c.r = literal{n.name}
+		}
+	}
+
+	if t.value == "=~" || t.value == "!~" {
+		lit, ok := r.(literal)
+		if !ok {
+			return nil, fmt.Errorf("regular expression at position %d must be a string", t.pos)
+		}
+		if c.re, err = regexp.Compile(fmt.Sprint(lit.value)); err != nil {
+			return nil, fmt.Errorf("cannot compile regular expression: %w", err)
+		}
+	}
+
+	return c, nil
+}
+
+func (p *parser) parseSum() (node, error) {
+	return p.parseArithmetic(p.parseProduct, "+", "-")
+}
+
+func (p *parser) parseProduct() (node, error) {
+	return p.parseArithmetic(p.parseNegation, "*", "/")
+}
+
+func (p *parser) parseArithmetic(next func() (node, error), ops ...string) (node, error) {
+	l, err := next()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		op, ok := p.acceptAny(ops...)
+		if !ok {
+			return l, nil
+		}
+		r, err := next()
+		if err != nil {
+			return nil, err
+		}
+		l = arithmetic{op, l, r}
+	}
+}
+
+func (p *parser) parseNegation() (node, error) {
+	if p.accept("-") {
+		x, err := p.parseNegation()
+		if err != nil {
+			return nil, err
+		}
+		return arithmetic{"-", literal{0.0}, x}, nil
+	}
+
+	return p.parseOperand()
+}
+
+func (p *parser) parseOperand() (node, error) {
+	t := p.next()
+
+	switch t.kind {
+	case tokenString:
+		return literal{t.value}, nil
+	case tokenNumber:
+		n, err := strconv.ParseFloat(t.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse number '%s' at position %d: %w", t.value, t.pos, err)
+		}
+		return literal{n}, nil
+	case tokenIdent:
+		switch t.value {
+		case "true":
+			return literal{true}, nil
+		case "false":
+			return literal{false}, nil
+		case "null":
+			return literal{nil}, nil
+		}
+		if p.names != nil {
+			return p.parseName(t)
+		}
+		return field{t.value}, nil
+	case tokenOperator:
+		if t.value == "(" {
+			x, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if !p.accept(")") {
+				return nil, fmt.Errorf("missing ')' at position %d", p.peek().pos)
+			}
+			return x, nil
+		}
+	}
+
+	if t.kind == tokenEOF {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+
+	return nil, fmt.Errorf("unexpected '%s' at position %d", t.value, t.pos)
+}
+
+// Field or aggregate function call of records set, functions take optional record filter in parentheses
+func (p *parser) parseName(t token) (node, error) {
+	if slices.Contains(p.names.Functions, t.value) && p.accept("(") {
+		if p.accept(")") {
+			return call{name: t.value}, nil
+		}
+
+		names := p.names
+		p.names = nil
+		where, err := p.parseOr()
+		p.names = names
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse %s filter: %w", t.value, err)
+		}
+		if !p.accept(")") {
+			return nil, fmt.Errorf("missing ')' of %s at position %d", t.value, p.peek().pos)
+		}
+		return call{name: t.value, where: &Filter{root: where}}, nil
+	}
+
+	if slices.Contains(p.names.Fields, t.value) {
+		return field{t.value}, nil
+	}
+
+	return nil, fmt.Errorf("unknown name '%s' at position %d, use %s or number", t.value, t.pos, p.names)
+}
+
+func (l literal) eval(env) any {
+	return l.value
+}
+
+func (f field) eval(e env) any {
+	v, _ := e.resolve(f.name)
+	return v
+}
+
+func (n not) eval(e env) any {
+	return !truthy(n.x.eval(e))
+}
+
+func (l logical) eval(e env) any {
+	if l.op == "&&" {
+		return truthy(l.l.eval(e)) && truthy(l.r.eval(e))
+	}
+	return truthy(l.l.eval(e)) || truthy(l.r.eval(e))
+}
+
+func (a arithmetic) eval(e env) any {
+	x, okx := toNumber(a.l.eval(e))
+	y, oky := toNumber(a.r.eval(e))
+	if !okx || !oky {
+		return nil
+	}
+
+	switch a.op {
+	case "+":
+		return x + y
+	case "-":
+		return x - y
+	case "*":
+		return x * y
+	}
+
+	if y == 0 {
+		return 0.0
+	}
+	return x / y
+}
+
+func (c call) eval(e env) any {
+	if e.aggregate == nil {
+		return nil
+	}
+
+	return e.aggregate(c.name, c.where)
+}
+
+func (c comparison) eval(e env) any {
+	a := c.l.eval(e)
+
+	if c.re != nil {
+		m := a != nil && c.re.MatchString(toString(a))
+		return m == (c.op == "=~")
+	}
+
+	b := c.r.eval(e)
+
+	if c.severity && a != nil && b != nil {
+		if x, y := logs.SeverityRank(toString(a)), logs.SeverityRank(toString(b)); x >= 0 && y >= 0 {
+			return holds(c.op, x-y)
+		}
+	}
+
+	switch c.op {
+	case "==":
+		return equal(a, b)
+	case "!=":
+		return !equal(a, b)
+	}
+
+	if a == nil || b == nil {
+		return false
+	}
+
+	var cmp int
+	x, okx := toNumber(a)
+	y, oky := toNumber(b)
+	if okx && oky {
+		cmp = compareNumbers(x, y)
+	} else {
+		cmp = strings.Compare(toString(a), toString(b))
+	}
+
+	return holds(c.op, cmp)
+}
+
+// Result of comparison operator for sign of compared values difference
+func holds(op string, cmp int) bool {
+	switch op {
+	case "==":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	default:
+		return cmp >= 0
+	}
+}
+
+func compareNumbers(x, y float64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+func equal(a, b any) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	_, numA := a.(float64)
+	_, numB := b.(float64)
+	if numA || numB {
+		x, okx := toNumber(a)
+		y, oky := toNumber(b)
+		return okx && oky && x == y
+	}
+
+	return toString(a) == toString(b)
+}
+
+func toNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func toString(v any) string {
+	switch s := v.(type) {
+	case string:
+		return s
+	case map[string]any, []any:
+		j, _ := json.Marshal(s)
+		return string(j)
+	}
+	return fmt.Sprint(v)
+}
+
+func truthy(v any) bool {
+	switch b := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return b
+	case string:
+		return b != ""
+	case float64:
+		return b != 0
+	}
+	return true
+}
+
+// Parse expression into Filter
+func Parse(expression string) (*Filter, error) {
+	return parse(expression, nil)
+}
+
+// Aggregation names expression over set of records can use: fields of the whole set, ie. rate, and functions
+// aggregating records matching their filter argument, ie. count(severity>=error)
+type Aggregation struct {
+	Fields    []string
+	Functions []string
+}
+
+// Parse expression over records set, ie. `count(severity>=error) > 10 || rate > 2*baseline`, other names are errors
+func (a Aggregation) Parse(expression string) (*Filter, error) {
+	return parse(expression, &a)
+}
+
+// Names of aggregation listed for error message
+func (a *Aggregation) String() string {
+	names := slices.Clone(a.Fields)
+	for _, f := range a.Functions {
+		names = append(names, f+"(<filter>)")
+	}
+
+	return strings.Join(names, ", ")
+}
+
+func parse(expression string, names *Aggregation) (*Filter, error) {
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	p := parser{tokens: tokens, names: names}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected '%s' at position %d", t.value, t.pos)
+	}
+
+	return &Filter{root: root}, nil
+}
+
+// Match reports if record described by Resolver fulfils the expression
+func (f *Filter) Match(r Resolver) bool {
+	return truthy(f.root.eval(env{resolve: r}))
+}
+
+// Holds reports if records set, with fields described by Resolver and aggregated by Aggregate, fulfils the expression
+func (f *Filter) Holds(r Resolver, a Aggregate) bool {
+	return truthy(f.root.eval(env{resolve: r, aggregate: a}))
+}
+
+// LogResolver resolves `id`, `severity`, `timestamp`, `label.<key>` and `json.<path>` fields of log record.
+// User data JSON is parsed only when needed and shared with other users of the record.
+func LogResolver(l *logs.Log) Resolver {
+	return func(name string) (any, bool) {
+		switch {
+		case name == idField:
+			return l.ID, true
+		case name == severityField:
+			return l.Severity, true
+		case name == timestampField:
+			return l.Time.Format(timeFormat), true
+		case strings.HasPrefix(name, labelPrefix):
+			return l.Label(name[len(labelPrefix):])
+		case strings.HasPrefix(name, jsonPrefix):
+			data, _ := l.Data()
+			v, err := logs.GetField(data, name[len(jsonPrefix):])
+			return v, err == nil
+		}
+		return nil, false
+	}
+}
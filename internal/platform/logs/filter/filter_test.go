@@ -0,0 +1,148 @@
+package filter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/logs"
+)
+
+var record = logs.Log{
+	ID:       "2875ffa6-d102-4043-b9dd-a8daf3f7d3c7",
+	Time:     time.Date(2025, 1, 11, 18, 52, 21, 26304000, time.Local),
+	Severity: "Error",
+	UserData: `{"kubernetes":{"namespace_name":"prod","labels":{"app":"some-agent"}},"status":503,"message":"upstream timeout","ok":false}`,
+	Labels:   []logs.KeyValue{{Key: "applicationname", Value: "some-observe"}, {Key: "subsystemname", Value: "some-agent"}},
+}
+
+func TestMatch(t *testing.T) {
+
+	testCases := []struct {
+		name       string
+		expression string
+		want       bool
+	}{
+		{name: "ID", expression: `id=="2875ffa6-d102-4043-b9dd-a8daf3f7d3c7"`, want: true},
+		{name: "Severity", expression: `severity=="Error"`, want: true},
+		{name: "SeverityNotEqual", expression: `severity != "Error"`, want: false},
+		{name: "JSONField", expression: `json.kubernetes.namespace_name=="prod"`, want: true},
+		{name: "And", expression: `severity=="Error" && json.kubernetes.namespace_name=="prod"`, want: true},
+		{name: "AndFalse", expression: `severity=="Error" && json.kubernetes.namespace_name=="dev"`, want: false},
+		{name: "Or", expression: `severity=="Info" || label.applicationname=='some-observe'`, want: true},
+		{name: "Not", expression: `!(severity=="Error")`, want: false},
+		{name: "Grouping", expression: `(severity=="Info" || severity=="Error") && json.status >= 500`, want: true},
+		{name: "NumberLess", expression: `json.status < 500`, want: false},
+		{name: "NumberEqual", expression: `json.status == 503`, want: true},
+		{name: "Regexp", expression: `json.message =~ "time(out)?$"`, want: true},
+		{name: "NotRegexp", expression: `json.message !~ "^upstream"`, want: false},
+		{name: "Exists", expression: `json.kubernetes.labels.app`, want: true},
+		{name: "Missing", expression: `json.kubernetes.pod_name`, want: false},
+		{name: "MissingNotEqual", expression: `json.kubernetes.pod_name != "x"`, want: true},
+		{name: "MissingIsNull", expression: `json.kubernetes.pod_name == null`, want: true},
+		{name: "Bool", expression: `json.ok == false`, want: true},
+		{name: "Timestamp", expression: `timestamp >= "2025-01-11T18:52" && timestamp < "2025-01-11T18:53"`, want: true},
+		{name: "UnknownField", expression: `unknown == "x"`, want: false},
+		{name: "Escapes", expression: `json.message != "say \"hi\""`, want: true},
+		{name: "SeverityRank", expression: `severity >= warning`, want: true},
+		{name: "SeverityRankAbove", expression: `severity > error`, want: false},
+		{name: "SeverityRankString", expression: `severity < "Critical"`, want: true},
+		{name: "SeverityBareName", expression: `severity == error`, want: true},
+		{name: "SeverityUnknown", expression: `severity >= "audit"`, want: false},
+		{name: "Arithmetic", expression: `json.status - 3 == 500`, want: true},
+		{name: "ArithmeticPrecedence", expression: `json.status == 3 + 50 * 10`, want: true},
+		{name: "Subtraction", expression: `json.status == 504-1`, want: true},
+		{name: "NegativeOperand", expression: `-json.status < -500`, want: true},
+		{name: "ArithmeticMissing", expression: `json.kubernetes.pod_name + 1 == null`, want: true},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := Parse(tt.expression)
+			if err != nil {
+				t.Fatalf("Got an error: '%v'", err)
+			}
+
+			if got := f.Match(LogResolver(&record)); got != tt.want {
+				t.Errorf("\nGot:\t%v\nWant:\t%v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+
+	testCases := []struct {
+		name       string
+		expression string
+	}{
+		{name: "Empty", expression: ""},
+		{name: "Unterminated", expression: `severity == "Error`},
+		{name: "MissingParen", expression: `(severity == "Error"`},
+		{name: "UnknownCharacter", expression: `severity # "Error"`},
+		{name: "DanglingOperator", expression: `severity == "Error" &&`},
+		{name: "TrailingToken", expression: `severity == "Error" "Info"`},
+		{name: "RegexpNotLiteral", expression: `json.message =~ severity`},
+		{name: "BadRegexp", expression: `json.message =~ "("`},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.expression); err == nil {
+				t.Error("Should get an error!")
+			}
+		})
+	}
+}
+
+func TestAggregation(t *testing.T) {
+	names := Aggregation{Fields: []string{"count", "rate"}, Functions: []string{"count"}}
+	fields := func(name string) (any, bool) {
+		switch name {
+		case "count":
+			return 6.0, true
+		case "rate":
+			return 3.0, true
+		}
+		return nil, false
+	}
+	count := func(name string, where *Filter) any {
+		if where == nil {
+			return 6.0
+		}
+		if where.Match(LogResolver(&record)) {
+			return 1.0
+		}
+		return 0.0
+	}
+
+	testCases := []struct {
+		name       string
+		expression string
+		want       bool
+	}{
+		{name: "Field", expression: `count > 5`, want: true},
+		{name: "Function", expression: `count(severity >= error) == 1`, want: true},
+		{name: "FunctionNoMatch", expression: `count(severity < error) > 0`, want: false},
+		{name: "FunctionAll", expression: `count() == count`, want: true},
+		{name: "Arithmetic", expression: `rate > 2*count(json.status == 503) && count / 0 == 0`, want: true},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := names.Parse(tt.expression)
+			if err != nil {
+				t.Fatalf("Got an error: '%v'", err)
+			}
+
+			if got := f.Holds(fields, count); got != tt.want {
+				t.Errorf("\nGot:\t%v\nWant:\t%v", got, tt.want)
+			}
+		})
+	}
+
+	for _, expression := range []string{`errors > 1`, `count(severity == error`, `count(count(id)) > 0`} {
+		if _, err := names.Parse(expression); err == nil {
+			t.Errorf("Should get an error for '%s'!", expression)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package logs
+
+import "io"
+
+// ResponseGuard of query response body, Confirm is called once the response grows over Max bytes,
+// with bytes and records read so far. Reading ends with its error, ie. when large response is not confirmed.
+type ResponseGuard struct {
+	Max     int64
+	Confirm func(size int64, records int) error
+}
+
+// Reader asking for confirmation once more than max bytes were read
+type guardedReader struct {
+	r       io.Reader
+	max     int64
+	confirm func(size int64, records int) error
+	records *int
+	read    int64
+	asked   bool
+	err     error
+}
+
+func guard(r io.Reader, g *ResponseGuard, records *int) io.Reader {
+	if g == nil || g.Max <= 0 || g.Confirm == nil {
+		return r
+	}
+
+	return &guardedReader{r: r, max: g.Max, confirm: g.Confirm, records: records}
+}
+
+func (g *guardedReader) Read(p []byte) (int, error) {
+	if g.err != nil {
+		return 0, g.err
+	}
+
+	n, err := g.r.Read(p)
+	g.read += int64(n)
+
+	if !g.asked && g.read > g.max {
+		g.asked = true
+		if g.err = g.confirm(g.read, *g.records); g.err != nil {
+			return n, g.err
+		}
+	}
+
+	return n, err
+}
@@ -0,0 +1,60 @@
+package logs
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGuard(t *testing.T) {
+	defaultQueryURL := GetQueryURL
+	GetQueryURL = func(endpoint string) (string, error) { return endpoint, nil }
+	defer func() { GetQueryURL = defaultQueryURL }()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, respResults)
+	}))
+	defer server.Close()
+
+	errDenied := errors.New("denied")
+
+	testCases := []struct {
+		name    string
+		max     int64
+		answer  error
+		asked   int
+		records int
+		err     error
+	}{
+		{name: "Small", max: int64(len(respResults)), asked: 0, records: len(expectedLogs)},
+		{name: "Confirmed", max: 10, asked: 1, records: len(expectedLogs)},
+		{name: "Denied", max: 10, answer: errDenied, asked: 1, records: 0, err: errDenied},
+		{name: "Unlimited", max: 0, asked: 0, records: len(expectedLogs)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			asked := 0
+			confirm := func(size int64, records int) error {
+				asked++
+				if size <= tc.max {
+					t.Errorf("Asked at %d bytes, within limit %d", size, tc.max)
+				}
+				return tc.answer
+			}
+
+			l, err := QueryLogs(server.URL, "Good_Token", "Good Query", QuerySpec{Guard: &ResponseGuard{Max: tc.max, Confirm: confirm}})
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("Expected error: %v, got: %v", tc.err, err)
+			}
+			if asked != tc.asked {
+				t.Errorf("Asked %d times, want: %d", asked, tc.asked)
+			}
+			if len(l.Logs) != tc.records {
+				t.Errorf("Got %d records, want: %d", len(l.Logs), tc.records)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package logs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// IdleTimeout replaces QueryTimeout when set, query is then aborted only when no bytes arrive for this long.
+// Keepalive comments sent by the service during quiet archive scans count as activity.
+var IdleTimeout time.Duration
+
+var ErrIdleTimeout = errors.New("no data received from logs service within idle timeout")
+
+// Cancels context when reads of the reader stall for timeout, timer starts right away to cover waiting for response
+type idleWatch struct {
+	timer   *time.Timer
+	timeout time.Duration
+}
+
+func watchIdle(ctx context.Context, timeout time.Duration) (context.Context, *idleWatch, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	w := &idleWatch{timeout: timeout, timer: time.AfterFunc(timeout, func() { cancel(ErrIdleTimeout) })}
+
+	return ctx, w, func() {
+		w.timer.Stop()
+		cancel(nil)
+	}
+}
+
+func (w *idleWatch) reader(r io.Reader) io.Reader {
+	return &idleReader{r: r, w: w}
+}
+
+type idleReader struct {
+	r io.Reader
+	w *idleWatch
+}
+
+func (r *idleReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.w.timer.Reset(r.w.timeout)
+	}
+
+	return n, err
+}
+
+// Replace error of cancelled request with idle timeout, when that is the cause
+func idleError(ctx context.Context, err error) error {
+	if errors.Is(context.Cause(ctx), ErrIdleTimeout) {
+		return ErrIdleTimeout
+	}
+
+	return err
+}
@@ -0,0 +1,57 @@
+package logs
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// Server sending keepalive comments every interval for given time before results
+func keepaliveServer(interval, quiet time.Duration) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		w.(http.Flusher).Flush()
+
+		for end := time.Now().Add(quiet); time.Now().Before(end); {
+			select {
+			case <-time.After(interval):
+			case <-r.Context().Done():
+				return
+			}
+			if interval < quiet {
+				fmt.Fprint(w, ": keepalive\n")
+				w.(http.Flusher).Flush()
+			}
+		}
+
+		fmt.Fprint(w, respResults)
+	}))
+}
+
+func TestIdleTimeout(t *testing.T) {
+	defaultQueryURL := GetQueryURL
+	GetQueryURL = func(endpoint string) (string, error) { return endpoint, nil }
+	IdleTimeout = 100 * time.Millisecond
+	defer func() { GetQueryURL, IdleTimeout = defaultQueryURL, 0 }()
+
+	// Keepalives keep quiet scan longer than idle timeout going
+	server := keepaliveServer(20*time.Millisecond, 300*time.Millisecond)
+	l, err := QueryLogs(server.URL, "Good_Token", "Good Query", QuerySpec{})
+	server.Close()
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	if len(l.Logs) == 0 {
+		t.Error("Got no records")
+	}
+
+	server = keepaliveServer(300*time.Millisecond, 300*time.Millisecond)
+	defer server.Close()
+	_, err = QueryLogs(server.URL, "Good_Token", "Good Query", QuerySpec{})
+	if !errors.Is(err, ErrIdleTimeout) {
+		t.Errorf("Got error: %v, want: %v", err, ErrIdleTimeout)
+	}
+}
@@ -4,10 +4,12 @@ package logs
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptrace"
 	"net/url"
 	"reflect"
 	"slices"
@@ -24,6 +26,7 @@ const (
 	timeFormat     = "2006-01-02T15:04:05.999999"
 	timestampField = "timestamp"
 	severityField  = "severity"
+	idField        = "logid"
 )
 
 const queryPath = "/v1/query"
@@ -36,22 +39,61 @@ type QuerySpec struct {
 	Tier      tier.Tier     `json:"tier"`
 	StartDate time.Time     `json:"start_date"`
 	EndDate   time.Time     `json:"end_date"`
+
+	Guard *ResponseGuard `json:"-"` // Of response size, not sent to the service
 }
 
 type KeyValue struct {
 	Key   string `json:"key"`
 	Value string `json:"value"`
 }
+
+// String formats label as `key:"value"`
+func (kv KeyValue) String() string {
+	return kv.Key + ":\"" + kv.Value + "\""
+}
+
+// Severities of records from the least severe
+var Severities = []string{"Debug", "Verbose", "Info", "Warning", "Error", "Critical"}
+
+// SeverityRank is position of severity in Severities regardless of case, -1 for unknown one
+func SeverityRank(name string) int {
+	for i, s := range Severities {
+		if strings.EqualFold(name, s) {
+			return i
+		}
+	}
+	return -1
+}
+
 type Log struct {
+	ID       string
 	Time     time.Time
 	Severity string
-	UserData string // RAW User Data JSON string
-	Labels   []string
+	UserData string     // RAW User Data JSON string
+	Labels   []KeyValue // Labels as received, formatted only when shown
+
+	data *parsedData // Parsed UserData shared by all features needing it, see Data
+}
+
+type parsedData struct {
+	raw string // UserData the map was parsed from
+	m   map[string]any
+	err error
 }
 
 type Result struct {
 	Logs     []Log
 	Warnings []string
+	Timings  Timings
+}
+
+// Timings of query phases, token fetch is measured by caller as token is given ready
+type Timings struct {
+	Token     time.Duration
+	FirstByte time.Duration // From sending request to first byte of response
+	Request   time.Duration // From sending request to response headers
+	Parse     time.Duration // Reading and parsing of streamed response body
 }
 
 type Record struct {
@@ -86,12 +128,31 @@ var QueryTimeout = time.Duration(3) * time.Minute // HTTP query timeout - defaul
 
 var MessageKeywords = [...]string{"message", "message_obj.msg", "log"} // Potential message fields
 
+// Payload of query request as sent to the logs endpoint
+func Payload(query string, spec QuerySpec) ([]byte, error) {
+	q := Query{Query: query}
+
+	if spec.Guard = nil; spec != (QuerySpec{}) {
+		meta := make(map[string]any)
+		structToMap(spec, &meta)
+
+		q.Metadata = &meta
+	}
+
+	j, err := json.Marshal(q)
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal payload: %w", err)
+	}
+
+	return j, nil
+}
+
 func structToMap(data any, m *map[string]any) {
 	fields := reflect.VisibleFields(reflect.TypeOf(data))
 	values := reflect.ValueOf(data)
 	for _, field := range fields {
 		v := values.FieldByName(field.Name)
-		if v.IsZero() {
+		if v.IsZero() || field.Tag.Get("json") == "-" {
 			continue
 		}
 
@@ -111,45 +172,119 @@ func getValue(kv []KeyValue, key string) (string, error) {
 	return "", fmt.Errorf("cannot find value for key: '%s'", key)
 }
 
-func traverseMap(m map[string]any, keys []string) (string, error) {
+func traverseMap(m map[string]any, keys []string) (any, error) {
 
 	key := keys[0]
 	v, ok := m[key]
 
 	if !ok {
-		return "", fmt.Errorf("key '%s' was not found in map", key)
+		return nil, fmt.Errorf("key '%s' was not found in map", key)
 	}
 
 	if len(keys) == 1 {
-		return fmt.Sprintf("%v", v), nil // let's convert always to string
+		return v, nil
+	}
+
+	next, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("key '%s' is not an object", key)
+	}
+
+	return traverseMap(next, keys[1:])
+
+}
+
+// Label returns value of record label with given key
+func (l *Log) Label(key string) (string, bool) {
+	for _, label := range l.Labels {
+		if label.Key == key {
+			return label.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// FormatLabels returns labels formatted as `key:"value"`
+func (l *Log) FormatLabels() []string {
+	return FormatLabels(l.Labels)
+}
+
+// FormatLabels returns given labels formatted as `key:"value"`
+func FormatLabels(labels []KeyValue) []string {
+	f := make([]string, len(labels))
+	for i, label := range labels {
+		f[i] = label.String()
+	}
+
+	return f
+}
+
+// ParseUserData unmarshal User Data JSON string into map
+func ParseUserData(userData string) (map[string]any, error) {
+
+	ud := make(map[string]any) // let's use map as `user_data`` can be really anything ...
+	if err := json.Unmarshal([]byte(userData), &ud); err != nil {
+		return nil, fmt.Errorf("cannot unmarshal user data: %w", err)
+	}
+
+	return ud, nil
+}
+
+// Data returns parsed user data. It is parsed on first use and shared until UserData changes.
+func (l *Log) Data() (map[string]any, error) {
+	if l.data == nil || l.data.raw != l.UserData {
+		m, err := ParseUserData(l.UserData)
+		l.data = &parsedData{raw: l.UserData, m: m, err: err}
+	}
+
+	return l.data.m, l.data.err
+}
+
+// Message returns the first of message fields found in user data, converted to string
+func (l *Log) Message(keyNames []string) (string, error) {
+	ud, err := l.Data()
+	if err != nil {
+		return "", err
 	}
 
-	return traverseMap(v.(map[string]any), keys[1:])
+	return findMessage(ud, keyNames)
+}
+
+// GetField retrieve value from parsed User Data by dot separated path, dotted key itself (ie. of flattened data) is found too
+func GetField(userData map[string]any, path string) (any, error) {
+	if v, ok := userData[path]; ok {
+		return v, nil
+	}
 
+	return traverseMap(userData, strings.Split(path, "."))
 }
 
 // GetMessage retrieve string from User Data JSON by specifying message key
 func GetMessage(userData *string, keyNames *[]string) (string, error) {
 
-	ud := make(map[string]any) // let's use map as `user_data`` can be really anything ...
-	if err := json.Unmarshal([]byte(*userData), &ud); err != nil {
-		return "", fmt.Errorf("cannot unmarshal user data: %w", err)
+	ud, err := ParseUserData(*userData)
+	if err != nil {
+		return "", err
 	}
 
+	return findMessage(ud, *keyNames)
+}
+
+func findMessage(ud map[string]any, keyNames []string) (string, error) {
 	var (
-		msg string
+		v   any
 		err error
 	)
 
-	for _, k := range *keyNames {
-		keys := strings.Split(k, ".")
-		msg, err = traverseMap(ud, keys)
+	for _, k := range keyNames {
+		v, err = GetField(ud, k)
 		if err == nil {
-			break
+			return fmt.Sprintf("%v", v), nil // let's convert always to string
 		}
 	}
 
-	return msg, err
+	return "", err
 }
 
 func parseRecord(record *Record) (Log, error) {
@@ -169,25 +304,24 @@ func parseRecord(record *Record) (Log, error) {
 		return Log{}, fmt.Errorf("cannot parse timestamp: %w", err)
 	}
 
-	labels := make([]string, len(record.Labels))
-	for i, label := range record.Labels {
-		labels[i] = fmt.Sprintf("%s:\"%s\"", label.Key, label.Value)
-	}
+	id, _ := getValue(record.Metadata, idField) // ID is nice to have, not required
 
 	log := Log{
+		ID:       id,
 		Time:     t,
 		Severity: severity,
 		UserData: record.Data,
-		Labels:   labels,
+		Labels:   record.Labels, // Each message is decoded into new records, so labels can be shared
 	}
 
 	return log, nil
 }
 
-func parseResponse(response io.Reader) ([]Log, []string, error) {
+// Parse streamed response calling fn with raw records of each data message
+func streamRecords(response io.Reader, fn func([]Record) error) ([]string, error) {
 
-	logs := []Log{}
 	var warnings []string
+	prefix := []byte(dataPrefix)
 
 	scanner := bufio.NewScanner(response)
 
@@ -195,28 +329,22 @@ func parseResponse(response io.Reader) ([]Log, []string, error) {
 	scanner.Buffer(buf, maxLineSize)
 
 	for scanner.Scan() {
-		line := scanner.Text()
-
-		if !strings.HasPrefix(line, dataPrefix) {
+		// Scanner buffer is reused for every line, decoding straight from it avoids copying big lines
+		d, ok := bytes.CutPrefix(scanner.Bytes(), prefix)
+		if !ok {
 			continue
 		}
 
-		d := line[len(dataPrefix):]
 		data := MessageResult{}
 
-		if err := json.Unmarshal([]byte(d), &data); err != nil {
-			return nil, nil, fmt.Errorf("cannot unmarshal data line payload: %w", err)
+		if err := json.Unmarshal(d, &data); err != nil {
+			return nil, fmt.Errorf("cannot unmarshal data line payload: %w", err)
 		}
 
-		for _, r := range data.Result.Results {
-
-			l, err := parseRecord(&r)
-			if err != nil {
-				return nil, nil, fmt.Errorf("cannot parse record from results: %w", err)
+		if len(data.Result.Results) > 0 {
+			if err := fn(data.Result.Results); err != nil {
+				return nil, err
 			}
-
-			logs = append(logs, l)
-
 		}
 
 		if m := data.Warning.Compile.Message; m != "" {
@@ -228,29 +356,97 @@ func parseResponse(response io.Reader) ([]Log, []string, error) {
 	}
 
 	if err := scanner.Err(); err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	// Sort logs
-	sort.Slice(logs, func(i, j int) bool { return logs[i].Time.Compare(logs[j].Time) < 0 })
+	return warnings, nil
+}
+
+// ParseRecords returns log records of raw records sorted by time
+func ParseRecords(records []Record) ([]Log, error) {
+	logs := make([]Log, 0, len(records))
+	for i := range records {
 
-	return logs, warnings, nil
+		l, err := parseRecord(&records[i])
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse record from results: %w", err)
+		}
+
+		logs = append(logs, l)
+
+	}
+	sortLogs(logs)
+
+	return logs, nil
+}
+
+func sortLogs(logs []Log) {
+	sort.SliceStable(logs, func(i, j int) bool { return logs[i].Time.Compare(logs[j].Time) < 0 })
+}
+
+// HTTPError of logs service response
+type HTTPError struct {
+	StatusCode int
+	Message    string
 }
 
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("got HTTP error code: %d, message: '%s'", e.StatusCode, e.Message)
+}
+
+// QueryLogs returns all found records sorted by time
 func QueryLogs(endpoint, token, query string, spec QuerySpec) (Result, error) {
+	return QueryLogsContext(context.Background(), endpoint, token, query, spec)
+}
 
-	q := Query{Query: query}
+// QueryLogsContext is QueryLogs with context, which can end the query before QueryTimeout.
+// When context ends the query, records received so far are returned with the error.
+func QueryLogsContext(ctx context.Context, endpoint, token, query string, spec QuerySpec) (Result, error) {
 
-	if spec != (QuerySpec{}) {
-		meta := make(map[string]any)
-		structToMap(spec, &meta)
+	logs := []Log{}
 
-		q.Metadata = &meta
+	r, err := StreamLogsContext(ctx, endpoint, token, query, spec, func(l []Log) error {
+		logs = append(logs, l...)
+		return nil
+	})
+	if err != nil && ctx.Err() != nil {
+		sortLogs(logs)
+		return Result{Logs: logs}, err
+	}
+	if err != nil {
+		return Result{}, err
 	}
 
-	j, err := json.Marshal(q)
+	sortLogs(logs)
+	r.Logs = logs
+
+	return r, nil
+}
+
+// StreamLogs calls fn with found records as they arrive, without keeping them. Records are sorted by time
+// only within one batch, result has no records.
+func StreamLogs(endpoint, token, query string, spec QuerySpec, fn func([]Log) error) (Result, error) {
+	return StreamLogsContext(context.Background(), endpoint, token, query, spec, fn)
+}
+
+// StreamLogsContext is StreamLogs with context, which can end the query before QueryTimeout
+func StreamLogsContext(ctx context.Context, endpoint, token, query string, spec QuerySpec, fn func([]Log) error) (Result, error) {
+	return StreamRecordsContext(ctx, endpoint, token, query, spec, func(records []Record) error {
+		logs, err := ParseRecords(records)
+		if err != nil {
+			return err
+		}
+		return fn(logs)
+	})
+}
+
+// StreamRecordsContext is StreamLogsContext calling fn with raw records of each data message, which are rows
+// without metadata for Dataprime aggregations
+func StreamRecordsContext(ctx context.Context, endpoint, token, query string, spec QuerySpec, fn func([]Record) error) (Result, error) {
+
+	j, err := Payload(query, spec)
 	if err != nil {
-		return Result{}, fmt.Errorf("cannot marshal payload: %w", err)
+		return Result{}, err
 	}
 
 	payload := bytes.NewBuffer(j)
@@ -261,7 +457,21 @@ func QueryLogs(endpoint, token, query string, spec QuerySpec) (Result, error) {
 	}
 
 	c := http.Client{Timeout: QueryTimeout}
-	req, err := http.NewRequest("POST", addr, payload)
+
+	body := func(r io.Reader) io.Reader { return r }
+	if IdleTimeout > 0 {
+		c.Timeout = 0
+
+		var (
+			idle   *idleWatch
+			cancel context.CancelFunc
+		)
+		ctx, idle, cancel = watchIdle(ctx, IdleTimeout)
+		defer cancel()
+		body = idle.reader
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", addr, payload)
 	if err != nil {
 		return Result{}, fmt.Errorf("cannot create POST request: %w", err)
 	}
@@ -269,13 +479,25 @@ func QueryLogs(endpoint, token, query string, spec QuerySpec) (Result, error) {
 	req.Header.Add("content-type", "application/json")
 	req.Header.Add("authorization", "Bearer "+token)
 
+	var firstByte time.Time
+	trace := &httptrace.ClientTrace{GotFirstResponseByte: func() { firstByte = time.Now() }}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+
+	start := time.Now()
 	resp, err := c.Do(req)
 
 	if err != nil {
-		return Result{}, fmt.Errorf("cannot POST data: %w", err)
+		return Result{}, fmt.Errorf("cannot POST data: %w", idleError(ctx, err))
+	}
+
+	t := Timings{Request: time.Since(start)}
+	if !firstByte.IsZero() {
+		t.FirstByte = firstByte.Sub(start)
 	}
 	defer resp.Body.Close()
 
+	skew, _ := measureSkew(resp.Header, start, start.Add(t.Request))
+
 	if resp.StatusCode != 200 {
 		body, err := io.ReadAll(resp.Body)
 
@@ -283,15 +505,31 @@ func QueryLogs(endpoint, token, query string, spec QuerySpec) (Result, error) {
 			return Result{}, fmt.Errorf("cannot read body: %w", err)
 		}
 
-		return Result{}, fmt.Errorf("got HTTP error code: %d, message: '%s'", resp.StatusCode, body)
+		err = &HTTPError{StatusCode: resp.StatusCode, Message: string(body)}
+		if skewed(skew) {
+			err = ClockSkewError{Skew: skew, Err: err}
+		}
+
+		return Result{}, err
 	}
 
-	l, w, err := parseResponse(resp.Body)
+	start = time.Now()
+	records := 0
+	count := func(b []Record) error {
+		records += len(b)
+		return fn(b)
+	}
+	w, err := streamRecords(guard(throttle(body(resp.Body), MaxBandwidth), spec.Guard, &records), count)
 
 	if err != nil {
-		return Result{}, fmt.Errorf("error when parsing results: %w", err)
+		return Result{}, fmt.Errorf("error when parsing results: %w", idleError(ctx, err))
+	}
+	t.Parse = time.Since(start)
+
+	if skewed(skew) {
+		w = append(w, describeSkew(skew)+", time range may miss or include unexpected records")
 	}
 
-	return Result{Logs: l, Warnings: w}, nil
+	return Result{Warnings: w, Timings: t}, nil
 
 }
@@ -1,7 +1,9 @@
 package logs
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -86,34 +88,38 @@ func mockServer(response string) *httptest.Server {
 	return httptest.NewServer(http.HandlerFunc(f))
 }
 
-var expectedLabels = []string{
-	"applicationname:\"some-observe\"",
-	"subsystemname:\"some-agent\"",
-	"computername:\"\"",
-	"threadid:\"\"",
-	"ipaddress:\"\"",
+var expectedLabels = []KeyValue{
+	{Key: "applicationname", Value: "some-observe"},
+	{Key: "subsystemname", Value: "some-agent"},
+	{Key: "computername", Value: ""},
+	{Key: "threadid", Value: ""},
+	{Key: "ipaddress", Value: ""},
 }
 
 var expectedLogs = []Log{
 	{
+		ID:       "2875ffa6-d102-4043-b9dd-a8daf3f7d3c7",
 		Time:     time.Date(2025, 1, 11, 18, 52, 21, 26304000, time.Local),
 		Severity: "Debug",
 		UserData: `{"node_name":"10.10.10.10","kubernetes":{"annotations":{"kubectl.kubernetes.io/restartedAt":"2024-03-15T11:44:11+05:30","kubernetes.io/config.seen":"2025-01-06T08:44:29.371412369Z","kubernetes.io/config.source":"api"},"container_hash":"url.com/ext/some/agent@sha256:7594347727a76fab1b6759575d84389ac1788bff6782046b330c730d67db790c","container_image":"url.com/ext/some/agent:latest","container_name":"some-agent","docker_id":"7ca9add76b8a725f0da735a948cb133965de0eb36ac31d6252060eaaaabb0fb7","host":"10.10.10.10","labels":{"app":"some-agent","controller-revision-hash":"f69c8df74","pod-template-generation":"12"},"namespace_name":"some-observe","pod_id":"3ba098ee-cc88-4cb7-b986-f61e182b6936","pod_name":"some-agent-c7gz7"},"tag":"kube.var.log.containers.some-agent-c7gz7_some-observe_some-agent-7ca9add76b8a725f0da735a948cb133965de0eb36ac31d6252060eaaaabb0fb7.log","meta":{"cluster_name":"wml-core-dallas-yp-qa"},"stream":"stdout","logtag":"F","message":"2025-01-11 18:52:23.025, 347267.347747, Debug, Example message first","file":"/var/log/containers/some-agent-c7gz7_some-observe_some-agent-7ca9add76b8a725f0da735a948cb133965de0eb36ac31d6252060eaaaabb0fb7.log"}`,
 		Labels:   expectedLabels,
 	},
 	{
+		ID:       "dc1a1257-a13a-4e9a-beca-f4ed5bc8cc2a",
 		Time:     time.Date(2025, 1, 11, 18, 52, 21, 26360000, time.Local),
 		Severity: "Info",
 		UserData: `{"node_name":"10.10.10.10","kubernetes":{"annotations":{"kubectl.kubernetes.io/restartedAt":"2024-03-15T11:44:11+05:30","kubernetes.io/config.seen":"2025-01-06T08:44:29.371412369Z","kubernetes.io/config.source":"api"},"container_hash":"url.com/ext/some/agent@sha256:7594347727a76fab1b6759575d84389ac1788bff6782046b330c730d67db790c","container_image":"url.com/ext/some/agent:latest","container_name":"some-agent","docker_id":"7ca9add76b8a725f0da735a948cb133965de0eb36ac31d6252060eaaaabb0fb7","host":"10.10.10.10","labels":{"app":"some-agent","controller-revision-hash":"f69c8df74","pod-template-generation":"12"},"namespace_name":"some-observe","pod_id":"3ba098ee-cc88-4cb7-b986-f61e182b6936","pod_name":"some-agent-c7gz7"},"tag":"kube.var.log.containers.some-agent-c7gz7_some-observe_some-agent-7ca9add76b8a725f0da735a948cb133965de0eb36ac31d6252060eaaaabb0fb7.log","meta":{"cluster_name":"wml-core-dallas-yp-qa"},"stream":"stdout","logtag":"F","message":"2025-01-11 18:52:23.026, 347267.347747, Information, second message","file":"/var/log/containers/some-agent-c7gz7_some-observe_some-agent-7ca9add76b8a725f0da735a948cb133965de0eb36ac31d6252060eaaaabb0fb7.log"}`,
 		Labels:   expectedLabels,
 	},
 	{
+		ID:       "2875ffa6-d102-4043-b9dd-a8daf3f7d3c7",
 		Time:     time.Date(2025, 1, 11, 18, 52, 23, 26304000, time.Local),
 		Severity: "Info",
 		UserData: `{"node_name":"10.10.10.10","kubernetes":{"annotations":{"kubectl.kubernetes.io/restartedAt":"2024-03-15T11:44:11+05:30","kubernetes.io/config.seen":"2025-01-06T08:44:29.371412369Z","kubernetes.io/config.source":"api"},"container_hash":"url.com/ext/some/agent@sha256:7594347727a76fab1b6759575d84389ac1788bff6782046b330c730d67db790c","container_image":"url.com/ext/some/agent:latest","container_name":"some-agent","docker_id":"7ca9add76b8a725f0da735a948cb133965de0eb36ac31d6252060eaaaabb0fb7","host":"10.10.10.10","labels":{"app":"some-agent","controller-revision-hash":"f69c8df74","pod-template-generation":"12"},"namespace_name":"some-observe","pod_id":"3ba098ee-cc88-4cb7-b986-f61e182b6936","pod_name":"some-agent-c7gz7"},"tag":"kube.var.log.containers.some-agent-c7gz7_some-observe_some-agent-7ca9add76b8a725f0da735a948cb133965de0eb36ac31d6252060eaaaabb0fb7.log","meta":{"cluster_name":"wml-core-dallas-yp-qa"},"stream":"stdout","logtag":"F","message":"2025-01-11 18:52:23.025, 347267.347747, Information, Example message","file":"/var/log/containers/some-agent-c7gz7_some-observe_some-agent-7ca9add76b8a725f0da735a948cb133965de0eb36ac31d6252060eaaaabb0fb7.log"}`,
 		Labels:   expectedLabels,
 	},
 	{
+		ID:       "dc1a1257-a13a-4e9a-beca-f4ed5bc8cc2a",
 		Time:     time.Date(2025, 1, 11, 18, 52, 23, 26360000, time.Local),
 		Severity: "Info",
 		UserData: `{"node_name":"10.10.10.10","kubernetes":{"annotations":{"kubectl.kubernetes.io/restartedAt":"2024-03-15T11:44:11+05:30","kubernetes.io/config.seen":"2025-01-06T08:44:29.371412369Z","kubernetes.io/config.source":"api"},"container_hash":"url.com/ext/some/agent@sha256:7594347727a76fab1b6759575d84389ac1788bff6782046b330c730d67db790c","container_image":"url.com/ext/some/agent:latest","container_name":"some-agent","docker_id":"7ca9add76b8a725f0da735a948cb133965de0eb36ac31d6252060eaaaabb0fb7","host":"10.10.10.10","labels":{"app":"some-agent","controller-revision-hash":"f69c8df74","pod-template-generation":"12"},"namespace_name":"some-observe","pod_id":"3ba098ee-cc88-4cb7-b986-f61e182b6936","pod_name":"some-agent-c7gz7"},"tag":"kube.var.log.containers.some-agent-c7gz7_some-observe_some-agent-7ca9add76b8a725f0da735a948cb133965de0eb36ac31d6252060eaaaabb0fb7.log","meta":{"cluster_name":"wml-core-dallas-yp-qa"},"stream":"stdout","logtag":"F","message":"2025-01-11 18:52:23.026, 347267.347747, Information, Next message","file":"/var/log/containers/some-agent-c7gz7_some-observe_some-agent-7ca9add76b8a725f0da735a948cb133965de0eb36ac31d6252060eaaaabb0fb7.log"}`,
@@ -168,6 +174,11 @@ func TestQueryLogs(t *testing.T) {
 				return
 			}
 
+			if tt.err == nil && got.Timings.Request <= 0 {
+				t.Errorf("Request time should be measured, got: %v", got.Timings)
+			}
+			got.Timings = Timings{}
+
 			if !reflect.DeepEqual(tt.want, got) {
 				t.Errorf("\nGot:\t'%+v',\nWant:\t'%+v'", got, tt.want)
 			}
@@ -177,6 +188,76 @@ func TestQueryLogs(t *testing.T) {
 
 }
 
+func TestLabels(t *testing.T) {
+	l := Log{Labels: expectedLabels[:2]}
+
+	if v, ok := l.Label("subsystemname"); !ok || v != "some-agent" {
+		t.Errorf("Got: '%s', %v, want: 'some-agent', true", v, ok)
+	}
+	if _, ok := l.Label("missing"); ok {
+		t.Error("Missing label should not be found")
+	}
+
+	want := []string{`applicationname:"some-observe"`, `subsystemname:"some-agent"`}
+	if got := l.FormatLabels(); !reflect.DeepEqual(got, want) {
+		t.Errorf("\nGot:\t%v\nWant:\t%v", got, want)
+	}
+}
+
+func TestData(t *testing.T) {
+	l := Log{UserData: `{"message":"first","level":"info"}`}
+
+	first, err := l.Data()
+	if err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+	first["shared"] = true
+
+	// Parsed map is shared until user data changes
+	if again, _ := l.Data(); again["shared"] != true {
+		t.Error("User data should be parsed only once")
+	}
+
+	l.UserData = `{"message":"second"}`
+	if msg, err := l.Message([]string{"msg", "message"}); err != nil || msg != "second" {
+		t.Errorf("Got: '%s', '%v', want: 'second'", msg, err)
+	}
+
+	l.UserData = `not json`
+	if _, err := l.Message([]string{"message"}); err == nil {
+		t.Error("Should get an error for invalid user data!")
+	}
+}
+
+func TestStreamLogs(t *testing.T) {
+	server := mockServer(respResults)
+	defer server.Close()
+
+	var batches [][]Log
+	r, err := StreamLogs(server.URL, "Good_Token", "Good Query", QuerySpec{Syntax: syntax.Lucene}, func(l []Log) error {
+		batches = append(batches, l)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+
+	if len(batches) != 2 {
+		t.Fatalf("Want 2 batches, got: %d", len(batches))
+	}
+	if got := len(batches[0]) + len(batches[1]); got != len(expectedLogs) {
+		t.Errorf("Want %d records, got: %d", len(expectedLogs), got)
+	}
+	if r.Logs != nil {
+		t.Errorf("Streamed result should not keep records, got: %d", len(r.Logs))
+	}
+
+	stop := errors.New("stop")
+	if _, err := StreamLogs(server.URL, "Good_Token", "Good Query", QuerySpec{}, func([]Log) error { return stop }); !errors.Is(err, stop) {
+		t.Errorf("Want callback error, got: '%v'", err)
+	}
+}
+
 func TestGetMessage(t *testing.T) {
 
 	testCases := []struct {
@@ -189,6 +270,7 @@ func TestGetMessage(t *testing.T) {
 		{name: "Message", userData: userData["message"], keyNames: []string{"message"}, want: "2025-01-11 18:52:23.025, 347267.347747, Debug, Example message first", err: false},
 		{name: "MessageObj", userData: userData["message_obj"], keyNames: []string{"message_obj.msg"}, want: "2025-01-11 18:52:23.025, 347267.347747, Information, Example message", err: false},
 		{name: "Error", userData: userData["message"], keyNames: []string{"message_obj.msg"}, want: "", err: true},
+		{name: "Flattened", userData: `{"message_obj.msg":"flat"}`, keyNames: []string{"message_obj.msg"}, want: "flat", err: false},
 		{name: "Log", userData: userData["log"], keyNames: []string{"message_obj.msg", "message", "log"}, want: "2025-01-11 18:52:23.025, 347267.347747, Debug, Example message first", err: false},
 	}
 
@@ -214,3 +296,61 @@ func TestGetMessage(t *testing.T) {
 		})
 	}
 }
+
+func BenchmarkStreamResponse(b *testing.B) {
+	response := strings.Repeat(respResults, 500)
+	b.ReportAllocs()
+
+	for b.Loop() {
+		_, err := streamRecords(strings.NewReader(response), func(r []Record) error {
+			_, err := ParseRecords(r)
+			return err
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestQueryLogsCancelled(t *testing.T) {
+	defaultQueryURL := GetQueryURL
+	GetQueryURL = func(endpoint string) (string, error) { return endpoint, nil }
+	defer func() { GetQueryURL = defaultQueryURL }()
+
+	// Results arrive, then the scan goes on
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, respResults)
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	l, err := QueryLogsContext(ctx, server.URL, "Good_Token", "Good Query", QuerySpec{})
+	if err == nil {
+		t.Fatal("Expected error of cancelled query")
+	}
+	if len(l.Logs) != len(expectedLogs) {
+		t.Errorf("Got %d records, want %d received before cancel", len(l.Logs), len(expectedLogs))
+	}
+}
+
+func TestPayload(t *testing.T) {
+	start := time.Date(2025, 1, 11, 12, 0, 0, 0, time.UTC)
+	spec := QuerySpec{Syntax: syntax.Lucene, Tier: tier.Archive, Limit: tier.LimitArchive, StartDate: start, EndDate: start.Add(time.Hour)}
+
+	got, err := Payload("app:web", spec)
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	want := `{"query":"app:web","metadata":{"end_date":"2025-01-11T13:00:00Z","limit":50000,"start_date":"2025-01-11T12:00:00Z","syntax":"lucene","tier":"archive"}}`
+	if string(got) != want {
+		t.Errorf("Got: %s, want: %s", got, want)
+	}
+
+	if got, _ := Payload("app:web", QuerySpec{}); string(got) != `{"query":"app:web","metadata":null}` {
+		t.Errorf("Expected payload without metadata, got: %s", got)
+	}
+}
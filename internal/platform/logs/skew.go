@@ -0,0 +1,54 @@
+package logs
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// MaxClockSkew is difference between local and service clock above which query time range is not trustworthy
+var MaxClockSkew = time.Minute
+
+// ClockSkewError wraps query error when local clock differs from service clock more than MaxClockSkew,
+// as skewed time range is the likely cause
+type ClockSkewError struct {
+	Skew time.Duration // Local clock minus service clock
+	Err  error
+}
+
+func (e ClockSkewError) Error() string {
+	return fmt.Sprintf("%s, fix system time: %v", describeSkew(e.Skew), e.Err)
+}
+
+func (e ClockSkewError) Unwrap() error {
+	return e.Err
+}
+
+func describeSkew(skew time.Duration) string {
+	if skew > 0 {
+		return fmt.Sprintf("local clock is %s ahead of service clock", skew.Round(time.Second))
+	}
+
+	return fmt.Sprintf("local clock is %s behind service clock", (-skew).Round(time.Second))
+}
+
+// Measure skew from response Date header against the middle of request, as the header has only second precision
+// skew below one second is not reported. Returns false without Date header.
+func measureSkew(h http.Header, sent, received time.Time) (time.Duration, bool) {
+	date, err := http.ParseTime(h.Get("Date"))
+	if err != nil {
+		return 0, false
+	}
+
+	skew := sent.Add(received.Sub(sent) / 2).Sub(date)
+	if skew > -time.Second && skew < time.Second {
+		return 0, true
+	}
+
+	return skew, true
+}
+
+// Skew above MaxClockSkew in either direction
+func skewed(skew time.Duration) bool {
+	return skew > MaxClockSkew || skew < -MaxClockSkew
+}
@@ -0,0 +1,84 @@
+package logs
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMeasureSkew(t *testing.T) {
+	sent := time.Date(2025, 1, 31, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name string
+		date string
+		want time.Duration
+		ok   bool
+	}{
+		{name: "InSync", date: "Fri, 31 Jan 2025 12:00:01 GMT", want: 0, ok: true},
+		{name: "Ahead", date: "Fri, 31 Jan 2025 11:55:01 GMT", want: 5 * time.Minute, ok: true},
+		{name: "Behind", date: "Fri, 31 Jan 2025 12:10:01 GMT", want: -10 * time.Minute, ok: true},
+		{name: "Missing", date: "", want: 0, ok: false},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.Header{}
+			if tt.date != "" {
+				h.Set("Date", tt.date)
+			}
+
+			got, ok := measureSkew(h, sent, sent.Add(2*time.Second))
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("Got: %v, %v, Want: %v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func skewedServer(status int, skew time.Duration, response string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", time.Now().Add(-skew).UTC().Format(http.TimeFormat))
+		w.WriteHeader(status)
+		fmt.Fprint(w, response)
+	}))
+}
+
+func TestStreamLogsSkew(t *testing.T) {
+	defaultQueryURL := GetQueryURL
+	GetQueryURL = func(endpoint string) (string, error) { return endpoint, nil }
+	defer func() { GetQueryURL = defaultQueryURL }()
+
+	server := skewedServer(200, 10*time.Minute, respResults)
+	l, err := QueryLogs(server.URL, "Good_Token", "Good Query", QuerySpec{})
+	server.Close()
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	if n := len(l.Warnings); n == 0 || !strings.Contains(l.Warnings[n-1], "ahead of service clock") {
+		t.Errorf("Got warnings: %v, want clock skew warning", l.Warnings)
+	}
+
+	server = skewedServer(400, -10*time.Minute, "Invalid time range")
+	_, err = QueryLogs(server.URL, "Good_Token", "Good Query", QuerySpec{})
+	server.Close()
+
+	skewErr := ClockSkewError{}
+	if !errors.As(err, &skewErr) {
+		t.Fatalf("Got error: %v, want ClockSkewError", err)
+	}
+	if skewErr.Skew > -9*time.Minute || !strings.Contains(err.Error(), "behind service clock, fix system time") {
+		t.Errorf("Got error: %v", err)
+	}
+
+	server = skewedServer(400, 0, "Invalid query")
+	_, err = QueryLogs(server.URL, "Good_Token", "Good Query", QuerySpec{})
+	server.Close()
+	if errors.As(err, &skewErr) {
+		t.Errorf("Got error: %v, want no ClockSkewError", err)
+	}
+}
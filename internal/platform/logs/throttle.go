@@ -0,0 +1,49 @@
+package logs
+
+import (
+	"io"
+	"time"
+)
+
+// MaxBandwidth limits reading of query response bodies to bytes per second when set, ie. during bulk exports
+var MaxBandwidth int64
+
+// Reads are split so throttled reader waits a fraction of a second at most
+const throttleSlices = 10
+
+// Reader keeping average rate of bytes read at most rate per second since its first read
+type throttledReader struct {
+	r     io.Reader
+	rate  int64
+	start time.Time
+	read  int64
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+func throttle(r io.Reader, rate int64) io.Reader {
+	if rate <= 0 {
+		return r
+	}
+
+	return &throttledReader{r: r, rate: rate, now: time.Now, sleep: time.Sleep}
+}
+
+func (t *throttledReader) Read(p []byte) (int, error) {
+	if t.start.IsZero() {
+		t.start = t.now()
+	}
+	if max := max(t.rate/throttleSlices, 1); int64(len(p)) > max {
+		p = p[:max]
+	}
+
+	n, err := t.r.Read(p)
+	t.read += int64(n)
+
+	due := time.Duration(float64(t.read) / float64(t.rate) * float64(time.Second))
+	if wait := due - t.now().Sub(t.start); wait > 0 {
+		t.sleep(wait)
+	}
+
+	return n, err
+}
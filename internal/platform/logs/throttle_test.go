@@ -0,0 +1,40 @@
+package logs
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestThrottle(t *testing.T) {
+	clock := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	start := clock
+	reads := 0
+
+	data := strings.Repeat("x", 1000)
+	r := &throttledReader{
+		r:     strings.NewReader(data),
+		rate:  500,
+		now:   func() time.Time { return clock },
+		sleep: func(d time.Duration) { reads++; clock = clock.Add(d) },
+	}
+
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	if string(got) != data {
+		t.Errorf("Got %d bytes, want: %d", len(got), len(data))
+	}
+	if took := clock.Sub(start); took != 2*time.Second {
+		t.Errorf("Took: %s, want 2s for 1000 bytes at 500 per second", took)
+	}
+	if reads != 20 {
+		t.Errorf("Got %d waits, want: 20 reads of 50 bytes", reads)
+	}
+
+	if r := strings.NewReader(data); throttle(r, 0) != r {
+		t.Error("Expected reader without limit unchanged")
+	}
+}
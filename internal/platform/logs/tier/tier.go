@@ -1,6 +1,8 @@
 // Package tier to have some logs API constants
 package tier
 
+import "strings"
+
 type Tier string
 
 const (
@@ -13,3 +15,29 @@ const (
 	LimitFrequent int = 12000
 	LimitArchive  int = 50000
 )
+
+// TCO policy tiers, records of priority insights tier are searchable in frequent search
+const (
+	PriorityInsights = "priority_insights"
+	AnalyzeAndAlert  = "analyze_and_alert"
+	StoreAndSearch   = "store_and_search"
+	Blocked          = "blocked"
+)
+
+// TCO policy tiers by priority as named by policies (type_high) and data usage (high) APIs
+var policyTiers = map[string]string{
+	"high":    PriorityInsights,
+	"medium":  AnalyzeAndAlert,
+	"low":     StoreAndSearch,
+	"block":   Blocked,
+	"blocked": Blocked,
+}
+
+// OfPriority returns TCO policy tier of priority, unknown priority is returned as is
+func OfPriority(priority string) string {
+	if t, ok := policyTiers[strings.TrimPrefix(priority, "type_")]; ok {
+		return t
+	}
+
+	return priority
+}
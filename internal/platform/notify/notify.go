@@ -0,0 +1,126 @@
+// Package notify to create and resolve incidents in alerting services
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Service endpoints, variables to be mocked in tests
+var (
+	PagerDutyURL = "https://events.pagerduty.com/v2/enqueue"
+	OpsgenieURL  = "https://api.opsgenie.com/v2/alerts"
+)
+
+var PostTimeout = time.Duration(10) * time.Second // HTTP post timeout - default 10 seconds
+
+// Event describes triggered or resolved condition, key identifies the same condition across events
+type Event struct {
+	Key     string
+	Summary string
+	Details string
+	Source  string
+}
+
+// Notifier creates incident when condition triggers and resolves it when condition clears
+type Notifier interface {
+	Trigger(e Event) error
+	Resolve(e Event) error
+}
+
+func post(u string, header http.Header, v any) error {
+	j, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("cannot marshal event: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, u, bytes.NewBuffer(j))
+	if err != nil {
+		return fmt.Errorf("cannot create request: %w", err)
+	}
+	req.Header = header
+	req.Header.Set("Content-Type", "application/json")
+
+	c := http.Client{Timeout: PostTimeout}
+	resp, err := c.Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot POST event: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("service returned HTTP error code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// PagerDuty Events API v2 integration
+type PagerDuty struct {
+	RoutingKey string
+}
+
+type pagerDutyPayload struct {
+	Summary       string            `json:"summary"`
+	Source        string            `json:"source"`
+	Severity      string            `json:"severity"`
+	CustomDetails map[string]string `json:"custom_details,omitempty"`
+}
+
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"`
+}
+
+func (p PagerDuty) Trigger(e Event) error {
+	return post(PagerDutyURL, http.Header{}, pagerDutyEvent{
+		RoutingKey:  p.RoutingKey,
+		EventAction: "trigger",
+		DedupKey:    e.Key,
+		Payload: &pagerDutyPayload{
+			Summary:       e.Summary,
+			Source:        e.Source,
+			Severity:      "error",
+			CustomDetails: map[string]string{"details": e.Details},
+		},
+	})
+}
+
+func (p PagerDuty) Resolve(e Event) error {
+	return post(PagerDutyURL, http.Header{}, pagerDutyEvent{
+		RoutingKey:  p.RoutingKey,
+		EventAction: "resolve",
+		DedupKey:    e.Key,
+	})
+}
+
+// Opsgenie Alert API integration
+type Opsgenie struct {
+	APIKey string
+}
+
+type opsgenieAlert struct {
+	Message     string `json:"message"`
+	Alias       string `json:"alias"`
+	Description string `json:"description,omitempty"`
+	Source      string `json:"source,omitempty"`
+}
+
+func (o Opsgenie) header() http.Header {
+	return http.Header{"Authorization": {"GenieKey " + o.APIKey}}
+}
+
+func (o Opsgenie) Trigger(e Event) error {
+	return post(OpsgenieURL, o.header(), opsgenieAlert{Message: e.Summary, Alias: e.Key, Description: e.Details, Source: e.Source})
+}
+
+func (o Opsgenie) Resolve(e Event) error {
+	u := OpsgenieURL + "/" + url.PathEscape(e.Key) + "/close?identifierType=alias"
+	return post(u, o.header(), map[string]string{"source": e.Source, "note": e.Summary})
+}
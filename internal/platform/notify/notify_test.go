@@ -0,0 +1,99 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+type request struct {
+	path  string
+	query string
+	auth  string
+	body  map[string]any
+}
+
+func mockServer(requests *[]request, status int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := request{path: r.URL.Path, query: r.URL.RawQuery, auth: r.Header.Get("Authorization")}
+		json.NewDecoder(r.Body).Decode(&req.body)
+		*requests = append(*requests, req)
+		w.WriteHeader(status)
+	}))
+}
+
+var event = Event{Key: "iclogs-abc", Summary: "12 records", Details: "query", Source: "host"}
+
+func TestPagerDuty(t *testing.T) {
+	var requests []request
+	server := mockServer(&requests, http.StatusAccepted)
+	defer server.Close()
+	PagerDutyURL = server.URL + "/v2/enqueue"
+
+	p := PagerDuty{RoutingKey: "R0UT1NG"}
+	if err := p.Trigger(event); err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+	if err := p.Resolve(event); err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+
+	want := []map[string]any{
+		{
+			"routing_key":  "R0UT1NG",
+			"event_action": "trigger",
+			"dedup_key":    "iclogs-abc",
+			"payload": map[string]any{
+				"summary":        "12 records",
+				"source":         "host",
+				"severity":       "error",
+				"custom_details": map[string]any{"details": "query"},
+			},
+		},
+		{"routing_key": "R0UT1NG", "event_action": "resolve", "dedup_key": "iclogs-abc"},
+	}
+
+	for i, r := range requests {
+		if !reflect.DeepEqual(r.body, want[i]) {
+			t.Errorf("\nGot:\t%+v\nWant:\t%+v", r.body, want[i])
+		}
+	}
+}
+
+func TestOpsgenie(t *testing.T) {
+	var requests []request
+	server := mockServer(&requests, http.StatusAccepted)
+	defer server.Close()
+	OpsgenieURL = server.URL + "/v2/alerts"
+
+	o := Opsgenie{APIKey: "K3Y"}
+	if err := o.Trigger(event); err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+	if err := o.Resolve(event); err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+
+	if len(requests) != 2 {
+		t.Fatalf("Want 2 requests, got: %d", len(requests))
+	}
+	if r := requests[0]; r.path != "/v2/alerts" || r.auth != "GenieKey K3Y" || r.body["alias"] != "iclogs-abc" || r.body["message"] != "12 records" {
+		t.Errorf("Unexpected trigger request: %+v", r)
+	}
+	if r := requests[1]; r.path != "/v2/alerts/iclogs-abc/close" || r.query != "identifierType=alias" {
+		t.Errorf("Unexpected resolve request: %+v", r)
+	}
+}
+
+func TestPostError(t *testing.T) {
+	var requests []request
+	server := mockServer(&requests, http.StatusBadRequest)
+	defer server.Close()
+	PagerDutyURL = server.URL
+
+	if err := (PagerDuty{}).Trigger(event); err == nil {
+		t.Error("Should get an error for HTTP error status!")
+	}
+}
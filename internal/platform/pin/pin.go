@@ -0,0 +1,84 @@
+// Package pin to keep resolved query context (endpoint, time window and token) under a name, so follow-up runs reuse it
+package pin
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/auth"
+	"github.com/wooyey/iclogs/internal/platform/cache"
+)
+
+const subDir = "sessions"
+
+// Dir returns pinned sessions directory in user cache directory
+var Dir = func() (string, error) {
+	return cache.Dir(subDir)
+}
+
+// Context pinned under session name
+type Context struct {
+	LogsURL   string     `json:"logs_url"`
+	AuthURL   string     `json:"auth_url"`
+	StartDate time.Time  `json:"start_date"`
+	EndDate   time.Time  `json:"end_date"`
+	Token     auth.Token `json:"token"`
+}
+
+func path(name string) (string, error) {
+	if name == "" || name == "." || name == ".." || filepath.Base(name) != name {
+		return "", fmt.Errorf("invalid session name '%s'", name)
+	}
+
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, name+".json"), nil
+}
+
+// Load context pinned under name, reporting whether session exists
+func Load(name string) (Context, bool, error) {
+	c := Context{}
+
+	p, err := path(name)
+	if err != nil {
+		return c, false, err
+	}
+
+	data, ok, err := cache.Read(p)
+	if err != nil {
+		return c, false, fmt.Errorf("cannot read session '%s': %w", name, err)
+	}
+	if !ok {
+		return c, false, nil
+	}
+
+	if err = json.Unmarshal(data, &c); err != nil {
+		return c, false, fmt.Errorf("cannot parse session '%s': %w", name, err)
+	}
+
+	return c, true, nil
+}
+
+// Save context under name, replacing previous one
+func Save(name string, c Context) error {
+	p, err := path(name)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot encode session: %w", err)
+	}
+
+	if err := cache.Write(p, data); err != nil {
+		return fmt.Errorf("cannot write session '%s': %w", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package pin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/auth"
+)
+
+func TestSaveLoad(t *testing.T) {
+	dir := t.TempDir()
+	Dir = func() (string, error) { return dir, nil }
+
+	if _, ok, err := Load("incident"); ok || err != nil {
+		t.Fatalf("Got session: %v, error: %v, want none", ok, err)
+	}
+
+	end := time.Date(2025, 1, 31, 12, 0, 0, 0, time.UTC)
+	want := Context{
+		LogsURL:   "https://logs.example.com",
+		AuthURL:   "https://iam.example.com",
+		StartDate: end.Add(-time.Hour),
+		EndDate:   end,
+		Token:     auth.Token{Value: "API_Token", Expiration: 3600, Created: 1234},
+	}
+
+	if err := Save("incident", want); err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+
+	got, ok, err := Load("incident")
+	if err != nil || !ok {
+		t.Fatalf("Got session: %v, error: %v", ok, err)
+	}
+	if got != want {
+		t.Errorf("Got: %+v, Want: %+v", got, want)
+	}
+}
+
+func TestInvalidName(t *testing.T) {
+	Dir = func() (string, error) { return t.TempDir(), nil }
+
+	for _, name := range []string{"", "..", "../config", "a/b"} {
+		t.Run(name, func(t *testing.T) {
+			if err := Save(name, Context{}); err == nil {
+				t.Errorf("Expected error for session name '%s'", name)
+			}
+			if _, _, err := Load(name); err == nil {
+				t.Errorf("Expected error for session name '%s'", name)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+// Package plugin to discover and run external output sink plugins, executables reading records as JSON lines from stdin
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/stdin"
+)
+
+const (
+	dirName = "iclogs"
+	subDir  = "plugins"
+
+	// Argument plugin is called with to describe itself, it should print JSON object with description field
+	describeArg = "describe"
+	// Argument plugin is called with to consume records
+	writeArg = "write"
+)
+
+var DescribeTimeout = time.Duration(5) * time.Second // Plugin describe call timeout - default 5 seconds
+
+// DefaultDir returns plugins directory in user config directory
+var DefaultDir = func() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot find user config directory: %w", err)
+	}
+
+	return filepath.Join(dir, dirName, subDir), nil
+}
+
+// Plugin is executable file in plugins directory, named by file name without extension
+type Plugin struct {
+	Name        string
+	Path        string
+	Description string
+}
+
+type description struct {
+	Description string `json:"description"`
+}
+
+// List plugins found in directory, missing directory means no plugins
+func List(dir string) ([]Plugin, error) {
+	l, err := executables(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range l {
+		l[i].Description = l[i].describe()
+	}
+
+	sort.Slice(l, func(i, j int) bool { return l[i].Name < l[j].Name })
+
+	return l, nil
+}
+
+// Find plugin with given name in directory, only the found one is asked for description
+func Find(dir, name string) (Plugin, error) {
+	l, err := executables(dir)
+	if err != nil {
+		return Plugin{}, err
+	}
+
+	for _, p := range l {
+		if p.Name == name {
+			p.Description = p.describe()
+			return p, nil
+		}
+	}
+
+	return Plugin{}, fmt.Errorf("plugin '%s' not found in %s", name, dir)
+}
+
+// Executable files in directory as plugins without description
+func executables(dir string) ([]Plugin, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read plugins directory: %w", err)
+	}
+
+	var l []Plugin
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil || !info.Mode().IsRegular() || info.Mode().Perm()&0o111 == 0 {
+			continue
+		}
+
+		l = append(l, Plugin{Name: strings.TrimSuffix(e.Name(), filepath.Ext(e.Name())), Path: filepath.Join(dir, e.Name())})
+	}
+
+	return l, nil
+}
+
+// Ask plugin for its description, empty when plugin doesn't provide one
+func (p Plugin) describe() string {
+	ctx, cancel := context.WithTimeout(context.Background(), DescribeTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, p.Path, describeArg).Output()
+	if err != nil {
+		return ""
+	}
+
+	d := description{}
+	if json.Unmarshal(out, &d) != nil {
+		return ""
+	}
+
+	return d.Description
+}
+
+// Writer starts plugin, data written to returned writer lands on plugin stdin.
+// Plugin gets given environment variables on top of current ones, its output is passed to out.
+func (p Plugin) Writer(env []string, out io.Writer) (io.WriteCloser, error) {
+	cmd := exec.Command(p.Path, writeArg)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdout = out
+
+	return stdin.Writer(cmd, "plugin "+p.Name)
+}
@@ -0,0 +1,110 @@
+package plugin
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// Plugin describing itself and counting received lines
+const script = `#!/bin/sh
+if [ "$1" = describe ]; then
+  echo '{"description": "Count records"}'
+  exit 0
+fi
+echo "$ICLOGS_QUERY: $(wc -l | tr -d ' ')"
+`
+
+func writePlugin(t *testing.T, dir, name, content string, mode os.FileMode) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), mode); err != nil {
+		t.Fatalf("Cannot write plugin: %v", err)
+	}
+}
+
+func TestList(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh command not available")
+	}
+
+	dir := t.TempDir()
+	writePlugin(t, dir, "count.sh", script, 0o700)
+	writePlugin(t, dir, "silent", "#!/bin/sh\nexit 1\n", 0o700)
+	writePlugin(t, dir, "README.md", "not a plugin", 0o600)
+
+	got, err := List(dir)
+	if err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+
+	want := []Plugin{
+		{Name: "count", Path: filepath.Join(dir, "count.sh"), Description: "Count records"},
+		{Name: "silent", Path: filepath.Join(dir, "silent")},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("\nGot:\t%+v\nWant:\t%+v", got, want)
+	}
+
+	if l, err := List(filepath.Join(dir, "missing")); err != nil || l != nil {
+		t.Errorf("Missing directory should mean no plugins, got: %v, '%v'", l, err)
+	}
+}
+
+func TestWriter(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh command not available")
+	}
+
+	dir := t.TempDir()
+	writePlugin(t, dir, "count.sh", script, 0o700)
+
+	p, err := Find(dir, "count")
+	if err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+
+	out := bytes.Buffer{}
+	w, err := p.Writer([]string{"ICLOGS_QUERY=severity:error"}, &out)
+	if err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+	io.WriteString(w, "{}\n{}\n")
+	if err = w.Close(); err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+
+	if out.String() != "severity:error: 2\n" {
+		t.Errorf("Got: %q", out.String())
+	}
+
+	if _, err := Find(dir, "missing"); err == nil {
+		t.Error("Should get an error for missing plugin!")
+	}
+}
+
+func TestFindDescribesOnlyMatch(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh command not available")
+	}
+
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "described")
+	writePlugin(t, dir, "count.sh", script, 0o700)
+	writePlugin(t, dir, "other", "#!/bin/sh\ntouch '"+marker+"'\n", 0o700)
+
+	p, err := Find(dir, "count")
+	if err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+	if p.Description != "Count records" {
+		t.Errorf("Got: %q, Want: %q", p.Description, "Count records")
+	}
+
+	if _, err := os.Stat(marker); err == nil {
+		t.Error("Other plugin should not be asked for description!")
+	}
+}
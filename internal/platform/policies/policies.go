@@ -0,0 +1,77 @@
+// Package policies to read TCO policies of IBM Cloud Logs instance, which place records of applications into tiers
+package policies
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/wooyey/iclogs/internal/platform/api"
+	"github.com/wooyey/iclogs/internal/platform/logs/tier"
+)
+
+const policiesPath = "/v1/policies"
+
+// Rule matches application or subsystem name, ie. `start_with` rule with name `payments`
+type Rule struct {
+	Type string `json:"rule_type_id"`
+	Name string `json:"name"`
+}
+
+// Operators of rule types as shown in rule description
+var ruleOperators = map[string]string{
+	"is":         "=",
+	"is_not":     "!=",
+	"start_with": "^=",
+	"includes":   "*=",
+}
+
+// String describes rule, ie. `^= payments`, empty rule matches everything
+func (r *Rule) String() string {
+	if r == nil || r.Name == "" {
+		return "*"
+	}
+
+	op, ok := ruleOperators[strings.TrimPrefix(r.Type, "rule_type_id_")]
+	if !ok {
+		op = r.Type
+	}
+
+	return op + " " + r.Name
+}
+
+// Policy places records matching its rules into tier of its priority, policies are evaluated by order
+type Policy struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Priority    string `json:"priority"`
+	Enabled     bool   `json:"enabled"`
+	Order       int    `json:"order"`
+	Application *Rule  `json:"application_rule"`
+	Subsystem   *Rule  `json:"subsystem_rule"`
+	LogRules    struct {
+		Severities []string `json:"severities"`
+	} `json:"log_rules"`
+}
+
+// Tier of records matched by policy
+func (p Policy) Tier() string {
+	return tier.OfPriority(p.Priority)
+}
+
+type response struct {
+	Policies []Policy `json:"policies"`
+}
+
+// List logs policies of the instance sorted by their order
+func List(ctx context.Context, endpoint string, token func() (string, error)) ([]Policy, error) {
+	var r response
+	if err := api.GetJSON(ctx, endpoint, token, policiesPath, url.Values{"source_type": {"logs"}}, &r); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(r.Policies, func(i, j int) bool { return r.Policies[i].Order < r.Policies[j].Order })
+
+	return r.Policies, nil
+}
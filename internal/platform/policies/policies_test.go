@@ -0,0 +1,65 @@
+package policies
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/wooyey/iclogs/tests"
+)
+
+const policiesResponse = `{"policies":[
+{"id":"2","name":"debug","priority":"type_low","enabled":true,"order":2,"application_rule":{"rule_type_id":"start_with","name":"batch"},"log_rules":{"severities":["debug","verbose"]}},
+{"id":"1","name":"payments","priority":"type_high","enabled":true,"order":1,"application_rule":{"rule_type_id":"is","name":"payments"},"subsystem_rule":{"rule_type_id":"is_not","name":"audit"}}
+]}`
+
+func TestList(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != policiesPath || r.Header.Get("authorization") != "Bearer Good_Token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(policiesResponse))
+	}))
+	defer server.Close()
+
+	p, err := List(context.Background(), server.URL, tests.TokenOf("Good_Token"))
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	if len(p) != 2 || p[0].Name != "payments" || p[1].Name != "debug" {
+		t.Fatalf("Expected policies sorted by order, got: %v", p)
+	}
+	if p[0].Tier() != "priority_insights" || p[1].Tier() != "store_and_search" {
+		t.Errorf("Got tiers: %s, %s", p[0].Tier(), p[1].Tier())
+	}
+
+	if _, err := List(context.Background(), server.URL, tests.TokenOf("Bad_Token")); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("Expected HTTP error, got: %v", err)
+	}
+}
+
+func TestRuleString(t *testing.T) {
+	testCases := []struct {
+		name string
+		rule *Rule
+		want string
+	}{
+		{name: "Nil", rule: nil, want: "*"},
+		{name: "Is", rule: &Rule{Type: "is", Name: "api"}, want: "= api"},
+		{name: "IsNot", rule: &Rule{Type: "is_not", Name: "api"}, want: "!= api"},
+		{name: "StartWith", rule: &Rule{Type: "rule_type_id_start_with", Name: "api"}, want: "^= api"},
+		{name: "Includes", rule: &Rule{Type: "includes", Name: "api"}, want: "*= api"},
+		{name: "Unknown", rule: &Rule{Type: "regex", Name: "api"}, want: "regex api"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.rule.String(); got != tc.want {
+				t.Errorf("Got: %s, want: %s", got, tc.want)
+			}
+		})
+	}
+}
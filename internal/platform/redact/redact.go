@@ -0,0 +1,162 @@
+// Package redact to hide sensitive data in log records
+package redact
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/netip"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const replacement = "[REDACTED:%s]"
+
+// Redactor replaces all matches of pattern, optionally confirmed by validation function
+type Redactor struct {
+	Name  string
+	re    *regexp.Regexp
+	valid func(string) bool
+}
+
+// Set of redactors applied one by one
+type Set []Redactor
+
+var builtin = map[string]Redactor{
+	"email": {
+		re: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+	},
+	"creditcard": {
+		re:    regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`),
+		valid: luhn,
+	},
+	"ip": {
+		re:    regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b|[0-9A-Fa-f]{0,4}(?::[0-9A-Fa-f]{0,4}){2,7}`),
+		valid: isIP,
+	},
+}
+
+// Names returns sorted names of built-in redactors
+func Names() []string {
+	return slices.Sorted(maps.Keys(builtin))
+}
+
+// Luhn checksum to limit false positives of card numbers
+func luhn(s string) bool {
+	sum, double := 0, false
+
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+
+		d := int(c - '0')
+		if double {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+
+	return sum%10 == 0
+}
+
+func isIP(s string) bool {
+	_, err := netip.ParseAddr(s)
+	return err == nil
+}
+
+// New creates Set from comma separated names of built-in or custom (name to regular expression) redactors
+func New(names string, custom map[string]string) (Set, error) {
+	var s Set
+
+	for _, n := range strings.Split(names, ",") {
+		if n = strings.TrimSpace(n); n == "" {
+			continue
+		}
+
+		if expr, ok := custom[n]; ok {
+			re, err := regexp.Compile(expr)
+			if err != nil {
+				return nil, fmt.Errorf("cannot compile redactor '%s': %w", n, err)
+			}
+			s = append(s, Redactor{Name: n, re: re})
+			continue
+		}
+
+		r, ok := builtin[n]
+		if !ok {
+			return nil, fmt.Errorf("unknown redactor '%s', available built-in: %s", n, strings.Join(Names(), ", "))
+		}
+		r.Name = n
+		s = append(s, r)
+	}
+
+	return s, nil
+}
+
+// Apply all redactors to text
+func (s Set) Apply(text string) string {
+	for _, r := range s {
+		mark := fmt.Sprintf(replacement, r.Name)
+		text = r.re.ReplaceAllStringFunc(text, func(m string) string {
+			if r.valid != nil && !r.valid(m) {
+				return m
+			}
+			return mark
+		})
+	}
+
+	return text
+}
+
+// ApplyJSON redacts string and number values of JSON document, so it stays valid JSON. Redacted number becomes
+// string with replacement. Text which is not JSON is redacted as plain text.
+func (s Set) ApplyJSON(text string) string {
+	if s.Apply(text) == text {
+		return text
+	}
+
+	d := json.NewDecoder(strings.NewReader(text))
+	d.UseNumber()
+
+	var v any
+	if err := d.Decode(&v); err != nil || d.More() {
+		return s.Apply(text)
+	}
+
+	var b bytes.Buffer
+	e := json.NewEncoder(&b)
+	e.SetEscapeHTML(false)
+	if err := e.Encode(s.applyValue(v)); err != nil {
+		return s.Apply(text)
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func (s Set) applyValue(v any) any {
+	switch x := v.(type) {
+	case map[string]any:
+		for k, e := range x {
+			x[k] = s.applyValue(e)
+		}
+	case []any:
+		for i, e := range x {
+			x[i] = s.applyValue(e)
+		}
+	case string:
+		return s.Apply(x)
+	case json.Number:
+		if r := s.Apply(x.String()); r != x.String() {
+			return r
+		}
+	}
+
+	return v
+}
@@ -0,0 +1,128 @@
+package redact
+
+import (
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+
+	testCases := []struct {
+		name   string
+		names  string
+		custom map[string]string
+		input  string
+		want   string
+	}{
+		{
+			name:  "Email",
+			names: "email",
+			input: `{"message":"user john.doe+test@example.com logged in"}`,
+			want:  `{"message":"user [REDACTED:email] logged in"}`,
+		},
+		{
+			name:  "CreditCard",
+			names: "creditcard",
+			input: "paid with 4111 1111 1111 1111, order 1234567890123",
+			want:  "paid with [REDACTED:creditcard], order 1234567890123",
+		},
+		{
+			name:  "IPv4",
+			names: "ip",
+			input: "client 10.1.2.3 version 1.2.3.999",
+			want:  "client [REDACTED:ip] version 1.2.3.999",
+		},
+		{
+			name:  "IPv6",
+			names: "ip",
+			input: "client 2001:db8::1 at 18:52:23.025",
+			want:  "client [REDACTED:ip] at 18:52:23.025",
+		},
+		{
+			name:   "Custom",
+			names:  "email,token",
+			custom: map[string]string{"token": `tok_[a-z0-9]+`},
+			input:  "a@b.io sent tok_abc123",
+			want:   "[REDACTED:email] sent [REDACTED:token]",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := New(tt.names, tt.custom)
+			if err != nil {
+				t.Fatalf("Got an error: '%v'", err)
+			}
+
+			if got := s.Apply(tt.input); got != tt.want {
+				t.Errorf("\nGot:\t%s\nWant:\t%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyJSON(t *testing.T) {
+
+	testCases := []struct {
+		name   string
+		names  string
+		custom map[string]string
+		input  string
+		want   string
+	}{
+		{
+			name:  "Number",
+			names: "creditcard",
+			input: `{"message":"paid","order_id":4111111111111111}`,
+			want:  `{"message":"paid","order_id":"[REDACTED:creditcard]"}`,
+		},
+		{
+			name:  "String",
+			names: "email",
+			input: `{"user":{"email":"john@example.com","tags":["a@b.io", 3]}}`,
+			want:  `{"user":{"email":"[REDACTED:email]","tags":["[REDACTED:email]",3]}}`,
+		},
+		{
+			name:   "AcrossQuotes",
+			names:  "pair",
+			custom: map[string]string{"pair": `"a":"b`},
+			input:  `{"a":"b","c":"<d>"}`,
+			want:   `{"a":"b","c":"<d>"}`,
+		},
+		{
+			name:  "Unchanged",
+			names: "email",
+			input: `{"z":1, "a":2.50}`,
+			want:  `{"z":1, "a":2.50}`,
+		},
+		{
+			name:  "NotJSON",
+			names: "ip",
+			input: "client 10.1.2.3",
+			want:  "client [REDACTED:ip]",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := New(tt.names, tt.custom)
+			if err != nil {
+				t.Fatalf("Got an error: '%v'", err)
+			}
+
+			if got := s.ApplyJSON(tt.input); got != tt.want {
+				t.Errorf("\nGot:\t%v\nWant:\t%v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+
+	if _, err := New("unknown", nil); err == nil {
+		t.Error("Should get an error for unknown redactor!")
+	}
+
+	if _, err := New("broken", map[string]string{"broken": "("}); err == nil {
+		t.Error("Should get an error for broken regular expression!")
+	}
+}
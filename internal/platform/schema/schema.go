@@ -0,0 +1,196 @@
+// Package schema to discover keypaths of records user data, cached to suggest fixes of misspelled fields
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/wooyey/iclogs/internal/platform/cache"
+	"github.com/wooyey/iclogs/internal/platform/logs"
+)
+
+const (
+	maxSuggestions = 3
+	maxSamples     = 200  // Records of one search keypaths are collected from
+	maxFields      = 2000 // The most frequent keypaths kept per endpoint
+)
+
+// CachePath returns location of discovered fields for given logs endpoint in user cache directory
+var CachePath = func(endpoint string) (string, error) {
+	return cache.Path("schema", endpoint)
+}
+
+// Fields are records count per keypath of user data, ie. `kubernetes.pod_name`
+type Fields map[string]int
+
+// Collect keypaths of user data of at most maxSamples records spread over all of them, records which are not
+// JSON objects are skipped
+func Collect(l []logs.Log) Fields {
+	f := Fields{}
+
+	step := max(1, (len(l)+maxSamples-1)/maxSamples)
+	for i := 0; i < len(l); i += step {
+		data, err := l[i].Data()
+		if err != nil {
+			continue
+		}
+		f.walk("", data)
+	}
+
+	return f
+}
+
+func (f Fields) walk(prefix string, m map[string]any) {
+	for k, v := range m {
+		path := prefix + k
+		if nested, ok := v.(map[string]any); ok && len(nested) != 0 {
+			f.walk(path+".", nested)
+			continue
+		}
+		f[path]++
+	}
+}
+
+// Merge counts of other fields, keeping only maxFields the most frequent keypaths. Reports whether any keypath
+// was added, counts of known ones are not worth saving alone.
+func (f Fields) Merge(other Fields) bool {
+	var added []string
+	for path, n := range other {
+		if _, ok := f[path]; !ok {
+			added = append(added, path)
+		}
+		f[path] += n
+	}
+
+	if len(f) > maxFields {
+		paths := make([]string, 0, len(f))
+		for path := range f {
+			paths = append(paths, path)
+		}
+		sort.Slice(paths, func(i, j int) bool {
+			if f[paths[i]] != f[paths[j]] {
+				return f[paths[i]] > f[paths[j]]
+			}
+			return paths[i] < paths[j]
+		})
+		for _, path := range paths[maxFields:] {
+			delete(f, path)
+		}
+	}
+
+	for _, path := range added {
+		if _, ok := f[path]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Suggest known keypaths closest to the unknown one, the closest first. Keypath can be given without leading
+// segments, ie. `pod_name` matches `kubernetes.pod_name`.
+func (f Fields) Suggest(path string) []string {
+	type candidate struct {
+		path     string
+		distance int
+	}
+
+	max := max(2, len(path)/4)
+	segments := strings.Count(path, ".") + 1
+
+	var found []candidate
+	for known := range f {
+		d := distance(path, known)
+		if parts := strings.Split(known, "."); len(parts) > segments {
+			d = min(d, distance(path, strings.Join(parts[len(parts)-segments:], "."))+1)
+		}
+		if d <= max && known != path {
+			found = append(found, candidate{known, d})
+		}
+	}
+
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].distance != found[j].distance {
+			return found[i].distance < found[j].distance
+		}
+		if f[found[i].path] != f[found[j].path] {
+			return f[found[i].path] > f[found[j].path]
+		}
+		return found[i].path < found[j].path
+	})
+
+	var paths []string
+	for i := 0; i < len(found) && i < maxSuggestions; i++ {
+		paths = append(paths, found[i].path)
+	}
+
+	return paths
+}
+
+// Levenshtein distance of strings
+func distance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+
+	return prev[len(b)]
+}
+
+// Load discovered fields of endpoint, none when nothing was discovered yet
+func Load(endpoint string) (Fields, error) {
+	f := Fields{}
+
+	path, err := CachePath(endpoint)
+	if err != nil {
+		return f, err
+	}
+
+	data, ok, err := cache.Read(path)
+	if err != nil {
+		return f, fmt.Errorf("cannot read cached schema: %w", err)
+	}
+	if !ok {
+		return f, nil
+	}
+
+	if err = json.Unmarshal(data, &f); err != nil {
+		return f, fmt.Errorf("cannot parse cached schema: %w", err)
+	}
+
+	return f, nil
+}
+
+// Save discovered fields of endpoint to cache
+func Save(endpoint string, f Fields) error {
+	path, err := CachePath(endpoint)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(f)
+	if err != nil {
+		return fmt.Errorf("cannot encode schema: %w", err)
+	}
+
+	if err := cache.Write(path, data); err != nil {
+		return fmt.Errorf("cannot write cached schema: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package schema
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/wooyey/iclogs/internal/platform/logs"
+)
+
+func TestCollect(t *testing.T) {
+	l := []logs.Log{
+		{UserData: `{"message":"ok","kubernetes":{"pod_name":"web-1","labels":{"app":"web"}}}`},
+		{UserData: `{"message":"fail","kubernetes":{"pod_name":"web-2"},"empty":{}}`},
+		{UserData: `plain text`},
+	}
+
+	got := Collect(l)
+	want := Fields{"message": 2, "kubernetes.pod_name": 2, "kubernetes.labels.app": 1, "empty": 1}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Got: %v, Want: %v", got, want)
+	}
+
+	if !got.Merge(Fields{"message": 1, "level": 1}) {
+		t.Error("Expected added keypath to be reported")
+	}
+	if got["message"] != 3 || got["level"] != 1 {
+		t.Errorf("Got merged: %v", got)
+	}
+	if got.Merge(Fields{"message": 1}) {
+		t.Error("Expected no added keypath to be reported")
+	}
+}
+
+func TestCollectSamples(t *testing.T) {
+	l := make([]logs.Log, 10*maxSamples)
+	for i := range l {
+		l[i].UserData = fmt.Sprintf(`{"field_%d":1}`, i)
+	}
+
+	if got := Collect(l); len(got) != maxSamples {
+		t.Errorf("Got: %d keypaths, Want: %d", len(got), maxSamples)
+	}
+}
+
+func TestMergeLimit(t *testing.T) {
+	f := Fields{"common": 10}
+	other := Fields{}
+	for i := range maxFields {
+		other[fmt.Sprintf("rare_%04d", i)] = 1
+	}
+
+	if !f.Merge(other) {
+		t.Error("Expected added keypaths to be reported")
+	}
+	if len(f) != maxFields || f["common"] != 10 {
+		t.Errorf("Got: %d keypaths, common: %d, Want: %d keypaths with common kept", len(f), f["common"], maxFields)
+	}
+
+	// Rare keypaths do not displace more frequent ones
+	if f.Merge(Fields{"zzz": 1}) {
+		t.Error("Expected dropped keypath not to be reported as added")
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	f := Fields{"kubernetes.pod_name": 10, "kubernetes.pod_id": 10, "kubernetes.namespace_name": 10, "message": 20, "level": 5}
+
+	testCases := []struct {
+		name string
+		path string
+		want []string
+	}{
+		{name: "Typo", path: "kubernetes.podname", want: []string{"kubernetes.pod_name", "kubernetes.pod_id"}},
+		{name: "Suffix", path: "pod_name", want: []string{"kubernetes.pod_name"}},
+		{name: "Short", path: "mesage", want: []string{"message"}},
+		{name: "Unrelated", path: "w.e", want: nil},
+		{name: "Known", path: "level", want: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := f.Suggest(tc.path); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Got: %v, Want: %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCache(t *testing.T) {
+	dir := t.TempDir()
+	CachePath = func(endpoint string) (string, error) { return filepath.Join(dir, "schema.json"), nil }
+
+	f, err := Load("https://logs.example.com")
+	if err != nil || len(f) != 0 {
+		t.Fatalf("Got: %v, error: %v, want none", f, err)
+	}
+
+	want := Fields{"kubernetes.pod_name": 2}
+	if err := Save("https://logs.example.com", want); err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+
+	got, err := Load("https://logs.example.com")
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Got: %v, Want: %v", got, want)
+	}
+}
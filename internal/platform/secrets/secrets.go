@@ -0,0 +1,100 @@
+// Package secrets to detect likely secrets accidentally written to logs
+package secrets
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/logs"
+)
+
+type detector struct {
+	kind string
+	re   *regexp.Regexp
+}
+
+// Finding summarizes records containing one kind of secret
+type Finding struct {
+	Kind  string
+	Count int
+	First time.Time
+}
+
+var detectors = []detector{
+	{kind: "private key", re: regexp.MustCompile(`-----BEGIN ([A-Z]+ )?PRIVATE KEY( BLOCK)?-----`)},
+	{kind: "AWS access key", re: regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{kind: "IBM Cloud API key", re: regexp.MustCompile(`(?i)api_?key\\?"?\s*[:=]\s*\\?"?[A-Za-z0-9_-]{44}\b`)},
+	{kind: "bearer token", re: regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9\-._~+/]{20,}=*`)},
+	{kind: "JSON Web Token", re: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{8,}\.eyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]+`)},
+}
+
+// Scan returns kinds of secrets found in text
+func Scan(text string) []string {
+	var kinds []string
+
+	for _, d := range detectors {
+		if d.re.MatchString(text) {
+			kinds = append(kinds, d.kind)
+		}
+	}
+
+	return kinds
+}
+
+// Summarize scans user data and labels of log records, findings are in detectors order
+func Summarize(l []logs.Log) []Finding {
+	found := make(map[string]*Finding)
+
+	for _, r := range l {
+		text := r.UserData + "\n" + strings.Join(r.FormatLabels(), "\n")
+
+		for _, k := range Scan(text) {
+			f, ok := found[k]
+			if !ok {
+				f = &Finding{Kind: k, First: r.Time}
+				found[k] = f
+			}
+			f.Count++
+			if r.Time.Before(f.First) {
+				f.First = r.Time
+			}
+		}
+	}
+
+	var result []Finding
+	for _, d := range detectors {
+		if f, ok := found[d.kind]; ok {
+			result = append(result, *f)
+		}
+	}
+
+	return result
+}
+
+// Merge findings of separately scanned records, result is in detectors order
+func Merge(a, b []Finding) []Finding {
+	found := make(map[string]Finding, len(a))
+	for _, f := range a {
+		found[f.Kind] = f
+	}
+
+	for _, f := range b {
+		if m, ok := found[f.Kind]; ok {
+			f.Count += m.Count
+			if m.First.Before(f.First) {
+				f.First = m.First
+			}
+		}
+		found[f.Kind] = f
+	}
+
+	var result []Finding
+	for _, d := range detectors {
+		if f, ok := found[d.kind]; ok {
+			result = append(result, f)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,117 @@
+// Package slack to verify Slack requests and post messages back
+package slack
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	signatureVersion = "v0"
+	maxRequestAge    = 5 * time.Minute
+)
+
+var (
+	errBadSignature = errors.New("request signature does not match")
+	errOldRequest   = errors.New("request timestamp is too old")
+)
+
+// APIURL of Slack Web API
+var APIURL = "https://slack.com/api"
+
+var PostTimeout = time.Duration(10) * time.Second // HTTP post timeout - default 10 seconds
+
+// Message posted to channel or as response to slash command
+type Message struct {
+	Channel      string `json:"channel,omitempty"`
+	Text         string `json:"text"`
+	ResponseType string `json:"response_type,omitempty"` // `in_channel` makes slash command response visible to everyone
+}
+
+// Verify request signature made with signing secret, timestamp needs to be recent to prevent replays
+func Verify(secret, timestamp, signature string, body []byte, now time.Time) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid request timestamp: %w", err)
+	}
+
+	if d := now.Sub(time.Unix(ts, 0)); d > maxRequestAge || d < -maxRequestAge {
+		return errOldRequest
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(Sign(secret, timestamp, body))) {
+		return errBadSignature
+	}
+
+	return nil
+}
+
+// Sign request body the same way Slack does
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%s:%s:", signatureVersion, timestamp)
+	mac.Write(body)
+
+	return signatureVersion + "=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func post(url, token string, m Message) error {
+	j, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("cannot marshal message: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(j))
+	if err != nil {
+		return fmt.Errorf("cannot create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	c := http.Client{Timeout: PostTimeout}
+	resp, err := c.Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot POST message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("slack returned HTTP error code: %d", resp.StatusCode)
+	}
+
+	// Web API reports errors in body with 200 status
+	if token != "" {
+		var r struct {
+			OK    bool   `json:"ok"`
+			Error string `json:"error"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+			return fmt.Errorf("cannot decode response: %w", err)
+		}
+		if !r.OK {
+			return fmt.Errorf("slack returned error: %s", r.Error)
+		}
+	}
+
+	return nil
+}
+
+// PostMessage to channel using bot token
+func PostMessage(token string, m Message) error {
+	return post(APIURL+"/chat.postMessage", token, m)
+}
+
+// Respond to slash command using its response URL
+func Respond(responseURL string, m Message) error {
+	return post(responseURL, "", m)
+}
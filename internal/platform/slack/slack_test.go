@@ -0,0 +1,73 @@
+package slack
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte("command=%2Flogs&text=errors")
+	signature := Sign("secret", "1700000000", body)
+
+	testCases := []struct {
+		name      string
+		secret    string
+		timestamp string
+		signature string
+		err       bool
+	}{
+		{name: "Valid", secret: "secret", timestamp: "1700000000", signature: signature, err: false},
+		{name: "WrongSecret", secret: "other", timestamp: "1700000000", signature: signature, err: true},
+		{name: "Old", secret: "secret", timestamp: "1699999000", signature: Sign("secret", "1699999000", body), err: true},
+		{name: "BrokenTimestamp", secret: "secret", timestamp: "now", signature: signature, err: true},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.secret, tt.timestamp, tt.signature, body, now)
+			if tt.err != (err != nil) {
+				t.Errorf("Want error: %v, got: '%v'", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestPost(t *testing.T) {
+	var got Message
+	var auth string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&got)
+		if r.URL.Path == "/chat.postMessage" && got.Channel == "missing" {
+			w.Write([]byte(`{"ok":false,"error":"channel_not_found"}`))
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	APIURL = server.URL
+
+	if err := PostMessage("xoxb-token", Message{Channel: "C1", Text: "hello"}); err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+	if auth != "Bearer xoxb-token" || got.Channel != "C1" || got.Text != "hello" {
+		t.Errorf("Unexpected request, auth: '%s', message: %+v", auth, got)
+	}
+
+	if err := PostMessage("xoxb-token", Message{Channel: "missing", Text: "hello"}); err == nil {
+		t.Error("Should get an error for failed API call!")
+	}
+
+	if err := Respond(server.URL+"/response", Message{Text: "hi", ResponseType: "in_channel"}); err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+	if auth != "" || got.ResponseType != "in_channel" {
+		t.Errorf("Unexpected response request, auth: '%s', message: %+v", auth, got)
+	}
+}
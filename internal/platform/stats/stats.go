@@ -0,0 +1,119 @@
+// Package stats to compute client-side statistics over log records values
+package stats
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Percentile of values using nearest-rank method, values need to be sorted
+func Percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return math.NaN()
+	}
+
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	rank = min(max(rank, 1), len(sorted))
+
+	return sorted[rank-1]
+}
+
+// Summary of values distribution
+type Summary struct {
+	Count int
+	Min   float64
+	P50   float64
+	P90   float64
+	P99   float64
+	Max   float64
+}
+
+// Summarize values distribution
+func Summarize(values []float64) Summary {
+	if len(values) == 0 {
+		return Summary{}
+	}
+
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+
+	return Summary{
+		Count: len(sorted),
+		Min:   sorted[0],
+		P50:   Percentile(sorted, 50),
+		P90:   Percentile(sorted, 90),
+		P99:   Percentile(sorted, 99),
+		Max:   sorted[len(sorted)-1],
+	}
+}
+
+// Aggregation functions
+const (
+	Sum = "sum"
+	Avg = "avg"
+	Min = "min"
+	Max = "max"
+)
+
+var aggregationRe = regexp.MustCompile(`^\s*(\w+)\(\s*([^()\s]+)\s*\)\s*$`)
+
+// Aggregation of record numeric field, ie. avg(json.response_time)
+type Aggregation struct {
+	Func  string
+	Field string
+}
+
+func (a Aggregation) String() string {
+	return fmt.Sprintf("%s(%s)", a.Func, a.Field)
+}
+
+// Compute aggregation of values, NaN when there are no values
+func (a Aggregation) Compute(values []float64) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+
+	switch a.Func {
+	case Min:
+		return slices.Min(values)
+	case Max:
+		return slices.Max(values)
+	}
+
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+
+	if a.Func == Avg {
+		return sum / float64(len(values))
+	}
+
+	return sum
+}
+
+// ParseAggregations from comma separated list, ie. avg(json.response_time),max(json.bytes)
+func ParseAggregations(spec string) ([]Aggregation, error) {
+	var aggs []Aggregation
+
+	for _, s := range strings.Split(spec, ",") {
+		m := aggregationRe.FindStringSubmatch(s)
+		if m == nil {
+			return nil, fmt.Errorf("invalid aggregation '%s'", strings.TrimSpace(s))
+		}
+
+		f := strings.ToLower(m[1])
+		switch f {
+		case Sum, Avg, Min, Max:
+		default:
+			return nil, fmt.Errorf("unknown aggregation function '%s'", m[1])
+		}
+
+		aggs = append(aggs, Aggregation{Func: f, Field: m[2]})
+	}
+
+	return aggs, nil
+}
@@ -0,0 +1,105 @@
+package stats
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestPercentile(t *testing.T) {
+
+	values := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+	testCases := []struct {
+		name   string
+		values []float64
+		p      float64
+		want   float64
+	}{
+		{name: "Median", values: values, p: 50, want: 5},
+		{name: "P90", values: values, p: 90, want: 9},
+		{name: "P99", values: values, p: 99, want: 10},
+		{name: "Zero", values: values, p: 0, want: 1},
+		{name: "Single", values: []float64{42}, p: 90, want: 42},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Percentile(tt.values, tt.p); got != tt.want {
+				t.Errorf("\nGot:\t%v\nWant:\t%v", got, tt.want)
+			}
+		})
+	}
+
+	if got := Percentile(nil, 50); !math.IsNaN(got) {
+		t.Errorf("Want NaN for no values, got: %v", got)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	got := Summarize([]float64{30, 10, 20})
+	want := Summary{Count: 3, Min: 10, P50: 20, P90: 30, P99: 30, Max: 30}
+
+	if got != want {
+		t.Errorf("\nGot:\t%+v\nWant:\t%+v", got, want)
+	}
+
+	if got := Summarize(nil); got != (Summary{}) {
+		t.Errorf("Want empty summary, got: %+v", got)
+	}
+}
+
+func TestParseAggregations(t *testing.T) {
+
+	testCases := []struct {
+		name    string
+		spec    string
+		want    []Aggregation
+		wantErr bool
+	}{
+		{name: "Single", spec: "avg(json.response_time)", want: []Aggregation{{Func: Avg, Field: "json.response_time"}}},
+		{name: "Multiple", spec: "SUM(json.bytes), max( json.bytes )", want: []Aggregation{{Func: Sum, Field: "json.bytes"}, {Func: Max, Field: "json.bytes"}}},
+		{name: "UnknownFunction", spec: "median(json.bytes)", wantErr: true},
+		{name: "MissingField", spec: "avg()", wantErr: true},
+		{name: "Invalid", spec: "json.bytes", wantErr: true},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseAggregations(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("\nGot:\t%+v\nWant:\t%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompute(t *testing.T) {
+
+	values := []float64{4, 1, 7}
+
+	testCases := []struct {
+		fn   string
+		want float64
+	}{
+		{fn: Sum, want: 12},
+		{fn: Avg, want: 4},
+		{fn: Min, want: 1},
+		{fn: Max, want: 7},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.fn, func(t *testing.T) {
+			if got := (Aggregation{Func: tt.fn}).Compute(values); got != tt.want {
+				t.Errorf("\nGot:\t%v\nWant:\t%v", got, tt.want)
+			}
+		})
+	}
+
+	if got := (Aggregation{Func: Avg}).Compute(nil); !math.IsNaN(got) {
+		t.Errorf("Want NaN for no values, got: %v", got)
+	}
+}
@@ -0,0 +1,48 @@
+// Package stdin to stream data into external commands reading it from standard input
+package stdin
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+// Writer starts command, data written to returned writer lands on its stdin. Command is named so in errors,
+// its stderr is reported when it fails.
+func Writer(cmd *exec.Cmd, name string) (io.WriteCloser, error) {
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+
+	in, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("cannot connect to %s: %w", name, err)
+	}
+
+	if err = cmd.Start(); err != nil {
+		return nil, fmt.Errorf("cannot run %s: %w", name, err)
+	}
+
+	return &writer{WriteCloser: in, cmd: cmd, name: name, stderr: stderr}, nil
+}
+
+type writer struct {
+	io.WriteCloser
+	cmd    *exec.Cmd
+	name   string
+	stderr *bytes.Buffer
+}
+
+// Close input and wait for command to finish
+func (w *writer) Close() error {
+	if err := w.WriteCloser.Close(); err != nil {
+		return fmt.Errorf("cannot close %s input: %w", w.name, err)
+	}
+
+	if err := w.cmd.Wait(); err != nil {
+		return fmt.Errorf("%s failed: %w: %s", w.name, err, strings.TrimSpace(w.stderr.String()))
+	}
+
+	return nil
+}
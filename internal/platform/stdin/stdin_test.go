@@ -0,0 +1,49 @@
+package stdin
+
+import (
+	"bytes"
+	"io"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestWriter(t *testing.T) {
+	if _, err := exec.LookPath("tr"); err != nil {
+		t.Skip("tr command not available")
+	}
+
+	out := bytes.Buffer{}
+	cmd := exec.Command("tr", "a-z", "A-Z")
+	cmd.Stdout = &out
+
+	w, err := Writer(cmd, "upper")
+	if err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+	io.WriteString(w, "data\n")
+	if err = w.Close(); err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+
+	if out.String() != "DATA\n" {
+		t.Errorf("Got: %q", out.String())
+	}
+}
+
+func TestWriterFailure(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh command not available")
+	}
+
+	w, err := Writer(exec.Command("sh", "-c", "cat >/dev/null; echo broken >&2; exit 1"), "broken")
+	if err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+	io.WriteString(w, "data")
+
+	err = w.Close()
+	if err == nil || !strings.HasPrefix(err.Error(), "broken failed:") || !strings.HasSuffix(err.Error(), ": broken") {
+		t.Errorf("Got: '%v', Want: 'broken failed: ...: broken'", err)
+	}
+}
@@ -0,0 +1,216 @@
+// Package update to check for newer iclogs releases, remembering the result to avoid network calls on every run
+package update
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/cache"
+)
+
+// Release channels, beta includes pre-releases
+const (
+	Stable = "stable"
+	Beta   = "beta"
+)
+
+var ReleasesURL = "https://api.github.com/repos/wooyey/iclogs/releases"
+
+var CheckTimeout = time.Duration(2) * time.Second // HTTP check timeout - default 2 seconds
+
+// CheckInterval is how long remembered latest release is used before asking again
+var CheckInterval = 24 * time.Hour
+
+// CachePath returns location of remembered check result for given channel in user cache directory
+var CachePath = func(channel string) (string, error) {
+	dir, err := cache.Dir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "release-"+channel+".json"), nil
+}
+
+// Suffix added by `git describe` to builds after the tag, ie. `-3-gabc1234-dirty`
+var describeSuffix = regexp.MustCompile(`(-\d+-g[0-9a-f]+)?(-dirty)?$`)
+
+type release struct {
+	TagName    string `json:"tag_name"`
+	Draft      bool   `json:"draft"`
+	Prerelease bool   `json:"prerelease"`
+}
+
+// FailureBackoff is how long failed check is not retried, doubled with each next failure up to CheckInterval
+var FailureBackoff = time.Hour
+
+type cached struct {
+	Checked  time.Time `json:"checked"`
+	Latest   string    `json:"latest"`
+	Failures int       `json:"failures,omitempty"` // Checks failed in a row, Latest is then from the last successful one
+}
+
+// How long the check result is used before checking again
+func (c cached) valid() time.Duration {
+	if c.Failures == 0 {
+		return CheckInterval
+	}
+
+	backoff := FailureBackoff
+	for i := 1; i < c.Failures && backoff < CheckInterval; i++ {
+		backoff *= 2
+	}
+
+	return min(backoff, CheckInterval)
+}
+
+// Check returns latest release of the channel when it is newer than current version, empty string otherwise.
+// Development builds without version tag are never reported as outdated.
+func Check(current, channel string) (string, error) {
+	if channel != Stable && channel != Beta {
+		return "", fmt.Errorf("unknown release channel '%s', use %s or %s", channel, Stable, Beta)
+	}
+
+	if _, _, ok := parse(current); !ok {
+		return "", nil
+	}
+
+	latest, err := Latest(channel)
+	if err != nil {
+		return "", err
+	}
+
+	if !Newer(latest, current) {
+		return "", nil
+	}
+
+	return latest, nil
+}
+
+// Latest returns tag of the latest release in the channel, remembered one when checked recently.
+// Failed check is remembered too, so it is retried only after FailureBackoff.
+func Latest(channel string) (string, error) {
+	path, err := CachePath(channel)
+	if err != nil {
+		return "", err
+	}
+
+	c := cached{}
+	if data, ok, err := cache.Read(path); err == nil && ok && json.Unmarshal(data, &c) == nil && time.Since(c.Checked) < c.valid() {
+		return c.Latest, nil
+	}
+
+	latest, err := fetch(channel)
+	if err != nil {
+		c.Checked, c.Failures = time.Now(), c.Failures+1
+		return "", errors.Join(err, save(path, c))
+	}
+
+	c = cached{Checked: time.Now(), Latest: latest}
+	if err := save(path, c); err != nil {
+		return "", err
+	}
+
+	return c.Latest, nil
+}
+
+func save(path string, c cached) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("cannot encode release check: %w", err)
+	}
+	if err := cache.Write(path, data); err != nil {
+		return fmt.Errorf("cannot write release check: %w", err)
+	}
+
+	return nil
+}
+
+func fetch(channel string) (string, error) {
+	client := http.Client{Timeout: CheckTimeout}
+	resp, err := client.Get(ReleasesURL)
+	if err != nil {
+		return "", fmt.Errorf("cannot check releases: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("releases check returned HTTP error code: %d", resp.StatusCode)
+	}
+
+	var releases []release
+	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
+		return "", fmt.Errorf("cannot parse releases: %w", err)
+	}
+
+	latest := ""
+	for _, r := range releases {
+		if r.Draft || (r.Prerelease && channel != Beta) {
+			continue
+		}
+		if _, _, ok := parse(r.TagName); ok && (latest == "" || Newer(r.TagName, latest)) {
+			latest = r.TagName
+		}
+	}
+
+	if latest == "" {
+		return "", errors.New("no release found")
+	}
+
+	return latest, nil
+}
+
+// Split `v1.2.3-beta.1` version into numbers and pre-release part
+func parse(v string) ([3]int, string, bool) {
+	var n [3]int
+
+	v = describeSuffix.ReplaceAllString(strings.TrimPrefix(v, "v"), "")
+	v, pre, _ := strings.Cut(v, "-")
+
+	parts := strings.Split(v, ".")
+	if len(parts) != 3 {
+		return n, "", false
+	}
+
+	for i, p := range parts {
+		x, err := strconv.Atoi(p)
+		if err != nil {
+			return n, "", false
+		}
+		n[i] = x
+	}
+
+	return n, pre, true
+}
+
+// Newer reports whether version a is newer than b, release is newer than its pre-releases
+func Newer(a, b string) bool {
+	na, pa, okA := parse(a)
+	nb, pb, okB := parse(b)
+	if !okA || !okB {
+		return false
+	}
+
+	for i := range na {
+		if na[i] != nb[i] {
+			return na[i] > nb[i]
+		}
+	}
+
+	switch {
+	case pa == pb:
+		return false
+	case pa == "":
+		return true
+	case pb == "":
+		return false
+	}
+
+	return pa > pb
+}
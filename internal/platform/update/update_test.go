@@ -0,0 +1,142 @@
+package update
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNewer(t *testing.T) {
+
+	testCases := []struct {
+		a, b string
+		want bool
+	}{
+		{a: "v1.3.0", b: "v1.2.9", want: true},
+		{a: "v1.2.9", b: "v1.3.0", want: false},
+		{a: "v1.2.0", b: "v1.2.0", want: false},
+		{a: "v1.2.0", b: "v1.2.0-beta.1", want: true},
+		{a: "v1.2.0-beta.2", b: "v1.2.0-beta.1", want: true},
+		{a: "v1.2.0", b: "v1.2.0-3-gabc1234-dirty", want: false},
+		{a: "v1.2.1", b: "v1.2.0-3-gabc1234", want: true},
+		{a: "v1.2.1", b: "abc1234", want: false},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.a+">"+tt.b, func(t *testing.T) {
+			if got := Newer(tt.a, tt.b); got != tt.want {
+				t.Errorf("Got: %v, want: %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(`[
+			{"tag_name": "v1.4.0", "draft": true},
+			{"tag_name": "v1.3.0-beta.1", "prerelease": true},
+			{"tag_name": "v1.2.0"},
+			{"tag_name": "v1.1.0"}
+		]`))
+	}))
+	defer server.Close()
+	ReleasesURL = server.URL
+
+	dir := t.TempDir()
+	CachePath = func(channel string) (string, error) {
+		return filepath.Join(dir, channel+".json"), nil
+	}
+
+	testCases := []struct {
+		name    string
+		current string
+		channel string
+		want    string
+		calls   int
+	}{
+		{name: "Stable", current: "v1.1.0", channel: Stable, want: "v1.2.0", calls: 1},
+		{name: "Cached", current: "v1.1.0", channel: Stable, want: "v1.2.0", calls: 1},
+		{name: "UpToDate", current: "v1.2.0", channel: Stable, want: "", calls: 1},
+		{name: "Beta", current: "v1.2.0", channel: Beta, want: "v1.3.0-beta.1", calls: 2},
+		{name: "Development", current: "abc1234", channel: Beta, want: "", calls: 2},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Check(tt.current, tt.channel)
+			if err != nil {
+				t.Fatalf("Got an error: '%v'", err)
+			}
+			if got != tt.want {
+				t.Errorf("Got: '%s', want: '%s'", got, tt.want)
+			}
+			if calls != tt.calls {
+				t.Errorf("Want %d calls, got: %d", tt.calls, calls)
+			}
+		})
+	}
+
+	if _, err := Check("v1.0.0", "nightly"); err == nil {
+		t.Error("Should get an error for unknown channel!")
+	}
+}
+
+func TestLatestFailureBackoff(t *testing.T) {
+	calls, fail := 0, true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if fail {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`[{"tag_name": "v1.2.0"}]`))
+	}))
+	defer server.Close()
+	ReleasesURL = server.URL
+
+	dir := t.TempDir()
+	CachePath = func(channel string) (string, error) {
+		return filepath.Join(dir, channel+".json"), nil
+	}
+
+	if _, err := Latest(Stable); err == nil {
+		t.Fatal("Should get an error for failed check!")
+	}
+
+	// Failed check is not retried until backoff passes
+	if got, err := Latest(Stable); got != "" || err != nil || calls != 1 {
+		t.Errorf("Got: '%s', '%v' after %d calls, want nothing after 1 call", got, err, calls)
+	}
+
+	defaultBackoff := FailureBackoff
+	FailureBackoff = 0
+	defer func() { FailureBackoff = defaultBackoff }()
+
+	fail = false
+	if got, err := Latest(Stable); got != "v1.2.0" || err != nil || calls != 2 {
+		t.Errorf("Got: '%s', '%v' after %d calls, want: 'v1.2.0' after 2 calls", got, err, calls)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	testCases := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{failures: 0, want: CheckInterval},
+		{failures: 1, want: FailureBackoff},
+		{failures: 3, want: 4 * FailureBackoff},
+		{failures: 10, want: CheckInterval},
+	}
+
+	for _, tt := range testCases {
+		if got := (cached{Failures: tt.failures}).valid(); got != tt.want {
+			t.Errorf("Got: %v, want: %v for %d failures", got, tt.want, tt.failures)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+// Package usage to read ingested data volume from IBM Cloud Logs data usage API
+package usage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/api"
+	"github.com/wooyey/iclogs/internal/platform/logs/tier"
+)
+
+const dataUsagePath = "/v1/data_usage"
+
+// Ranges of data usage API, the default one is the last week
+const (
+	LastWeek     = "last_week"
+	CurrentMonth = "current_month"
+	Last30Days   = "last_30_days"
+	Last90Days   = "last_90_days"
+)
+
+const (
+	dataPrefix   = "data: "
+	priorityKey  = "priority"
+	unknownTier  = "unknown"
+	dailyQuery   = "daily"
+	maxLineBytes = 1024 * 1024
+)
+
+var errUnknownRange = errors.New("unknown usage range, use last_week, current_month, last_30_days or last_90_days")
+
+// Entry is ingested volume of one day and combination of dimensions, ie. priority
+type Entry struct {
+	Time       time.Time
+	SizeGB     float64
+	Dimensions map[string]string
+}
+
+// Tier of TCO policy the entry volume went to, by its priority dimension
+func (e Entry) Tier() string {
+	p, ok := e.Dimensions[priorityKey]
+	if !ok {
+		return unknownTier
+	}
+
+	return tier.OfPriority(p)
+}
+
+type dimension struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type entry struct {
+	Timestamp  time.Time   `json:"timestamp"`
+	SizeGB     float64     `json:"size_gb"`
+	Dimensions []dimension `json:"dimensions"`
+}
+
+type message struct {
+	Entries []entry `json:"entries"`
+}
+
+// ValidRange checks range is one known by data usage API
+func ValidRange(r string) error {
+	switch r {
+	case LastWeek, CurrentMonth, Last30Days, Last90Days:
+		return nil
+	}
+
+	return errUnknownRange
+}
+
+// Get daily ingested volumes in given range, entries of the response stream are concatenated
+func Get(ctx context.Context, endpoint string, token func() (string, error), r string) ([]Entry, error) {
+	if err := ValidRange(r); err != nil {
+		return nil, err
+	}
+
+	body, err := api.Get(ctx, endpoint, token, dataUsagePath, url.Values{"range": {r}, "query": {dailyQuery}})
+	if err != nil {
+		return nil, err
+	}
+
+	return parse(bytes.NewReader(body))
+}
+
+// Parse JSON response, either one object or stream of them, optionally as server-sent events
+func parse(r io.Reader) ([]Entry, error) {
+	var b bytes.Buffer
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxLineBytes)
+	for scanner.Scan() {
+		b.WriteString(strings.TrimPrefix(scanner.Text(), dataPrefix))
+		b.WriteByte('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read data usage: %w", err)
+	}
+
+	var entries []Entry
+	dec := json.NewDecoder(&b)
+	for {
+		var m message
+		if err := dec.Decode(&m); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("cannot parse data usage: %w", err)
+		}
+
+		for _, e := range m.Entries {
+			d := make(map[string]string, len(e.Dimensions))
+			for _, kv := range e.Dimensions {
+				d[kv.Key] = kv.Value
+			}
+			entries = append(entries, Entry{Time: e.Timestamp, SizeGB: e.SizeGB, Dimensions: d})
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Time.Before(entries[j].Time) })
+
+	return entries, nil
+}
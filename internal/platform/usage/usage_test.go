@@ -0,0 +1,107 @@
+package usage
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wooyey/iclogs/tests"
+)
+
+const response = `data: {"entries":[{"timestamp":"2025-01-11T00:00:00Z","size_gb":1.5,"dimensions":[{"key":"pillar","value":"logs"},{"key":"priority","value":"high"}]}]}
+data: {"entries":[{"timestamp":"2025-01-10T00:00:00Z","size_gb":0.25,"dimensions":[{"key":"priority","value":"low"}]}]}
+`
+
+func TestGet(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != dataUsagePath || r.Header.Get("authorization") != "Bearer Good_Token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.URL.Query().Get("range") != LastWeek || r.URL.Query().Get("query") != dailyQuery {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	entries, err := Get(context.Background(), server.URL, tests.TokenOf("Good_Token"), LastWeek)
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+
+	want := []Entry{
+		{Time: time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC), SizeGB: 0.25, Dimensions: map[string]string{"priority": "low"}},
+		{Time: time.Date(2025, 1, 11, 0, 0, 0, 0, time.UTC), SizeGB: 1.5, Dimensions: map[string]string{"pillar": "logs", "priority": "high"}},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("Got: %v, want: %v", entries, want)
+	}
+
+	if _, err := Get(context.Background(), server.URL, tests.TokenOf("Bad_Token"), LastWeek); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("Expected HTTP error, got: %v", err)
+	}
+
+	if _, err := Get(context.Background(), server.URL, tests.TokenOf("Good_Token"), "yesterday"); err != errUnknownRange {
+		t.Errorf("Expected error: %v, got: %v", errUnknownRange, err)
+	}
+}
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+		count int
+	}{
+		{name: "Object", input: `{"entries":[{"size_gb":1},{"size_gb":2}]}`, count: 2},
+		{name: "Indented", input: "{\n  \"entries\": [\n    {\"size_gb\": 1}\n  ]\n}\n", count: 1},
+		{name: "Lines", input: "{\"entries\":[{\"size_gb\":1}]}\n{\"entries\":[{\"size_gb\":2}]}\n", count: 2},
+		{name: "Empty", input: "", count: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			entries, err := parse(strings.NewReader(tc.input))
+			if err != nil {
+				t.Fatalf("Got unexpected error: %v", err)
+			}
+			if len(entries) != tc.count {
+				t.Errorf("Got %d entries, want: %d", len(entries), tc.count)
+			}
+		})
+	}
+
+	if _, err := parse(strings.NewReader("{broken")); err == nil {
+		t.Error("Expected error of broken response")
+	}
+}
+
+func TestTier(t *testing.T) {
+	testCases := []struct {
+		priority string
+		want     string
+	}{
+		{priority: "high", want: "priority_insights"},
+		{priority: "medium", want: "analyze_and_alert"},
+		{priority: "low", want: "store_and_search"},
+		{priority: "other", want: "other"},
+		{priority: "", want: unknownTier},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.want, func(t *testing.T) {
+			e := Entry{Dimensions: map[string]string{}}
+			if tc.priority != "" {
+				e.Dimensions[priorityKey] = tc.priority
+			}
+			if got := e.Tier(); got != tc.want {
+				t.Errorf("Got: %s, want: %s", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+// Package webhooks to read outbound integrations (outgoing webhooks) of IBM Cloud Logs instance and test-fire them
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wooyey/iclogs/internal/platform/api"
+)
+
+const webhooksPath = "/v1/outgoing_webhooks"
+
+var FireTimeout = time.Duration(10) * time.Second // HTTP timeout of test notification - default 10 seconds
+
+var errNoURL = errors.New("integration has no URL to fire test notification to")
+
+// Generic webhook settings, notifications are sent with its method and headers
+type Generic struct {
+	Method  string            `json:"method"`
+	Headers map[string]string `json:"headers"`
+}
+
+// Webhook is outbound integration used as alert notification channel
+type Webhook struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	URL     string   `json:"url"`
+	Generic *Generic `json:"generic_webhook"`
+}
+
+type response struct {
+	Webhooks []Webhook `json:"outgoing_webhooks"`
+}
+
+// List outbound integrations of the instance sorted by name
+func List(ctx context.Context, endpoint string, token func() (string, error)) ([]Webhook, error) {
+	var r response
+	if err := api.GetJSON(ctx, endpoint, token, webhooksPath, nil, &r); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(r.Webhooks, func(i, j int) bool { return r.Webhooks[i].Name < r.Webhooks[j].Name })
+
+	return r.Webhooks, nil
+}
+
+// Get outbound integration by its ID
+func Get(ctx context.Context, endpoint string, token func() (string, error), id string) (Webhook, error) {
+	var w Webhook
+	err := api.GetJSON(ctx, endpoint, token, webhooksPath+"/"+id, nil, &w)
+
+	return w, err
+}
+
+// Fire sends payload to webhook URL with its method and headers, returning HTTP status of the receiver
+func Fire(ctx context.Context, w Webhook, payload []byte) (int, error) {
+	if w.URL == "" {
+		return 0, errNoURL
+	}
+
+	method := http.MethodPost
+	var headers map[string]string
+	if w.Generic != nil {
+		if w.Generic.Method != "" && !strings.EqualFold(w.Generic.Method, "unknown") {
+			method = strings.ToUpper(w.Generic.Method)
+		}
+		headers = w.Generic.Headers
+	}
+
+	var body io.Reader
+	if method != http.MethodGet {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, w.URL, body)
+	if err != nil {
+		return 0, fmt.Errorf("cannot create %s request: %w", method, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	c := http.Client{Timeout: FireTimeout}
+	resp, err := c.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("cannot send test notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return resp.StatusCode, fmt.Errorf("got HTTP error code: %d, message: '%s'", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	return resp.StatusCode, nil
+}
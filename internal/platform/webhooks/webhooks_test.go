@@ -0,0 +1,84 @@
+package webhooks
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/wooyey/iclogs/tests"
+)
+
+func TestListAndGet(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case webhooksPath:
+			w.Write([]byte(`{"outgoing_webhooks":[{"id":"w2","name":"pager","type":"generic","url":"https://pager"},{"id":"w1","name":"chat","type":"generic","url":"https://chat"}]}`))
+		case webhooksPath + "/w1":
+			w.Write([]byte(`{"id":"w1","name":"chat","type":"generic","url":"https://chat","generic_webhook":{"method":"put","headers":{"X-Key":"secret"}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	l, err := List(context.Background(), server.URL, tests.TokenOf("Good_Token"))
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	if len(l) != 2 || l[0].Name != "chat" || l[1].Name != "pager" {
+		t.Fatalf("Expected webhooks sorted by name, got: %v", l)
+	}
+
+	w, err := Get(context.Background(), server.URL, tests.TokenOf("Good_Token"), "w1")
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	if w.Generic == nil || w.Generic.Method != "put" || w.Generic.Headers["X-Key"] != "secret" {
+		t.Errorf("Got unexpected webhook: %+v", w)
+	}
+}
+
+func TestFire(t *testing.T) {
+	var method, key, body string
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, key = r.Method, r.Header.Get("X-Key")
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		if key == "bad" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte("wrong key\n"))
+		}
+	}))
+	defer receiver.Close()
+
+	testCases := []struct {
+		name    string
+		webhook Webhook
+		method  string
+		status  int
+		err     bool
+	}{
+		{name: "DefaultPost", webhook: Webhook{URL: receiver.URL}, method: http.MethodPost, status: 200},
+		{name: "Put", webhook: Webhook{URL: receiver.URL, Generic: &Generic{Method: "put", Headers: map[string]string{"X-Key": "good"}}}, method: http.MethodPut, status: 200},
+		{name: "Rejected", webhook: Webhook{URL: receiver.URL, Generic: &Generic{Headers: map[string]string{"X-Key": "bad"}}}, method: http.MethodPost, status: 401, err: true},
+		{name: "NoURL", webhook: Webhook{Type: "ibm_event_notifications"}, err: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			method, body = "", ""
+			status, err := Fire(context.Background(), tc.webhook, []byte(`{"test":true}`))
+			if (err != nil) != tc.err {
+				t.Fatalf("Expected error: %v, got: %v", tc.err, err)
+			}
+			if status != tc.status || method != tc.method {
+				t.Errorf("Got status %d with method %s, want: %d with %s", status, method, tc.status, tc.method)
+			}
+			if tc.method != "" && body != `{"test":true}` {
+				t.Errorf("Got body: %s", body)
+			}
+		})
+	}
+}
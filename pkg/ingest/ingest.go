@@ -0,0 +1,295 @@
+// Package ingest to send log records to IBM Cloud Logs ingestion API, in batches, optionally gzip compressed,
+// retrying throttled and failed requests. It is usable by Go services shipping their logs as well.
+package ingest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const singlesPath = "/logs/v1/singles"
+
+// Defaults of Client
+const (
+	DefaultBatchSize  = 500
+	DefaultMaxRetries = 3
+	DefaultBackoff    = time.Second
+)
+
+var PostTimeout = time.Duration(30) * time.Second // HTTP post timeout - default 30 seconds
+
+var errUnknownSeverity = errors.New("unknown severity, use debug, verbose, info, warning, error or critical")
+
+// Severities of ingestion API
+const (
+	Debug    = 1
+	Verbose  = 2
+	Info     = 3
+	Warning  = 4
+	Error    = 5
+	Critical = 6
+)
+
+// Severities by name, case is ignored. Common short names are accepted too.
+var severities = map[string]int{
+	"debug":    Debug,
+	"verbose":  Verbose,
+	"trace":    Verbose,
+	"info":     Info,
+	"warning":  Warning,
+	"warn":     Warning,
+	"error":    Error,
+	"err":      Error,
+	"critical": Critical,
+	"fatal":    Critical,
+}
+
+// Record sent to ingestion API, Text is plain message or JSON object
+type Record struct {
+	Application string    `json:"applicationName"`
+	Subsystem   string    `json:"subsystemName"`
+	Computer    string    `json:"computerName,omitempty"`
+	Category    string    `json:"category,omitempty"`
+	ClassName   string    `json:"className,omitempty"`
+	MethodName  string    `json:"methodName,omitempty"`
+	ThreadID    string    `json:"threadId,omitempty"`
+	Severity    int       `json:"severity"`
+	Text        string    `json:"text"`
+	Time        time.Time `json:"-"`
+}
+
+// Record as expected by singles endpoint, with timestamp in milliseconds
+type single struct {
+	Record
+	Timestamp float64 `json:"timestamp"`
+}
+
+// Severity returns ingestion API severity of its name
+func Severity(name string) (int, error) {
+	s, ok := severities[strings.ToLower(name)]
+	if !ok {
+		return 0, errUnknownSeverity
+	}
+
+	return s, nil
+}
+
+// SlogSeverity returns ingestion API severity of slog level, levels above error are critical
+func SlogSeverity(l slog.Level) int {
+	switch {
+	case l < slog.LevelDebug:
+		return Verbose
+	case l < slog.LevelInfo:
+		return Debug
+	case l < slog.LevelWarn:
+		return Info
+	case l < slog.LevelError:
+		return Warning
+	case l == slog.LevelError:
+		return Error
+	default:
+		return Critical
+	}
+}
+
+// FromLabels sets record fields from labels as returned by logs search, ie. `applicationname` and `subsystemname`.
+// Unknown labels are ignored.
+func (r *Record) FromLabels(labels map[string]string) {
+	fields := map[string]*string{
+		"applicationname": &r.Application,
+		"subsystemname":   &r.Subsystem,
+		"computername":    &r.Computer,
+		"category":        &r.Category,
+		"classname":       &r.ClassName,
+		"methodname":      &r.MethodName,
+		"threadid":        &r.ThreadID,
+	}
+
+	for k, v := range labels {
+		if f, ok := fields[strings.ToLower(k)]; ok {
+			*f = v
+		}
+	}
+}
+
+// URL of ingestion endpoint for logs API endpoint of the same instance, ie. `https://<id>.api.<region>.logs.cloud.ibm.com`
+func URL(logsURL string) (string, error) {
+	u, err := url.Parse(logsURL)
+	if err != nil {
+		return "", fmt.Errorf("cannot parse logs endpoint: %w", err)
+	}
+
+	parts := strings.Split(u.Hostname(), ".")
+	for i, p := range parts {
+		if p == "api" && i > 0 {
+			parts[i] = "ingress"
+			return u.Scheme + "://" + strings.Join(parts, "."), nil
+		}
+	}
+
+	return "", fmt.Errorf("cannot derive ingestion endpoint from '%s', use ingestion endpoint option", logsURL)
+}
+
+// Client sends records to ingestion endpoint. Zero values of batch size, retries and backoff mean defaults.
+type Client struct {
+	Endpoint   string                 // Ingestion endpoint, see URL
+	Token      func() (string, error) // IAM token, called for each request so it can be renewed
+	BatchSize  int                    // Records per request
+	MaxRetries int                    // Retries of throttled or failed request, negative disables them
+	Backoff    time.Duration          // Wait before the first retry, doubled for each next one
+	Gzip       bool                   // Compress request body
+	HTTPClient *http.Client           // Client with PostTimeout by default
+}
+
+// HTTP error of ingestion endpoint
+type HTTPError struct {
+	StatusCode int
+	Message    string
+	retryAfter time.Duration
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("got HTTP error code: %d, message: '%s'", e.StatusCode, e.Message)
+}
+
+// Throttled and server side errors are retried
+func (e *HTTPError) temporary() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// Send records in batches, stopping at the first batch which cannot be sent
+func (c *Client) Send(ctx context.Context, records []Record) error {
+	size := c.BatchSize
+	if size <= 0 {
+		size = DefaultBatchSize
+	}
+
+	for start := 0; start < len(records); start += size {
+		end := min(start+size, len(records))
+		if err := c.sendBatch(ctx, records[start:end]); err != nil {
+			return fmt.Errorf("cannot send records %d-%d of %d: %w", start+1, end, len(records), err)
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) sendBatch(ctx context.Context, records []Record) error {
+	body, err := c.encode(records)
+	if err != nil {
+		return err
+	}
+
+	retries := c.MaxRetries
+	if retries == 0 {
+		retries = DefaultMaxRetries
+	}
+	backoff := c.Backoff
+	if backoff <= 0 {
+		backoff = DefaultBackoff
+	}
+
+	for attempt := 0; ; attempt++ {
+		token, err := c.Token()
+		if err != nil {
+			return err // Renewing token is not retried
+		}
+
+		err = c.post(ctx, token, body)
+
+		var httpErr *HTTPError
+		temporary := err != nil && (!errors.As(err, &httpErr) || httpErr.temporary())
+		if !temporary || attempt >= retries {
+			return err
+		}
+
+		wait := backoff << attempt
+		if httpErr != nil && httpErr.retryAfter > 0 {
+			wait = httpErr.retryAfter
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (c *Client) encode(records []Record) ([]byte, error) {
+	s := make([]single, len(records))
+	for i, r := range records {
+		if r.Time.IsZero() {
+			r.Time = time.Now()
+		}
+		s[i] = single{Record: r, Timestamp: float64(r.Time.UnixNano()) / float64(time.Millisecond)}
+	}
+
+	j, err := json.Marshal(s)
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal records: %w", err)
+	}
+	if !c.Gzip {
+		return j, nil
+	}
+
+	var b bytes.Buffer
+	zw := gzip.NewWriter(&b)
+	if _, err := zw.Write(j); err != nil {
+		return nil, fmt.Errorf("cannot compress records: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("cannot compress records: %w", err)
+	}
+
+	return b.Bytes(), nil
+}
+
+func (c *Client) post(ctx context.Context, token string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(c.Endpoint, "/")+singlesPath, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("cannot create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	if c.Gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = &http.Client{Timeout: PostTimeout}
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot POST records: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		e := &HTTPError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(msg))}
+		if s, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && s > 0 {
+			e.retryAfter = time.Duration(s) * time.Second
+		}
+		return e
+	}
+
+	return nil
+}
+
+// Send records to ingestion endpoint using IAM token, with default batching and retries
+func Send(endpoint, token string, records []Record) error {
+	c := Client{Endpoint: endpoint, Token: func() (string, error) { return token, nil }}
+	return c.Send(context.Background(), records)
+}
@@ -0,0 +1,193 @@
+package ingest
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSeverity(t *testing.T) {
+	s, err := Severity("Warning")
+	if err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+	if s != 4 {
+		t.Errorf("Got: %d, want: 4", s)
+	}
+
+	if _, err := Severity("panic"); err != errUnknownSeverity {
+		t.Errorf("Got: '%v', want: '%v'", err, errUnknownSeverity)
+	}
+}
+
+func TestURL(t *testing.T) {
+	testCases := []struct {
+		name    string
+		logsURL string
+		want    string
+		err     bool
+	}{
+		{name: "API", logsURL: "https://abc-123.api.eu-de.logs.cloud.ibm.com", want: "https://abc-123.ingress.eu-de.logs.cloud.ibm.com"},
+		{name: "Path", logsURL: "https://abc-123.api.eu-de.logs.cloud.ibm.com/", want: "https://abc-123.ingress.eu-de.logs.cloud.ibm.com"},
+		{name: "Other", logsURL: "https://logs.example.com", err: true},
+		{name: "APIFirst", logsURL: "https://api.example.com", err: true},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := URL(tt.logsURL)
+			if (err != nil) != tt.err {
+				t.Fatalf("Got error: '%v', want error: %v", err, tt.err)
+			}
+			if got != tt.want {
+				t.Errorf("Got: '%s', want: '%s'", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSend(t *testing.T) {
+	var (
+		path, auth string
+		body       []map[string]any
+	)
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, auth = r.URL.Path, r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&body)
+		w.WriteHeader(status)
+		w.Write([]byte("bad record"))
+	}))
+	defer server.Close()
+
+	r := Record{Application: "test", Subsystem: "iclogs", Severity: 3, Text: "hello", Time: time.UnixMilli(1736589600123)}
+	if err := Send(server.URL+"/", "Good_Token", []Record{r}); err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+
+	if path != singlesPath || auth != "Bearer Good_Token" {
+		t.Errorf("Got request to '%s' with '%s'", path, auth)
+	}
+	want := []map[string]any{{"applicationName": "test", "subsystemName": "iclogs", "severity": float64(3), "text": "hello", "timestamp": float64(1736589600123)}}
+	if !reflect.DeepEqual(body, want) {
+		t.Errorf("\nGot:\t%+v\nWant:\t%+v", body, want)
+	}
+
+	status = http.StatusBadRequest
+	err := Send(server.URL, "Good_Token", []Record{r})
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusBadRequest || httpErr.Message != "bad record" {
+		t.Errorf("Got: '%v', want HTTP error", err)
+	}
+}
+
+func TestSlogSeverity(t *testing.T) {
+	testCases := []struct {
+		level slog.Level
+		want  int
+	}{
+		{level: slog.LevelDebug - 4, want: Verbose},
+		{level: slog.LevelDebug, want: Debug},
+		{level: slog.LevelInfo, want: Info},
+		{level: slog.LevelWarn, want: Warning},
+		{level: slog.LevelError, want: Error},
+		{level: slog.LevelError + 4, want: Critical},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			if got := SlogSeverity(tt.level); got != tt.want {
+				t.Errorf("Got: %d, want: %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromLabels(t *testing.T) {
+	var r Record
+	r.FromLabels(map[string]string{"applicationname": "web", "SubsystemName": "api", "threadid": "7", "window": "ignored"})
+
+	want := Record{Application: "web", Subsystem: "api", ThreadID: "7"}
+	if !reflect.DeepEqual(r, want) {
+		t.Errorf("\nGot:\t%+v\nWant:\t%+v", r, want)
+	}
+}
+
+func TestClient(t *testing.T) {
+	var (
+		batches  []int
+		statuses = []int{http.StatusServiceUnavailable, http.StatusTooManyRequests}
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(statuses) != 0 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(statuses[0])
+			statuses = statuses[1:]
+			return
+		}
+
+		var body io.Reader = r.Body
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			zr, err := gzip.NewReader(r.Body)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			body = zr
+		}
+		var records []map[string]any
+		if err := json.NewDecoder(body).Decode(&records); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		batches = append(batches, len(records))
+	}))
+	defer server.Close()
+
+	tokens := 0
+	c := Client{
+		Endpoint:  server.URL,
+		Token:     func() (string, error) { tokens++; return "Good_Token", nil },
+		BatchSize: 2,
+		Backoff:   time.Millisecond,
+		Gzip:      true,
+	}
+
+	records := make([]Record, 5)
+	if err := c.Send(context.Background(), records); err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+
+	if !reflect.DeepEqual(batches, []int{2, 2, 1}) {
+		t.Errorf("Got batches: %v, want: [2 2 1]", batches)
+	}
+	if tokens != 5 {
+		t.Errorf("Got %d token calls, want one per request: 5", tokens)
+	}
+
+	// Retries run out
+	statuses = []int{http.StatusBadGateway, http.StatusBadGateway}
+	c.MaxRetries = 1
+	err := c.Send(context.Background(), records[:1])
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusBadGateway {
+		t.Errorf("Got: '%v', want HTTP error", err)
+	}
+
+	// Cancelled while waiting for retry
+	statuses = []int{http.StatusServiceUnavailable}
+	c.Backoff = time.Hour
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := c.Send(ctx, records[:1]); !errors.Is(err, context.Canceled) {
+		t.Errorf("Got: '%v', want: '%v'", err, context.Canceled)
+	}
+}
@@ -0,0 +1,120 @@
+// Package query runs IBM Cloud Logs queries returning found log records, or rows of Dataprime aggregations
+// (ie. countby or groupby) as table, without parsing raw responses. It is usable by Go programs querying logs as well.
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/wooyey/iclogs/internal/platform/logs"
+)
+
+var errRecords = errors.New("query returned log records instead of aggregation rows")
+
+// Spec of query time range, tier, syntax and records limit
+type Spec = logs.QuerySpec
+
+// Result is LogsResult with log records, or TableResult with rows of Dataprime aggregation
+type Result interface {
+	result()
+}
+
+// LogsResult is result of search returning log records sorted by time
+type LogsResult logs.Result
+
+// Row of Dataprime aggregation, values by column name
+type Row map[string]any
+
+// TableResult is result of Dataprime aggregation, which returns rows instead of records
+type TableResult struct {
+	Columns  []string // Names of all row values, sorted
+	Rows     []Row
+	Warnings []string
+	Timings  logs.Timings
+}
+
+func (LogsResult) result()  {}
+func (TableResult) result() {}
+
+// Run returns found records as LogsResult, or rows of Dataprime aggregation as TableResult
+func Run(ctx context.Context, endpoint, token, query string, spec Spec) (Result, error) {
+	records, r, err := fetch(ctx, endpoint, token, query, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	if aggregation(records) {
+		return table(records, r)
+	}
+
+	if r.Logs, err = logs.ParseRecords(records); err != nil {
+		return nil, err
+	}
+
+	return LogsResult(r), nil
+}
+
+// Table returns rows of Dataprime aggregation, query returning log records is an error
+func Table(ctx context.Context, endpoint, token, query string, spec Spec) (TableResult, error) {
+	records, r, err := fetch(ctx, endpoint, token, query, spec)
+	if err != nil {
+		return TableResult{}, err
+	}
+
+	if len(records) != 0 && !aggregation(records) {
+		return TableResult{}, errRecords
+	}
+
+	return table(records, r)
+}
+
+func fetch(ctx context.Context, endpoint, token, query string, spec Spec) ([]logs.Record, logs.Result, error) {
+	var records []logs.Record
+
+	r, err := logs.StreamRecordsContext(ctx, endpoint, token, query, spec, func(b []logs.Record) error {
+		records = append(records, b...)
+		return nil
+	})
+
+	return records, r, err
+}
+
+// Rows of Dataprime aggregations have only user data, without metadata of log records
+func aggregation(records []logs.Record) bool {
+	if len(records) == 0 {
+		return false
+	}
+	for i := range records {
+		if len(records[i].Metadata) != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func table(records []logs.Record, r logs.Result) (TableResult, error) {
+	t := TableResult{Columns: []string{}, Rows: make([]Row, 0, len(records)), Warnings: r.Warnings, Timings: r.Timings}
+
+	columns := map[string]bool{}
+	for i := range records {
+		var row Row
+		if err := json.Unmarshal([]byte(records[i].Data), &row); err != nil {
+			return TableResult{}, fmt.Errorf("cannot parse aggregation row: %w", err)
+		}
+		for k := range row {
+			columns[k] = true
+		}
+		t.Rows = append(t.Rows, row)
+	}
+
+	for c := range columns {
+		t.Columns = append(t.Columns, c)
+	}
+	sort.Strings(t.Columns)
+
+	return t, nil
+}
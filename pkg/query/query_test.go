@@ -0,0 +1,104 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+const (
+	respAggregation = `data: {"result":{"results":[{"user_data":"{\"applicationname\":\"web\",\"_count\":3}","metadata":[],"labels":[]},{"user_data":"{\"applicationname\":\"api\",\"_count\":1,\"subsystemname\":\"auth\"}","metadata":[],"labels":[]}]}}
+data: {"warning":{"compile_warning":{"warning_message":"keypath does not exist"}}}
+`
+	respRecords = `data: {"result":{"results":[{"user_data":"{\"message\":\"hello\"}","metadata":[{"key":"timestamp","value":"2025-01-11T12:00:00.000000"},{"key":"severity","value":"Info"}],"labels":[{"key":"applicationname","value":"web"}]}]}}
+`
+	respInvalid = `data: {"result":{"results":[{"user_data":"not json","metadata":[],"labels":[]}]}}
+`
+)
+
+func mockServer(response string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/query" || r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		fmt.Fprint(w, response)
+	}))
+}
+
+func TestRun(t *testing.T) {
+	srv := mockServer(respAggregation)
+	defer srv.Close()
+
+	got, err := Run(context.Background(), srv.URL, "token", "source logs | countby $l.applicationname", Spec{})
+	if err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+	want := TableResult{
+		Columns:  []string{"_count", "applicationname", "subsystemname"},
+		Rows:     []Row{{"applicationname": "web", "_count": float64(3)}, {"applicationname": "api", "_count": float64(1), "subsystemname": "auth"}},
+		Warnings: []string{"keypath does not exist"},
+	}
+	table, ok := got.(TableResult)
+	if !ok {
+		t.Fatalf("Got: %T, want: TableResult", got)
+	}
+	table.Timings = want.Timings
+	if !reflect.DeepEqual(table, want) {
+		t.Errorf("Got: '%+v', Want: '%+v'", table, want)
+	}
+
+	srv = mockServer(respRecords)
+	defer srv.Close()
+
+	got, err = Run(context.Background(), srv.URL, "token", "app:web", Spec{})
+	if err != nil {
+		t.Fatalf("Got an error: '%v'", err)
+	}
+	l, ok := got.(LogsResult)
+	if !ok {
+		t.Fatalf("Got: %T, want: LogsResult", got)
+	}
+	if len(l.Logs) != 1 || l.Logs[0].Severity != "Info" {
+		t.Errorf("Got: '%+v', want one record", l.Logs)
+	}
+}
+
+func TestTable(t *testing.T) {
+	testCases := []struct {
+		name     string
+		response string
+		rows     int
+		err      bool
+	}{
+		{name: "Rows", response: respAggregation, rows: 2},
+		{name: "Empty", response: "", rows: 0},
+		{name: "Records", response: respRecords, err: true},
+		{name: "Invalid", response: respInvalid, err: true},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := mockServer(tt.response)
+			defer srv.Close()
+
+			got, err := Table(context.Background(), srv.URL, "token", "source logs | countby $l.applicationname", Spec{})
+			if (err != nil) != tt.err {
+				t.Fatalf("Got error: '%v', want error: %v", err, tt.err)
+			}
+			if len(got.Rows) != tt.rows {
+				t.Errorf("Got: %d rows, want: %d", len(got.Rows), tt.rows)
+			}
+		})
+	}
+
+	srv := mockServer(respRecords)
+	defer srv.Close()
+	if _, err := Table(context.Background(), srv.URL, "token", "app:web", Spec{}); !errors.Is(err, errRecords) {
+		t.Errorf("Got: '%v', Want: '%v'", err, errRecords)
+	}
+}
@@ -19,3 +19,8 @@ func LoadData(name string) string {
 
 	return string(r)
 }
+
+// TokenOf returns token getter always giving token t
+func TokenOf(t string) func() (string, error) {
+	return func() (string, error) { return t, nil }
+}